@@ -28,7 +28,9 @@ const (
 	defaultWakeupInterval       = 15 * time.Minute
 	defaultCatalogNamespace     = "openshift-operator-lifecycle-manager"
 	defaultConfigMapServerImage = "quay.io/operatorframework/configmap-operator-registry:latest"
+	defaultShardServerImage     = "quay.io/operator-framework/olm:master"
 	defaultOperatorName         = ""
+	defaultResolver             = catalog.GreedyResolver
 )
 
 // config flags defined globally so that they appear on the test binary as well
@@ -48,9 +50,39 @@ var (
 	configmapServerImage = flag.String(
 		"configmapServerImage", defaultConfigMapServerImage, "the image to use for serving the operator registry api for a configmap")
 
+	shardServerImage = flag.String(
+		"shardServerImage", defaultShardServerImage, "the image to use for serving the operator registry api for a configmap catalog backed by several configmaps; it must provide configmap-shard-server, like the olm image does")
+
 	writeStatusName = flag.String(
 		"writeStatusName", defaultOperatorName, "ClusterOperator name in which to write status, set to \"\" to disable.")
 
+	queryTimeout = flag.Duration(
+		"queryTimeout", catalog.DefaultQueryTimeout, "how long to wait for a single CatalogSource to answer a query during resolution, 0 to wait indefinitely")
+
+	resolutionTimeout = flag.Duration(
+		"resolutionTimeout", catalog.DefaultResolutionTimeout, "how long the resolution of a namespace may take, 0 to wait indefinitely")
+
+	resolverName = flag.String(
+		"resolver", defaultResolver, "the resolver to resolve namespaces with: \"greedy\" adds operators one at a time, \"sat\" solves all of their constraints together")
+
+	transactionalInstallPlans = flag.Bool(
+		"transactionalInstallPlans", false, "roll back the resources a failed InstallPlan created or updated")
+
+	allowedStepKinds = flag.String(
+		"allowedStepKinds", "", "comma separated list of the kinds without dedicated handling InstallPlans may install on top of ConfigMaps, PodDisruptionBudgets, PrometheusRules and ServiceMonitors, as Kind.group or Kind for the core group")
+
+	deniedStepKinds = flag.String(
+		"deniedStepKinds", "", "comma separated list of the kinds InstallPlans may not install, as Kind.group or Kind for the core group")
+
+	registryReplicas = flag.Int(
+		"registryReplicas", 0, "default number of replicas of each catalog registry server, run in a Deployment with a PodDisruptionBudget; registry servers run as single pods when 0. CatalogSources can override it with spec.registryPodConfig.replicas")
+
+	urlCatalogRoot = flag.String(
+		"urlCatalogRoot", "", "absolute path of the directory CatalogSources with a url may load catalogs from with file urls or paths; local catalogs can't be loaded when empty")
+
+	urlCatalogHosts = flag.String(
+		"urlCatalogHosts", "", "comma separated list of the hosts CatalogSources with a url may download catalogs from, as host or host:port; catalogs can't be downloaded when empty")
+
 	debug = flag.Bool(
 		"debug", false, "use debug log level")
 
@@ -146,7 +178,18 @@ func main() {
 	opClient := operatorclient.NewClientFromConfig(*kubeConfigPath, logger)
 
 	// Create a new instance of the operator.
-	op, err := catalog.NewOperator(ctx, *kubeConfigPath, utilclock.RealClock{}, logger, *wakeupInterval, *configmapServerImage, *catalogNamespace, namespaces...)
+	op, err := catalog.NewOperator(ctx, *kubeConfigPath, utilclock.RealClock{}, logger, *wakeupInterval, *configmapServerImage, *catalogNamespace, namespaces,
+		catalog.WithQueryTimeout(*queryTimeout),
+		catalog.WithResolutionTimeout(*resolutionTimeout),
+		catalog.WithResolver(*resolverName),
+		catalog.WithTransactionalInstallPlans(*transactionalInstallPlans),
+		catalog.WithAllowedStepKinds(splitList(*allowedStepKinds)...),
+		catalog.WithDeniedStepKinds(splitList(*deniedStepKinds)...),
+		catalog.WithRegistryReplicas(int32(*registryReplicas)),
+		catalog.WithShardServerImage(*shardServerImage),
+		catalog.WithURLCatalogRoot(*urlCatalogRoot),
+		catalog.WithURLCatalogHosts(splitList(*urlCatalogHosts)...),
+	)
 	if err != nil {
 		log.Panicf("error configuring operator: %s", err.Error())
 	}
@@ -160,3 +203,14 @@ func main() {
 
 	<-op.Done()
 }
+
+// splitList splits a comma separated flag value into its trimmed elements, leaving out empty ones.
+func splitList(value string) []string {
+	var elements []string
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
+	olmversion "github.com/operator-framework/operator-lifecycle-manager/pkg/version"
+)
+
+// config flags defined globally so that they appear on the test binary as well
+var (
+	port = flag.String(
+		"port", "50051", "port number to serve the registry api on")
+
+	terminationLogPath = flag.String(
+		"termination-log", "/dev/termination-log", "path to a container termination log file")
+
+	debug = flag.Bool(
+		"debug", false, "use debug log level")
+
+	version = flag.Bool("version", false, "displays olm version")
+)
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] DIRECTORY...\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Serves the catalog held by the ConfigMaps mounted in the given directories, loaded in order.")
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	// Parse the command-line flags.
+	flag.Parse()
+
+	// Check if version flag was set
+	if *version {
+		fmt.Print(olmversion.String())
+
+		// Exit early
+		os.Exit(0)
+	}
+
+	logger := log.New()
+	if *debug {
+		logger.SetLevel(log.DebugLevel)
+	}
+	logger.Infof("log level %s", logger.Level)
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		fatal(logger, fmt.Errorf("no configmap directories given"))
+	}
+
+	catalog, err := inprocess.LoadConfigMapDirectories(dirs...)
+	if err != nil {
+		fatal(logger, fmt.Errorf("error loading catalog: %s", err))
+	}
+
+	// The server only listens once the catalog is loaded, so the registry pod is ready as soon as its port is open
+	lis, err := net.Listen("tcp", ":"+*port)
+	if err != nil {
+		fatal(logger, fmt.Errorf("failed to listen: %s", err))
+	}
+	logger.WithField("port", *port).Infof("serving registry from %d configmaps", len(dirs))
+	if err := inprocess.NewGRPCServer(catalog).Serve(lis); err != nil {
+		fatal(logger, fmt.Errorf("failed to serve: %s", err))
+	}
+}
+
+// fatal writes the given error to the termination log, so it's reported in the status of the registry pod, and exits.
+func fatal(logger *log.Logger, err error) {
+	if writeErr := ioutil.WriteFile(*terminationLogPath, []byte(err.Error()), 0644); writeErr != nil {
+		logger.WithError(writeErr).Warn("couldn't write termination log")
+	}
+	logger.Fatal(err)
+}
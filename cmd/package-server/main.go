@@ -11,6 +11,7 @@ import (
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/server"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/signals"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/metrics"
 )
 
 const (
@@ -34,6 +35,8 @@ var (
 )
 
 func init() {
+	metrics.RegisterPackageServer()
+
 	flags := cmd.Flags()
 
 	flags.DurationVar(&options.WakeupInterval, "interval", options.WakeupInterval, "Interval at which to re-sync CatalogSources")
@@ -0,0 +1,62 @@
+package operators
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CatalogPolicyKind is the PascalCase name of a CatalogPolicy's kind.
+const CatalogPolicyKind = "CatalogPolicy"
+
+// CatalogPolicySpec is the spec for a CatalogPolicy resource. A CatalogSource that is denied is never allowed, and
+// if any CatalogSources are allowed, all others are denied.
+type CatalogPolicySpec struct {
+	// Namespaces is the set of namespaces whose Subscriptions the policy applies to.
+	// The policy applies to every namespace if it is empty.
+	// +optional
+	Namespaces []string
+
+	// Allowed is a list of CatalogSources that are allowed.
+	// +optional
+	Allowed []CatalogSourceReference
+
+	// AllowedSelector selects the CatalogSources that are allowed by their labels.
+	// +optional
+	AllowedSelector *metav1.LabelSelector
+
+	// Denied is a list of CatalogSources that are denied.
+	// +optional
+	Denied []CatalogSourceReference
+
+	// DeniedSelector selects the CatalogSources that are denied by their labels.
+	// +optional
+	DeniedSelector *metav1.LabelSelector
+}
+
+// CatalogSourceReference identifies a CatalogSource.
+type CatalogSourceReference struct {
+	Name      string
+	Namespace string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +genclient:nonNamespaced
+
+// CatalogPolicy restricts the CatalogSources that Subscriptions may resolve against.
+// It's cluster-scoped, so that only cluster administrators can set it.
+type CatalogPolicy struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec CatalogPolicySpec
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CatalogPolicyList is a list of CatalogPolicy resources.
+type CatalogPolicyList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []CatalogPolicy
+}
@@ -2,7 +2,10 @@ package operators
 
 import (
 	"fmt"
+	"net"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -23,6 +26,10 @@ const (
 	// SourceTypeGrpc specifies a CatalogSource that can use an operator registry image to generate a
 	// registry-server or connect to a pre-existing registry at an address.
 	SourceTypeGrpc SourceType = "grpc"
+
+	// SourceTypeURL specifies a CatalogSource whose catalog is loaded from a URL and served from within the catalog
+	// operator, without a registry pod.
+	SourceTypeURL SourceType = "url"
 )
 
 type CatalogSourceSpec struct {
@@ -34,6 +41,19 @@ type CatalogSourceSpec struct {
 	// +Optional
 	ConfigMap string
 
+	// ConfigMaps are the names of ConfigMaps that together back a registry. Each ConfigMap is mounted into the registry
+	// pod, which loads the lists under their customResourceDefinitions, clusterServiceVersions and packages keys in the
+	// given order, so only each ConfigMap on its own must fit the ConfigMap size limit.
+	// Only used when SourceType = SourceTypeConfigmap or SourceTypeInternal, and ConfigMap isn't set.
+	// +Optional
+	ConfigMaps []string
+
+	// ConfigMapSelector selects ConfigMaps that together back a registry, loaded in order of their names like
+	// ConfigMaps.
+	// Only used when SourceType = SourceTypeConfigmap or SourceTypeInternal, and neither ConfigMap nor ConfigMaps is set.
+	// +Optional
+	ConfigMapSelector *metav1.LabelSelector
+
 	// Address is a host that OLM can use to connect to a pre-existing registry.
 	// Format: <registry-host or ip>:<port>
 	// Only used when SourceType = SourceTypeGrpc.
@@ -47,11 +67,37 @@ type CatalogSourceSpec struct {
 	// +Optional
 	Image string
 
+	// URL locates the manifest directory of the catalog, in the operator-registry layout, to serve from within the
+	// catalog operator. Either an http(s) URL of a gzipped tarball of the directory, or a file URL or absolute path of
+	// the directory or of such a tarball, e.g. on a volume mounted into the catalog operator. The catalog operator only
+	// downloads catalogs from the hosts it's configured to allow, and only reads local catalogs within the directory
+	// it's configured to allow. Its packages aren't listed by the package server.
+	// Only used when SourceType = SourceTypeURL.
+	// +Optional
+	URL string
+
 	// Secrets represent set of secrets that can be used to access the contents of the catalog.
+	// They are also used as the image pull secrets of the registry pods.
 	// It is best to keep this list small, since each will need to be tried for every catalog entry.
 	// +Optional
 	Secrets []string
 
+	// RegistryPodConfig configures the registry pods created for the CatalogSource.
+	// Changing it rolls out new registry pods.
+	// Only used when SourceType = SourceTypeConfigmap or SourceTypeInternal, or when the Image field is set.
+	// +Optional
+	RegistryPodConfig *RegistryPodConfig
+
+	// Priority orders catalogs that can satisfy the same dependency, highest first. The Subscription's own catalog is
+	// always consulted first, and catalogs in the Subscription's namespace are consulted before global ones.
+	// +Optional
+	Priority int32
+
+	// UpdateStrategy defines how updated catalog content is discovered and rolled out.
+	// Only used when SourceType = SourceTypeGrpc and Image is set, or when SourceType = SourceTypeURL.
+	// +Optional
+	UpdateStrategy *UpdateStrategy
+
 	// Metadata
 	DisplayName string
 	Description string
@@ -59,6 +105,55 @@ type CatalogSourceSpec struct {
 	Icon        Icon
 }
 
+// RegistryPodConfig holds the settings of a CatalogSource's registry pods.
+type RegistryPodConfig struct {
+	// Replicas is the number of registry servers to run in a Deployment with a PodDisruptionBudget, so that the catalog
+	// stays available while registry pods are rolled out or evicted. Zero runs the registry server as a single pod.
+	// Defaults to the number of replicas the catalog operator is configured with.
+	// +Optional
+	Replicas *int32
+
+	// Resources are the compute resources of the registry server container.
+	// +Optional
+	Resources corev1.ResourceRequirements
+
+	// NodeSelector restricts the nodes the registry pods are scheduled on.
+	// +Optional
+	NodeSelector map[string]string
+
+	// Tolerations are the tolerations of the registry pods. When set, they replace the default toleration of every
+	// taint.
+	// +Optional
+	Tolerations []corev1.Toleration
+
+	// Affinity holds the scheduling constraints of the registry pods.
+	// +Optional
+	Affinity *corev1.Affinity
+
+	// PriorityClassName is the name of the priority class of the registry pods.
+	// +Optional
+	PriorityClassName string
+
+	// SecurityContext is the security context of the registry pods.
+	// +Optional
+	SecurityContext *corev1.PodSecurityContext
+}
+
+// UpdateStrategy holds the ways a CatalogSource can discover updated catalog content.
+type UpdateStrategy struct {
+	// RegistryPoll polls the registry image, or the URL, for updated content.
+	// +Optional
+	RegistryPoll *RegistryPoll
+}
+
+// RegistryPoll periodically starts a registry pod from the CatalogSource's image, and rolls it out in place of the
+// serving registry pod if it resolved to a different image digest. Useful for images published under floating tags.
+// For SourceTypeURL, it periodically loads the catalog from the URL again, and serves it if its content changed.
+type RegistryPoll struct {
+	// Interval is the time to wait between polls of the registry image.
+	Interval metav1.Duration
+}
+
 type RegistryServiceStatus struct {
 	Protocol         string
 	ServiceName      string
@@ -74,7 +169,51 @@ func (s *RegistryServiceStatus) Address() string {
 type CatalogSourceStatus struct {
 	ConfigMapResource     *ConfigMapResourceReference
 	RegistryServiceStatus *RegistryServiceStatus
+	GRPCConnectionState   *GRPCConnectionState
 	LastSync              metav1.Time
+
+	// ConfigMapShards reference the ConfigMaps backing a CatalogSource with ConfigMaps or a ConfigMapSelector, in the
+	// order they are loaded.
+	ConfigMapShards []ConfigMapResourceReference
+
+	// LatestImageRegistryPoll is the last time the registry image was polled for updated content.
+	LatestImageRegistryPoll *metav1.Time
+
+	// ContentValidation summarizes the last validation of the catalog's content.
+	ContentValidation *CatalogContentValidation
+}
+
+// CatalogContentValidation summarizes the problems found by walking every package, channel and bundle of a catalog.
+type CatalogContentValidation struct {
+	// LastValidated is the time the catalog's content was last validated.
+	LastValidated metav1.Time
+
+	// Packages, Channels and Bundles count the content that was validated.
+	Packages int
+	Channels int
+	Bundles  int
+
+	// ProblemCount is the number of problems found.
+	ProblemCount int
+
+	// Problems describes the first problems found.
+	Problems []string
+}
+
+// GRPCConnectionState describes the last health check of a CatalogSource's registry server.
+type GRPCConnectionState struct {
+	// Address is the address of the registry server that was checked.
+	Address string
+
+	// LastObservedState is the serving status reported by the registry server, or the state of the connection to it
+	// when the registry server couldn't be reached.
+	LastObservedState string
+
+	// LastError is the error encountered by the last health check, if any.
+	LastError string
+
+	// LastTransitionTime is the time the observed state or error last changed.
+	LastTransitionTime metav1.Time
 }
 
 type ConfigMapResourceReference struct {
@@ -101,9 +240,24 @@ func (c *CatalogSource) Address() string {
 	if c.Spec.Address != "" {
 		return c.Spec.Address
 	}
+	if c.Spec.SourceType == SourceTypeURL {
+		// Served from within the catalog operator, on the loopback interface
+		return net.JoinHostPort("localhost", c.Status.RegistryServiceStatus.Port)
+	}
 	return c.Status.RegistryServiceStatus.Address()
 }
 
+// PollInterval returns the interval to poll the CatalogSource's registry image or URL for updated content at, and
+// false if neither is polled.
+func (c *CatalogSource) PollInterval() (time.Duration, bool) {
+	polled := (c.Spec.SourceType == SourceTypeGrpc && c.Spec.Image != "") || c.Spec.SourceType == SourceTypeURL
+	if !polled || c.Spec.UpdateStrategy == nil ||
+		c.Spec.UpdateStrategy.RegistryPoll == nil || c.Spec.UpdateStrategy.RegistryPoll.Interval.Duration <= 0 {
+		return 0, false
+	}
+	return c.Spec.UpdateStrategy.RegistryPoll.Interval.Duration, true
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // CatalogSourceList is a list of CatalogSource resources.
@@ -153,6 +153,21 @@ type ClusterServiceVersionSpec struct {
 	// +optional
 	Replaces string
 
+	// RequiredPackages are the operator packages this one depends on, regardless of the apis they provide.
+	// A matching operator is resolved and installed alongside this one for each of them.
+	// +optional
+	RequiredPackages []PackageDependency
+
+	// InstallPolicy bounds how long the install strategy is given to become ready, and how many times its install
+	// is retried, before the ClusterServiceVersion fails.
+	// +optional
+	InstallPolicy *InstallPolicy
+
+	// Overrides are carried over from the Subscription that installed the ClusterServiceVersion, and are merged into
+	// each of the deployments of its install strategy. They aren't meant to be set in bundles.
+	// +optional
+	Overrides *DeploymentOverrides
+
 	// Map of string keys and values that can be used to organize and categorize
 	// (scope and select) objects.
 	// +optional
@@ -168,6 +183,34 @@ type ClusterServiceVersionSpec struct {
 	Selector *metav1.LabelSelector
 }
 
+// PackageDependency declares a dependency on another operator package.
+type PackageDependency struct {
+	// The name of the required package.
+	PackageName string
+
+	// A semver range the version of the required operator must satisfy. Empty means any version.
+	// +optional
+	VersionRange string
+}
+
+// InstallPolicy bounds the attempts made to install the install strategy of a ClusterServiceVersion.
+type InstallPolicy struct {
+	// How long an install attempt waits for the components of the install strategy to become ready. Components
+	// that time out on their own, like deployments past their progress deadline, only fail an attempt early when
+	// no timeout is set. Defaults to 5m, which a timeout that isn't positive also uses.
+	// +optional
+	Timeout *metav1.Duration
+
+	// How many times the install is retried after an attempt fails. Defaults to 0.
+	// +optional
+	Retries int32
+
+	// How long to wait before the first retry. The wait doubles with each retry after it. Defaults to 10s, which a
+	// backoff that isn't positive also uses.
+	// +optional
+	Backoff *metav1.Duration
+}
+
 type Maintainer struct {
 	Name  string
 	Email string
@@ -239,6 +282,12 @@ const (
 	CSVReasonTooManyOperatorGroups                       ConditionReason = "TooManyOperatorGroups"
 	CSVReasonInterOperatorGroupOwnerConflict             ConditionReason = "InterOperatorGroupOwnerConflict"
 	CSVReasonCannotModifyStaticOperatorGroupProvidedAPIs ConditionReason = "CannotModifyStaticOperatorGroupProvidedAPIs"
+	CSVReasonInstallAttemptFailed                        ConditionReason = "InstallAttemptFailed"
+	CSVReasonInstallRetrying                             ConditionReason = "InstallRetrying"
+	CSVReasonRolledBack                                  ConditionReason = "RolledBack"
+	CSVReasonUpgradeRolledBack                           ConditionReason = "UpgradeRolledBack"
+	CSVReasonComponentDrifted                            ConditionReason = "ComponentDrifted"
+	CSVReasonOverridesChanged                            ConditionReason = "OverridesChanged"
 )
 
 // Conditions appear in the status as a record of state transitions on the ClusterServiceVersion
@@ -345,6 +394,36 @@ type ClusterServiceVersionStatus struct {
 	// Time the owned APIService certs will rotate next
 	// +optional
 	CertsRotateAt metav1.Time
+	// The attempts made to install the install strategy
+	// +optional
+	Install *InstallAttemptStatus
+	// The spec hashes of the resources installed for the ClusterServiceVersion, recorded once it has succeeded
+	// +optional
+	Components []ComponentHash
+}
+
+// ComponentHash records the spec hash of a resource installed for a ClusterServiceVersion.
+type ComponentHash struct {
+	// The kind of the resource
+	Kind string
+	// The name of the resource
+	Name string
+	// The hash of the resource's spec when it was recorded
+	Hash string
+}
+
+// InstallAttemptStatus tracks the attempts made to install the install strategy of a ClusterServiceVersion.
+type InstallAttemptStatus struct {
+	// The number of the current install attempt, starting from 1
+	Attempt int32
+	// Time the current install attempt started
+	StartTime metav1.Time
+	// Time the install will be retried, once the current attempt has failed
+	// +optional
+	RetryTime *metav1.Time
+	// Why the last failed attempt failed
+	// +optional
+	LastFailure string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
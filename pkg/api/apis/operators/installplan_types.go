@@ -67,6 +67,12 @@ const (
 	StepStatusNotPresent StepStatus = "NotPresent"
 	StepStatusPresent    StepStatus = "Present"
 	StepStatusCreated    StepStatus = "Created"
+
+	// StepStatusRolledBack means the step's resource was deleted, or restored to its previous manifest, after
+	// the InstallPlan failed.
+	StepStatusRolledBack StepStatus = "RolledBack"
+	// StepStatusRollbackFailed means the step's resource couldn't be rolled back after the InstallPlan failed.
+	StepStatusRollbackFailed StepStatus = "RollbackFailed"
 )
 
 // ErrInvalidInstallPlan is the error returned by functions that operate on
@@ -139,6 +145,10 @@ type Step struct {
 	Resolving string
 	Resource  StepResource
 	Status    StepStatus
+	// PreviousSnapshot is the name of the Secret, in the InstallPlan's namespace, that holds the manifest the step's
+	// resource had before the step updated it. It is only recorded when InstallPlans are executed transactionally, and
+	// the manifest is restored if the InstallPlan fails.
+	PreviousSnapshot string
 }
 
 // ManifestsMatch returns true if the CSV manifests in the StepResources of the given list of steps
@@ -45,6 +45,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterServiceVersionList{},
 		&OperatorGroup{},
 		&OperatorGroupList{},
+		&CatalogPolicy{},
+		&CatalogPolicyList{},
 	)
 	return nil
 }
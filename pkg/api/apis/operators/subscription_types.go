@@ -18,6 +18,13 @@ const (
 	SubscriptionStateUpgradeAvailable = "UpgradeAvailable"
 	SubscriptionStateUpgradePending   = "UpgradePending"
 	SubscriptionStateAtLatest         = "AtLatestKnown"
+
+	// SubscriptionStateUpgradeBlockedByConstraint means an update is available, but is outside of the Subscription's version range.
+	SubscriptionStateUpgradeBlockedByConstraint = "UpgradeBlockedByConstraint"
+
+	// SubscriptionStateUpgradeRolledBack means the Subscription's current CSV failed to install, and was rolled back to the
+	// CSV it replaced.
+	SubscriptionStateUpgradeRolledBack = "UpgradeRolledBack"
 )
 
 const (
@@ -25,6 +32,19 @@ const (
 	SubscriptionReasonUpgradeSucceeded ConditionReason = "UpgradeSucceeded"
 )
 
+const (
+	// SubscriptionDryRunAnnotationKey marks a Subscription as a dry-run. Dry-run Subscriptions are resolved
+	// alongside the other Subscriptions in their namespace, but never generate an InstallPlan.
+	SubscriptionDryRunAnnotationKey = "olm.dryRun"
+
+	// SubscriptionDryRunResultAnnotationKey is the annotation a dry-run Subscription's resolution result is written to.
+	SubscriptionDryRunResultAnnotationKey = "olm.dryRunResult"
+
+	// SubscriptionDryRunKeyAnnotationKey is the annotation recording what a dry-run Subscription's result was resolved
+	// from, so that it's only resolved again once that changes.
+	SubscriptionDryRunKeyAnnotationKey = "olm.dryRunKey"
+)
+
 // SubscriptionSpec defines an Application that can be installed
 type SubscriptionSpec struct {
 	CatalogSource          string
@@ -33,6 +53,47 @@ type SubscriptionSpec struct {
 	Channel                string
 	StartingCSV            string
 	InstallPlanApproval    Approval
+
+	// VersionRange is a semver range, such as ">=1.2.0 <2.0.0", that the installed operator's version must stay within.
+	// Updates outside of the range aren't installed, and the Subscription reports UpgradeBlockedByConstraint instead.
+	// +optional
+	VersionRange string
+
+	// RollbackPolicy opts the Subscription into rolling back upgrades that fail to install.
+	// +optional
+	RollbackPolicy *RollbackPolicy
+
+	// Overrides customize the deployments of the installed operator without changing its bundle.
+	// +optional
+	Overrides *DeploymentOverrides
+}
+
+// RollbackPolicy describes when an upgrade that fails to install is rolled back. A CSV that fails to install within the
+// window is marked as rolled back, and the CSV it replaces is installed again.
+type RollbackPolicy struct {
+	// Window is how long after the upgraded CSV is created a failure to install is rolled back. Defaults to 30m.
+	// +optional
+	Window *metav1.Duration
+}
+
+// DeploymentOverrides customize the pods of each deployment of an operator, e.g. to set proxy settings or to schedule
+// them onto dedicated nodes.
+type DeploymentOverrides struct {
+	// Env is set on every container, replacing variables of the same name.
+	// +optional
+	Env []corev1.EnvVar
+
+	// Resources replace the limits and requests of every container for the resources they list.
+	// +optional
+	Resources *corev1.ResourceRequirements
+
+	// NodeSelector is merged into the node selector of every pod, replacing labels of the same name.
+	// +optional
+	NodeSelector map[string]string
+
+	// Tolerations are added to those of every pod.
+	// +optional
+	Tolerations []corev1.Toleration
 }
 
 // SubscriptionConditionType indicates an explicit state condition about a Subscription in "abnormal-true"
@@ -42,6 +103,13 @@ type SubscriptionConditionType string
 const (
 	// SubscriptionCatalogSourcesUnhealthy indicates that some or all of the CatalogSources to be used in resolution are unhealthy.
 	SubscriptionCatalogSourcesUnhealthy SubscriptionConditionType = "CatalogSourcesUnhealthy"
+
+	// SubscriptionResolutionFailed indicates that the Subscriptions in a namespace couldn't be resolved together.
+	SubscriptionResolutionFailed SubscriptionConditionType = "ResolutionFailed"
+
+	// SubscriptionCatalogNotAllowed indicates that the CatalogSource of a Subscription isn't allowed by the catalog policy
+	// of its namespace's OperatorGroup.
+	SubscriptionCatalogNotAllowed SubscriptionConditionType = "CatalogNotAllowed"
 )
 
 const (
@@ -62,6 +130,18 @@ const (
 
 	// UnhealthyCatalogSourceFound is a reason string for Subscriptions that transitioned because an unhealthy CatalogSource was found.
 	UnhealthyCatalogSourceFound = "UnhealthyCatalogSourceFound"
+
+	// MultipleResolutionProblems is a reason string for Subscriptions whose resolution failed for more than one kind of reason.
+	MultipleResolutionProblems = "MultipleResolutionProblems"
+
+	// ResolutionSucceeded is a reason string for Subscriptions whose namespace resolved after a previous failure.
+	ResolutionSucceeded = "ResolutionSucceeded"
+
+	// CatalogDeniedByPolicy is a reason string for Subscriptions whose CatalogSource is denied by a catalog policy.
+	CatalogDeniedByPolicy = "CatalogDeniedByPolicy"
+
+	// CatalogAllowedByPolicy is a reason string for Subscriptions whose CatalogSource is allowed after being denied.
+	CatalogAllowedByPolicy = "CatalogAllowedByPolicy"
 )
 
 type SubscriptionCondition struct {
@@ -125,6 +205,11 @@ type SubscriptionStatus struct {
 	// +optional
 	CatalogHealth []SubscriptionCatalogHealth
 
+	// CatalogQueryTimeouts lists the CatalogSources that didn't answer a query within the catalog operator's deadline
+	// during the latest resolution of the Subscription's namespace.
+	// +optional
+	CatalogQueryTimeouts []SubscriptionCatalogQueryTimeout
+
 	// Conditions is a list of the latest available observations about a Subscription's current state.
 	// +optional
 	Conditions []SubscriptionCondition
@@ -185,6 +270,15 @@ func (s SubscriptionCatalogHealth) Equals(health SubscriptionCatalogHealth) bool
 	return s.Healthy == health.Healthy && s.CatalogSourceRef.UID == health.CatalogSourceRef.UID
 }
 
+// SubscriptionCatalogQueryTimeout records a CatalogSource that timed out while resolving the Subscription's namespace.
+type SubscriptionCatalogQueryTimeout struct {
+	// CatalogSourceRef is a reference to a CatalogSource.
+	CatalogSourceRef *corev1.ObjectReference
+
+	// Since is the time of the first resolution in which the CatalogSource timed out.
+	Since *metav1.Time
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +genclient
 
@@ -215,6 +309,19 @@ func (s *Subscription) GetInstallPlanApproval() Approval {
 	return ApprovalAutomatic
 }
 
+// GetCatalogSourceNamespace gets the namespace of the subscription's catalog source, or its own namespace by default
+func (s *Subscription) GetCatalogSourceNamespace() string {
+	if s.Spec.CatalogSourceNamespace == "" {
+		return s.GetNamespace()
+	}
+	return s.Spec.CatalogSourceNamespace
+}
+
+// IsDryRun returns true if the Subscription has been marked as a dry-run and false otherwise.
+func (s *Subscription) IsDryRun() bool {
+	return s.GetAnnotations()[SubscriptionDryRunAnnotationKey] == "true"
+}
+
 // NewInstallPlanReference returns an InstallPlanReference for the given ObjectReference.
 func NewInstallPlanReference(ref *corev1.ObjectReference) *InstallPlanReference {
 	return &InstallPlanReference{
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// CatalogPolicyKind is the PascalCase name of a CatalogPolicy's kind.
+const CatalogPolicyKind = "CatalogPolicy"
+
+// CatalogPolicySpec is the spec for a CatalogPolicy resource. A CatalogSource that is denied is never allowed, and
+// if any CatalogSources are allowed, all others are denied.
+type CatalogPolicySpec struct {
+	// Namespaces is the set of namespaces whose Subscriptions the policy applies to.
+	// The policy applies to every namespace if it is empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Allowed is a list of CatalogSources that are allowed.
+	// +optional
+	Allowed []CatalogSourceReference `json:"allowed,omitempty"`
+
+	// AllowedSelector selects the CatalogSources that are allowed by their labels.
+	// +optional
+	AllowedSelector *metav1.LabelSelector `json:"allowedSelector,omitempty"`
+
+	// Denied is a list of CatalogSources that are denied.
+	// +optional
+	Denied []CatalogSourceReference `json:"denied,omitempty"`
+
+	// DeniedSelector selects the CatalogSources that are denied by their labels.
+	// +optional
+	DeniedSelector *metav1.LabelSelector `json:"deniedSelector,omitempty"`
+}
+
+// CatalogSourceReference identifies a CatalogSource.
+type CatalogSourceReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +genclient:nonNamespaced
+
+// CatalogPolicy restricts the CatalogSources that Subscriptions may resolve against.
+// It's cluster-scoped, so that only cluster administrators can set it.
+type CatalogPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec CatalogPolicySpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CatalogPolicyList is a list of CatalogPolicy resources.
+type CatalogPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CatalogPolicy `json:"items"`
+}
+
+// AppliesTo returns true if the policy applies to Subscriptions in the given namespace.
+func (p *CatalogPolicy) AppliesTo(namespace string) bool {
+	if len(p.Spec.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range p.Spec.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if the policy allows the given CatalogSource with the given labels.
+func (p *CatalogPolicy) Allows(catalog CatalogSourceReference, catalogLabels map[string]string) (bool, error) {
+	denied, err := catalogMatches(p.Spec.Denied, p.Spec.DeniedSelector, catalog, catalogLabels)
+	if err != nil || denied {
+		return false, err
+	}
+
+	if len(p.Spec.Allowed) == 0 && p.Spec.AllowedSelector == nil {
+		return true, nil
+	}
+	return catalogMatches(p.Spec.Allowed, p.Spec.AllowedSelector, catalog, catalogLabels)
+}
+
+func catalogMatches(refs []CatalogSourceReference, selector *metav1.LabelSelector, ref CatalogSourceReference, catalogLabels map[string]string) (bool, error) {
+	for _, r := range refs {
+		if r == ref {
+			return true, nil
+		}
+	}
+	if selector == nil {
+		return false, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(catalogLabels)), nil
+}
+
+// CatalogDeniedBy returns the first of the given CatalogPolicies that applies to the given namespace and doesn't allow
+// the given CatalogSource with the given labels, or nil if they all allow it.
+func CatalogDeniedBy(policies []*CatalogPolicy, namespace string, catalog CatalogSourceReference, catalogLabels map[string]string) (*CatalogPolicy, error) {
+	for _, policy := range policies {
+		if !policy.AppliesTo(namespace) {
+			continue
+		}
+		allowed, err := policy.Allows(catalog, catalogLabels)
+		if err != nil {
+			return nil, fmt.Errorf("invalid catalogpolicy %s: %s", policy.GetName(), err)
+		}
+		if !allowed {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCatalogPolicyAllows(t *testing.T) {
+	curated := CatalogSourceReference{Name: "curated", Namespace: "olm"}
+	community := CatalogSourceReference{Name: "community", Namespace: "olm"}
+	curatedLabels := map[string]string{"catalog": "curated"}
+
+	tests := []struct {
+		name          string
+		policy        CatalogPolicySpec
+		catalog       CatalogSourceReference
+		catalogLabels map[string]string
+		allowed       bool
+		err           string
+	}{
+		{
+			name:    "EmptyPolicy",
+			policy:  CatalogPolicySpec{},
+			catalog: community,
+			allowed: true,
+		},
+		{
+			name:    "Allowed/Listed",
+			policy:  CatalogPolicySpec{Allowed: []CatalogSourceReference{curated}},
+			catalog: curated,
+			allowed: true,
+		},
+		{
+			name:    "Allowed/NotListed",
+			policy:  CatalogPolicySpec{Allowed: []CatalogSourceReference{curated}},
+			catalog: community,
+			allowed: false,
+		},
+		{
+			name:    "Allowed/OtherNamespace",
+			policy:  CatalogPolicySpec{Allowed: []CatalogSourceReference{curated}},
+			catalog: CatalogSourceReference{Name: "curated", Namespace: "ns"},
+			allowed: false,
+		},
+		{
+			name:          "AllowedSelector/Matches",
+			policy:        CatalogPolicySpec{AllowedSelector: &metav1.LabelSelector{MatchLabels: curatedLabels}},
+			catalog:       community,
+			catalogLabels: curatedLabels,
+			allowed:       true,
+		},
+		{
+			name:    "AllowedSelector/NoMatch",
+			policy:  CatalogPolicySpec{AllowedSelector: &metav1.LabelSelector{MatchLabels: curatedLabels}},
+			catalog: community,
+			allowed: false,
+		},
+		{
+			name:    "Denied/Listed",
+			policy:  CatalogPolicySpec{Denied: []CatalogSourceReference{community}},
+			catalog: community,
+			allowed: false,
+		},
+		{
+			name:    "Denied/NotListed",
+			policy:  CatalogPolicySpec{Denied: []CatalogSourceReference{community}},
+			catalog: curated,
+			allowed: true,
+		},
+		{
+			name:          "DeniedSelector/Matches",
+			policy:        CatalogPolicySpec{DeniedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"catalog": "community"}}},
+			catalog:       community,
+			catalogLabels: map[string]string{"catalog": "community"},
+			allowed:       false,
+		},
+		{
+			name: "DeniedOverridesAllowed",
+			policy: CatalogPolicySpec{
+				AllowedSelector: &metav1.LabelSelector{MatchLabels: curatedLabels},
+				Denied:          []CatalogSourceReference{curated},
+			},
+			catalog:       curated,
+			catalogLabels: curatedLabels,
+			allowed:       false,
+		},
+		{
+			name: "InvalidSelector",
+			policy: CatalogPolicySpec{DeniedSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "catalog", Operator: "Bad"},
+			}}},
+			catalog: curated,
+			err:     `"Bad" is not a valid pod selector operator`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &CatalogPolicy{Spec: tt.policy}
+			allowed, err := policy.Allows(tt.catalog, tt.catalogLabels)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.allowed, allowed)
+		})
+	}
+}
+
+func TestCatalogDeniedBy(t *testing.T) {
+	policy := func(name string, spec CatalogPolicySpec) *CatalogPolicy {
+		return &CatalogPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       spec,
+		}
+	}
+	curated := CatalogSourceReference{Name: "curated", Namespace: "olm"}
+	community := CatalogSourceReference{Name: "community", Namespace: "olm"}
+	open := policy("open", CatalogPolicySpec{})
+	curatedOnly := policy("curated-only", CatalogPolicySpec{Allowed: []CatalogSourceReference{curated}})
+	otherNamespace := policy("other-namespace", CatalogPolicySpec{Namespaces: []string{"other"}, Denied: []CatalogSourceReference{curated}})
+
+	deniedBy, err := CatalogDeniedBy(nil, "ns", community, nil)
+	require.NoError(t, err)
+	require.Nil(t, deniedBy)
+
+	deniedBy, err = CatalogDeniedBy([]*CatalogPolicy{open, curatedOnly, otherNamespace}, "ns", curated, nil)
+	require.NoError(t, err)
+	require.Nil(t, deniedBy)
+
+	// A catalog must be allowed by every policy that applies to the namespace
+	deniedBy, err = CatalogDeniedBy([]*CatalogPolicy{open, curatedOnly, otherNamespace}, "ns", community, nil)
+	require.NoError(t, err)
+	require.Equal(t, curatedOnly, deniedBy)
+
+	deniedBy, err = CatalogDeniedBy([]*CatalogPolicy{open, otherNamespace}, "other", curated, nil)
+	require.NoError(t, err)
+	require.Equal(t, otherNamespace, deniedBy)
+
+	invalid := policy("invalid", CatalogPolicySpec{AllowedSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "catalog", Operator: "Bad"},
+	}}})
+	_, err = CatalogDeniedBy([]*CatalogPolicy{invalid}, "ns", community, nil)
+	require.EqualError(t, err, `invalid catalogpolicy invalid: "Bad" is not a valid pod selector operator`)
+}
@@ -36,6 +36,46 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*CatalogPolicy)(nil), (*operators.CatalogPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CatalogPolicy_To_operators_CatalogPolicy(a.(*CatalogPolicy), b.(*operators.CatalogPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.CatalogPolicy)(nil), (*CatalogPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_CatalogPolicy_To_v1_CatalogPolicy(a.(*operators.CatalogPolicy), b.(*CatalogPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CatalogPolicyList)(nil), (*operators.CatalogPolicyList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CatalogPolicyList_To_operators_CatalogPolicyList(a.(*CatalogPolicyList), b.(*operators.CatalogPolicyList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.CatalogPolicyList)(nil), (*CatalogPolicyList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_CatalogPolicyList_To_v1_CatalogPolicyList(a.(*operators.CatalogPolicyList), b.(*CatalogPolicyList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CatalogPolicySpec)(nil), (*operators.CatalogPolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CatalogPolicySpec_To_operators_CatalogPolicySpec(a.(*CatalogPolicySpec), b.(*operators.CatalogPolicySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.CatalogPolicySpec)(nil), (*CatalogPolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_CatalogPolicySpec_To_v1_CatalogPolicySpec(a.(*operators.CatalogPolicySpec), b.(*CatalogPolicySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CatalogSourceReference)(nil), (*operators.CatalogSourceReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CatalogSourceReference_To_operators_CatalogSourceReference(a.(*CatalogSourceReference), b.(*operators.CatalogSourceReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.CatalogSourceReference)(nil), (*CatalogSourceReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_CatalogSourceReference_To_v1_CatalogSourceReference(a.(*operators.CatalogSourceReference), b.(*CatalogSourceReference), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OperatorGroup)(nil), (*operators.OperatorGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_OperatorGroup_To_operators_OperatorGroup(a.(*OperatorGroup), b.(*operators.OperatorGroup), scope)
 	}); err != nil {
@@ -79,6 +119,104 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1_CatalogPolicy_To_operators_CatalogPolicy(in *CatalogPolicy, out *operators.CatalogPolicy, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_CatalogPolicySpec_To_operators_CatalogPolicySpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_CatalogPolicy_To_operators_CatalogPolicy is an autogenerated conversion function.
+func Convert_v1_CatalogPolicy_To_operators_CatalogPolicy(in *CatalogPolicy, out *operators.CatalogPolicy, s conversion.Scope) error {
+	return autoConvert_v1_CatalogPolicy_To_operators_CatalogPolicy(in, out, s)
+}
+
+func autoConvert_operators_CatalogPolicy_To_v1_CatalogPolicy(in *operators.CatalogPolicy, out *CatalogPolicy, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_operators_CatalogPolicySpec_To_v1_CatalogPolicySpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_operators_CatalogPolicy_To_v1_CatalogPolicy is an autogenerated conversion function.
+func Convert_operators_CatalogPolicy_To_v1_CatalogPolicy(in *operators.CatalogPolicy, out *CatalogPolicy, s conversion.Scope) error {
+	return autoConvert_operators_CatalogPolicy_To_v1_CatalogPolicy(in, out, s)
+}
+
+func autoConvert_v1_CatalogPolicyList_To_operators_CatalogPolicyList(in *CatalogPolicyList, out *operators.CatalogPolicyList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]operators.CatalogPolicy)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1_CatalogPolicyList_To_operators_CatalogPolicyList is an autogenerated conversion function.
+func Convert_v1_CatalogPolicyList_To_operators_CatalogPolicyList(in *CatalogPolicyList, out *operators.CatalogPolicyList, s conversion.Scope) error {
+	return autoConvert_v1_CatalogPolicyList_To_operators_CatalogPolicyList(in, out, s)
+}
+
+func autoConvert_operators_CatalogPolicyList_To_v1_CatalogPolicyList(in *operators.CatalogPolicyList, out *CatalogPolicyList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]CatalogPolicy)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_operators_CatalogPolicyList_To_v1_CatalogPolicyList is an autogenerated conversion function.
+func Convert_operators_CatalogPolicyList_To_v1_CatalogPolicyList(in *operators.CatalogPolicyList, out *CatalogPolicyList, s conversion.Scope) error {
+	return autoConvert_operators_CatalogPolicyList_To_v1_CatalogPolicyList(in, out, s)
+}
+
+func autoConvert_v1_CatalogPolicySpec_To_operators_CatalogPolicySpec(in *CatalogPolicySpec, out *operators.CatalogPolicySpec, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.Allowed = *(*[]operators.CatalogSourceReference)(unsafe.Pointer(&in.Allowed))
+	out.AllowedSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.AllowedSelector))
+	out.Denied = *(*[]operators.CatalogSourceReference)(unsafe.Pointer(&in.Denied))
+	out.DeniedSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.DeniedSelector))
+	return nil
+}
+
+// Convert_v1_CatalogPolicySpec_To_operators_CatalogPolicySpec is an autogenerated conversion function.
+func Convert_v1_CatalogPolicySpec_To_operators_CatalogPolicySpec(in *CatalogPolicySpec, out *operators.CatalogPolicySpec, s conversion.Scope) error {
+	return autoConvert_v1_CatalogPolicySpec_To_operators_CatalogPolicySpec(in, out, s)
+}
+
+func autoConvert_operators_CatalogPolicySpec_To_v1_CatalogPolicySpec(in *operators.CatalogPolicySpec, out *CatalogPolicySpec, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.Allowed = *(*[]CatalogSourceReference)(unsafe.Pointer(&in.Allowed))
+	out.AllowedSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.AllowedSelector))
+	out.Denied = *(*[]CatalogSourceReference)(unsafe.Pointer(&in.Denied))
+	out.DeniedSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.DeniedSelector))
+	return nil
+}
+
+// Convert_operators_CatalogPolicySpec_To_v1_CatalogPolicySpec is an autogenerated conversion function.
+func Convert_operators_CatalogPolicySpec_To_v1_CatalogPolicySpec(in *operators.CatalogPolicySpec, out *CatalogPolicySpec, s conversion.Scope) error {
+	return autoConvert_operators_CatalogPolicySpec_To_v1_CatalogPolicySpec(in, out, s)
+}
+
+func autoConvert_v1_CatalogSourceReference_To_operators_CatalogSourceReference(in *CatalogSourceReference, out *operators.CatalogSourceReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_v1_CatalogSourceReference_To_operators_CatalogSourceReference is an autogenerated conversion function.
+func Convert_v1_CatalogSourceReference_To_operators_CatalogSourceReference(in *CatalogSourceReference, out *operators.CatalogSourceReference, s conversion.Scope) error {
+	return autoConvert_v1_CatalogSourceReference_To_operators_CatalogSourceReference(in, out, s)
+}
+
+func autoConvert_operators_CatalogSourceReference_To_v1_CatalogSourceReference(in *operators.CatalogSourceReference, out *CatalogSourceReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_operators_CatalogSourceReference_To_v1_CatalogSourceReference is an autogenerated conversion function.
+func Convert_operators_CatalogSourceReference_To_v1_CatalogSourceReference(in *operators.CatalogSourceReference, out *CatalogSourceReference, s conversion.Scope) error {
+	return autoConvert_operators_CatalogSourceReference_To_v1_CatalogSourceReference(in, out, s)
+}
+
 func autoConvert_v1_OperatorGroup_To_operators_OperatorGroup(in *OperatorGroup, out *operators.OperatorGroup, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1_OperatorGroupSpec_To_operators_OperatorGroupSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,6 +16,17 @@ const (
 	// given ClusterServiceVersion object. The oldest condition(s) are removed
 	// from the list as it grows over time to keep it at limit.
 	ConditionsLengthLimit = 20
+
+	// DefaultInstallTimeout is how long an install attempt waits for its components when the install policy of a
+	// ClusterServiceVersion doesn't say.
+	DefaultInstallTimeout = 5 * time.Minute
+
+	// DefaultInstallBackoff is how long the first install retry waits when the install policy of a
+	// ClusterServiceVersion doesn't say.
+	DefaultInstallBackoff = 10 * time.Second
+
+	// maxInstallBackoff caps how long any install retry waits.
+	maxInstallBackoff = time.Hour
 )
 
 // obsoleteReasons are the set of reasons that mean a CSV should no longer be processed as active
@@ -107,15 +119,25 @@ func (c *ClusterServiceVersion) SetRequirementStatus(statuses []RequirementStatu
 	c.Status.RequirementStatus = statuses
 }
 
-// IsObsolete returns if this CSV is being replaced or is marked for deletion
+// IsObsolete returns if this CSV is being replaced or is marked for deletion. A CSV whose upgrade was rolled back is
+// only obsolete if it's being replaced again.
 func (c *ClusterServiceVersion) IsObsolete() bool {
+	obsolete := false
 	for _, condition := range c.Status.Conditions {
-		_, ok := obsoleteReasons[condition.Reason]
-		if ok {
-			return true
+		if condition.Reason == CSVReasonUpgradeRolledBack {
+			obsolete = false
+			continue
+		}
+		if _, ok := obsoleteReasons[condition.Reason]; ok {
+			obsolete = true
 		}
 	}
-	return false
+	return obsolete
+}
+
+// IsRolledBack returns true if the CSV failed to install and was rolled back to the CSV it replaces.
+func (c *ClusterServiceVersion) IsRolledBack() bool {
+	return c.Status.Phase == CSVPhaseFailed && c.Status.Reason == CSVReasonRolledBack
 }
 
 // IsCopied returns true if the CSV has been copied and false otherwise.
@@ -206,3 +228,40 @@ func (c *ClusterServiceVersion) TrimConditionsIfLimitExceeded() {
 	firstIndex := len(c.Status.Conditions) - ConditionsLengthLimit
 	c.Status.Conditions = c.Status.Conditions[firstIndex:len(c.Status.Conditions)]
 }
+
+// GetTimeout returns how long an install attempt waits for the components of the install strategy to become ready.
+func (p *InstallPolicy) GetTimeout() time.Duration {
+	if !p.HasTimeout() {
+		return DefaultInstallTimeout
+	}
+	return p.Timeout.Duration
+}
+
+// HasTimeout returns true if the policy sets its own install timeout. Timeouts that aren't positive aren't used.
+func (p *InstallPolicy) HasTimeout() bool {
+	return p != nil && p.Timeout != nil && p.Timeout.Duration > 0
+}
+
+// GetRetries returns how many times the install is retried after an attempt fails.
+func (p *InstallPolicy) GetRetries() int32 {
+	if p == nil || p.Retries < 0 {
+		return 0
+	}
+	return p.Retries
+}
+
+// RetryBackoff returns how long to wait before the given retry, counting from 1. The backoff doubles with each retry,
+// up to an hour.
+func (p *InstallPolicy) RetryBackoff(retry int32) time.Duration {
+	backoff := DefaultInstallBackoff
+	if p != nil && p.Backoff != nil && p.Backoff.Duration > 0 {
+		backoff = p.Backoff.Duration
+	}
+	for i := int32(1); i < retry && backoff < maxInstallBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxInstallBackoff {
+		return maxInstallBackoff
+	}
+	return backoff
+}
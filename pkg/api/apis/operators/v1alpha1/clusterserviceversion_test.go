@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -93,6 +94,24 @@ func TestIsObsolete(t *testing.T) {
 			out:               true,
 			description:       "CSVPhaseDeleting",
 		},
+		{
+			currentPhase: CSVPhaseInstallReady,
+			currentConditions: []ClusterServiceVersionCondition{
+				{Phase: CSVPhaseReplacing, Reason: CSVReasonBeingReplaced},
+				{Phase: CSVPhaseInstallReady, Reason: CSVReasonUpgradeRolledBack},
+			},
+			out:         false,
+			description: "UpgradeRolledBack",
+		},
+		{
+			currentPhase: CSVPhaseReplacing,
+			currentConditions: []ClusterServiceVersionCondition{
+				{Phase: CSVPhaseInstallReady, Reason: CSVReasonUpgradeRolledBack},
+				{Phase: CSVPhaseReplacing, Reason: CSVReasonBeingReplaced},
+			},
+			out:         true,
+			description: "UpgradeRolledBack/Replacing",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -374,3 +393,37 @@ func helperNewConditions(count int) []ClusterServiceVersionCondition {
 
 	return conditions
 }
+
+func TestInstallPolicy(t *testing.T) {
+	var unset *InstallPolicy
+	require.Equal(t, DefaultInstallTimeout, unset.GetTimeout())
+	require.False(t, unset.HasTimeout())
+	require.Equal(t, int32(0), unset.GetRetries())
+	require.Equal(t, DefaultInstallBackoff, unset.RetryBackoff(1))
+	require.Equal(t, 4*DefaultInstallBackoff, unset.RetryBackoff(3))
+
+	policy := &InstallPolicy{
+		Timeout: &metav1.Duration{Duration: 20 * time.Minute},
+		Retries: 3,
+		Backoff: &metav1.Duration{Duration: time.Minute},
+	}
+	require.Equal(t, 20*time.Minute, policy.GetTimeout())
+	require.True(t, policy.HasTimeout())
+	require.Equal(t, int32(3), policy.GetRetries())
+	require.Equal(t, time.Minute, policy.RetryBackoff(1))
+	require.Equal(t, 2*time.Minute, policy.RetryBackoff(2))
+	require.Equal(t, 4*time.Minute, policy.RetryBackoff(3))
+	require.Equal(t, time.Hour, policy.RetryBackoff(20))
+
+	require.Equal(t, int32(0), (&InstallPolicy{Retries: -1}).GetRetries())
+
+	// Durations that aren't positive use the defaults
+	invalid := &InstallPolicy{
+		Timeout: &metav1.Duration{},
+		Backoff: &metav1.Duration{Duration: -time.Second},
+	}
+	require.Equal(t, DefaultInstallTimeout, invalid.GetTimeout())
+	require.False(t, invalid.HasTimeout())
+	require.Equal(t, DefaultInstallBackoff, invalid.RetryBackoff(1))
+	require.Equal(t, 2*DefaultInstallBackoff, invalid.RetryBackoff(2))
+}
@@ -14,6 +14,9 @@ const (
 	ClusterServiceVersionAPIVersion     = GroupName + "/" + GroupVersion
 	ClusterServiceVersionKind           = "ClusterServiceVersion"
 	OperatorGroupNamespaceAnnotationKey = "olm.operatorNamespace"
+
+	// PackageAnnotationKey is the annotation a CSV installed through resolution records the package it came from in.
+	PackageAnnotationKey = "olm.package"
 )
 
 // InstallModeType is a supported type of install mode for CSV installation
@@ -165,6 +168,21 @@ type ClusterServiceVersionSpec struct {
 	// +optional
 	Replaces string `json:"replaces,omitempty"`
 
+	// RequiredPackages are the operator packages this one depends on, regardless of the apis they provide.
+	// A matching operator is resolved and installed alongside this one for each of them.
+	// +optional
+	RequiredPackages []PackageDependency `json:"requiredPackages,omitempty"`
+
+	// InstallPolicy bounds how long the install strategy is given to become ready, and how many times its install
+	// is retried, before the ClusterServiceVersion fails.
+	// +optional
+	InstallPolicy *InstallPolicy `json:"installPolicy,omitempty"`
+
+	// Overrides are carried over from the Subscription that installed the ClusterServiceVersion, and are merged into
+	// each of the deployments of its install strategy. They aren't meant to be set in bundles.
+	// +optional
+	Overrides *DeploymentOverrides `json:"overrides,omitempty"`
+
 	// Map of string keys and values that can be used to organize and categorize
 	// (scope and select) objects.
 	// +optional
@@ -180,6 +198,34 @@ type ClusterServiceVersionSpec struct {
 	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,2,opt,name=selector"`
 }
 
+// PackageDependency declares a dependency on another operator package.
+type PackageDependency struct {
+	// The name of the required package.
+	PackageName string `json:"packageName"`
+
+	// A semver range the version of the required operator must satisfy. Empty means any version.
+	// +optional
+	VersionRange string `json:"versionRange,omitempty"`
+}
+
+// InstallPolicy bounds the attempts made to install the install strategy of a ClusterServiceVersion.
+type InstallPolicy struct {
+	// How long an install attempt waits for the components of the install strategy to become ready. Components
+	// that time out on their own, like deployments past their progress deadline, only fail an attempt early when
+	// no timeout is set. Defaults to 5m, which a timeout that isn't positive also uses.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// How many times the install is retried after an attempt fails. Defaults to 0.
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+
+	// How long to wait before the first retry. The wait doubles with each retry after it. Defaults to 10s, which a
+	// backoff that isn't positive also uses.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
 type Maintainer struct {
 	Name  string `json:"name,omitempty"`
 	Email string `json:"email,omitempty"`
@@ -251,6 +297,12 @@ const (
 	CSVReasonTooManyOperatorGroups                       ConditionReason = "TooManyOperatorGroups"
 	CSVReasonInterOperatorGroupOwnerConflict             ConditionReason = "InterOperatorGroupOwnerConflict"
 	CSVReasonCannotModifyStaticOperatorGroupProvidedAPIs ConditionReason = "CannotModifyStaticOperatorGroupProvidedAPIs"
+	CSVReasonInstallAttemptFailed                        ConditionReason = "InstallAttemptFailed"
+	CSVReasonInstallRetrying                             ConditionReason = "InstallRetrying"
+	CSVReasonRolledBack                                  ConditionReason = "RolledBack"
+	CSVReasonUpgradeRolledBack                           ConditionReason = "UpgradeRolledBack"
+	CSVReasonComponentDrifted                            ConditionReason = "ComponentDrifted"
+	CSVReasonOverridesChanged                            ConditionReason = "OverridesChanged"
 )
 
 // Conditions appear in the status as a record of state transitions on the ClusterServiceVersion
@@ -357,6 +409,36 @@ type ClusterServiceVersionStatus struct {
 	// Time the owned APIService certs will rotate next
 	// +optional
 	CertsRotateAt metav1.Time `json:"certsRotateAt,omitempty"`
+	// The attempts made to install the install strategy
+	// +optional
+	Install *InstallAttemptStatus `json:"install,omitempty"`
+	// The spec hashes of the resources installed for the ClusterServiceVersion, recorded once it has succeeded
+	// +optional
+	Components []ComponentHash `json:"components,omitempty"`
+}
+
+// ComponentHash records the spec hash of a resource installed for a ClusterServiceVersion.
+type ComponentHash struct {
+	// The kind of the resource
+	Kind string `json:"kind"`
+	// The name of the resource
+	Name string `json:"name"`
+	// The hash of the resource's spec when it was recorded
+	Hash string `json:"hash"`
+}
+
+// InstallAttemptStatus tracks the attempts made to install the install strategy of a ClusterServiceVersion.
+type InstallAttemptStatus struct {
+	// The number of the current install attempt, starting from 1
+	Attempt int32 `json:"attempt"`
+	// Time the current install attempt started
+	StartTime metav1.Time `json:"startTime"`
+	// Time the install will be retried, once the current attempt has failed
+	// +optional
+	RetryTime *metav1.Time `json:"retryTime,omitempty"`
+	// Why the last failed attempt failed
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
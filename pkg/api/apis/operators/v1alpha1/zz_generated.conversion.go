@@ -99,6 +99,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CatalogContentValidation)(nil), (*operators.CatalogContentValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CatalogContentValidation_To_operators_CatalogContentValidation(a.(*CatalogContentValidation), b.(*operators.CatalogContentValidation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.CatalogContentValidation)(nil), (*CatalogContentValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_CatalogContentValidation_To_v1alpha1_CatalogContentValidation(a.(*operators.CatalogContentValidation), b.(*CatalogContentValidation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CatalogSource)(nil), (*operators.CatalogSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_CatalogSource_To_operators_CatalogSource(a.(*CatalogSource), b.(*operators.CatalogSource), scope)
 	}); err != nil {
@@ -189,6 +199,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ComponentHash)(nil), (*operators.ComponentHash)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ComponentHash_To_operators_ComponentHash(a.(*ComponentHash), b.(*operators.ComponentHash), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.ComponentHash)(nil), (*ComponentHash)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_ComponentHash_To_v1alpha1_ComponentHash(a.(*operators.ComponentHash), b.(*ComponentHash), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ConfigMapResourceReference)(nil), (*operators.ConfigMapResourceReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ConfigMapResourceReference_To_operators_ConfigMapResourceReference(a.(*ConfigMapResourceReference), b.(*operators.ConfigMapResourceReference), scope)
 	}); err != nil {
@@ -219,6 +239,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DeploymentOverrides)(nil), (*operators.DeploymentOverrides)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DeploymentOverrides_To_operators_DeploymentOverrides(a.(*DeploymentOverrides), b.(*operators.DeploymentOverrides), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.DeploymentOverrides)(nil), (*DeploymentOverrides)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_DeploymentOverrides_To_v1alpha1_DeploymentOverrides(a.(*operators.DeploymentOverrides), b.(*DeploymentOverrides), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GRPCConnectionState)(nil), (*operators.GRPCConnectionState)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GRPCConnectionState_To_operators_GRPCConnectionState(a.(*GRPCConnectionState), b.(*operators.GRPCConnectionState), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.GRPCConnectionState)(nil), (*GRPCConnectionState)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_GRPCConnectionState_To_v1alpha1_GRPCConnectionState(a.(*operators.GRPCConnectionState), b.(*GRPCConnectionState), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Icon)(nil), (*operators.Icon)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Icon_To_operators_Icon(a.(*Icon), b.(*operators.Icon), scope)
 	}); err != nil {
@@ -229,6 +269,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstallAttemptStatus)(nil), (*operators.InstallAttemptStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_InstallAttemptStatus_To_operators_InstallAttemptStatus(a.(*InstallAttemptStatus), b.(*operators.InstallAttemptStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.InstallAttemptStatus)(nil), (*InstallAttemptStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_InstallAttemptStatus_To_v1alpha1_InstallAttemptStatus(a.(*operators.InstallAttemptStatus), b.(*InstallAttemptStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstallMode)(nil), (*operators.InstallMode)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_InstallMode_To_operators_InstallMode(a.(*InstallMode), b.(*operators.InstallMode), scope)
 	}); err != nil {
@@ -299,6 +349,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstallPolicy)(nil), (*operators.InstallPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_InstallPolicy_To_operators_InstallPolicy(a.(*InstallPolicy), b.(*operators.InstallPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.InstallPolicy)(nil), (*InstallPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_InstallPolicy_To_v1alpha1_InstallPolicy(a.(*operators.InstallPolicy), b.(*InstallPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Maintainer)(nil), (*operators.Maintainer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Maintainer_To_operators_Maintainer(a.(*Maintainer), b.(*operators.Maintainer), scope)
 	}); err != nil {
@@ -319,6 +379,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageDependency)(nil), (*operators.PackageDependency)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageDependency_To_operators_PackageDependency(a.(*PackageDependency), b.(*operators.PackageDependency), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.PackageDependency)(nil), (*PackageDependency)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_PackageDependency_To_v1alpha1_PackageDependency(a.(*operators.PackageDependency), b.(*PackageDependency), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RegistryPodConfig)(nil), (*operators.RegistryPodConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegistryPodConfig_To_operators_RegistryPodConfig(a.(*RegistryPodConfig), b.(*operators.RegistryPodConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.RegistryPodConfig)(nil), (*RegistryPodConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_RegistryPodConfig_To_v1alpha1_RegistryPodConfig(a.(*operators.RegistryPodConfig), b.(*RegistryPodConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RegistryPoll)(nil), (*operators.RegistryPoll)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegistryPoll_To_operators_RegistryPoll(a.(*RegistryPoll), b.(*operators.RegistryPoll), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.RegistryPoll)(nil), (*RegistryPoll)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_RegistryPoll_To_v1alpha1_RegistryPoll(a.(*operators.RegistryPoll), b.(*RegistryPoll), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RegistryServiceStatus)(nil), (*operators.RegistryServiceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_RegistryServiceStatus_To_operators_RegistryServiceStatus(a.(*RegistryServiceStatus), b.(*operators.RegistryServiceStatus), scope)
 	}); err != nil {
@@ -339,6 +429,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RollbackPolicy)(nil), (*operators.RollbackPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RollbackPolicy_To_operators_RollbackPolicy(a.(*RollbackPolicy), b.(*operators.RollbackPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.RollbackPolicy)(nil), (*RollbackPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_RollbackPolicy_To_v1alpha1_RollbackPolicy(a.(*operators.RollbackPolicy), b.(*RollbackPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SpecDescriptor)(nil), (*operators.SpecDescriptor)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SpecDescriptor_To_operators_SpecDescriptor(a.(*SpecDescriptor), b.(*operators.SpecDescriptor), scope)
 	}); err != nil {
@@ -399,6 +499,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SubscriptionCatalogQueryTimeout)(nil), (*operators.SubscriptionCatalogQueryTimeout)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SubscriptionCatalogQueryTimeout_To_operators_SubscriptionCatalogQueryTimeout(a.(*SubscriptionCatalogQueryTimeout), b.(*operators.SubscriptionCatalogQueryTimeout), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.SubscriptionCatalogQueryTimeout)(nil), (*SubscriptionCatalogQueryTimeout)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_SubscriptionCatalogQueryTimeout_To_v1alpha1_SubscriptionCatalogQueryTimeout(a.(*operators.SubscriptionCatalogQueryTimeout), b.(*SubscriptionCatalogQueryTimeout), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SubscriptionCondition)(nil), (*operators.SubscriptionCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SubscriptionCondition_To_operators_SubscriptionCondition(a.(*SubscriptionCondition), b.(*operators.SubscriptionCondition), scope)
 	}); err != nil {
@@ -439,6 +549,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*UpdateStrategy)(nil), (*operators.UpdateStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_UpdateStrategy_To_operators_UpdateStrategy(a.(*UpdateStrategy), b.(*operators.UpdateStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*operators.UpdateStrategy)(nil), (*UpdateStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_operators_UpdateStrategy_To_v1alpha1_UpdateStrategy(a.(*operators.UpdateStrategy), b.(*UpdateStrategy), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -616,6 +736,36 @@ func Convert_operators_CRDDescription_To_v1alpha1_CRDDescription(in *operators.C
 	return autoConvert_operators_CRDDescription_To_v1alpha1_CRDDescription(in, out, s)
 }
 
+func autoConvert_v1alpha1_CatalogContentValidation_To_operators_CatalogContentValidation(in *CatalogContentValidation, out *operators.CatalogContentValidation, s conversion.Scope) error {
+	out.LastValidated = in.LastValidated
+	out.Packages = in.Packages
+	out.Channels = in.Channels
+	out.Bundles = in.Bundles
+	out.ProblemCount = in.ProblemCount
+	out.Problems = *(*[]string)(unsafe.Pointer(&in.Problems))
+	return nil
+}
+
+// Convert_v1alpha1_CatalogContentValidation_To_operators_CatalogContentValidation is an autogenerated conversion function.
+func Convert_v1alpha1_CatalogContentValidation_To_operators_CatalogContentValidation(in *CatalogContentValidation, out *operators.CatalogContentValidation, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CatalogContentValidation_To_operators_CatalogContentValidation(in, out, s)
+}
+
+func autoConvert_operators_CatalogContentValidation_To_v1alpha1_CatalogContentValidation(in *operators.CatalogContentValidation, out *CatalogContentValidation, s conversion.Scope) error {
+	out.LastValidated = in.LastValidated
+	out.Packages = in.Packages
+	out.Channels = in.Channels
+	out.Bundles = in.Bundles
+	out.ProblemCount = in.ProblemCount
+	out.Problems = *(*[]string)(unsafe.Pointer(&in.Problems))
+	return nil
+}
+
+// Convert_operators_CatalogContentValidation_To_v1alpha1_CatalogContentValidation is an autogenerated conversion function.
+func Convert_operators_CatalogContentValidation_To_v1alpha1_CatalogContentValidation(in *operators.CatalogContentValidation, out *CatalogContentValidation, s conversion.Scope) error {
+	return autoConvert_operators_CatalogContentValidation_To_v1alpha1_CatalogContentValidation(in, out, s)
+}
+
 func autoConvert_v1alpha1_CatalogSource_To_operators_CatalogSource(in *CatalogSource, out *operators.CatalogSource, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_CatalogSourceSpec_To_operators_CatalogSourceSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -673,9 +823,15 @@ func Convert_operators_CatalogSourceList_To_v1alpha1_CatalogSourceList(in *opera
 func autoConvert_v1alpha1_CatalogSourceSpec_To_operators_CatalogSourceSpec(in *CatalogSourceSpec, out *operators.CatalogSourceSpec, s conversion.Scope) error {
 	out.SourceType = operators.SourceType(in.SourceType)
 	out.ConfigMap = in.ConfigMap
+	out.ConfigMaps = *(*[]string)(unsafe.Pointer(&in.ConfigMaps))
+	out.ConfigMapSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ConfigMapSelector))
 	out.Address = in.Address
 	out.Image = in.Image
+	out.URL = in.URL
 	out.Secrets = *(*[]string)(unsafe.Pointer(&in.Secrets))
+	out.RegistryPodConfig = (*operators.RegistryPodConfig)(unsafe.Pointer(in.RegistryPodConfig))
+	out.Priority = in.Priority
+	out.UpdateStrategy = (*operators.UpdateStrategy)(unsafe.Pointer(in.UpdateStrategy))
 	out.DisplayName = in.DisplayName
 	out.Description = in.Description
 	out.Publisher = in.Publisher
@@ -693,9 +849,15 @@ func Convert_v1alpha1_CatalogSourceSpec_To_operators_CatalogSourceSpec(in *Catal
 func autoConvert_operators_CatalogSourceSpec_To_v1alpha1_CatalogSourceSpec(in *operators.CatalogSourceSpec, out *CatalogSourceSpec, s conversion.Scope) error {
 	out.SourceType = SourceType(in.SourceType)
 	out.ConfigMap = in.ConfigMap
+	out.ConfigMaps = *(*[]string)(unsafe.Pointer(&in.ConfigMaps))
+	out.ConfigMapSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ConfigMapSelector))
 	out.Address = in.Address
 	out.Image = in.Image
+	out.URL = in.URL
 	out.Secrets = *(*[]string)(unsafe.Pointer(&in.Secrets))
+	out.RegistryPodConfig = (*RegistryPodConfig)(unsafe.Pointer(in.RegistryPodConfig))
+	out.Priority = in.Priority
+	out.UpdateStrategy = (*UpdateStrategy)(unsafe.Pointer(in.UpdateStrategy))
 	out.DisplayName = in.DisplayName
 	out.Description = in.Description
 	out.Publisher = in.Publisher
@@ -713,7 +875,11 @@ func Convert_operators_CatalogSourceSpec_To_v1alpha1_CatalogSourceSpec(in *opera
 func autoConvert_v1alpha1_CatalogSourceStatus_To_operators_CatalogSourceStatus(in *CatalogSourceStatus, out *operators.CatalogSourceStatus, s conversion.Scope) error {
 	out.ConfigMapResource = (*operators.ConfigMapResourceReference)(unsafe.Pointer(in.ConfigMapResource))
 	out.RegistryServiceStatus = (*operators.RegistryServiceStatus)(unsafe.Pointer(in.RegistryServiceStatus))
+	out.GRPCConnectionState = (*operators.GRPCConnectionState)(unsafe.Pointer(in.GRPCConnectionState))
 	out.LastSync = in.LastSync
+	out.ConfigMapShards = *(*[]operators.ConfigMapResourceReference)(unsafe.Pointer(&in.ConfigMapShards))
+	out.LatestImageRegistryPoll = (*v1.Time)(unsafe.Pointer(in.LatestImageRegistryPoll))
+	out.ContentValidation = (*operators.CatalogContentValidation)(unsafe.Pointer(in.ContentValidation))
 	return nil
 }
 
@@ -725,7 +891,11 @@ func Convert_v1alpha1_CatalogSourceStatus_To_operators_CatalogSourceStatus(in *C
 func autoConvert_operators_CatalogSourceStatus_To_v1alpha1_CatalogSourceStatus(in *operators.CatalogSourceStatus, out *CatalogSourceStatus, s conversion.Scope) error {
 	out.ConfigMapResource = (*ConfigMapResourceReference)(unsafe.Pointer(in.ConfigMapResource))
 	out.RegistryServiceStatus = (*RegistryServiceStatus)(unsafe.Pointer(in.RegistryServiceStatus))
+	out.GRPCConnectionState = (*GRPCConnectionState)(unsafe.Pointer(in.GRPCConnectionState))
 	out.LastSync = in.LastSync
+	out.ConfigMapShards = *(*[]ConfigMapResourceReference)(unsafe.Pointer(&in.ConfigMapShards))
+	out.LatestImageRegistryPoll = (*v1.Time)(unsafe.Pointer(in.LatestImageRegistryPoll))
+	out.ContentValidation = (*CatalogContentValidation)(unsafe.Pointer(in.ContentValidation))
 	return nil
 }
 
@@ -841,6 +1011,9 @@ func autoConvert_v1alpha1_ClusterServiceVersionSpec_To_operators_ClusterServiceV
 	out.Icon = *(*[]operators.Icon)(unsafe.Pointer(&in.Icon))
 	out.InstallModes = *(*[]operators.InstallMode)(unsafe.Pointer(&in.InstallModes))
 	out.Replaces = in.Replaces
+	out.RequiredPackages = *(*[]operators.PackageDependency)(unsafe.Pointer(&in.RequiredPackages))
+	out.InstallPolicy = (*operators.InstallPolicy)(unsafe.Pointer(in.InstallPolicy))
+	out.Overrides = (*operators.DeploymentOverrides)(unsafe.Pointer(in.Overrides))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
@@ -877,6 +1050,9 @@ func autoConvert_operators_ClusterServiceVersionSpec_To_v1alpha1_ClusterServiceV
 	out.Icon = *(*[]Icon)(unsafe.Pointer(&in.Icon))
 	out.InstallModes = *(*[]InstallMode)(unsafe.Pointer(&in.InstallModes))
 	out.Replaces = in.Replaces
+	out.RequiredPackages = *(*[]PackageDependency)(unsafe.Pointer(&in.RequiredPackages))
+	out.InstallPolicy = (*InstallPolicy)(unsafe.Pointer(in.InstallPolicy))
+	out.Overrides = (*DeploymentOverrides)(unsafe.Pointer(in.Overrides))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
@@ -898,6 +1074,8 @@ func autoConvert_v1alpha1_ClusterServiceVersionStatus_To_operators_ClusterServic
 	out.RequirementStatus = *(*[]operators.RequirementStatus)(unsafe.Pointer(&in.RequirementStatus))
 	out.CertsLastUpdated = in.CertsLastUpdated
 	out.CertsRotateAt = in.CertsRotateAt
+	out.Install = (*operators.InstallAttemptStatus)(unsafe.Pointer(in.Install))
+	out.Components = *(*[]operators.ComponentHash)(unsafe.Pointer(&in.Components))
 	return nil
 }
 
@@ -916,6 +1094,8 @@ func autoConvert_operators_ClusterServiceVersionStatus_To_v1alpha1_ClusterServic
 	out.RequirementStatus = *(*[]RequirementStatus)(unsafe.Pointer(&in.RequirementStatus))
 	out.CertsLastUpdated = in.CertsLastUpdated
 	out.CertsRotateAt = in.CertsRotateAt
+	out.Install = (*InstallAttemptStatus)(unsafe.Pointer(in.Install))
+	out.Components = *(*[]ComponentHash)(unsafe.Pointer(&in.Components))
 	return nil
 }
 
@@ -924,6 +1104,30 @@ func Convert_operators_ClusterServiceVersionStatus_To_v1alpha1_ClusterServiceVer
 	return autoConvert_operators_ClusterServiceVersionStatus_To_v1alpha1_ClusterServiceVersionStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_ComponentHash_To_operators_ComponentHash(in *ComponentHash, out *operators.ComponentHash, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	out.Hash = in.Hash
+	return nil
+}
+
+// Convert_v1alpha1_ComponentHash_To_operators_ComponentHash is an autogenerated conversion function.
+func Convert_v1alpha1_ComponentHash_To_operators_ComponentHash(in *ComponentHash, out *operators.ComponentHash, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ComponentHash_To_operators_ComponentHash(in, out, s)
+}
+
+func autoConvert_operators_ComponentHash_To_v1alpha1_ComponentHash(in *operators.ComponentHash, out *ComponentHash, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	out.Hash = in.Hash
+	return nil
+}
+
+// Convert_operators_ComponentHash_To_v1alpha1_ComponentHash is an autogenerated conversion function.
+func Convert_operators_ComponentHash_To_v1alpha1_ComponentHash(in *operators.ComponentHash, out *ComponentHash, s conversion.Scope) error {
+	return autoConvert_operators_ComponentHash_To_v1alpha1_ComponentHash(in, out, s)
+}
+
 func autoConvert_v1alpha1_ConfigMapResourceReference_To_operators_ConfigMapResourceReference(in *ConfigMapResourceReference, out *operators.ConfigMapResourceReference, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Namespace = in.Namespace
@@ -1002,6 +1206,58 @@ func Convert_operators_DependentStatus_To_v1alpha1_DependentStatus(in *operators
 	return autoConvert_operators_DependentStatus_To_v1alpha1_DependentStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_DeploymentOverrides_To_operators_DeploymentOverrides(in *DeploymentOverrides, out *operators.DeploymentOverrides, s conversion.Scope) error {
+	out.Env = *(*[]corev1.EnvVar)(unsafe.Pointer(&in.Env))
+	out.Resources = (*corev1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	return nil
+}
+
+// Convert_v1alpha1_DeploymentOverrides_To_operators_DeploymentOverrides is an autogenerated conversion function.
+func Convert_v1alpha1_DeploymentOverrides_To_operators_DeploymentOverrides(in *DeploymentOverrides, out *operators.DeploymentOverrides, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DeploymentOverrides_To_operators_DeploymentOverrides(in, out, s)
+}
+
+func autoConvert_operators_DeploymentOverrides_To_v1alpha1_DeploymentOverrides(in *operators.DeploymentOverrides, out *DeploymentOverrides, s conversion.Scope) error {
+	out.Env = *(*[]corev1.EnvVar)(unsafe.Pointer(&in.Env))
+	out.Resources = (*corev1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	return nil
+}
+
+// Convert_operators_DeploymentOverrides_To_v1alpha1_DeploymentOverrides is an autogenerated conversion function.
+func Convert_operators_DeploymentOverrides_To_v1alpha1_DeploymentOverrides(in *operators.DeploymentOverrides, out *DeploymentOverrides, s conversion.Scope) error {
+	return autoConvert_operators_DeploymentOverrides_To_v1alpha1_DeploymentOverrides(in, out, s)
+}
+
+func autoConvert_v1alpha1_GRPCConnectionState_To_operators_GRPCConnectionState(in *GRPCConnectionState, out *operators.GRPCConnectionState, s conversion.Scope) error {
+	out.Address = in.Address
+	out.LastObservedState = in.LastObservedState
+	out.LastError = in.LastError
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1alpha1_GRPCConnectionState_To_operators_GRPCConnectionState is an autogenerated conversion function.
+func Convert_v1alpha1_GRPCConnectionState_To_operators_GRPCConnectionState(in *GRPCConnectionState, out *operators.GRPCConnectionState, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GRPCConnectionState_To_operators_GRPCConnectionState(in, out, s)
+}
+
+func autoConvert_operators_GRPCConnectionState_To_v1alpha1_GRPCConnectionState(in *operators.GRPCConnectionState, out *GRPCConnectionState, s conversion.Scope) error {
+	out.Address = in.Address
+	out.LastObservedState = in.LastObservedState
+	out.LastError = in.LastError
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_operators_GRPCConnectionState_To_v1alpha1_GRPCConnectionState is an autogenerated conversion function.
+func Convert_operators_GRPCConnectionState_To_v1alpha1_GRPCConnectionState(in *operators.GRPCConnectionState, out *GRPCConnectionState, s conversion.Scope) error {
+	return autoConvert_operators_GRPCConnectionState_To_v1alpha1_GRPCConnectionState(in, out, s)
+}
+
 func autoConvert_v1alpha1_Icon_To_operators_Icon(in *Icon, out *operators.Icon, s conversion.Scope) error {
 	out.Data = in.Data
 	out.MediaType = in.MediaType
@@ -1024,6 +1280,32 @@ func Convert_operators_Icon_To_v1alpha1_Icon(in *operators.Icon, out *Icon, s co
 	return autoConvert_operators_Icon_To_v1alpha1_Icon(in, out, s)
 }
 
+func autoConvert_v1alpha1_InstallAttemptStatus_To_operators_InstallAttemptStatus(in *InstallAttemptStatus, out *operators.InstallAttemptStatus, s conversion.Scope) error {
+	out.Attempt = in.Attempt
+	out.StartTime = in.StartTime
+	out.RetryTime = (*v1.Time)(unsafe.Pointer(in.RetryTime))
+	out.LastFailure = in.LastFailure
+	return nil
+}
+
+// Convert_v1alpha1_InstallAttemptStatus_To_operators_InstallAttemptStatus is an autogenerated conversion function.
+func Convert_v1alpha1_InstallAttemptStatus_To_operators_InstallAttemptStatus(in *InstallAttemptStatus, out *operators.InstallAttemptStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_InstallAttemptStatus_To_operators_InstallAttemptStatus(in, out, s)
+}
+
+func autoConvert_operators_InstallAttemptStatus_To_v1alpha1_InstallAttemptStatus(in *operators.InstallAttemptStatus, out *InstallAttemptStatus, s conversion.Scope) error {
+	out.Attempt = in.Attempt
+	out.StartTime = in.StartTime
+	out.RetryTime = (*v1.Time)(unsafe.Pointer(in.RetryTime))
+	out.LastFailure = in.LastFailure
+	return nil
+}
+
+// Convert_operators_InstallAttemptStatus_To_v1alpha1_InstallAttemptStatus is an autogenerated conversion function.
+func Convert_operators_InstallAttemptStatus_To_v1alpha1_InstallAttemptStatus(in *operators.InstallAttemptStatus, out *InstallAttemptStatus, s conversion.Scope) error {
+	return autoConvert_operators_InstallAttemptStatus_To_v1alpha1_InstallAttemptStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_InstallMode_To_operators_InstallMode(in *InstallMode, out *operators.InstallMode, s conversion.Scope) error {
 	out.Type = operators.InstallModeType(in.Type)
 	out.Supported = in.Supported
@@ -1210,6 +1492,30 @@ func Convert_operators_InstallPlanStatus_To_v1alpha1_InstallPlanStatus(in *opera
 	return autoConvert_operators_InstallPlanStatus_To_v1alpha1_InstallPlanStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_InstallPolicy_To_operators_InstallPolicy(in *InstallPolicy, out *operators.InstallPolicy, s conversion.Scope) error {
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	out.Retries = in.Retries
+	out.Backoff = (*v1.Duration)(unsafe.Pointer(in.Backoff))
+	return nil
+}
+
+// Convert_v1alpha1_InstallPolicy_To_operators_InstallPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_InstallPolicy_To_operators_InstallPolicy(in *InstallPolicy, out *operators.InstallPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_InstallPolicy_To_operators_InstallPolicy(in, out, s)
+}
+
+func autoConvert_operators_InstallPolicy_To_v1alpha1_InstallPolicy(in *operators.InstallPolicy, out *InstallPolicy, s conversion.Scope) error {
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	out.Retries = in.Retries
+	out.Backoff = (*v1.Duration)(unsafe.Pointer(in.Backoff))
+	return nil
+}
+
+// Convert_operators_InstallPolicy_To_v1alpha1_InstallPolicy is an autogenerated conversion function.
+func Convert_operators_InstallPolicy_To_v1alpha1_InstallPolicy(in *operators.InstallPolicy, out *InstallPolicy, s conversion.Scope) error {
+	return autoConvert_operators_InstallPolicy_To_v1alpha1_InstallPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha1_Maintainer_To_operators_Maintainer(in *Maintainer, out *operators.Maintainer, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Email = in.Email
@@ -1254,6 +1560,80 @@ func Convert_operators_NamedInstallStrategy_To_v1alpha1_NamedInstallStrategy(in
 	return autoConvert_operators_NamedInstallStrategy_To_v1alpha1_NamedInstallStrategy(in, out, s)
 }
 
+func autoConvert_v1alpha1_PackageDependency_To_operators_PackageDependency(in *PackageDependency, out *operators.PackageDependency, s conversion.Scope) error {
+	out.PackageName = in.PackageName
+	out.VersionRange = in.VersionRange
+	return nil
+}
+
+// Convert_v1alpha1_PackageDependency_To_operators_PackageDependency is an autogenerated conversion function.
+func Convert_v1alpha1_PackageDependency_To_operators_PackageDependency(in *PackageDependency, out *operators.PackageDependency, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageDependency_To_operators_PackageDependency(in, out, s)
+}
+
+func autoConvert_operators_PackageDependency_To_v1alpha1_PackageDependency(in *operators.PackageDependency, out *PackageDependency, s conversion.Scope) error {
+	out.PackageName = in.PackageName
+	out.VersionRange = in.VersionRange
+	return nil
+}
+
+// Convert_operators_PackageDependency_To_v1alpha1_PackageDependency is an autogenerated conversion function.
+func Convert_operators_PackageDependency_To_v1alpha1_PackageDependency(in *operators.PackageDependency, out *PackageDependency, s conversion.Scope) error {
+	return autoConvert_operators_PackageDependency_To_v1alpha1_PackageDependency(in, out, s)
+}
+
+func autoConvert_v1alpha1_RegistryPodConfig_To_operators_RegistryPodConfig(in *RegistryPodConfig, out *operators.RegistryPodConfig, s conversion.Scope) error {
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Resources = in.Resources
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.PriorityClassName = in.PriorityClassName
+	out.SecurityContext = (*corev1.PodSecurityContext)(unsafe.Pointer(in.SecurityContext))
+	return nil
+}
+
+// Convert_v1alpha1_RegistryPodConfig_To_operators_RegistryPodConfig is an autogenerated conversion function.
+func Convert_v1alpha1_RegistryPodConfig_To_operators_RegistryPodConfig(in *RegistryPodConfig, out *operators.RegistryPodConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegistryPodConfig_To_operators_RegistryPodConfig(in, out, s)
+}
+
+func autoConvert_operators_RegistryPodConfig_To_v1alpha1_RegistryPodConfig(in *operators.RegistryPodConfig, out *RegistryPodConfig, s conversion.Scope) error {
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Resources = in.Resources
+	out.NodeSelector = *(*map[string]string)(unsafe.Pointer(&in.NodeSelector))
+	out.Tolerations = *(*[]corev1.Toleration)(unsafe.Pointer(&in.Tolerations))
+	out.Affinity = (*corev1.Affinity)(unsafe.Pointer(in.Affinity))
+	out.PriorityClassName = in.PriorityClassName
+	out.SecurityContext = (*corev1.PodSecurityContext)(unsafe.Pointer(in.SecurityContext))
+	return nil
+}
+
+// Convert_operators_RegistryPodConfig_To_v1alpha1_RegistryPodConfig is an autogenerated conversion function.
+func Convert_operators_RegistryPodConfig_To_v1alpha1_RegistryPodConfig(in *operators.RegistryPodConfig, out *RegistryPodConfig, s conversion.Scope) error {
+	return autoConvert_operators_RegistryPodConfig_To_v1alpha1_RegistryPodConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_RegistryPoll_To_operators_RegistryPoll(in *RegistryPoll, out *operators.RegistryPoll, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha1_RegistryPoll_To_operators_RegistryPoll is an autogenerated conversion function.
+func Convert_v1alpha1_RegistryPoll_To_operators_RegistryPoll(in *RegistryPoll, out *operators.RegistryPoll, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegistryPoll_To_operators_RegistryPoll(in, out, s)
+}
+
+func autoConvert_operators_RegistryPoll_To_v1alpha1_RegistryPoll(in *operators.RegistryPoll, out *RegistryPoll, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_operators_RegistryPoll_To_v1alpha1_RegistryPoll is an autogenerated conversion function.
+func Convert_operators_RegistryPoll_To_v1alpha1_RegistryPoll(in *operators.RegistryPoll, out *RegistryPoll, s conversion.Scope) error {
+	return autoConvert_operators_RegistryPoll_To_v1alpha1_RegistryPoll(in, out, s)
+}
+
 func autoConvert_v1alpha1_RegistryServiceStatus_To_operators_RegistryServiceStatus(in *RegistryServiceStatus, out *operators.RegistryServiceStatus, s conversion.Scope) error {
 	out.Protocol = in.Protocol
 	out.ServiceName = in.ServiceName
@@ -1316,6 +1696,26 @@ func Convert_operators_RequirementStatus_To_v1alpha1_RequirementStatus(in *opera
 	return autoConvert_operators_RequirementStatus_To_v1alpha1_RequirementStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_RollbackPolicy_To_operators_RollbackPolicy(in *RollbackPolicy, out *operators.RollbackPolicy, s conversion.Scope) error {
+	out.Window = (*v1.Duration)(unsafe.Pointer(in.Window))
+	return nil
+}
+
+// Convert_v1alpha1_RollbackPolicy_To_operators_RollbackPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_RollbackPolicy_To_operators_RollbackPolicy(in *RollbackPolicy, out *operators.RollbackPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RollbackPolicy_To_operators_RollbackPolicy(in, out, s)
+}
+
+func autoConvert_operators_RollbackPolicy_To_v1alpha1_RollbackPolicy(in *operators.RollbackPolicy, out *RollbackPolicy, s conversion.Scope) error {
+	out.Window = (*v1.Duration)(unsafe.Pointer(in.Window))
+	return nil
+}
+
+// Convert_operators_RollbackPolicy_To_v1alpha1_RollbackPolicy is an autogenerated conversion function.
+func Convert_operators_RollbackPolicy_To_v1alpha1_RollbackPolicy(in *operators.RollbackPolicy, out *RollbackPolicy, s conversion.Scope) error {
+	return autoConvert_operators_RollbackPolicy_To_v1alpha1_RollbackPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha1_SpecDescriptor_To_operators_SpecDescriptor(in *SpecDescriptor, out *operators.SpecDescriptor, s conversion.Scope) error {
 	out.Path = in.Path
 	out.DisplayName = in.DisplayName
@@ -1378,6 +1778,7 @@ func autoConvert_v1alpha1_Step_To_operators_Step(in *Step, out *operators.Step,
 		return err
 	}
 	out.Status = operators.StepStatus(in.Status)
+	out.PreviousSnapshot = in.PreviousSnapshot
 	return nil
 }
 
@@ -1392,6 +1793,7 @@ func autoConvert_operators_Step_To_v1alpha1_Step(in *operators.Step, out *Step,
 		return err
 	}
 	out.Status = StepStatus(in.Status)
+	out.PreviousSnapshot = in.PreviousSnapshot
 	return nil
 }
 
@@ -1484,6 +1886,28 @@ func Convert_operators_SubscriptionCatalogHealth_To_v1alpha1_SubscriptionCatalog
 	return autoConvert_operators_SubscriptionCatalogHealth_To_v1alpha1_SubscriptionCatalogHealth(in, out, s)
 }
 
+func autoConvert_v1alpha1_SubscriptionCatalogQueryTimeout_To_operators_SubscriptionCatalogQueryTimeout(in *SubscriptionCatalogQueryTimeout, out *operators.SubscriptionCatalogQueryTimeout, s conversion.Scope) error {
+	out.CatalogSourceRef = (*corev1.ObjectReference)(unsafe.Pointer(in.CatalogSourceRef))
+	out.Since = (*v1.Time)(unsafe.Pointer(in.Since))
+	return nil
+}
+
+// Convert_v1alpha1_SubscriptionCatalogQueryTimeout_To_operators_SubscriptionCatalogQueryTimeout is an autogenerated conversion function.
+func Convert_v1alpha1_SubscriptionCatalogQueryTimeout_To_operators_SubscriptionCatalogQueryTimeout(in *SubscriptionCatalogQueryTimeout, out *operators.SubscriptionCatalogQueryTimeout, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SubscriptionCatalogQueryTimeout_To_operators_SubscriptionCatalogQueryTimeout(in, out, s)
+}
+
+func autoConvert_operators_SubscriptionCatalogQueryTimeout_To_v1alpha1_SubscriptionCatalogQueryTimeout(in *operators.SubscriptionCatalogQueryTimeout, out *SubscriptionCatalogQueryTimeout, s conversion.Scope) error {
+	out.CatalogSourceRef = (*corev1.ObjectReference)(unsafe.Pointer(in.CatalogSourceRef))
+	out.Since = (*v1.Time)(unsafe.Pointer(in.Since))
+	return nil
+}
+
+// Convert_operators_SubscriptionCatalogQueryTimeout_To_v1alpha1_SubscriptionCatalogQueryTimeout is an autogenerated conversion function.
+func Convert_operators_SubscriptionCatalogQueryTimeout_To_v1alpha1_SubscriptionCatalogQueryTimeout(in *operators.SubscriptionCatalogQueryTimeout, out *SubscriptionCatalogQueryTimeout, s conversion.Scope) error {
+	return autoConvert_operators_SubscriptionCatalogQueryTimeout_To_v1alpha1_SubscriptionCatalogQueryTimeout(in, out, s)
+}
+
 func autoConvert_v1alpha1_SubscriptionCondition_To_operators_SubscriptionCondition(in *SubscriptionCondition, out *operators.SubscriptionCondition, s conversion.Scope) error {
 	out.Type = operators.SubscriptionConditionType(in.Type)
 	out.Status = corev1.ConditionStatus(in.Status)
@@ -1543,6 +1967,9 @@ func autoConvert_v1alpha1_SubscriptionSpec_To_operators_SubscriptionSpec(in *Sub
 	out.Channel = in.Channel
 	out.StartingCSV = in.StartingCSV
 	out.InstallPlanApproval = operators.Approval(in.InstallPlanApproval)
+	out.VersionRange = in.VersionRange
+	out.RollbackPolicy = (*operators.RollbackPolicy)(unsafe.Pointer(in.RollbackPolicy))
+	out.Overrides = (*operators.DeploymentOverrides)(unsafe.Pointer(in.Overrides))
 	return nil
 }
 
@@ -1558,6 +1985,9 @@ func autoConvert_operators_SubscriptionSpec_To_v1alpha1_SubscriptionSpec(in *ope
 	out.Channel = in.Channel
 	out.StartingCSV = in.StartingCSV
 	out.InstallPlanApproval = Approval(in.InstallPlanApproval)
+	out.VersionRange = in.VersionRange
+	out.RollbackPolicy = (*RollbackPolicy)(unsafe.Pointer(in.RollbackPolicy))
+	out.Overrides = (*DeploymentOverrides)(unsafe.Pointer(in.Overrides))
 	return nil
 }
 
@@ -1574,6 +2004,7 @@ func autoConvert_v1alpha1_SubscriptionStatus_To_operators_SubscriptionStatus(in
 	out.Reason = operators.ConditionReason(in.Reason)
 	out.InstallPlanRef = (*corev1.ObjectReference)(unsafe.Pointer(in.InstallPlanRef))
 	out.CatalogHealth = *(*[]operators.SubscriptionCatalogHealth)(unsafe.Pointer(&in.CatalogHealth))
+	out.CatalogQueryTimeouts = *(*[]operators.SubscriptionCatalogQueryTimeout)(unsafe.Pointer(&in.CatalogQueryTimeouts))
 	out.Conditions = *(*[]operators.SubscriptionCondition)(unsafe.Pointer(&in.Conditions))
 	out.LastUpdated = in.LastUpdated
 	return nil
@@ -1592,6 +2023,7 @@ func autoConvert_operators_SubscriptionStatus_To_v1alpha1_SubscriptionStatus(in
 	out.Reason = ConditionReason(in.Reason)
 	out.InstallPlanRef = (*corev1.ObjectReference)(unsafe.Pointer(in.InstallPlanRef))
 	out.CatalogHealth = *(*[]SubscriptionCatalogHealth)(unsafe.Pointer(&in.CatalogHealth))
+	out.CatalogQueryTimeouts = *(*[]SubscriptionCatalogQueryTimeout)(unsafe.Pointer(&in.CatalogQueryTimeouts))
 	out.Conditions = *(*[]SubscriptionCondition)(unsafe.Pointer(&in.Conditions))
 	out.LastUpdated = in.LastUpdated
 	return nil
@@ -1601,3 +2033,23 @@ func autoConvert_operators_SubscriptionStatus_To_v1alpha1_SubscriptionStatus(in
 func Convert_operators_SubscriptionStatus_To_v1alpha1_SubscriptionStatus(in *operators.SubscriptionStatus, out *SubscriptionStatus, s conversion.Scope) error {
 	return autoConvert_operators_SubscriptionStatus_To_v1alpha1_SubscriptionStatus(in, out, s)
 }
+
+func autoConvert_v1alpha1_UpdateStrategy_To_operators_UpdateStrategy(in *UpdateStrategy, out *operators.UpdateStrategy, s conversion.Scope) error {
+	out.RegistryPoll = (*operators.RegistryPoll)(unsafe.Pointer(in.RegistryPoll))
+	return nil
+}
+
+// Convert_v1alpha1_UpdateStrategy_To_operators_UpdateStrategy is an autogenerated conversion function.
+func Convert_v1alpha1_UpdateStrategy_To_operators_UpdateStrategy(in *UpdateStrategy, out *operators.UpdateStrategy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_UpdateStrategy_To_operators_UpdateStrategy(in, out, s)
+}
+
+func autoConvert_operators_UpdateStrategy_To_v1alpha1_UpdateStrategy(in *operators.UpdateStrategy, out *UpdateStrategy, s conversion.Scope) error {
+	out.RegistryPoll = (*RegistryPoll)(unsafe.Pointer(in.RegistryPoll))
+	return nil
+}
+
+// Convert_operators_UpdateStrategy_To_v1alpha1_UpdateStrategy is an autogenerated conversion function.
+func Convert_operators_UpdateStrategy_To_v1alpha1_UpdateStrategy(in *operators.UpdateStrategy, out *UpdateStrategy, s conversion.Scope) error {
+	return autoConvert_operators_UpdateStrategy_To_v1alpha1_UpdateStrategy(in, out, s)
+}
@@ -204,6 +204,28 @@ func (in *CRDDescription) DeepCopy() *CRDDescription {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogContentValidation) DeepCopyInto(out *CatalogContentValidation) {
+	*out = *in
+	in.LastValidated.DeepCopyInto(&out.LastValidated)
+	if in.Problems != nil {
+		in, out := &in.Problems, &out.Problems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogContentValidation.
+func (in *CatalogContentValidation) DeepCopy() *CatalogContentValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogContentValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogSource) DeepCopyInto(out *CatalogSource) {
 	*out = *in
@@ -268,11 +290,31 @@ func (in *CatalogSourceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogSourceSpec) DeepCopyInto(out *CatalogSourceSpec) {
 	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapSelector != nil {
+		in, out := &in.ConfigMapSelector, &out.ConfigMapSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Secrets != nil {
 		in, out := &in.Secrets, &out.Secrets
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RegistryPodConfig != nil {
+		in, out := &in.RegistryPodConfig, &out.RegistryPodConfig
+		*out = new(RegistryPodConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Icon = in.Icon
 	return
 }
@@ -300,7 +342,26 @@ func (in *CatalogSourceStatus) DeepCopyInto(out *CatalogSourceStatus) {
 		*out = new(RegistryServiceStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GRPCConnectionState != nil {
+		in, out := &in.GRPCConnectionState, &out.GRPCConnectionState
+		*out = new(GRPCConnectionState)
+		(*in).DeepCopyInto(*out)
+	}
 	in.LastSync.DeepCopyInto(&out.LastSync)
+	if in.ConfigMapShards != nil {
+		in, out := &in.ConfigMapShards, &out.ConfigMapShards
+		*out = make([]ConfigMapResourceReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LatestImageRegistryPoll != nil {
+		in, out := &in.LatestImageRegistryPoll, &out.LatestImageRegistryPoll
+		*out = (*in).DeepCopy()
+	}
+	if in.ContentValidation != nil {
+		in, out := &in.ContentValidation, &out.ContentValidation
+		*out = new(CatalogContentValidation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -431,6 +492,21 @@ func (in *ClusterServiceVersionSpec) DeepCopyInto(out *ClusterServiceVersionSpec
 		*out = make([]InstallMode, len(*in))
 		copy(*out, *in)
 	}
+	if in.RequiredPackages != nil {
+		in, out := &in.RequiredPackages, &out.RequiredPackages
+		*out = make([]PackageDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallPolicy != nil {
+		in, out := &in.InstallPolicy, &out.InstallPolicy
+		*out = new(InstallPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(DeploymentOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -484,6 +560,16 @@ func (in *ClusterServiceVersionStatus) DeepCopyInto(out *ClusterServiceVersionSt
 	}
 	in.CertsLastUpdated.DeepCopyInto(&out.CertsLastUpdated)
 	in.CertsRotateAt.DeepCopyInto(&out.CertsRotateAt)
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = new(InstallAttemptStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentHash, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -497,6 +583,22 @@ func (in *ClusterServiceVersionStatus) DeepCopy() *ClusterServiceVersionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentHash) DeepCopyInto(out *ComponentHash) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHash.
+func (in *ComponentHash) DeepCopy() *ComponentHash {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentHash)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapResourceReference) DeepCopyInto(out *ConfigMapResourceReference) {
 	*out = *in
@@ -559,6 +661,65 @@ func (in *DependentStatus) DeepCopy() *DependentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentOverrides) DeepCopyInto(out *DeploymentOverrides) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentOverrides.
+func (in *DeploymentOverrides) DeepCopy() *DeploymentOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCConnectionState) DeepCopyInto(out *GRPCConnectionState) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCConnectionState.
+func (in *GRPCConnectionState) DeepCopy() *GRPCConnectionState {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCConnectionState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Icon) DeepCopyInto(out *Icon) {
 	*out = *in
@@ -575,6 +736,27 @@ func (in *Icon) DeepCopy() *Icon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallAttemptStatus) DeepCopyInto(out *InstallAttemptStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.RetryTime != nil {
+		in, out := &in.RetryTime, &out.RetryTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallAttemptStatus.
+func (in *InstallAttemptStatus) DeepCopy() *InstallAttemptStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallAttemptStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallMode) DeepCopyInto(out *InstallMode) {
 	*out = *in
@@ -768,6 +950,32 @@ func (in *InstallPlanStatus) DeepCopy() *InstallPlanStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallPolicy) DeepCopyInto(out *InstallPolicy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallPolicy.
+func (in *InstallPolicy) DeepCopy() *InstallPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Maintainer) DeepCopyInto(out *Maintainer) {
 	*out = *in
@@ -805,6 +1013,85 @@ func (in *NamedInstallStrategy) DeepCopy() *NamedInstallStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageDependency) DeepCopyInto(out *PackageDependency) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageDependency.
+func (in *PackageDependency) DeepCopy() *PackageDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryPodConfig) DeepCopyInto(out *RegistryPodConfig) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryPodConfig.
+func (in *RegistryPodConfig) DeepCopy() *RegistryPodConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryPodConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryPoll) DeepCopyInto(out *RegistryPoll) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryPoll.
+func (in *RegistryPoll) DeepCopy() *RegistryPoll {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryPoll)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryServiceStatus) DeepCopyInto(out *RegistryServiceStatus) {
 	*out = *in
@@ -843,6 +1130,27 @@ func (in *RequirementStatus) DeepCopy() *RequirementStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackPolicy) DeepCopyInto(out *RollbackPolicy) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackPolicy.
+func (in *RollbackPolicy) DeepCopy() *RollbackPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpecDescriptor) DeepCopyInto(out *SpecDescriptor) {
 	*out = *in
@@ -944,7 +1252,7 @@ func (in *Subscription) DeepCopyInto(out *Subscription) {
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
 		*out = new(SubscriptionSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.Status.DeepCopyInto(&out.Status)
 	return
@@ -993,6 +1301,31 @@ func (in *SubscriptionCatalogHealth) DeepCopy() *SubscriptionCatalogHealth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionCatalogQueryTimeout) DeepCopyInto(out *SubscriptionCatalogQueryTimeout) {
+	*out = *in
+	if in.CatalogSourceRef != nil {
+		in, out := &in.CatalogSourceRef, &out.CatalogSourceRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionCatalogQueryTimeout.
+func (in *SubscriptionCatalogQueryTimeout) DeepCopy() *SubscriptionCatalogQueryTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionCatalogQueryTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionCondition) DeepCopyInto(out *SubscriptionCondition) {
 	*out = *in
@@ -1053,6 +1386,16 @@ func (in *SubscriptionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 	*out = *in
+	if in.RollbackPolicy != nil {
+		in, out := &in.RollbackPolicy, &out.RollbackPolicy
+		*out = new(RollbackPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(DeploymentOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1086,6 +1429,13 @@ func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CatalogQueryTimeouts != nil {
+		in, out := &in.CatalogQueryTimeouts, &out.CatalogQueryTimeouts
+		*out = make([]SubscriptionCatalogQueryTimeout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]SubscriptionCondition, len(*in))
@@ -1106,3 +1456,24 @@ func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+	*out = *in
+	if in.RegistryPoll != nil {
+		in, out := &in.RegistryPoll, &out.RegistryPoll
+		*out = new(RegistryPoll)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
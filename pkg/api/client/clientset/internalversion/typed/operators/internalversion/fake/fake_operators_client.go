@@ -28,6 +28,10 @@ type FakeOperators struct {
 	*testing.Fake
 }
 
+func (c *FakeOperators) CatalogPolicies() internalversion.CatalogPolicyInterface {
+	return &FakeCatalogPolicies{c}
+}
+
 func (c *FakeOperators) CatalogSources(namespace string) internalversion.CatalogSourceInterface {
 	return &FakeCatalogSources{c, namespace}
 }
@@ -18,6 +18,8 @@ limitations under the License.
 
 package internalversion
 
+type CatalogPolicyExpansion interface{}
+
 type CatalogSourceExpansion interface{}
 
 type ClusterServiceVersionExpansion interface{}
@@ -25,6 +25,7 @@ import (
 
 type OperatorsInterface interface {
 	RESTClient() rest.Interface
+	CatalogPoliciesGetter
 	CatalogSourcesGetter
 	ClusterServiceVersionsGetter
 	InstallPlansGetter
@@ -37,6 +38,10 @@ type OperatorsClient struct {
 	restClient rest.Interface
 }
 
+func (c *OperatorsClient) CatalogPolicies() CatalogPolicyInterface {
+	return newCatalogPolicies(c)
+}
+
 func (c *OperatorsClient) CatalogSources(namespace string) CatalogSourceInterface {
 	return newCatalogSources(c, namespace)
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
+	scheme "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CatalogPoliciesGetter has a method to return a CatalogPolicyInterface.
+// A group's client should implement this interface.
+type CatalogPoliciesGetter interface {
+	CatalogPolicies() CatalogPolicyInterface
+}
+
+// CatalogPolicyInterface has methods to work with CatalogPolicy resources.
+type CatalogPolicyInterface interface {
+	Create(*v1.CatalogPolicy) (*v1.CatalogPolicy, error)
+	Update(*v1.CatalogPolicy) (*v1.CatalogPolicy, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.CatalogPolicy, error)
+	List(opts metav1.ListOptions) (*v1.CatalogPolicyList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CatalogPolicy, err error)
+	CatalogPolicyExpansion
+}
+
+// catalogPolicies implements CatalogPolicyInterface
+type catalogPolicies struct {
+	client rest.Interface
+}
+
+// newCatalogPolicies returns a CatalogPolicies
+func newCatalogPolicies(c *OperatorsV1Client) *catalogPolicies {
+	return &catalogPolicies{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the catalogPolicy, and returns the corresponding catalogPolicy object, and an error if there is any.
+func (c *catalogPolicies) Get(name string, options metav1.GetOptions) (result *v1.CatalogPolicy, err error) {
+	result = &v1.CatalogPolicy{}
+	err = c.client.Get().
+		Resource("catalogpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CatalogPolicies that match those selectors.
+func (c *catalogPolicies) List(opts metav1.ListOptions) (result *v1.CatalogPolicyList, err error) {
+	result = &v1.CatalogPolicyList{}
+	err = c.client.Get().
+		Resource("catalogpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested catalogPolicies.
+func (c *catalogPolicies) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Resource("catalogpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a catalogPolicy and creates it.  Returns the server's representation of the catalogPolicy, and an error, if there is any.
+func (c *catalogPolicies) Create(catalogPolicy *v1.CatalogPolicy) (result *v1.CatalogPolicy, err error) {
+	result = &v1.CatalogPolicy{}
+	err = c.client.Post().
+		Resource("catalogpolicies").
+		Body(catalogPolicy).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a catalogPolicy and updates it. Returns the server's representation of the catalogPolicy, and an error, if there is any.
+func (c *catalogPolicies) Update(catalogPolicy *v1.CatalogPolicy) (result *v1.CatalogPolicy, err error) {
+	result = &v1.CatalogPolicy{}
+	err = c.client.Put().
+		Resource("catalogpolicies").
+		Name(catalogPolicy.Name).
+		Body(catalogPolicy).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the catalogPolicy and deletes it. Returns an error if one occurs.
+func (c *catalogPolicies) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("catalogpolicies").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *catalogPolicies) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return c.client.Delete().
+		Resource("catalogpolicies").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched catalogPolicy.
+func (c *catalogPolicies) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CatalogPolicy, err error) {
+	result = &v1.CatalogPolicy{}
+	err = c.client.Patch(pt).
+		Resource("catalogpolicies").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
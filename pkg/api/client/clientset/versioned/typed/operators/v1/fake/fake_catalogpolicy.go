@@ -0,0 +1,120 @@
+/*
+Copyright 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCatalogPolicies implements CatalogPolicyInterface
+type FakeCatalogPolicies struct {
+	Fake *FakeOperatorsV1
+}
+
+var catalogpoliciesResource = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1", Resource: "catalogpolicies"}
+
+var catalogpoliciesKind = schema.GroupVersionKind{Group: "operators.coreos.com", Version: "v1", Kind: "CatalogPolicy"}
+
+// Get takes name of the catalogPolicy, and returns the corresponding catalogPolicy object, and an error if there is any.
+func (c *FakeCatalogPolicies) Get(name string, options v1.GetOptions) (result *operatorsv1.CatalogPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(catalogpoliciesResource, name), &operatorsv1.CatalogPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorsv1.CatalogPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of CatalogPolicies that match those selectors.
+func (c *FakeCatalogPolicies) List(opts v1.ListOptions) (result *operatorsv1.CatalogPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(catalogpoliciesResource, catalogpoliciesKind, opts), &operatorsv1.CatalogPolicyList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &operatorsv1.CatalogPolicyList{ListMeta: obj.(*operatorsv1.CatalogPolicyList).ListMeta}
+	for _, item := range obj.(*operatorsv1.CatalogPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested catalogPolicies.
+func (c *FakeCatalogPolicies) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(catalogpoliciesResource, opts))
+}
+
+// Create takes the representation of a catalogPolicy and creates it.  Returns the server's representation of the catalogPolicy, and an error, if there is any.
+func (c *FakeCatalogPolicies) Create(catalogPolicy *operatorsv1.CatalogPolicy) (result *operatorsv1.CatalogPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(catalogpoliciesResource, catalogPolicy), &operatorsv1.CatalogPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorsv1.CatalogPolicy), err
+}
+
+// Update takes the representation of a catalogPolicy and updates it. Returns the server's representation of the catalogPolicy, and an error, if there is any.
+func (c *FakeCatalogPolicies) Update(catalogPolicy *operatorsv1.CatalogPolicy) (result *operatorsv1.CatalogPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(catalogpoliciesResource, catalogPolicy), &operatorsv1.CatalogPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorsv1.CatalogPolicy), err
+}
+
+// Delete takes name of the catalogPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeCatalogPolicies) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(catalogpoliciesResource, name), &operatorsv1.CatalogPolicy{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCatalogPolicies) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(catalogpoliciesResource, listOptions)
+
+	_, err := c.Fake.Invokes(action, &operatorsv1.CatalogPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched catalogPolicy.
+func (c *FakeCatalogPolicies) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *operatorsv1.CatalogPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(catalogpoliciesResource, name, data, subresources...), &operatorsv1.CatalogPolicy{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorsv1.CatalogPolicy), err
+}
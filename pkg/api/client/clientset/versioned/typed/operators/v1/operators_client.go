@@ -27,6 +27,7 @@ import (
 
 type OperatorsV1Interface interface {
 	RESTClient() rest.Interface
+	CatalogPoliciesGetter
 	OperatorGroupsGetter
 }
 
@@ -35,6 +36,10 @@ type OperatorsV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *OperatorsV1Client) CatalogPolicies() CatalogPolicyInterface {
+	return newCatalogPolicies(c)
+}
+
 func (c *OperatorsV1Client) OperatorGroups(namespace string) OperatorGroupInterface {
 	return newOperatorGroups(c, namespace)
 }
@@ -54,6 +54,8 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=operators.coreos.com, Version=v1
+	case v1.SchemeGroupVersion.WithResource("catalogpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operators().V1().CatalogPolicies().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("operatorgroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operators().V1().OperatorGroups().Informer()}, nil
 
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// CatalogPolicies returns a CatalogPolicyInformer.
+	CatalogPolicies() CatalogPolicyInformer
 	// OperatorGroups returns a OperatorGroupInformer.
 	OperatorGroups() OperatorGroupInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// CatalogPolicies returns a CatalogPolicyInformer.
+func (v *version) CatalogPolicies() CatalogPolicyInformer {
+	return &catalogPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // OperatorGroups returns a OperatorGroupInformer.
 func (v *version) OperatorGroups() OperatorGroupInformer {
 	return &operatorGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
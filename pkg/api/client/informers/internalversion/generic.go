@@ -53,6 +53,8 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=operators.coreos.com, Version=internalVersion
+	case operators.SchemeGroupVersion.WithResource("catalogpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operators().InternalVersion().CatalogPolicies().Informer()}, nil
 	case operators.SchemeGroupVersion.WithResource("catalogsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operators().InternalVersion().CatalogSources().Informer()}, nil
 	case operators.SchemeGroupVersion.WithResource("clusterserviceversions"):
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// CatalogPolicies returns a CatalogPolicyInformer.
+	CatalogPolicies() CatalogPolicyInformer
 	// CatalogSources returns a CatalogSourceInformer.
 	CatalogSources() CatalogSourceInformer
 	// ClusterServiceVersions returns a ClusterServiceVersionInformer.
@@ -47,6 +49,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// CatalogPolicies returns a CatalogPolicyInformer.
+func (v *version) CatalogPolicies() CatalogPolicyInformer {
+	return &catalogPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // CatalogSources returns a CatalogSourceInformer.
 func (v *version) CatalogSources() CatalogSourceInformer {
 	return &catalogSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
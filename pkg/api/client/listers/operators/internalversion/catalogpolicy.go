@@ -0,0 +1,65 @@
+/*
+Copyright 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package internalversion
+
+import (
+	operators "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CatalogPolicyLister helps list CatalogPolicies.
+type CatalogPolicyLister interface {
+	// List lists all CatalogPolicies in the indexer.
+	List(selector labels.Selector) (ret []*operators.CatalogPolicy, err error)
+	// Get retrieves the CatalogPolicy from the index for a given name.
+	Get(name string) (*operators.CatalogPolicy, error)
+	CatalogPolicyListerExpansion
+}
+
+// catalogPolicyLister implements the CatalogPolicyLister interface.
+type catalogPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewCatalogPolicyLister returns a new CatalogPolicyLister.
+func NewCatalogPolicyLister(indexer cache.Indexer) CatalogPolicyLister {
+	return &catalogPolicyLister{indexer: indexer}
+}
+
+// List lists all CatalogPolicies in the indexer.
+func (s *catalogPolicyLister) List(selector labels.Selector) (ret []*operators.CatalogPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*operators.CatalogPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the CatalogPolicy from the index for a given name.
+func (s *catalogPolicyLister) Get(name string) (*operators.CatalogPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(operators.Resource("catalogpolicy"), name)
+	}
+	return obj.(*operators.CatalogPolicy), nil
+}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package internalversion
 
+// CatalogPolicyListerExpansion allows custom methods to be added to
+// CatalogPolicyLister.
+type CatalogPolicyListerExpansion interface{}
+
 // CatalogSourceListerExpansion allows custom methods to be added to
 // CatalogSourceLister.
 type CatalogSourceListerExpansion interface{}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1
 
+// CatalogPolicyListerExpansion allows custom methods to be added to
+// CatalogPolicyLister.
+type CatalogPolicyListerExpansion interface{}
+
 // OperatorGroupListerExpansion allows custom methods to be added to
 // OperatorGroupLister.
 type OperatorGroupListerExpansion interface{}
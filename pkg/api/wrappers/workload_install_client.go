@@ -0,0 +1,149 @@
+//go:generate counterfeiter workload_install_client.go InstallStrategyWorkloadInterface
+package wrappers
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
+)
+
+// Workload is an object that runs pods from a template, such as a StatefulSet or a DaemonSet.
+type Workload interface {
+	metav1.Object
+	runtime.Object
+}
+
+// WorkloadKind gets, writes and lists the workloads of one kind.
+type WorkloadKind struct {
+	Get    func(client kubernetes.Interface, namespace, name string) (Workload, error)
+	Create func(client kubernetes.Interface, namespace string, workload Workload) (Workload, error)
+	Update func(client kubernetes.Interface, namespace string, workload Workload) (Workload, error)
+	Delete func(client kubernetes.Interface, namespace, name string, options *metav1.DeleteOptions) error
+	List   func(lister operatorlister.OperatorLister, namespace string, selector labels.Selector) ([]Workload, error)
+}
+
+var (
+	// StatefulSetKind is the WorkloadKind of StatefulSets.
+	StatefulSetKind = WorkloadKind{
+		Get: func(client kubernetes.Interface, namespace, name string) (Workload, error) {
+			return statefulSetWorkload(client.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{}))
+		},
+		Create: func(client kubernetes.Interface, namespace string, workload Workload) (Workload, error) {
+			return statefulSetWorkload(client.AppsV1().StatefulSets(namespace).Create(workload.(*appsv1.StatefulSet)))
+		},
+		Update: func(client kubernetes.Interface, namespace string, workload Workload) (Workload, error) {
+			return statefulSetWorkload(client.AppsV1().StatefulSets(namespace).Update(workload.(*appsv1.StatefulSet)))
+		},
+		Delete: func(client kubernetes.Interface, namespace, name string, options *metav1.DeleteOptions) error {
+			return client.AppsV1().StatefulSets(namespace).Delete(name, options)
+		},
+		List: func(lister operatorlister.OperatorLister, namespace string, selector labels.Selector) ([]Workload, error) {
+			statefulSets, err := lister.AppsV1().StatefulSetLister().StatefulSets(namespace).List(selector)
+			workloads := make([]Workload, 0, len(statefulSets))
+			for _, sts := range statefulSets {
+				workloads = append(workloads, sts)
+			}
+			return workloads, err
+		},
+	}
+
+	// DaemonSetKind is the WorkloadKind of DaemonSets.
+	DaemonSetKind = WorkloadKind{
+		Get: func(client kubernetes.Interface, namespace, name string) (Workload, error) {
+			return daemonSetWorkload(client.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{}))
+		},
+		Create: func(client kubernetes.Interface, namespace string, workload Workload) (Workload, error) {
+			return daemonSetWorkload(client.AppsV1().DaemonSets(namespace).Create(workload.(*appsv1.DaemonSet)))
+		},
+		Update: func(client kubernetes.Interface, namespace string, workload Workload) (Workload, error) {
+			return daemonSetWorkload(client.AppsV1().DaemonSets(namespace).Update(workload.(*appsv1.DaemonSet)))
+		},
+		Delete: func(client kubernetes.Interface, namespace, name string, options *metav1.DeleteOptions) error {
+			return client.AppsV1().DaemonSets(namespace).Delete(name, options)
+		},
+		List: func(lister operatorlister.OperatorLister, namespace string, selector labels.Selector) ([]Workload, error) {
+			daemonSets, err := lister.AppsV1().DaemonSetLister().DaemonSets(namespace).List(selector)
+			workloads := make([]Workload, 0, len(daemonSets))
+			for _, ds := range daemonSets {
+				workloads = append(workloads, ds)
+			}
+			return workloads, err
+		},
+	}
+)
+
+// statefulSetWorkload and daemonSetWorkload keep a failed call from returning a non-nil Workload holding a nil pointer.
+func statefulSetWorkload(sts *appsv1.StatefulSet, err error) (Workload, error) {
+	if err != nil {
+		return nil, err
+	}
+	return sts, nil
+}
+
+func daemonSetWorkload(ds *appsv1.DaemonSet, err error) (Workload, error) {
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+type InstallStrategyWorkloadInterface interface {
+	CreateOrUpdateWorkload(workload Workload) (Workload, error)
+	DeleteWorkload(name string) error
+	FindAnyWorkloadsMatchingLabels(label labels.Selector) ([]Workload, error)
+}
+
+type InstallStrategyWorkloadClientForNamespace struct {
+	opClient  operatorclient.ClientInterface
+	opLister  operatorlister.OperatorLister
+	kind      WorkloadKind
+	Namespace string
+}
+
+var _ InstallStrategyWorkloadInterface = &InstallStrategyWorkloadClientForNamespace{}
+
+func NewInstallStrategyWorkloadClient(opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, kind WorkloadKind, namespace string) InstallStrategyWorkloadInterface {
+	return &InstallStrategyWorkloadClientForNamespace{
+		opClient:  opClient,
+		opLister:  opLister,
+		kind:      kind,
+		Namespace: namespace,
+	}
+}
+
+func (c *InstallStrategyWorkloadClientForNamespace) CreateOrUpdateWorkload(workload Workload) (Workload, error) {
+	client := c.opClient.KubernetesInterface()
+	existing, err := c.kind.Get(client, c.Namespace, workload.GetName())
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return c.kind.Create(client, c.Namespace, workload)
+	}
+
+	workload.SetResourceVersion(existing.GetResourceVersion())
+	return c.kind.Update(client, c.Namespace, workload)
+}
+
+func (c *InstallStrategyWorkloadClientForNamespace) DeleteWorkload(name string) error {
+	foregroundDelete := metav1.DeletePropagationForeground // cascading delete
+	immediate := int64(0)
+	immediateForegroundDelete := &metav1.DeleteOptions{GracePeriodSeconds: &immediate, PropagationPolicy: &foregroundDelete}
+	return c.kind.Delete(c.opClient.KubernetesInterface(), c.Namespace, name, immediateForegroundDelete)
+}
+
+func (c *InstallStrategyWorkloadClientForNamespace) FindAnyWorkloadsMatchingLabels(label labels.Selector) ([]Workload, error) {
+	workloads, err := c.kind.List(c.opLister, c.Namespace, label)
+	// Any errors other than !exists are propagated up
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	return workloads, nil
+}
@@ -5,7 +5,9 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/wrappers"
@@ -47,7 +49,15 @@ func (d *StrategyDetailsDeployment) GetStrategyName() string {
 	return InstallStrategyNameDeployment
 }
 
-var _ Strategy = &StrategyDetailsDeployment{}
+func (d *StrategyDetailsDeployment) GetPermissions() []StrategyDeploymentPermissions {
+	return d.Permissions
+}
+
+func (d *StrategyDetailsDeployment) GetClusterPermissions() []StrategyDeploymentPermissions {
+	return d.ClusterPermissions
+}
+
+var _ StrategyWithPermissions = &StrategyDetailsDeployment{}
 var _ StrategyInstaller = &StrategyDeploymentInstaller{}
 
 func NewStrategyDeploymentInstaller(strategyClient wrappers.InstallStrategyDeploymentInterface, templateAnnotations map[string]string, owner ownerutil.Owner, previousStrategy Strategy) StrategyInstaller {
@@ -61,7 +71,7 @@ func NewStrategyDeploymentInstaller(strategyClient wrappers.InstallStrategyDeplo
 
 func (i *StrategyDeploymentInstaller) installDeployments(deps []StrategyDeploymentSpec) error {
 	for _, d := range deps {
-		dep := &appsv1.Deployment{Spec: d.Spec}
+		dep := &appsv1.Deployment{Spec: *d.Spec.DeepCopy()}
 		dep.SetName(d.Name)
 		dep.SetNamespace(i.owner.GetNamespace())
 
@@ -75,6 +85,9 @@ func (i *StrategyDeploymentInstaller) installDeployments(deps []StrategyDeployme
 		}
 		dep.Spec.Template.SetAnnotations(annotations)
 
+		// Merge the overrides the CSV carries over from its subscription
+		applyOverrides(&dep.Spec.Template, overridesFor(i.owner))
+
 		ownerutil.AddNonBlockingOwner(dep, i.owner)
 		if err := ownerutil.AddOwnerLabels(dep, i.owner); err != nil {
 			return err
@@ -133,6 +146,57 @@ func (i *StrategyDeploymentInstaller) CheckInstalled(s Strategy) (installed bool
 	return true, nil
 }
 
+// Uninstall deletes the deployments of the given strategy, except those the installer's previous strategy also has.
+func (i *StrategyDeploymentInstaller) Uninstall(s Strategy) error {
+	strategy, ok := s.(*StrategyDetailsDeployment)
+	if !ok {
+		return fmt.Errorf("attempted to uninstall %s strategy with deployment installer", s.GetStrategyName())
+	}
+
+	kept := map[string]struct{}{}
+	if previous, ok := i.previousStrategy.(*StrategyDetailsDeployment); ok {
+		for _, spec := range previous.DeploymentSpecs {
+			kept[spec.Name] = struct{}{}
+		}
+	}
+
+	var err error
+	for _, spec := range strategy.DeploymentSpecs {
+		if _, ok := kept[spec.Name]; ok {
+			continue
+		}
+		if deleteErr := i.strategyClient.DeleteDeployment(spec.Name); deleteErr != nil {
+			log.Warnf("error cleaning up deployment %s: %s", spec.Name, deleteErr)
+			err = deleteErr
+		}
+	}
+	return err
+}
+
+// UpdateTemplateAnnotations sets the installer's template annotations on the pod templates of the deployments its
+// owner has installed.
+func (i *StrategyDeploymentInstaller) UpdateTemplateAnnotations() error {
+	csv, ok := i.owner.(*v1alpha1.ClusterServiceVersion)
+	if !ok {
+		return fmt.Errorf("owner %s is not a CSV", i.owner.GetName())
+	}
+
+	existingDeployments, err := i.strategyClient.FindAnyDeploymentsMatchingLabels(ownerutil.CSVOwnerSelector(csv))
+	if err != nil {
+		return err
+	}
+	updateErrs := []error{}
+	for _, existing := range existingDeployments {
+		dep := existing.DeepCopy()
+		if mergeTemplateAnnotations(&dep.Spec.Template, i.templateAnnotations) {
+			if _, err := i.strategyClient.CreateOrUpdateDeployment(dep); err != nil {
+				updateErrs = append(updateErrs, err)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(updateErrs)
+}
+
 func (i *StrategyDeploymentInstaller) checkForDeployments(deploymentSpecs []StrategyDeploymentSpec) error {
 	var depNames []string
 	for _, dep := range deploymentSpecs {
@@ -179,6 +243,11 @@ func (i *StrategyDeploymentInstaller) checkForDeployments(deploymentSpecs []Stra
 				return StrategyError{Reason: StrategyErrReasonAnnotationsMissing, Message: fmt.Sprintf("annotations on deployment don't match. couldn't find %s: %s", key, value)}
 			}
 		}
+
+		// check overrides
+		if err := checkOverrides(dep.Spec.Template, csv.Spec.Overrides); err != nil {
+			return StrategyError{Reason: StrategyErrReasonOverridesMissing, Message: fmt.Sprintf("overrides on deployment %s don't match: %s", dep.Name, err)}
+		}
 	}
 	return nil
 }
@@ -218,3 +287,19 @@ func (i *StrategyDeploymentInstaller) cleanupOrphanedDeployments(deploymentSpecs
 
 	return nil
 }
+
+// mergeTemplateAnnotations sets the given annotations on a pod template, and returns true if any of them changed.
+func mergeTemplateAnnotations(template *corev1.PodTemplateSpec, annotations map[string]string) bool {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+
+	changed := false
+	for key, value := range annotations {
+		if v, ok := template.Annotations[key]; !ok || v != value {
+			template.Annotations[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
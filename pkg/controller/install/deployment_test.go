@@ -335,6 +335,62 @@ func TestInstallStrategyDeploymentCheckInstallErrors(t *testing.T) {
 	}
 }
 
+func TestInstallStrategyDeploymentOverrides(t *testing.T) {
+	namespace := "olm-test-deployment"
+	mockOwner := v1alpha1.ClusterServiceVersion{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha1.ClusterServiceVersionKind,
+			APIVersion: v1alpha1.ClusterServiceVersionAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clusterserviceversion-owner",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.ClusterServiceVersionSpec{
+			Overrides: testOverrides(),
+		},
+	}
+	strategy := strategy(1, namespace, &mockOwner)
+	strategy.DeploymentSpecs[0].Spec.Template.Spec.Containers = []corev1.Container{{Name: "operator"}}
+
+	fakeClient := new(clientfakes.FakeInstallStrategyDeploymentInterface)
+	installer := NewStrategyDeploymentInstaller(fakeClient, nil, &mockOwner, nil)
+
+	// The overrides are merged into the installed deployment
+	require.NoError(t, installer.Install(strategy))
+	require.Equal(t, 1, fakeClient.CreateOrUpdateDeploymentCallCount())
+	installed := fakeClient.CreateOrUpdateDeploymentArgsForCall(0)
+	require.NoError(t, checkOverrides(installed.Spec.Template, testOverrides()))
+	require.Empty(t, strategy.DeploymentSpecs[0].Spec.Template.Spec.Containers[0].Env)
+
+	fakeClient.FindAnyDeploymentsMatchingLabelsReturns([]*appsv1.Deployment{installed}, nil)
+	ok, err := installer.CheckInstalled(strategy)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// A deployment that drifted from them needs to be installed again
+	drifted := installed.DeepCopy()
+	drifted.Spec.Template.Spec.Containers[0].Env = nil
+	fakeClient.FindAnyDeploymentsMatchingLabelsReturns([]*appsv1.Deployment{drifted}, nil)
+	ok, err = installer.CheckInstalled(strategy)
+	require.False(t, ok)
+	require.Error(t, err)
+	require.Equal(t, StrategyErrReasonOverridesMissing, err.(StrategyError).Reason)
+
+	// So does one whose overrides were removed, which is installed without them
+	mockOwner.Spec.Overrides = nil
+	fakeClient.FindAnyDeploymentsMatchingLabelsReturns([]*appsv1.Deployment{installed}, nil)
+	ok, err = installer.CheckInstalled(strategy)
+	require.False(t, ok)
+	require.Error(t, err)
+	require.Equal(t, StrategyErrReasonOverridesMissing, err.(StrategyError).Reason)
+
+	require.NoError(t, installer.Install(strategy))
+	reinstalled := fakeClient.CreateOrUpdateDeploymentArgsForCall(1)
+	require.Empty(t, reinstalled.Spec.Template.Spec.Containers[0].Env)
+	require.NotContains(t, reinstalled.Spec.Template.GetAnnotations(), OverridesHashAnnotationKey)
+}
+
 func TestInstallStrategyDeploymentCleanupDeployments(t *testing.T) {
 	var (
 		mockOwner = v1alpha1.ClusterServiceVersion{
@@ -507,3 +563,62 @@ func TestInstallStrategyDeploymentCleanupDeployments(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallStrategyDeploymentUninstall(t *testing.T) {
+	namespace := "olm-test-deployment"
+	mockOwner := v1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clusterserviceversion-owner",
+			Namespace: namespace,
+		},
+	}
+	strategy := strategy(2, namespace, &mockOwner)
+
+	fakeClient := new(clientfakes.FakeInstallStrategyDeploymentInterface)
+	installer := NewStrategyDeploymentInstaller(fakeClient, nil, &mockOwner, nil)
+
+	// Every deployment is deleted, even if deleting one fails
+	fakeClient.DeleteDeploymentReturnsOnCall(0, fmt.Errorf("error deleting deployment"))
+	require.EqualError(t, installer.Uninstall(strategy), "error deleting deployment")
+	require.Equal(t, 2, fakeClient.DeleteDeploymentCallCount())
+	require.Equal(t, strategy.DeploymentSpecs[0].Name, fakeClient.DeleteDeploymentArgsForCall(0))
+	require.Equal(t, strategy.DeploymentSpecs[1].Name, fakeClient.DeleteDeploymentArgsForCall(1))
+
+	require.Error(t, installer.Uninstall(&BadStrategy{}))
+
+	// The deployments the previous strategy also has are kept
+	fakeClient = new(clientfakes.FakeInstallStrategyDeploymentInterface)
+	installer = NewStrategyDeploymentInstaller(fakeClient, nil, &mockOwner, &StrategyDetailsDeployment{DeploymentSpecs: strategy.DeploymentSpecs[:1]})
+	require.NoError(t, installer.Uninstall(strategy))
+	require.Equal(t, 1, fakeClient.DeleteDeploymentCallCount())
+	require.Equal(t, strategy.DeploymentSpecs[1].Name, fakeClient.DeleteDeploymentArgsForCall(0))
+}
+
+func TestInstallStrategyDeploymentUpdateTemplateAnnotations(t *testing.T) {
+	mockOwner := v1alpha1.ClusterServiceVersion{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha1.ClusterServiceVersionKind,
+			APIVersion: v1alpha1.ClusterServiceVersionAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clusterserviceversion-owner",
+			Namespace: "olm-test-deployment",
+		},
+	}
+	annotated := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "annotated"}}
+	annotated.Spec.Template.SetAnnotations(map[string]string{"test": "annotation"})
+	unannotated := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "unannotated"}}
+
+	fakeClient := new(clientfakes.FakeInstallStrategyDeploymentInterface)
+	fakeClient.FindAnyDeploymentsMatchingLabelsReturns([]*appsv1.Deployment{annotated, unannotated}, nil)
+	installer := NewStrategyDeploymentInstaller(fakeClient, map[string]string{"test": "annotation"}, &mockOwner, nil)
+	require.NoError(t, installer.UpdateTemplateAnnotations())
+
+	// Only the deployment missing the annotations is updated, and the listed one isn't modified
+	require.Equal(t, ownerutil.CSVOwnerSelector(&mockOwner), fakeClient.FindAnyDeploymentsMatchingLabelsArgsForCall(0))
+	require.Equal(t, 1, fakeClient.CreateOrUpdateDeploymentCallCount())
+	updated := fakeClient.CreateOrUpdateDeploymentArgsForCall(0)
+	require.Equal(t, "unannotated", updated.GetName())
+	require.Equal(t, map[string]string{"test": "annotation"}, updated.Spec.Template.GetAnnotations())
+	require.Empty(t, unannotated.Spec.Template.GetAnnotations())
+}
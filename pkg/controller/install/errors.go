@@ -5,6 +5,7 @@ import "fmt"
 const (
 	StrategyErrReasonComponentMissing   = "ComponentMissing"
 	StrategyErrReasonAnnotationsMissing = "AnnotationsMissing"
+	StrategyErrReasonOverridesMissing   = "OverridesMissing"
 	StrategyErrReasonWaiting            = "Waiting"
 	StrategyErrReasonInvalidStrategy    = "InvalidStrategy"
 	StrategyErrReasonTimeout            = "Timeout"
@@ -39,6 +40,16 @@ func IsErrorUnrecoverable(err error) bool {
 	return ok
 }
 
+// IsErrorTimeout reports if a given strategy error means a component timed out becoming ready
+func IsErrorTimeout(err error) bool {
+	return err != nil && reasonForError(err) == StrategyErrReasonTimeout
+}
+
+// IsErrorOverridesMissing reports if a given strategy error means a component doesn't have the current overrides
+func IsErrorOverridesMissing(err error) bool {
+	return err != nil && reasonForError(err) == StrategyErrReasonOverridesMissing
+}
+
 func reasonForError(err error) string {
 	switch t := err.(type) {
 	case StrategyError:
@@ -0,0 +1,171 @@
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+const (
+	// OverridesHashAnnotationKey is the key for a pod template annotation containing a hash of the overrides merged
+	// into the template.
+	OverridesHashAnnotationKey string = "olm.overridesHash"
+)
+
+// overridesFor returns the deployment overrides of the given owner, if it's a CSV that has any.
+func overridesFor(owner ownerutil.Owner) *v1alpha1.DeploymentOverrides {
+	csv, ok := owner.(*v1alpha1.ClusterServiceVersion)
+	if !ok {
+		return nil
+	}
+	return csv.Spec.Overrides
+}
+
+// overridesHash returns a hash of the given overrides, or an empty string if there aren't any.
+func overridesHash(overrides *v1alpha1.DeploymentOverrides) string {
+	if overrides == nil {
+		return ""
+	}
+
+	// The overrides are a plain API type, which always marshals
+	data, _ := json.Marshal(overrides)
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+// applyOverrides merges the given overrides into a pod template, and records their hash on it so that changing or
+// removing them later can be detected.
+func applyOverrides(template *corev1.PodTemplateSpec, overrides *v1alpha1.DeploymentOverrides) {
+	annotations := template.GetAnnotations()
+	if overrides == nil {
+		delete(annotations, OverridesHashAnnotationKey)
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[OverridesHashAnnotationKey] = overridesHash(overrides)
+	template.SetAnnotations(annotations)
+
+	spec := &template.Spec
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		for _, env := range overrides.Env {
+			container.Env = setEnvVar(container.Env, env)
+		}
+		if overrides.Resources != nil {
+			container.Resources.Limits = mergeResources(container.Resources.Limits, overrides.Resources.Limits)
+			container.Resources.Requests = mergeResources(container.Resources.Requests, overrides.Resources.Requests)
+		}
+	}
+
+	if len(overrides.NodeSelector) > 0 && spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range overrides.NodeSelector {
+		spec.NodeSelector[k] = v
+	}
+
+	for _, toleration := range overrides.Tolerations {
+		if !hasToleration(spec.Tolerations, toleration) {
+			spec.Tolerations = append(spec.Tolerations, toleration)
+		}
+	}
+}
+
+// checkOverrides returns an error if the overrides merged into a pod template aren't the given ones, or describing the
+// first of them that's missing from its pod spec.
+func checkOverrides(template corev1.PodTemplateSpec, overrides *v1alpha1.DeploymentOverrides) error {
+	if template.GetAnnotations()[OverridesHashAnnotationKey] != overridesHash(overrides) {
+		return fmt.Errorf("overrides changed since the pod template was updated")
+	}
+	if overrides == nil {
+		return nil
+	}
+
+	spec := template.Spec
+	for _, container := range spec.Containers {
+		for _, env := range overrides.Env {
+			if !hasEnvVar(container.Env, env) {
+				return fmt.Errorf("env var %s of container %s doesn't match", env.Name, container.Name)
+			}
+		}
+		if overrides.Resources == nil {
+			continue
+		}
+		if name, ok := resourcesMatch(container.Resources.Limits, overrides.Resources.Limits); !ok {
+			return fmt.Errorf("%s limit of container %s doesn't match", name, container.Name)
+		}
+		if name, ok := resourcesMatch(container.Resources.Requests, overrides.Resources.Requests); !ok {
+			return fmt.Errorf("%s request of container %s doesn't match", name, container.Name)
+		}
+	}
+
+	for k, v := range overrides.NodeSelector {
+		if selected, ok := spec.NodeSelector[k]; !ok || selected != v {
+			return fmt.Errorf("node selector %s doesn't match", k)
+		}
+	}
+
+	for _, toleration := range overrides.Tolerations {
+		if !hasToleration(spec.Tolerations, toleration) {
+			return fmt.Errorf("toleration for %s is missing", toleration.Key)
+		}
+	}
+
+	return nil
+}
+
+func setEnvVar(vars []corev1.EnvVar, env corev1.EnvVar) []corev1.EnvVar {
+	for i := range vars {
+		if vars[i].Name == env.Name {
+			vars[i] = env
+			return vars
+		}
+	}
+	return append(vars, env)
+}
+
+func hasEnvVar(vars []corev1.EnvVar, env corev1.EnvVar) bool {
+	for _, v := range vars {
+		if v.Name == env.Name {
+			return equality.Semantic.DeepEqual(v, env)
+		}
+	}
+	return false
+}
+
+func mergeResources(resources, overrides corev1.ResourceList) corev1.ResourceList {
+	if len(overrides) > 0 && resources == nil {
+		resources = corev1.ResourceList{}
+	}
+	for name, quantity := range overrides {
+		resources[name] = quantity
+	}
+	return resources
+}
+
+func resourcesMatch(resources, overrides corev1.ResourceList) (corev1.ResourceName, bool) {
+	for name, quantity := range overrides {
+		if existing, ok := resources[name]; !ok || existing.Cmp(quantity) != 0 {
+			return name, false
+		}
+	}
+	return "", true
+}
+
+func hasToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for _, t := range tolerations {
+		if equality.Semantic.DeepEqual(t, toleration) {
+			return true
+		}
+	}
+	return false
+}
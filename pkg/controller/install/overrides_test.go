@@ -0,0 +1,149 @@
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+)
+
+func testOverrides() *v1alpha1.DeploymentOverrides {
+	return &v1alpha1.DeploymentOverrides{
+		Env: []corev1.EnvVar{
+			{Name: "HTTP_PROXY", Value: "http://proxy:3128"},
+			{Name: "LOG_LEVEL", Value: "debug"},
+		},
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations: []corev1.Toleration{
+			{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		},
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "operator",
+					Env:  []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "WATCH_NAMESPACE"}},
+					Resources: corev1.ResourceRequirements{
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi"), corev1.ResourceCPU: resource.MustParse("1")},
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+					},
+				},
+				{Name: "sidecar"},
+			},
+			NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			Tolerations:  testOverrides().Tolerations,
+		},
+	}
+	require.NoError(t, checkOverrides(template, nil))
+	require.Error(t, checkOverrides(template, testOverrides()))
+
+	applyOverrides(&template, testOverrides())
+	require.NoError(t, checkOverrides(template, testOverrides()))
+	require.Equal(t, overridesHash(testOverrides()), template.GetAnnotations()[OverridesHashAnnotationKey])
+
+	// Overridden values replace existing ones, and the rest are kept
+	spec := template.Spec
+	require.Equal(t, []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "WATCH_NAMESPACE"},
+		{Name: "HTTP_PROXY", Value: "http://proxy:3128"},
+	}, spec.Containers[0].Env)
+	require.Equal(t, corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi"), corev1.ResourceCPU: resource.MustParse("1")},
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}, spec.Containers[0].Resources)
+	require.Equal(t, testOverrides().Env, spec.Containers[1].Env)
+	require.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}, spec.Containers[1].Resources.Limits)
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux", "node-role.kubernetes.io/infra": ""}, spec.NodeSelector)
+	require.Equal(t, testOverrides().Tolerations, spec.Tolerations)
+
+	// Applying them again changes nothing
+	applied := template.DeepCopy()
+	applyOverrides(applied, testOverrides())
+	require.Equal(t, &template, applied)
+
+	// A template the overrides were removed from no longer records them
+	applyOverrides(applied, nil)
+	require.Equal(t, template.Spec, applied.Spec)
+	require.NotContains(t, applied.GetAnnotations(), OverridesHashAnnotationKey)
+	require.NoError(t, checkOverrides(*applied, nil))
+}
+
+func TestCheckOverrides(t *testing.T) {
+	applied := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "operator"}}}}
+	applyOverrides(&applied, testOverrides())
+
+	tests := []struct {
+		name      string
+		modify    func(template *corev1.PodTemplateSpec)
+		overrides *v1alpha1.DeploymentOverrides
+		err       string
+	}{
+		{
+			name:      "Applied",
+			modify:    func(template *corev1.PodTemplateSpec) {},
+			overrides: testOverrides(),
+		},
+		{
+			name:   "Removed",
+			modify: func(template *corev1.PodTemplateSpec) {},
+			err:    "overrides changed since the pod template was updated",
+		},
+		{
+			name:   "Changed",
+			modify: func(template *corev1.PodTemplateSpec) {},
+			overrides: func() *v1alpha1.DeploymentOverrides {
+				overrides := testOverrides()
+				overrides.Env = overrides.Env[:1]
+				return overrides
+			}(),
+			err: "overrides changed since the pod template was updated",
+		},
+		{
+			name:      "EnvChanged",
+			modify:    func(template *corev1.PodTemplateSpec) { template.Spec.Containers[0].Env[0].Value = "http://other:3128" },
+			overrides: testOverrides(),
+			err:       "env var HTTP_PROXY of container operator doesn't match",
+		},
+		{
+			name:      "ResourcesChanged",
+			modify:    func(template *corev1.PodTemplateSpec) { template.Spec.Containers[0].Resources.Limits = nil },
+			overrides: testOverrides(),
+			err:       "memory limit of container operator doesn't match",
+		},
+		{
+			name:      "NodeSelectorChanged",
+			modify:    func(template *corev1.PodTemplateSpec) { template.Spec.NodeSelector = nil },
+			overrides: testOverrides(),
+			err:       "node selector node-role.kubernetes.io/infra doesn't match",
+		},
+		{
+			name:      "TolerationMissing",
+			modify:    func(template *corev1.PodTemplateSpec) { template.Spec.Tolerations = nil },
+			overrides: testOverrides(),
+			err:       "toleration for node-role.kubernetes.io/infra is missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := applied.DeepCopy()
+			tt.modify(template)
+			err := checkOverrides(*template, tt.overrides)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
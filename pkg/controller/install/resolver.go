@@ -6,6 +6,7 @@ package install
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/wrappers"
@@ -21,6 +22,37 @@ type Strategy interface {
 type StrategyInstaller interface {
 	Install(strategy Strategy) error
 	CheckInstalled(strategy Strategy) (bool, error)
+	// Uninstall deletes the components of the given strategy, except those the installer's previous strategy also has.
+	Uninstall(strategy Strategy) error
+	// UpdateTemplateAnnotations sets the installer's template annotations on the components its owner has installed.
+	UpdateTemplateAnnotations() error
+}
+
+// StrategyWithPermissions is a Strategy whose components run as service accounts that need the given permissions.
+type StrategyWithPermissions interface {
+	Strategy
+	GetPermissions() []StrategyDeploymentPermissions
+	GetClusterPermissions() []StrategyDeploymentPermissions
+}
+
+// clusterScopedStrategy needs all the permissions of the strategy it wraps at the cluster scope.
+type clusterScopedStrategy struct {
+	StrategyWithPermissions
+}
+
+func (s clusterScopedStrategy) GetPermissions() []StrategyDeploymentPermissions {
+	return nil
+}
+
+func (s clusterScopedStrategy) GetClusterPermissions() []StrategyDeploymentPermissions {
+	permissions := append([]StrategyDeploymentPermissions{}, s.StrategyWithPermissions.GetClusterPermissions()...)
+	return append(permissions, s.StrategyWithPermissions.GetPermissions()...)
+}
+
+// ClusterScoped returns a strategy that needs all the permissions of the given strategy at the cluster scope, as it does
+// when its operator watches every namespace.
+func ClusterScoped(strategy StrategyWithPermissions) StrategyWithPermissions {
+	return clusterScopedStrategy{strategy}
 }
 
 type StrategyResolverInterface interface {
@@ -28,26 +60,79 @@ type StrategyResolverInterface interface {
 	InstallerForStrategy(strategyName string, opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, owner ownerutil.Owner, annotations map[string]string, previousStrategy Strategy) StrategyInstaller
 }
 
+// StrategyFactory creates the strategies and installers of a named install strategy.
+type StrategyFactory struct {
+	// NewStrategy returns an empty strategy to unmarshal the spec of a NamedInstallStrategy into. Strategies declare
+	// the permissions of their components, since the RBAC installed for a ClusterServiceVersion is generated from them.
+	NewStrategy func() StrategyWithPermissions
+
+	// NewInstaller returns an installer for the strategies returned by NewStrategy.
+	NewInstaller func(opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, owner ownerutil.Owner, annotations map[string]string, previousStrategy Strategy) StrategyInstaller
+}
+
+var (
+	strategyFactoriesLock sync.RWMutex
+	strategyFactories     = map[string]StrategyFactory{
+		InstallStrategyNameDeployment: {
+			NewStrategy: func() StrategyWithPermissions { return &StrategyDetailsDeployment{} },
+			NewInstaller: func(opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, owner ownerutil.Owner, annotations map[string]string, previousStrategy Strategy) StrategyInstaller {
+				strategyClient := wrappers.NewInstallStrategyDeploymentClient(opClient, opLister, owner.GetNamespace())
+				return NewStrategyDeploymentInstaller(strategyClient, annotations, owner, previousStrategy)
+			},
+		},
+		InstallStrategyNameStatefulSet: workloadStrategyFactory(StatefulSetKind, func() StrategyWithPermissions { return &StrategyDetailsStatefulSet{} }),
+		InstallStrategyNameDaemonSet:   workloadStrategyFactory(DaemonSetKind, func() StrategyWithPermissions { return &StrategyDetailsDaemonSet{} }),
+	}
+)
+
+// workloadStrategyFactory returns a StrategyFactory for the given WorkloadStrategy of the given kind.
+func workloadStrategyFactory(kind WorkloadKind, newStrategy func() StrategyWithPermissions) StrategyFactory {
+	return StrategyFactory{
+		NewStrategy: newStrategy,
+		NewInstaller: func(opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, owner ownerutil.Owner, annotations map[string]string, previousStrategy Strategy) StrategyInstaller {
+			strategyClient := wrappers.NewInstallStrategyWorkloadClient(opClient, opLister, kind.Client, owner.GetNamespace())
+			return NewStrategyWorkloadInstaller(kind, strategyClient, annotations, owner, previousStrategy)
+		},
+	}
+}
+
+// RegisterStrategy makes the install strategy with the given name known to every StrategyResolver, replacing any
+// strategy already registered under that name.
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategyFactoriesLock.Lock()
+	defer strategyFactoriesLock.Unlock()
+
+	strategyFactories[name] = factory
+}
+
+func strategyFactoryFor(name string) (StrategyFactory, bool) {
+	strategyFactoriesLock.RLock()
+	defer strategyFactoriesLock.RUnlock()
+
+	factory, ok := strategyFactories[name]
+	return factory, ok
+}
+
+// StrategyResolver resolves the install strategies registered with RegisterStrategy.
 type StrategyResolver struct{}
 
 func (r *StrategyResolver) UnmarshalStrategy(s v1alpha1.NamedInstallStrategy) (strategy Strategy, err error) {
-	switch s.StrategyName {
-	case InstallStrategyNameDeployment:
-		strategy = &StrategyDetailsDeployment{}
-		if err := json.Unmarshal(s.StrategySpecRaw, strategy); err != nil {
-			return nil, err
-		}
+	factory, ok := strategyFactoryFor(s.StrategyName)
+	if !ok || factory.NewStrategy == nil {
+		err = fmt.Errorf("unrecognized install strategy")
 		return
 	}
-	err = fmt.Errorf("unrecognized install strategy")
+
+	strategy = factory.NewStrategy()
+	if err := json.Unmarshal(s.StrategySpecRaw, strategy); err != nil {
+		return nil, err
+	}
 	return
 }
 
 func (r *StrategyResolver) InstallerForStrategy(strategyName string, opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, owner ownerutil.Owner, annotations map[string]string, previousStrategy Strategy) StrategyInstaller {
-	switch strategyName {
-	case InstallStrategyNameDeployment:
-		strategyClient := wrappers.NewInstallStrategyDeploymentClient(opClient, opLister, owner.GetNamespace())
-		return NewStrategyDeploymentInstaller(strategyClient, annotations, owner, previousStrategy)
+	if factory, ok := strategyFactoryFor(strategyName); ok && factory.NewInstaller != nil {
+		return factory.NewInstaller(opClient, opLister, owner, annotations, previousStrategy)
 	}
 
 	// Insurance against these functions being called incorrectly (unmarshal strategy will return a valid strategy name)
@@ -65,3 +150,11 @@ func (i *NullStrategyInstaller) Install(s Strategy) error {
 func (i *NullStrategyInstaller) CheckInstalled(s Strategy) (bool, error) {
 	return true, nil
 }
+
+func (i *NullStrategyInstaller) Uninstall(s Strategy) error {
+	return fmt.Errorf("null InstallStrategy used")
+}
+
+func (i *NullStrategyInstaller) UpdateTemplateAnnotations() error {
+	return nil
+}
@@ -0,0 +1,131 @@
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+func TestStrategyResolverUnmarshalStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy v1alpha1.NamedInstallStrategy
+		expected Strategy
+		err      string
+	}{
+		{
+			name: "Deployment",
+			strategy: v1alpha1.NamedInstallStrategy{
+				StrategyName:    InstallStrategyNameDeployment,
+				StrategySpecRaw: []byte(`{"deployments":[{"name":"dep"}]}`),
+			},
+			expected: &StrategyDetailsDeployment{DeploymentSpecs: []StrategyDeploymentSpec{{Name: "dep"}}},
+		},
+		{
+			name: "StatefulSet",
+			strategy: v1alpha1.NamedInstallStrategy{
+				StrategyName:    InstallStrategyNameStatefulSet,
+				StrategySpecRaw: []byte(`{"statefulSets":[{"name":"sts"}],"permissions":[{"serviceAccountName":"sa"}]}`),
+			},
+			expected: &StrategyDetailsStatefulSet{
+				StatefulSetSpecs: []StrategyStatefulSetSpec{{Name: "sts"}},
+				Permissions:      []StrategyDeploymentPermissions{{ServiceAccountName: "sa"}},
+			},
+		},
+		{
+			name: "DaemonSet",
+			strategy: v1alpha1.NamedInstallStrategy{
+				StrategyName:    InstallStrategyNameDaemonSet,
+				StrategySpecRaw: []byte(`{"daemonSets":[{"name":"ds"}],"clusterPermissions":[{"serviceAccountName":"sa"}]}`),
+			},
+			expected: &StrategyDetailsDaemonSet{
+				DaemonSetSpecs:     []StrategyDaemonSetSpec{{Name: "ds"}},
+				ClusterPermissions: []StrategyDeploymentPermissions{{ServiceAccountName: "sa"}},
+			},
+		},
+		{
+			name: "Unrecognized",
+			strategy: v1alpha1.NamedInstallStrategy{
+				StrategyName:    "replicaset",
+				StrategySpecRaw: []byte(`{}`),
+			},
+			err: "unrecognized install strategy",
+		},
+		{
+			name: "InvalidSpec",
+			strategy: v1alpha1.NamedInstallStrategy{
+				StrategyName:    InstallStrategyNameStatefulSet,
+				StrategySpecRaw: []byte(`{"statefulSets":{}}`),
+			},
+			err: "json: cannot unmarshal object into Go struct field StrategyDetailsStatefulSet.statefulSets of type []install.StrategyStatefulSetSpec",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &StrategyResolver{}
+			strategy, err := resolver.UnmarshalStrategy(tt.strategy)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, strategy)
+			require.Equal(t, tt.strategy.StrategyName, strategy.GetStrategyName())
+		})
+	}
+}
+
+func TestStrategyResolverInstallerForStrategy(t *testing.T) {
+	owner := &v1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: "csv", Namespace: "ns"}}
+	resolver := &StrategyResolver{}
+
+	require.IsType(t, &StrategyDeploymentInstaller{}, resolver.InstallerForStrategy(InstallStrategyNameDeployment, nil, nil, owner, nil, nil))
+	for _, kind := range []WorkloadKind{StatefulSetKind, DaemonSetKind} {
+		installer := resolver.InstallerForStrategy(kind.Name, nil, nil, owner, nil, nil)
+		require.IsType(t, &StrategyWorkloadInstaller{}, installer)
+		require.Equal(t, kind.Name, installer.(*StrategyWorkloadInstaller).kind.Name)
+	}
+	require.IsType(t, &NullStrategyInstaller{}, resolver.InstallerForStrategy("replicaset", nil, nil, owner, nil, nil))
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	installer := &NullStrategyInstaller{}
+	RegisterStrategy("custom", StrategyFactory{
+		NewStrategy: func() StrategyWithPermissions { return &StrategyDetailsDeployment{} },
+		NewInstaller: func(opClient operatorclient.ClientInterface, opLister operatorlister.OperatorLister, owner ownerutil.Owner, annotations map[string]string, previousStrategy Strategy) StrategyInstaller {
+			return installer
+		},
+	})
+	defer func() {
+		strategyFactoriesLock.Lock()
+		defer strategyFactoriesLock.Unlock()
+		delete(strategyFactories, "custom")
+	}()
+
+	resolver := &StrategyResolver{}
+	strategy, err := resolver.UnmarshalStrategy(v1alpha1.NamedInstallStrategy{StrategyName: "custom", StrategySpecRaw: []byte(`{"permissions":[{"serviceAccountName":"sa"}]}`)})
+	require.NoError(t, err)
+	require.Equal(t, []StrategyDeploymentPermissions{{ServiceAccountName: "sa"}}, strategy.(StrategyWithPermissions).GetPermissions())
+	require.Equal(t, installer, resolver.InstallerForStrategy("custom", nil, nil, nil, nil, nil))
+}
+
+func TestClusterScoped(t *testing.T) {
+	strategy := &StrategyDetailsDeployment{
+		Permissions:        []StrategyDeploymentPermissions{{ServiceAccountName: "namespaced"}},
+		ClusterPermissions: []StrategyDeploymentPermissions{{ServiceAccountName: "cluster"}},
+	}
+
+	clusterScoped := ClusterScoped(strategy)
+	require.Equal(t, InstallStrategyNameDeployment, clusterScoped.GetStrategyName())
+	require.Empty(t, clusterScoped.GetPermissions())
+	require.Equal(t, []StrategyDeploymentPermissions{{ServiceAccountName: "cluster"}, {ServiceAccountName: "namespaced"}}, clusterScoped.GetClusterPermissions())
+
+	// The wrapped strategy isn't modified
+	require.Equal(t, []StrategyDeploymentPermissions{{ServiceAccountName: "cluster"}}, strategy.ClusterPermissions)
+}
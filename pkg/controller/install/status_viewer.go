@@ -36,6 +36,52 @@ func DeploymentStatus(deployment *appsv1.Deployment) (string, bool, error) {
 	return fmt.Sprintf("Waiting for deployment spec update to be observed...\n"), false, nil
 }
 
+// StatefulSetStatus returns a message describing statefulset status, and a bool value indicating if the status is considered done.
+func StatefulSetStatus(sts *appsv1.StatefulSet) (string, bool, error) {
+	if sts.Generation <= sts.Status.ObservedGeneration {
+		// not all replicas are ready yet
+		if sts.Spec.Replicas != nil && sts.Status.ReadyReplicas < *sts.Spec.Replicas {
+			return fmt.Sprintf("Waiting for %d pods to be ready...\n", *sts.Spec.Replicas-sts.Status.ReadyReplicas), false, nil
+		}
+		// pods of an OnDelete statefulset are only updated once they're deleted
+		if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			return fmt.Sprintf("statefulset %q pods are ready\n", sts.Name), true, nil
+		}
+		// a partitioned rollout only updates the pods above the partition
+		if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil && sts.Spec.Replicas != nil {
+			updating := *sts.Spec.Replicas - *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+			if sts.Status.UpdatedReplicas < updating {
+				return fmt.Sprintf("Waiting for partitioned roll out to finish: %d out of %d new pods have been updated...\n", sts.Status.UpdatedReplicas, updating), false, nil
+			}
+			return fmt.Sprintf("partitioned roll out complete: %d new pods have been updated...\n", sts.Status.UpdatedReplicas), true, nil
+		}
+		// waiting for pods to be updated to the new revision
+		if sts.Status.UpdateRevision != sts.Status.CurrentRevision {
+			return fmt.Sprintf("Waiting for rolling update to complete %d pods at revision %s...\n", sts.Status.UpdatedReplicas, sts.Status.UpdateRevision), false, nil
+		}
+		// statefulset is finished
+		return fmt.Sprintf("statefulset rolling update complete %d pods at revision %s...\n", sts.Status.CurrentReplicas, sts.Status.CurrentRevision), true, nil
+	}
+	return fmt.Sprintf("Waiting for statefulset spec update to be observed...\n"), false, nil
+}
+
+// DaemonSetStatus returns a message describing daemonset status, and a bool value indicating if the status is considered done.
+func DaemonSetStatus(daemon *appsv1.DaemonSet) (string, bool, error) {
+	if daemon.Generation <= daemon.Status.ObservedGeneration {
+		// pods of an OnDelete daemonset are only updated once they're deleted
+		if daemon.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType && daemon.Status.UpdatedNumberScheduled < daemon.Status.DesiredNumberScheduled {
+			return fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d out of %d new pods have been updated...\n", daemon.Name, daemon.Status.UpdatedNumberScheduled, daemon.Status.DesiredNumberScheduled), false, nil
+		}
+		// waiting for pods to report as available
+		if daemon.Status.NumberAvailable < daemon.Status.DesiredNumberScheduled {
+			return fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d of %d updated pods are available...\n", daemon.Name, daemon.Status.NumberAvailable, daemon.Status.DesiredNumberScheduled), false, nil
+		}
+		// daemonset is finished
+		return fmt.Sprintf("daemon set %q successfully rolled out\n", daemon.Name), true, nil
+	}
+	return fmt.Sprintf("Waiting for daemon set spec update to be observed...\n"), false, nil
+}
+
 func getDeploymentCondition(status appsv1.DeploymentStatus, condType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
 	for i := range status.Conditions {
 		c := status.Conditions[i]
@@ -117,3 +117,242 @@ func TestDeploymentStatusViewerStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestStatefulSetStatusViewerStatus(t *testing.T) {
+	partition := int32(1)
+	tests := []struct {
+		generation     int64
+		specReplicas   int32
+		updateStrategy apps.StatefulSetUpdateStrategy
+		status         apps.StatefulSetStatus
+		msg            string
+		done           bool
+	}{
+		{
+			generation:   2,
+			specReplicas: 2,
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      2,
+			},
+
+			msg:  "Waiting for statefulset spec update to be observed...\n",
+			done: false,
+		},
+		{
+			generation:   1,
+			specReplicas: 2,
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      1,
+			},
+
+			msg:  "Waiting for 1 pods to be ready...\n",
+			done: false,
+		},
+		{
+			generation:   1,
+			specReplicas: 2,
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      2,
+				UpdatedReplicas:    1,
+				CurrentRevision:    "foo-1",
+				UpdateRevision:     "foo-2",
+			},
+
+			msg:  "Waiting for rolling update to complete 1 pods at revision foo-2...\n",
+			done: false,
+		},
+		{
+			generation:   1,
+			specReplicas: 2,
+			updateStrategy: apps.StatefulSetUpdateStrategy{
+				Type:          apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{Partition: &partition},
+			},
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      2,
+				UpdatedReplicas:    0,
+			},
+
+			msg:  "Waiting for partitioned roll out to finish: 0 out of 1 new pods have been updated...\n",
+			done: false,
+		},
+		{
+			generation:   1,
+			specReplicas: 2,
+			updateStrategy: apps.StatefulSetUpdateStrategy{
+				Type:          apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{Partition: &partition},
+			},
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      2,
+				UpdatedReplicas:    1,
+			},
+
+			msg:  "partitioned roll out complete: 1 new pods have been updated...\n",
+			done: true,
+		},
+		{
+			generation:     1,
+			specReplicas:   2,
+			updateStrategy: apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      2,
+				CurrentRevision:    "foo-1",
+				UpdateRevision:     "foo-2",
+			},
+
+			msg:  "statefulset \"foo\" pods are ready\n",
+			done: true,
+		},
+		{
+			generation:   1,
+			specReplicas: 2,
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      2,
+				CurrentReplicas:    2,
+				CurrentRevision:    "foo-2",
+				UpdateRevision:     "foo-2",
+			},
+
+			msg:  "statefulset rolling update complete 2 pods at revision foo-2...\n",
+			done: true,
+		},
+	}
+
+	for _, test := range tests {
+		s := &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "bar",
+				Name:       "foo",
+				Generation: test.generation,
+			},
+			Spec: apps.StatefulSetSpec{
+				Replicas:       &test.specReplicas,
+				UpdateStrategy: test.updateStrategy,
+			},
+			Status: test.status,
+		}
+		msg, done, err := StatefulSetStatus(s)
+		if err != nil {
+			t.Fatalf("StatefulSetStatus(): %v", err)
+		}
+		if done != test.done || msg != test.msg {
+			t.Errorf("StatefulSetStatus() for statefulset with generation %d, %d replicas specified, and status %+v returned %q, %t, want %q, %t",
+				test.generation,
+				test.specReplicas,
+				test.status,
+				msg,
+				done,
+				test.msg,
+				test.done,
+			)
+		}
+	}
+}
+
+func TestDaemonSetStatusViewerStatus(t *testing.T) {
+	tests := []struct {
+		generation     int64
+		updateStrategy apps.DaemonSetUpdateStrategy
+		status         apps.DaemonSetStatus
+		msg            string
+		done           bool
+	}{
+		{
+			generation: 2,
+			status: apps.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 2,
+				UpdatedNumberScheduled: 2,
+				NumberAvailable:        2,
+			},
+
+			msg:  "Waiting for daemon set spec update to be observed...\n",
+			done: false,
+		},
+		{
+			generation: 1,
+			status: apps.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 2,
+				UpdatedNumberScheduled: 1,
+				NumberAvailable:        2,
+			},
+
+			msg:  "Waiting for daemon set \"foo\" rollout to finish: 1 out of 2 new pods have been updated...\n",
+			done: false,
+		},
+		{
+			generation: 1,
+			status: apps.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 2,
+				UpdatedNumberScheduled: 2,
+				NumberAvailable:        1,
+			},
+
+			msg:  "Waiting for daemon set \"foo\" rollout to finish: 1 of 2 updated pods are available...\n",
+			done: false,
+		},
+		{
+			generation:     1,
+			updateStrategy: apps.DaemonSetUpdateStrategy{Type: apps.OnDeleteDaemonSetStrategyType},
+			status: apps.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 2,
+				UpdatedNumberScheduled: 1,
+				NumberAvailable:        2,
+			},
+
+			msg:  "daemon set \"foo\" successfully rolled out\n",
+			done: true,
+		},
+		{
+			generation: 1,
+			status: apps.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 2,
+				UpdatedNumberScheduled: 2,
+				NumberAvailable:        2,
+			},
+
+			msg:  "daemon set \"foo\" successfully rolled out\n",
+			done: true,
+		},
+	}
+
+	for _, test := range tests {
+		d := &apps.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "bar",
+				Name:       "foo",
+				Generation: test.generation,
+			},
+			Spec: apps.DaemonSetSpec{
+				UpdateStrategy: test.updateStrategy,
+			},
+			Status: test.status,
+		}
+		msg, done, err := DaemonSetStatus(d)
+		if err != nil {
+			t.Fatalf("DaemonSetStatus(): %v", err)
+		}
+		if done != test.done || msg != test.msg {
+			t.Errorf("DaemonSetStatus() for daemonset with generation %d and status %+v returned %q, %t, want %q, %t",
+				test.generation,
+				test.status,
+				msg,
+				done,
+				test.msg,
+				test.done,
+			)
+		}
+	}
+}
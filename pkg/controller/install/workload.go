@@ -0,0 +1,366 @@
+package install
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/wrappers"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+const (
+	InstallStrategyNameStatefulSet = "statefulset"
+	InstallStrategyNameDaemonSet   = "daemonset"
+)
+
+// WorkloadKind is a kind of workload, such as StatefulSets, that a StrategyWorkloadInstaller installs.
+type WorkloadKind struct {
+	// Name is the lowercase name of the kind, which is also the name of its install strategy.
+	Name string
+
+	// Client gets, writes and lists the workloads of the kind.
+	Client wrappers.WorkloadKind
+
+	// PodTemplate returns the pod template of a workload of the kind.
+	PodTemplate func(workload wrappers.Workload) *corev1.PodTemplateSpec
+
+	// Status returns a message describing the status of a workload of the kind, and whether it's ready.
+	Status func(workload wrappers.Workload) (string, bool, error)
+}
+
+var (
+	StatefulSetKind = WorkloadKind{
+		Name:   InstallStrategyNameStatefulSet,
+		Client: wrappers.StatefulSetKind,
+		PodTemplate: func(workload wrappers.Workload) *corev1.PodTemplateSpec {
+			return &workload.(*appsv1.StatefulSet).Spec.Template
+		},
+		Status: func(workload wrappers.Workload) (string, bool, error) {
+			return StatefulSetStatus(workload.(*appsv1.StatefulSet))
+		},
+	}
+
+	DaemonSetKind = WorkloadKind{
+		Name:   InstallStrategyNameDaemonSet,
+		Client: wrappers.DaemonSetKind,
+		PodTemplate: func(workload wrappers.Workload) *corev1.PodTemplateSpec {
+			return &workload.(*appsv1.DaemonSet).Spec.Template
+		},
+		Status: func(workload wrappers.Workload) (string, bool, error) {
+			return DaemonSetStatus(workload.(*appsv1.DaemonSet))
+		},
+	}
+)
+
+// WorkloadStrategy is a Strategy whose components are workloads of a single kind.
+type WorkloadStrategy interface {
+	StrategyWithPermissions
+
+	// GetWorkloads returns new copies of the workloads the strategy installs, without a namespace.
+	GetWorkloads() []wrappers.Workload
+}
+
+// StrategyStatefulSetSpec contains the name and spec for the statefulset OLM should create
+type StrategyStatefulSetSpec struct {
+	Name string                 `json:"name"`
+	Spec appsv1.StatefulSetSpec `json:"spec"`
+}
+
+// StrategyDetailsStatefulSet represents the parsed details of a StatefulSet
+// InstallStrategy.
+type StrategyDetailsStatefulSet struct {
+	StatefulSetSpecs   []StrategyStatefulSetSpec       `json:"statefulSets"`
+	Permissions        []StrategyDeploymentPermissions `json:"permissions,omitempty"`
+	ClusterPermissions []StrategyDeploymentPermissions `json:"clusterPermissions,omitempty"`
+}
+
+func (d *StrategyDetailsStatefulSet) GetStrategyName() string {
+	return InstallStrategyNameStatefulSet
+}
+
+func (d *StrategyDetailsStatefulSet) GetPermissions() []StrategyDeploymentPermissions {
+	return d.Permissions
+}
+
+func (d *StrategyDetailsStatefulSet) GetClusterPermissions() []StrategyDeploymentPermissions {
+	return d.ClusterPermissions
+}
+
+func (d *StrategyDetailsStatefulSet) GetWorkloads() []wrappers.Workload {
+	var workloads []wrappers.Workload
+	for _, s := range d.StatefulSetSpecs {
+		sts := &appsv1.StatefulSet{Spec: *s.Spec.DeepCopy()}
+		sts.SetName(s.Name)
+		workloads = append(workloads, sts)
+	}
+	return workloads
+}
+
+// StrategyDaemonSetSpec contains the name and spec for the daemonset OLM should create
+type StrategyDaemonSetSpec struct {
+	Name string               `json:"name"`
+	Spec appsv1.DaemonSetSpec `json:"spec"`
+}
+
+// StrategyDetailsDaemonSet represents the parsed details of a DaemonSet
+// InstallStrategy.
+type StrategyDetailsDaemonSet struct {
+	DaemonSetSpecs     []StrategyDaemonSetSpec         `json:"daemonSets"`
+	Permissions        []StrategyDeploymentPermissions `json:"permissions,omitempty"`
+	ClusterPermissions []StrategyDeploymentPermissions `json:"clusterPermissions,omitempty"`
+}
+
+func (d *StrategyDetailsDaemonSet) GetStrategyName() string {
+	return InstallStrategyNameDaemonSet
+}
+
+func (d *StrategyDetailsDaemonSet) GetPermissions() []StrategyDeploymentPermissions {
+	return d.Permissions
+}
+
+func (d *StrategyDetailsDaemonSet) GetClusterPermissions() []StrategyDeploymentPermissions {
+	return d.ClusterPermissions
+}
+
+func (d *StrategyDetailsDaemonSet) GetWorkloads() []wrappers.Workload {
+	var workloads []wrappers.Workload
+	for _, s := range d.DaemonSetSpecs {
+		ds := &appsv1.DaemonSet{Spec: *s.Spec.DeepCopy()}
+		ds.SetName(s.Name)
+		workloads = append(workloads, ds)
+	}
+	return workloads
+}
+
+var _ WorkloadStrategy = &StrategyDetailsStatefulSet{}
+var _ WorkloadStrategy = &StrategyDetailsDaemonSet{}
+
+// StrategyWorkloadInstaller installs the workloads of a WorkloadStrategy of its kind.
+type StrategyWorkloadInstaller struct {
+	kind                WorkloadKind
+	strategyClient      wrappers.InstallStrategyWorkloadInterface
+	owner               ownerutil.Owner
+	previousStrategy    Strategy
+	templateAnnotations map[string]string
+}
+
+var _ StrategyInstaller = &StrategyWorkloadInstaller{}
+
+func NewStrategyWorkloadInstaller(kind WorkloadKind, strategyClient wrappers.InstallStrategyWorkloadInterface, templateAnnotations map[string]string, owner ownerutil.Owner, previousStrategy Strategy) StrategyInstaller {
+	return &StrategyWorkloadInstaller{
+		kind:                kind,
+		strategyClient:      strategyClient,
+		owner:               owner,
+		previousStrategy:    previousStrategy,
+		templateAnnotations: templateAnnotations,
+	}
+}
+
+// workloadStrategy returns the given strategy if it's a WorkloadStrategy of the installer's kind.
+func (i *StrategyWorkloadInstaller) workloadStrategy(s Strategy) (WorkloadStrategy, bool) {
+	strategy, ok := s.(WorkloadStrategy)
+	return strategy, ok && strategy.GetStrategyName() == i.kind.Name
+}
+
+func (i *StrategyWorkloadInstaller) installWorkloads(workloads []wrappers.Workload) error {
+	for _, workload := range workloads {
+		workload.SetNamespace(i.owner.GetNamespace())
+
+		// Merge annotations (to avoid losing info from pod template)
+		template := i.kind.PodTemplate(workload)
+		annotations := map[string]string{}
+		for k, v := range i.templateAnnotations {
+			annotations[k] = v
+		}
+		for k, v := range template.GetAnnotations() {
+			annotations[k] = v
+		}
+		template.SetAnnotations(annotations)
+
+		// Merge the overrides the CSV carries over from its subscription
+		applyOverrides(template, overridesFor(i.owner))
+
+		ownerutil.AddNonBlockingOwner(workload, i.owner)
+		if err := ownerutil.AddOwnerLabels(workload, i.owner); err != nil {
+			return err
+		}
+		if _, err := i.strategyClient.CreateOrUpdateWorkload(workload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *StrategyWorkloadInstaller) Install(s Strategy) error {
+	strategy, ok := i.workloadStrategy(s)
+	if !ok {
+		return fmt.Errorf("attempted to install %s strategy with %s installer", s.GetStrategyName(), i.kind.Name)
+	}
+
+	workloads := strategy.GetWorkloads()
+	if err := i.installWorkloads(workloads); err != nil {
+		return err
+	}
+
+	// Clean up orphaned workloads
+	return i.cleanupOrphanedWorkloads(workloads)
+}
+
+// CheckInstalled can return nil (installed), or errors
+// Errors can indicate: some component missing (keep installing), unable to query (check again later), or unrecoverable (failed in a way we know we can't recover from)
+func (i *StrategyWorkloadInstaller) CheckInstalled(s Strategy) (installed bool, err error) {
+	strategy, ok := i.workloadStrategy(s)
+	if !ok {
+		return false, StrategyError{Reason: StrategyErrReasonInvalidStrategy, Message: fmt.Sprintf("attempted to check %s strategy with %s installer", s.GetStrategyName(), i.kind.Name)}
+	}
+
+	if err := i.checkForWorkloads(strategy.GetWorkloads()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Uninstall deletes the workloads of the given strategy, except those the installer's previous strategy also has.
+func (i *StrategyWorkloadInstaller) Uninstall(s Strategy) error {
+	strategy, ok := i.workloadStrategy(s)
+	if !ok {
+		return fmt.Errorf("attempted to uninstall %s strategy with %s installer", s.GetStrategyName(), i.kind.Name)
+	}
+
+	kept := map[string]struct{}{}
+	if i.previousStrategy != nil {
+		if previous, ok := i.workloadStrategy(i.previousStrategy); ok {
+			for _, workload := range previous.GetWorkloads() {
+				kept[workload.GetName()] = struct{}{}
+			}
+		}
+	}
+
+	var err error
+	for _, workload := range strategy.GetWorkloads() {
+		if _, ok := kept[workload.GetName()]; ok {
+			continue
+		}
+		if deleteErr := i.strategyClient.DeleteWorkload(workload.GetName()); deleteErr != nil {
+			log.Warnf("error cleaning up %s %s: %s", i.kind.Name, workload.GetName(), deleteErr)
+			err = deleteErr
+		}
+	}
+	return err
+}
+
+// UpdateTemplateAnnotations sets the installer's template annotations on the pod templates of the workloads its owner
+// has installed.
+func (i *StrategyWorkloadInstaller) UpdateTemplateAnnotations() error {
+	csv, ok := i.owner.(*v1alpha1.ClusterServiceVersion)
+	if !ok {
+		return fmt.Errorf("owner %s is not a CSV", i.owner.GetName())
+	}
+
+	existingWorkloads, err := i.strategyClient.FindAnyWorkloadsMatchingLabels(ownerutil.CSVOwnerSelector(csv))
+	if err != nil {
+		return err
+	}
+	updateErrs := []error{}
+	for _, existing := range existingWorkloads {
+		workload := existing.DeepCopyObject().(wrappers.Workload)
+		if mergeTemplateAnnotations(i.kind.PodTemplate(workload), i.templateAnnotations) {
+			if _, err := i.strategyClient.CreateOrUpdateWorkload(workload); err != nil {
+				updateErrs = append(updateErrs, err)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(updateErrs)
+}
+
+func (i *StrategyWorkloadInstaller) checkForWorkloads(workloads []wrappers.Workload) error {
+	// Check the owner is a CSV
+	csv, ok := i.owner.(*v1alpha1.ClusterServiceVersion)
+	if !ok {
+		return StrategyError{Reason: StrategyErrReasonComponentMissing, Message: fmt.Sprintf("owner %s is not a CSV", i.owner.GetName())}
+	}
+
+	existingWorkloads, err := i.strategyClient.FindAnyWorkloadsMatchingLabels(ownerutil.CSVOwnerSelector(csv))
+	if err != nil {
+		return StrategyError{Reason: StrategyErrReasonComponentMissing, Message: fmt.Sprintf("error querying existing %ss for CSV %s: %s", i.kind.Name, csv.GetName(), err)}
+	}
+
+	existingMap := map[string]wrappers.Workload{}
+	for _, w := range existingWorkloads {
+		existingMap[w.GetName()] = w
+	}
+	for _, workload := range workloads {
+		name := workload.GetName()
+		existing, exists := existingMap[name]
+		if !exists {
+			log.Debugf("missing %s with name=%s", i.kind.Name, name)
+			return StrategyError{Reason: StrategyErrReasonComponentMissing, Message: fmt.Sprintf("missing %s with name=%s", i.kind.Name, name)}
+		}
+		reason, ready, err := i.kind.Status(existing)
+		if err != nil {
+			log.Debugf("%s %s not ready before timeout: %s", i.kind.Name, name, err.Error())
+			return StrategyError{Reason: StrategyErrReasonTimeout, Message: fmt.Sprintf("%s %s not ready before timeout: %s", i.kind.Name, name, err.Error())}
+		}
+		if !ready {
+			return StrategyError{Reason: StrategyErrReasonWaiting, Message: fmt.Sprintf("waiting for %s %s to become ready: %s", i.kind.Name, name, reason)}
+		}
+
+		// check annotations
+		template := i.kind.PodTemplate(existing)
+		if len(i.templateAnnotations) > 0 && template.Annotations == nil {
+			return StrategyError{Reason: StrategyErrReasonAnnotationsMissing, Message: fmt.Sprintf("no annotations found on %s", i.kind.Name)}
+		}
+		for key, value := range i.templateAnnotations {
+			if template.Annotations[key] != value {
+				return StrategyError{Reason: StrategyErrReasonAnnotationsMissing, Message: fmt.Sprintf("annotations on %s don't match. couldn't find %s: %s", i.kind.Name, key, value)}
+			}
+		}
+
+		// check overrides
+		if err := checkOverrides(*template, csv.Spec.Overrides); err != nil {
+			return StrategyError{Reason: StrategyErrReasonOverridesMissing, Message: fmt.Sprintf("overrides on %s %s don't match: %s", i.kind.Name, name, err)}
+		}
+	}
+	return nil
+}
+
+// Clean up orphaned workloads after reinstalling workloads process
+func (i *StrategyWorkloadInstaller) cleanupOrphanedWorkloads(workloads []wrappers.Workload) error {
+	names := map[string]struct{}{}
+	for _, w := range workloads {
+		names[w.GetName()] = struct{}{}
+	}
+
+	// Check the owner is a CSV
+	csv, ok := i.owner.(*v1alpha1.ClusterServiceVersion)
+	if !ok {
+		return fmt.Errorf("owner %s is not a CSV", i.owner.GetName())
+	}
+
+	// Get existing workloads in CSV's namespace and owned by CSV
+	existingWorkloads, err := i.strategyClient.FindAnyWorkloadsMatchingLabels(ownerutil.CSVOwnerSelector(csv))
+	if err != nil {
+		return err
+	}
+
+	// compare existing workloads to workloads in CSV's spec to see if any need to be deleted
+	for _, w := range existingWorkloads {
+		if _, exists := names[w.GetName()]; !exists {
+			if ownerutil.IsOwnedBy(w, i.owner) {
+				log.Infof("found an orphaned %s %s in namespace %s", i.kind.Name, w.GetName(), i.owner.GetNamespace())
+				if err := i.strategyClient.DeleteWorkload(w.GetName()); err != nil {
+					log.Warnf("error cleaning up %s %s", i.kind.Name, w.GetName())
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
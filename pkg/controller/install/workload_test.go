@@ -0,0 +1,302 @@
+package install
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/wrappers"
+	clientfakes "github.com/operator-framework/operator-lifecycle-manager/pkg/api/wrappers/wrappersfakes"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+// workloadKindTest is a kind of workload to run the workload installer tests with.
+type workloadKindTest struct {
+	kind WorkloadKind
+
+	// strategy returns a strategy of the kind that installs a single workload named wl-1, whose pod template is
+	// annotated with template: annotation.
+	strategy func() WorkloadStrategy
+
+	// existing returns a ready workload of the kind.
+	existing func(name string) wrappers.Workload
+
+	// notReady makes a workload of the kind not ready.
+	notReady func(workload wrappers.Workload)
+}
+
+var workloadKindTests = []workloadKindTest{
+	{
+		kind: StatefulSetKind,
+		strategy: func() WorkloadStrategy {
+			strategy := &StrategyDetailsStatefulSet{StatefulSetSpecs: []StrategyStatefulSetSpec{{Name: "wl-1"}}}
+			strategy.StatefulSetSpecs[0].Spec.Template.SetAnnotations(map[string]string{"template": "annotation"})
+			return strategy
+		},
+		existing: func(name string) wrappers.Workload {
+			replicas := int32(1)
+			return &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas:   1,
+					CurrentReplicas: 1,
+					CurrentRevision: name + "-1",
+					UpdateRevision:  name + "-1",
+				},
+			}
+		},
+		notReady: func(workload wrappers.Workload) {
+			workload.(*appsv1.StatefulSet).Status.ReadyReplicas = 0
+		},
+	},
+	{
+		kind: DaemonSetKind,
+		strategy: func() WorkloadStrategy {
+			strategy := &StrategyDetailsDaemonSet{DaemonSetSpecs: []StrategyDaemonSetSpec{{Name: "wl-1"}}}
+			strategy.DaemonSetSpecs[0].Spec.Template.SetAnnotations(map[string]string{"template": "annotation"})
+			return strategy
+		},
+		existing: func(name string) wrappers.Workload {
+			return &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 2,
+					UpdatedNumberScheduled: 2,
+					NumberAvailable:        2,
+				},
+			}
+		},
+		notReady: func(workload wrappers.Workload) {
+			workload.(*appsv1.DaemonSet).Status.NumberAvailable = 0
+		},
+	},
+}
+
+func testWorkloadOwner() *v1alpha1.ClusterServiceVersion {
+	return &v1alpha1.ClusterServiceVersion{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha1.ClusterServiceVersionKind,
+			APIVersion: v1alpha1.ClusterServiceVersionAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clusterserviceversion-owner",
+			Namespace: "olm-test-workload",
+		},
+	}
+}
+
+// existingWorkload returns a ready workload of the kind, installed by the given owner.
+func (tt workloadKindTest) existingWorkload(name string, owner ownerutil.Owner) wrappers.Workload {
+	workload := tt.existing(name)
+	workload.SetNamespace(owner.GetNamespace())
+	ownerutil.AddNonBlockingOwner(workload, owner)
+	ownerutil.AddOwnerLabels(workload, owner)
+	return workload
+}
+
+func (tt workloadKindTest) annotated(workload wrappers.Workload) wrappers.Workload {
+	tt.kind.PodTemplate(workload).SetAnnotations(map[string]string{"test": "annotation"})
+	return workload
+}
+
+func TestInstallStrategyWorkloadInstall(t *testing.T) {
+	for _, tt := range workloadKindTests {
+		t.Run(tt.kind.Name, func(t *testing.T) {
+			mockOwner := testWorkloadOwner()
+			strategy := tt.strategy()
+
+			fakeClient := new(clientfakes.FakeInstallStrategyWorkloadInterface)
+			owned := tt.existingWorkload("wl-2", mockOwner)
+			unowned := tt.existingWorkload("wl-3", mockOwner)
+			unowned.SetOwnerReferences(nil)
+			fakeClient.FindAnyWorkloadsMatchingLabelsReturns([]wrappers.Workload{owned, unowned}, nil)
+
+			installer := NewStrategyWorkloadInstaller(tt.kind, fakeClient, map[string]string{"test": "annotation"}, mockOwner, nil)
+			require.NoError(t, installer.Install(strategy))
+
+			// The workload is owned by the CSV and its pods get the CSV's annotations
+			require.Equal(t, 1, fakeClient.CreateOrUpdateWorkloadCallCount())
+			created := fakeClient.CreateOrUpdateWorkloadArgsForCall(0)
+			require.IsType(t, strategy.GetWorkloads()[0], created)
+			require.Equal(t, "wl-1", created.GetName())
+			require.Equal(t, mockOwner.GetNamespace(), created.GetNamespace())
+			require.True(t, ownerutil.IsOwnedBy(created, mockOwner))
+			require.True(t, ownerutil.CSVOwnerSelector(mockOwner).Matches(labels.Set(created.GetLabels())))
+			require.Equal(t, map[string]string{"test": "annotation", "template": "annotation"}, tt.kind.PodTemplate(created).GetAnnotations())
+
+			// Only owned workloads missing from the strategy are cleaned up
+			require.Equal(t, ownerutil.CSVOwnerSelector(mockOwner), fakeClient.FindAnyWorkloadsMatchingLabelsArgsForCall(0))
+			require.Equal(t, 1, fakeClient.DeleteWorkloadCallCount())
+			require.Equal(t, "wl-2", fakeClient.DeleteWorkloadArgsForCall(0))
+
+			fakeClient.CreateOrUpdateWorkloadReturns(nil, fmt.Errorf("error creating workload"))
+			require.EqualError(t, installer.Install(strategy), "error creating workload")
+
+			require.Error(t, installer.Install(&BadStrategy{}))
+		})
+	}
+}
+
+func TestInstallStrategyWorkloadOverrides(t *testing.T) {
+	for _, tt := range workloadKindTests {
+		t.Run(tt.kind.Name, func(t *testing.T) {
+			mockOwner := testWorkloadOwner()
+			mockOwner.Spec.Overrides = testOverrides()
+			strategy := tt.strategy()
+			tt.kind.PodTemplate(strategy.GetWorkloads()[0]).Spec.Containers = []corev1.Container{{Name: "operator"}}
+
+			fakeClient := new(clientfakes.FakeInstallStrategyWorkloadInterface)
+			installer := NewStrategyWorkloadInstaller(tt.kind, fakeClient, nil, mockOwner, nil)
+
+			// The overrides are merged into the installed workload
+			require.NoError(t, installer.Install(strategy))
+			require.Equal(t, 1, fakeClient.CreateOrUpdateWorkloadCallCount())
+			installed := fakeClient.CreateOrUpdateWorkloadArgsForCall(0)
+			require.NoError(t, checkOverrides(*tt.kind.PodTemplate(installed), testOverrides()))
+
+			ready := tt.existingWorkload("wl-1", mockOwner)
+			tt.kind.PodTemplate(ready).ObjectMeta = tt.kind.PodTemplate(installed).ObjectMeta
+			tt.kind.PodTemplate(ready).Spec = tt.kind.PodTemplate(installed).Spec
+			fakeClient.FindAnyWorkloadsMatchingLabelsReturns([]wrappers.Workload{ready}, nil)
+			ok, err := installer.CheckInstalled(strategy)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			// A workload whose overrides were removed needs to be installed again
+			mockOwner.Spec.Overrides = nil
+			ok, err = installer.CheckInstalled(strategy)
+			require.False(t, ok)
+			require.Error(t, err)
+			require.Equal(t, StrategyErrReasonOverridesMissing, err.(StrategyError).Reason)
+		})
+	}
+}
+
+func TestInstallStrategyWorkloadInstallOtherKind(t *testing.T) {
+	// A strategy of one workload kind can't be installed by the installer of another
+	installer := NewStrategyWorkloadInstaller(StatefulSetKind, new(clientfakes.FakeInstallStrategyWorkloadInterface), nil, testWorkloadOwner(), nil)
+	require.EqualError(t, installer.Install(&StrategyDetailsDaemonSet{}), "attempted to install daemonset strategy with statefulset installer")
+}
+
+func TestInstallStrategyWorkloadCheckInstalled(t *testing.T) {
+	for _, kt := range workloadKindTests {
+		mockOwner := testWorkloadOwner()
+
+		tests := []struct {
+			description string
+			existing    []wrappers.Workload
+			findErr     error
+			strategy    Strategy
+			reason      string
+		}{
+			{
+				description: "Installed",
+				existing:    []wrappers.Workload{kt.annotated(kt.existingWorkload("wl-1", mockOwner))},
+				strategy:    kt.strategy(),
+			},
+			{
+				description: "Missing",
+				existing:    []wrappers.Workload{kt.annotated(kt.existingWorkload("wl-2", mockOwner))},
+				strategy:    kt.strategy(),
+				reason:      StrategyErrReasonComponentMissing,
+			},
+			{
+				description: "QueryError",
+				findErr:     fmt.Errorf("error listing workloads"),
+				strategy:    kt.strategy(),
+				reason:      StrategyErrReasonComponentMissing,
+			},
+			{
+				description: "NotReady",
+				existing: []wrappers.Workload{func() wrappers.Workload {
+					workload := kt.annotated(kt.existingWorkload("wl-1", mockOwner))
+					kt.notReady(workload)
+					return workload
+				}()},
+				strategy: kt.strategy(),
+				reason:   StrategyErrReasonWaiting,
+			},
+			{
+				description: "AnnotationsMissing",
+				existing:    []wrappers.Workload{kt.existingWorkload("wl-1", mockOwner)},
+				strategy:    kt.strategy(),
+				reason:      StrategyErrReasonAnnotationsMissing,
+			},
+			{
+				description: "InvalidStrategy",
+				strategy:    &BadStrategy{},
+				reason:      StrategyErrReasonInvalidStrategy,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(kt.kind.Name+"/"+tt.description, func(t *testing.T) {
+				fakeClient := new(clientfakes.FakeInstallStrategyWorkloadInterface)
+				fakeClient.FindAnyWorkloadsMatchingLabelsReturns(tt.existing, tt.findErr)
+				installer := NewStrategyWorkloadInstaller(kt.kind, fakeClient, map[string]string{"test": "annotation"}, mockOwner, nil)
+
+				installed, err := installer.CheckInstalled(tt.strategy)
+				if tt.reason == "" {
+					require.NoError(t, err)
+					require.True(t, installed)
+					return
+				}
+				require.False(t, installed)
+				require.Error(t, err)
+				require.Equal(t, tt.reason, err.(StrategyError).Reason)
+			})
+		}
+	}
+}
+
+func TestInstallStrategyWorkloadUninstall(t *testing.T) {
+	for _, tt := range workloadKindTests {
+		t.Run(tt.kind.Name, func(t *testing.T) {
+			fakeClient := new(clientfakes.FakeInstallStrategyWorkloadInterface)
+			installer := NewStrategyWorkloadInstaller(tt.kind, fakeClient, nil, testWorkloadOwner(), nil)
+
+			require.NoError(t, installer.Uninstall(tt.strategy()))
+			require.Equal(t, 1, fakeClient.DeleteWorkloadCallCount())
+			require.Equal(t, "wl-1", fakeClient.DeleteWorkloadArgsForCall(0))
+
+			fakeClient.DeleteWorkloadReturns(fmt.Errorf("error deleting workload"))
+			require.EqualError(t, installer.Uninstall(tt.strategy()), "error deleting workload")
+
+			require.Error(t, installer.Uninstall(&BadStrategy{}))
+
+			// The workloads the previous strategy also has are kept
+			fakeClient = new(clientfakes.FakeInstallStrategyWorkloadInterface)
+			installer = NewStrategyWorkloadInstaller(tt.kind, fakeClient, nil, testWorkloadOwner(), tt.strategy())
+			require.NoError(t, installer.Uninstall(tt.strategy()))
+			require.Equal(t, 0, fakeClient.DeleteWorkloadCallCount())
+		})
+	}
+}
+
+func TestInstallStrategyWorkloadUpdateTemplateAnnotations(t *testing.T) {
+	for _, tt := range workloadKindTests {
+		t.Run(tt.kind.Name, func(t *testing.T) {
+			mockOwner := testWorkloadOwner()
+			annotated := tt.annotated(tt.existingWorkload("wl-1", mockOwner))
+			unannotated := tt.existingWorkload("wl-2", mockOwner)
+
+			fakeClient := new(clientfakes.FakeInstallStrategyWorkloadInterface)
+			fakeClient.FindAnyWorkloadsMatchingLabelsReturns([]wrappers.Workload{annotated, unannotated}, nil)
+			installer := NewStrategyWorkloadInstaller(tt.kind, fakeClient, map[string]string{"test": "annotation"}, mockOwner, nil)
+			require.NoError(t, installer.UpdateTemplateAnnotations())
+
+			// Only the workload missing the annotations is updated, and the listed one isn't modified
+			require.Equal(t, 1, fakeClient.CreateOrUpdateWorkloadCallCount())
+			updated := fakeClient.CreateOrUpdateWorkloadArgsForCall(0)
+			require.Equal(t, "wl-2", updated.GetName())
+			require.Equal(t, map[string]string{"test": "annotation"}, tt.kind.PodTemplate(updated).GetAnnotations())
+			require.Empty(t, tt.kind.PodTemplate(unannotated).GetAnnotations())
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/queueinformer"
+)
+
+const (
+	// maxCatalogProblemsReported bounds the problems listed in the content validation of a CatalogSource.
+	maxCatalogProblemsReported = 10
+
+	// catalogValidationTimeout bounds each attempt to validate the content of a single catalog. Failed attempts are
+	// retried with backoff.
+	catalogValidationTimeout = 2 * time.Minute
+)
+
+// contentValidationOutdated returns true if the content of the given CatalogSource hasn't been validated since its
+// registry server was last (re)created or connected to, which is when its content may have changed.
+func contentValidationOutdated(catsrc *v1alpha1.CatalogSource) bool {
+	validation := catsrc.Status.ContentValidation
+	return validation == nil || validation.LastValidated.Before(&catsrc.Status.LastSync)
+}
+
+// syncCatalogContentValidation validates the content of a CatalogSource whose validation is outdated, and records the
+// result in its status. Errors querying the registry server are returned, so that the validation is retried.
+func (o *Operator) syncCatalogContentValidation(obj interface{}) error {
+	catsrc, ok := obj.(*v1alpha1.CatalogSource)
+	if !ok {
+		o.logger.Debugf("wrong type: %#v", obj)
+		return fmt.Errorf("casting CatalogSource failed")
+	}
+	if !contentValidationOutdated(catsrc) {
+		return nil
+	}
+
+	logger := o.logger.WithFields(logrus.Fields{
+		"source": catsrc.GetName(),
+		"id":     queueinformer.NewLoopID(),
+	})
+
+	// Sources that aren't healthy are queued again by their sync once they are
+	source, ok := o.sources.Get(connection.Key(catsrc))
+	if !ok || !source.Healthy() {
+		logger.Debug("registry server isn't healthy, skipping catalog content validation")
+		return nil
+	}
+	client, ok := source.Client.(*resolver.RegistryClient)
+	if !ok {
+		logger.Warnf("can't validate catalog content served to a %T", source.Client)
+		return nil
+	}
+
+	logger.Debug("validating catalog content")
+	ctx, cancel := context.WithTimeout(context.TODO(), catalogValidationTimeout)
+	defer cancel()
+	validation, err := validateCatalog(ctx, client.Registry, resolver.CatalogKey{Name: catsrc.GetName(), Namespace: catsrc.GetNamespace()}, o.now())
+	if err != nil {
+		logger.WithError(err).Warn("couldn't validate catalog content")
+		return err
+	}
+	if validation.ProblemCount > 0 {
+		logger.WithField("problems", validation.ProblemCount).Warn("catalog content is invalid")
+	}
+
+	out := catsrc.DeepCopy()
+	out.Status.ContentValidation = validation
+	_, err = o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out)
+	return err
+}
+
+// catalogValidator walks the content of a catalog over the registry API, and summarizes the problems that would
+// otherwise only surface when the content is resolved.
+type catalogValidator struct {
+	client     api.RegistryClient
+	sourceKey  resolver.CatalogKey
+	validation *v1alpha1.CatalogContentValidation
+}
+
+// validateCatalog validates every package, channel and bundle of the catalog served by the given registry client. It
+// returns an error only if the registry server can't be queried, in which case the content isn't known.
+func validateCatalog(ctx context.Context, client api.RegistryClient, sourceKey resolver.CatalogKey, now metav1.Time) (*v1alpha1.CatalogContentValidation, error) {
+	v := &catalogValidator{
+		client:     client,
+		sourceKey:  sourceKey,
+		validation: &v1alpha1.CatalogContentValidation{LastValidated: now},
+	}
+
+	stream, err := client.ListPackages(ctx, &api.ListPackageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var packageNames []string
+	for {
+		name, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		packageNames = append(packageNames, name.GetName())
+	}
+
+	for _, name := range packageNames {
+		pkg, err := client.GetPackage(ctx, &api.GetPackageRequest{Name: name})
+		if err != nil {
+			if unreachable(err) {
+				return nil, err
+			}
+			v.problemf("package %s: %s", name, status.Convert(err).Message())
+			continue
+		}
+		if err := v.validatePackage(ctx, pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	return v.validation, nil
+}
+
+// unreachable returns true if the given error means the registry server couldn't answer, rather than that it
+// couldn't find or serve the requested content.
+func unreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return true
+	}
+	return false
+}
+
+func (v *catalogValidator) problemf(format string, args ...interface{}) {
+	v.validation.ProblemCount++
+	if len(v.validation.Problems) < maxCatalogProblemsReported {
+		v.validation.Problems = append(v.validation.Problems, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *catalogValidator) validatePackage(ctx context.Context, pkg *api.Package) error {
+	v.validation.Packages++
+
+	bundles := map[string]struct{}{}
+	defaultChannelFound := false
+	for _, channel := range pkg.GetChannels() {
+		if channel.GetName() == pkg.GetDefaultChannelName() {
+			defaultChannelFound = true
+		}
+		if err := v.validateChannel(ctx, pkg.GetName(), channel, bundles); err != nil {
+			return err
+		}
+	}
+	v.validation.Bundles += len(bundles)
+
+	switch {
+	case len(pkg.GetChannels()) == 0:
+		v.problemf("package %s: no channels", pkg.GetName())
+	case pkg.GetDefaultChannelName() == "":
+		v.problemf("package %s: no default channel", pkg.GetName())
+	case !defaultChannelFound:
+		v.problemf("package %s: default channel %s doesn't exist", pkg.GetName(), pkg.GetDefaultChannelName())
+	}
+
+	return nil
+}
+
+// channelBundle is a bundle of a channel to validate, and the bundle that found it by replacing it, if any.
+type channelBundle struct {
+	name       string
+	replacedBy string
+}
+
+// validateChannel records and validates every bundle of a channel. Bundles are found from the channel's head by
+// following what each bundle replaces, and the channel entries that replace each bundle, so that bundles off the
+// replaces chain of the head, such as those of skipped upgrade paths, are validated too. The replaces chain of the
+// head is checked for cycles.
+func (v *catalogValidator) validateChannel(ctx context.Context, packageName string, channel *api.Channel, bundles map[string]struct{}) error {
+	v.validation.Channels++
+
+	replaces := map[string]string{}
+	queue := []channelBundle{{name: channel.GetCsvName()}}
+	visited := map[string]struct{}{channel.GetCsvName(): {}}
+	visit := func(next channelBundle) {
+		if _, ok := visited[next.name]; next.name != "" && !ok {
+			visited[next.name] = struct{}{}
+			queue = append(queue, next)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		bundle, err := v.client.GetBundle(ctx, &api.GetBundleRequest{PkgName: packageName, ChannelName: channel.GetName(), CsvName: current.name})
+		if err != nil {
+			if unreachable(err) {
+				return err
+			}
+			if current.replacedBy == "" {
+				v.problemf("package %s, channel %s: head %s is missing: %s", packageName, channel.GetName(), current.name, status.Convert(err).Message())
+			} else {
+				v.problemf("package %s, channel %s: %s replaces %s, which is missing: %s", packageName, channel.GetName(), current.replacedBy, current.name, status.Convert(err).Message())
+			}
+			continue
+		}
+		bundles[current.name] = struct{}{}
+
+		replaced, err := v.validateBundle(bundle)
+		if err != nil {
+			v.problemf("package %s, channel %s: bundle %s: %s", packageName, channel.GetName(), current.name, err)
+		} else {
+			replaces[current.name] = replaced
+			visit(channelBundle{name: replaced, replacedBy: current.name})
+		}
+
+		replacements, err := v.replacements(ctx, packageName, channel.GetName(), current.name)
+		if err != nil {
+			return err
+		}
+		for _, replacement := range replacements {
+			visit(channelBundle{name: replacement})
+		}
+	}
+
+	inChain := map[string]struct{}{}
+	for csvName := channel.GetCsvName(); csvName != ""; csvName = replaces[csvName] {
+		if _, ok := inChain[csvName]; ok {
+			v.problemf("package %s, channel %s: replaces cycle through %s", packageName, channel.GetName(), csvName)
+			break
+		}
+		inChain[csvName] = struct{}{}
+	}
+
+	return nil
+}
+
+// replacements returns the names of the bundles of the given channel that replace the given bundle.
+func (v *catalogValidator) replacements(ctx context.Context, packageName, channelName, csvName string) ([]string, error) {
+	stream, err := v.client.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: csvName})
+	if err != nil {
+		if unreachable(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	var names []string
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			if unreachable(err) {
+				return nil, err
+			}
+			// Registry servers report that nothing replaces a bundle as an error
+			return names, nil
+		}
+		if entry.GetPackageName() == packageName && entry.GetChannelName() == channelName {
+			names = append(names, entry.GetBundleName())
+		}
+	}
+}
+
+// validateBundle checks that a bundle can be turned into an operator the way resolution does, and returns the name of
+// the bundle it replaces.
+func (v *catalogValidator) validateBundle(bundle *api.Bundle) (string, error) {
+	b, err := opregistry.NewBundleFromStrings(bundle.GetCsvName(), bundle.GetPackageName(), bundle.GetChannelName(), bundle.GetObject())
+	if err != nil {
+		return "", fmt.Errorf("error parsing objects: %s", err)
+	}
+	csv, err := b.ClusterServiceVersion()
+	if err != nil {
+		return "", fmt.Errorf("error parsing CSV: %s", err)
+	}
+	if csv == nil {
+		return "", fmt.Errorf("no CSV")
+	}
+	if csv.GetName() != bundle.GetCsvName() {
+		return "", fmt.Errorf("CSV is named %s", csv.GetName())
+	}
+	if skipRange, ok := csv.GetAnnotations()[resolver.SkipPackageAnnotationKey]; ok {
+		if _, err := semver.ParseRange(skipRange); err != nil {
+			return "", fmt.Errorf("invalid skipRange %q: %s", skipRange, err)
+		}
+	}
+
+	op, err := resolver.NewOperatorFromBundle(b, "", "", v.sourceKey)
+	if err != nil {
+		return "", err
+	}
+	return op.Replaces(), nil
+}
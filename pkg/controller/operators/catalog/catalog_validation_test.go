@@ -0,0 +1,333 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	registryclient "github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver"
+)
+
+// fakeCatalogServer serves packages and bundles over the registry API. Only the calls made by catalog validation are
+// implemented.
+type fakeCatalogServer struct {
+	api.RegistryServer
+
+	packages []*api.Package
+	bundles  map[string]*api.Bundle
+	entries  []*api.ChannelEntry
+}
+
+func (s *fakeCatalogServer) ListPackages(_ *api.ListPackageRequest, stream api.Registry_ListPackagesServer) error {
+	for _, pkg := range s.packages {
+		if err := stream.Send(&api.PackageName{Name: pkg.GetName()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeCatalogServer) GetPackage(_ context.Context, req *api.GetPackageRequest) (*api.Package, error) {
+	for _, pkg := range s.packages {
+		if pkg.GetName() == req.GetName() {
+			return pkg, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "package %s not found", req.GetName())
+}
+
+func (s *fakeCatalogServer) GetBundle(_ context.Context, req *api.GetBundleRequest) (*api.Bundle, error) {
+	bundle, ok := s.bundles[req.GetCsvName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "bundle %s not found", req.GetCsvName())
+	}
+	return bundle, nil
+}
+
+func (s *fakeCatalogServer) GetChannelEntriesThatReplace(req *api.GetAllReplacementsRequest, stream api.Registry_GetChannelEntriesThatReplaceServer) error {
+	found := false
+	for _, entry := range s.entries {
+		if entry.GetReplaces() != req.GetCsvName() {
+			continue
+		}
+		found = true
+		if err := stream.Send(entry); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return status.Errorf(codes.Unknown, "no channel entries found that replace %s", req.GetCsvName())
+	}
+	return nil
+}
+
+func catalogBundle(t *testing.T, name, replaces string, annotations map[string]string) *api.Bundle {
+	csv := &v1alpha1.ClusterServiceVersion{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha1.ClusterServiceVersionKind,
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+		},
+		Spec: v1alpha1.ClusterServiceVersionSpec{
+			Replaces: replaces,
+		},
+	}
+	data, err := json.Marshal(csv)
+	require.NoError(t, err)
+	return &api.Bundle{CsvName: name, CsvJson: string(data), Object: []string{string(data)}}
+}
+
+func serveCatalog(t *testing.T, server *fakeCatalogServer) (api.RegistryClient, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	api.RegisterRegistryServer(s, server)
+	go s.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+
+	return api.NewRegistryClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func TestValidateCatalog(t *testing.T) {
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	sourceKey := resolver.CatalogKey{Name: "catalog", Namespace: "ns"}
+
+	bundles := func(bundles ...*api.Bundle) map[string]*api.Bundle {
+		m := map[string]*api.Bundle{}
+		for _, b := range bundles {
+			m[b.GetCsvName()] = b
+		}
+		return m
+	}
+	renamed := func(bundle *api.Bundle, name string) *api.Bundle {
+		bundle.CsvName = name
+		return bundle
+	}
+
+	tests := []struct {
+		name   string
+		server *fakeCatalogServer
+		want   *v1alpha1.CatalogContentValidation
+	}{
+		{
+			name: "Valid",
+			server: &fakeCatalogServer{
+				packages: []*api.Package{{
+					Name:               "etcd",
+					DefaultChannelName: "stable",
+					Channels: []*api.Channel{
+						{Name: "stable", CsvName: "etcd.v2"},
+						{Name: "alpha", CsvName: "etcd.v3"},
+					},
+				}},
+				bundles: bundles(
+					catalogBundle(t, "etcd.v1", "", nil),
+					catalogBundle(t, "etcd.v2", "etcd.v1", map[string]string{resolver.SkipPackageAnnotationKey: "<2.0.0"}),
+					catalogBundle(t, "etcd.v3", "etcd.v2", nil),
+				),
+			},
+			want: &v1alpha1.CatalogContentValidation{
+				LastValidated: now,
+				Packages:      1,
+				Channels:      2,
+				Bundles:       3,
+			},
+		},
+		{
+			name: "OffChainBundles",
+			server: &fakeCatalogServer{
+				packages: []*api.Package{{
+					Name:               "etcd",
+					DefaultChannelName: "stable",
+					Channels:           []*api.Channel{{Name: "stable", CsvName: "etcd.v3"}},
+				}},
+				bundles: bundles(
+					catalogBundle(t, "etcd.v1", "", nil),
+					catalogBundle(t, "etcd.v2", "etcd.v1", map[string]string{resolver.SkipPackageAnnotationKey: "not a range"}),
+					catalogBundle(t, "etcd.v3", "etcd.v1", nil),
+					catalogBundle(t, "other.v1", "etcd.v1", nil),
+				),
+				entries: []*api.ChannelEntry{
+					{PackageName: "etcd", ChannelName: "stable", BundleName: "etcd.v2", Replaces: "etcd.v1"},
+					{PackageName: "etcd", ChannelName: "stable", BundleName: "etcd.v3", Replaces: "etcd.v1"},
+					{PackageName: "other", ChannelName: "stable", BundleName: "other.v1", Replaces: "etcd.v1"},
+				},
+			},
+			want: &v1alpha1.CatalogContentValidation{
+				LastValidated: now,
+				Packages:      1,
+				Channels:      1,
+				Bundles:       3,
+				ProblemCount:  1,
+				Problems: []string{
+					"package etcd, channel stable: bundle etcd.v2: invalid skipRange \"not a range\": " +
+						"Could not get version from string: \"not\"",
+				},
+			},
+		},
+		{
+			name: "Invalid",
+			server: &fakeCatalogServer{
+				packages: []*api.Package{
+					{
+						Name:               "missing",
+						DefaultChannelName: "beta",
+						Channels: []*api.Channel{
+							{Name: "stable", CsvName: "missing.v2"},
+							{Name: "alpha", CsvName: "missing.v3"},
+						},
+					},
+					{
+						Name:     "cycle",
+						Channels: []*api.Channel{{Name: "stable", CsvName: "cycle.v2"}},
+					},
+					{
+						Name:               "broken",
+						DefaultChannelName: "stable",
+						Channels: []*api.Channel{
+							{Name: "stable", CsvName: "broken.v1"},
+							{Name: "alpha", CsvName: "broken.v2"},
+							{Name: "beta", CsvName: "broken.v3"},
+						},
+					},
+				},
+				bundles: bundles(
+					catalogBundle(t, "missing.v2", "missing.v1", nil),
+					catalogBundle(t, "cycle.v1", "cycle.v2", nil),
+					catalogBundle(t, "cycle.v2", "cycle.v1", nil),
+					&api.Bundle{CsvName: "broken.v1", Object: []string{"{bad json"}},
+					catalogBundle(t, "broken.v2", "", map[string]string{resolver.SkipPackageAnnotationKey: "not a range"}),
+					renamed(catalogBundle(t, "other.v1", "", nil), "broken.v3"),
+				),
+			},
+			want: &v1alpha1.CatalogContentValidation{
+				LastValidated: now,
+				Packages:      3,
+				Channels:      6,
+				Bundles:       6,
+				ProblemCount:  8,
+				Problems: []string{
+					"package missing, channel stable: missing.v2 replaces missing.v1, which is missing: bundle missing.v1 not found",
+					"package missing, channel alpha: head missing.v3 is missing: bundle missing.v3 not found",
+					"package missing: default channel beta doesn't exist",
+					"package cycle, channel stable: replaces cycle through cycle.v2",
+					"package cycle: no default channel",
+					"package broken, channel stable: bundle broken.v1: error parsing objects: " +
+						"json: line 0: invalid character 'b' looking for beginning of object key string",
+					"package broken, channel alpha: bundle broken.v2: invalid skipRange \"not a range\": " +
+						"Could not get version from string: \"not\"",
+					"package broken, channel beta: bundle broken.v3: CSV is named other.v1",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, stop := serveCatalog(t, tt.server)
+			defer stop()
+
+			validation, err := validateCatalog(context.Background(), client, sourceKey, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validation)
+		})
+	}
+}
+
+func TestValidateCatalogProblemsBounded(t *testing.T) {
+	server := &fakeCatalogServer{bundles: map[string]*api.Bundle{}}
+	for i := 0; i < maxCatalogProblemsReported+5; i++ {
+		server.packages = append(server.packages, &api.Package{Name: fmt.Sprintf("pkg%d", i)})
+	}
+
+	client, stop := serveCatalog(t, server)
+	defer stop()
+
+	validation, err := validateCatalog(context.Background(), client, resolver.CatalogKey{}, metav1.Now())
+	require.NoError(t, err)
+	require.Equal(t, maxCatalogProblemsReported+5, validation.ProblemCount)
+	require.Len(t, validation.Problems, maxCatalogProblemsReported)
+}
+
+func TestValidateCatalogUnreachable(t *testing.T) {
+	client, stop := serveCatalog(t, &fakeCatalogServer{})
+	stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := validateCatalog(ctx, client, resolver.CatalogKey{}, metav1.Now())
+	require.Error(t, err)
+}
+
+func TestContentValidationOutdated(t *testing.T) {
+	synced := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	catsrc := &v1alpha1.CatalogSource{Status: v1alpha1.CatalogSourceStatus{LastSync: synced}}
+	require.True(t, contentValidationOutdated(catsrc))
+
+	catsrc.Status.ContentValidation = &v1alpha1.CatalogContentValidation{LastValidated: metav1.NewTime(synced.Add(-time.Minute))}
+	require.True(t, contentValidationOutdated(catsrc))
+
+	catsrc.Status.ContentValidation.LastValidated = synced
+	require.False(t, contentValidationOutdated(catsrc))
+}
+
+func TestSyncCatalogContentValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	namespace := "ns"
+	catsrc := &v1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: namespace},
+		Spec:       v1alpha1.CatalogSourceSpec{SourceType: v1alpha1.SourceTypeGrpc, Address: "catalog:50051"},
+		Status:     v1alpha1.CatalogSourceStatus{LastSync: metav1.Now()},
+	}
+	op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(catsrc))
+	require.NoError(t, err)
+
+	// Nothing is validated until the source has a healthy connection
+	require.NoError(t, op.syncCatalogContentValidation(catsrc))
+
+	// Registry servers that can't be reached are validated again later
+	client, stop := serveCatalog(t, &fakeCatalogServer{})
+	stop()
+	source := connection.Source{
+		Address:     catsrc.Address(),
+		Client:      &resolver.RegistryClient{Client: &registryclient.Client{Registry: client}},
+		LastHealthy: metav1.Now(),
+	}
+	op.sources.Add(connection.Key(catsrc), source)
+	require.Error(t, op.syncCatalogContentValidation(catsrc))
+
+	client, stop = serveCatalog(t, &fakeCatalogServer{packages: []*api.Package{{Name: "empty"}}})
+	defer stop()
+	source.Client = &resolver.RegistryClient{Client: &registryclient.Client{Registry: client}}
+	op.sources.Add(connection.Key(catsrc), source)
+	require.NoError(t, op.syncCatalogContentValidation(catsrc))
+
+	out, err := op.client.OperatorsV1alpha1().CatalogSources(namespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, out.Status.ContentValidation)
+	require.Equal(t, 1, out.Status.ContentValidation.Packages)
+	require.Equal(t, []string{"package empty: no channels"}, out.Status.ContentValidation.Problems)
+}
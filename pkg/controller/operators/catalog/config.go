@@ -0,0 +1,161 @@
+package catalog
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type OperatorOption func(*operatorConfig)
+
+const (
+	// GreedyResolver resolves namespaces by growing their generation one operator at a time.
+	GreedyResolver = "greedy"
+
+	// SatResolver resolves namespaces by solving the constraints of all of their operators together.
+	SatResolver = "sat"
+
+	// DefaultQueryTimeout is how long a single CatalogSource may take to answer a query during resolution by default.
+	DefaultQueryTimeout = 30 * time.Second
+
+	// DefaultResolutionTimeout is how long the resolution of a namespace may take by default.
+	DefaultResolutionTimeout = 2 * time.Minute
+)
+
+type operatorConfig struct {
+	queryTimeout      time.Duration
+	resolutionTimeout time.Duration
+	resolver          string
+
+	transactionalInstallPlans bool
+
+	allowedStepKinds []string
+	deniedStepKinds  []string
+
+	registryReplicas int32
+
+	shardServerImage string
+
+	urlCatalogRoot  string
+	urlCatalogHosts []string
+}
+
+func (o *operatorConfig) apply(options []OperatorOption) {
+	for _, option := range options {
+		option(o)
+	}
+}
+
+func newInvalidConfigError(name, msg string) error {
+	return errors.Errorf("%s config invalid: %s", name, msg)
+}
+
+func (o *operatorConfig) validate() (err error) {
+	switch {
+	case o.queryTimeout < 0:
+		err = newInvalidConfigError("query timeout", "must be >= 0")
+	case o.resolutionTimeout < 0:
+		err = newInvalidConfigError("resolution timeout", "must be >= 0")
+	case o.resolver != GreedyResolver && o.resolver != SatResolver:
+		err = newInvalidConfigError("resolver", fmt.Sprintf("must be %q or %q", GreedyResolver, SatResolver))
+	case !validStepKinds(o.allowedStepKinds):
+		err = newInvalidConfigError("allowed step kinds", "must be of the form Kind or Kind.group")
+	case !validStepKinds(o.deniedStepKinds):
+		err = newInvalidConfigError("denied step kinds", "must be of the form Kind or Kind.group")
+	case o.registryReplicas < 0:
+		err = newInvalidConfigError("registry replicas", "must be >= 0")
+	case o.urlCatalogRoot != "" && !filepath.IsAbs(o.urlCatalogRoot):
+		err = newInvalidConfigError("url catalog root", "must be an absolute path")
+	}
+
+	return
+}
+
+func defaultOperatorConfig() *operatorConfig {
+	return &operatorConfig{
+		queryTimeout:      DefaultQueryTimeout,
+		resolutionTimeout: DefaultResolutionTimeout,
+		resolver:          GreedyResolver,
+	}
+}
+
+// WithQueryTimeout bounds each query made to a single CatalogSource during resolution. Zero disables the deadline.
+func WithQueryTimeout(timeout time.Duration) OperatorOption {
+	return func(config *operatorConfig) {
+		config.queryTimeout = timeout
+	}
+}
+
+// WithResolutionTimeout bounds the resolution of a whole namespace. Zero disables the deadline.
+func WithResolutionTimeout(timeout time.Duration) OperatorOption {
+	return func(config *operatorConfig) {
+		config.resolutionTimeout = timeout
+	}
+}
+
+// WithResolver selects the resolver namespaces are resolved with, either GreedyResolver or SatResolver.
+func WithResolver(name string) OperatorOption {
+	return func(config *operatorConfig) {
+		config.resolver = name
+	}
+}
+
+// WithTransactionalInstallPlans makes a failed InstallPlan delete the resources it created and restore the ones it
+// updated.
+func WithTransactionalInstallPlans(transactional bool) OperatorOption {
+	return func(config *operatorConfig) {
+		config.transactionalInstallPlans = transactional
+	}
+}
+
+// WithAllowedStepKinds lets InstallPlan steps create or update the given kinds without dedicated handling, written as
+// Kind.group, or just Kind for the core group, on top of a small set of namespaced kinds that are always allowed.
+func WithAllowedStepKinds(kinds ...string) OperatorOption {
+	return func(config *operatorConfig) {
+		config.allowedStepKinds = kinds
+	}
+}
+
+// WithDeniedStepKinds keeps InstallPlan steps from creating or updating the given kinds, written as Kind.group, or
+// just Kind for the core group. Denied kinds take precedence over allowed ones.
+func WithDeniedStepKinds(kinds ...string) OperatorOption {
+	return func(config *operatorConfig) {
+		config.deniedStepKinds = kinds
+	}
+}
+
+// WithRegistryReplicas runs each registry server as a Deployment with the given number of replicas and a
+// PodDisruptionBudget, so that catalogs stay available while registry pods are rolled out or evicted. Zero runs each
+// registry server as a single pod. CatalogSources can set their own number of replicas in their registry pod config.
+func WithRegistryReplicas(replicas int32) OperatorOption {
+	return func(config *operatorConfig) {
+		config.registryReplicas = replicas
+	}
+}
+
+// WithShardServerImage runs the registry servers of configmap CatalogSources backed by several ConfigMaps with the
+// given image, which must provide configmap-shard-server. The ConfigMaps are mounted into them, so together they may
+// hold more than fits in a single ConfigMap.
+func WithShardServerImage(image string) OperatorOption {
+	return func(config *operatorConfig) {
+		config.shardServerImage = image
+	}
+}
+
+// WithURLCatalogRoot lets CatalogSources with a URL load catalogs from file URLs or absolute paths within the given
+// directory. Local catalogs can't be loaded by default, since they're read from the catalog operator's own filesystem.
+func WithURLCatalogRoot(dir string) OperatorOption {
+	return func(config *operatorConfig) {
+		config.urlCatalogRoot = dir
+	}
+}
+
+// WithURLCatalogHosts lets CatalogSources with a URL download catalogs from the given hosts, written as host or
+// host:port. Catalogs can't be downloaded by default, since the requests are made from within the cluster.
+func WithURLCatalogHosts(hosts ...string) OperatorOption {
+	return func(config *operatorConfig) {
+		config.urlCatalogHosts = hosts
+	}
+}
@@ -0,0 +1,196 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1beta1ext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// maxInvalidCRsReported bounds the custom resources listed in the error of an unsafe CRD update.
+	maxInvalidCRsReported = 5
+
+	// crListPageSize is how many custom resources are validated at a time when checking a CRD update.
+	crListPageSize = 500
+)
+
+// crdVersions returns the versions of a CRD, and whether each of them is served.
+func crdVersions(crd *v1beta1ext.CustomResourceDefinition) map[string]bool {
+	versions := map[string]bool{}
+	for _, version := range crd.Spec.Versions {
+		versions[version.Name] = version.Served
+	}
+	if len(versions) == 0 && crd.Spec.Version != "" {
+		versions[crd.Spec.Version] = true
+	}
+	return versions
+}
+
+// crdListVersion returns a version the existing custom resources of a CRD can be listed at, preferring the storage
+// version. It returns false if the CRD serves no version.
+func crdListVersion(crd *v1beta1ext.CustomResourceDefinition) (string, bool) {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage && version.Served {
+			return version.Name, true
+		}
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			return version.Name, true
+		}
+	}
+	return crd.Spec.Version, crd.Spec.Version != ""
+}
+
+// crdVersionSchemas returns the schemas that the versions of a CRD manifest declare for themselves, by version. The CRD
+// type OLM is built with can't hold them, so they're read from the manifest itself.
+func crdVersionSchemas(manifest []byte) (map[string]*v1beta1ext.CustomResourceValidation, error) {
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Name   string                               `json:"name"`
+				Schema *v1beta1ext.CustomResourceValidation `json:"schema,omitempty"`
+			} `json:"versions,omitempty"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(manifest, &crd); err != nil {
+		return nil, err
+	}
+
+	schemas := map[string]*v1beta1ext.CustomResourceValidation{}
+	for _, version := range crd.Spec.Versions {
+		if version.Schema != nil {
+			schemas[version.Name] = version.Schema
+		}
+	}
+	return schemas, nil
+}
+
+// validateCRDUpdate checks that replacing the current CRD with the updated one doesn't break it. The updated CRD must
+// still serve every version custom resources have been stored at, and every existing custom resource must be valid
+// under the schema of every version the updated CRD serves. Versions use their own schema from versionSchemas if they
+// have one, and the CRD's otherwise. The returned error describes everything the update would break.
+func (o *Operator) validateCRDUpdate(current, updated *v1beta1ext.CustomResourceDefinition, versionSchemas map[string]*v1beta1ext.CustomResourceValidation) error {
+	var problems []string
+
+	// Stored versions can't be dropped, or the objects stored at them become unreadable
+	updatedVersions := crdVersions(updated)
+	var dropped []string
+	for _, version := range current.Status.StoredVersions {
+		if served, ok := updatedVersions[version]; !ok || !served {
+			dropped = append(dropped, version)
+		}
+	}
+	if len(dropped) > 0 {
+		problems = append(problems, fmt.Sprintf("stored versions %s would no longer be served", strings.Join(dropped, ", ")))
+	}
+
+	invalid, count, err := o.invalidCustomResources(current, updated, versionSchemas)
+	if err != nil {
+		return fmt.Errorf("error checking whether updating CRD %s is safe: %s", current.GetName(), err)
+	}
+	if count > len(invalid) {
+		invalid = append(invalid, fmt.Sprintf("and %d more", count-len(invalid)))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, fmt.Sprintf("existing custom resources would be invalid: %s", strings.Join(invalid, "; ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("refusing to update CRD %s: %s", current.GetName(), strings.Join(problems, ", and "))
+	}
+	return nil
+}
+
+// versionValidators returns a function validating custom resources against the schema of each version the updated
+// CRD serves, if it has one.
+func versionValidators(updated *v1beta1ext.CustomResourceDefinition, versionSchemas map[string]*v1beta1ext.CustomResourceValidation) (map[string]func(cr interface{}) error, error) {
+	validators := map[string]func(cr interface{}) error{}
+	for version, served := range crdVersions(updated) {
+		if !served {
+			continue
+		}
+		schema, ok := versionSchemas[version]
+		if !ok {
+			schema = updated.Spec.Validation
+		}
+		if schema == nil {
+			continue
+		}
+
+		var validation apiextensions.CustomResourceValidation
+		if err := v1beta1ext.Convert_v1beta1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(schema, &validation, nil); err != nil {
+			return nil, err
+		}
+		validator, _, err := apiservervalidation.NewSchemaValidator(&validation)
+		if err != nil {
+			return nil, err
+		}
+		validators[version] = func(cr interface{}) error {
+			return apiservervalidation.ValidateCustomResource(cr, validator)
+		}
+	}
+	return validators, nil
+}
+
+// invalidCustomResources validates the existing custom resources of the current CRD against the schemas of the
+// versions the updated CRD serves. It describes the first few custom resources that aren't valid, and counts all of
+// them. Custom resources are listed a page at a time, so that CRDs with many of them don't have to fit in memory.
+func (o *Operator) invalidCustomResources(current, updated *v1beta1ext.CustomResourceDefinition, versionSchemas map[string]*v1beta1ext.CustomResourceValidation) ([]string, int, error) {
+	validators, err := versionValidators(updated, versionSchemas)
+	if err != nil || len(validators) == 0 {
+		return nil, 0, err
+	}
+	listVersion, ok := crdListVersion(current)
+	if !ok {
+		return nil, 0, nil
+	}
+	versions := make([]string, 0, len(validators))
+	for version := range validators {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	var invalid []string
+	count := 0
+	gvk := schema.GroupVersionKind{Group: current.Spec.Group, Version: listVersion, Kind: current.Spec.Names.Kind}
+	options := metav1.ListOptions{Limit: crListPageSize}
+	for {
+		crs, err := o.unstructuredClient.ListUnstructured(gvk, "", options)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, cr := range crs.Items {
+			var failures []string
+			for _, version := range versions {
+				if err := validators[version](cr.UnstructuredContent()); err != nil {
+					failures = append(failures, fmt.Sprintf("%s: %s", version, err))
+				}
+			}
+			if len(failures) == 0 {
+				continue
+			}
+
+			count++
+			if len(invalid) < maxInvalidCRsReported {
+				name := cr.GetName()
+				if cr.GetNamespace() != "" {
+					name = cr.GetNamespace() + "/" + name
+				}
+				invalid = append(invalid, fmt.Sprintf("%s at %s", name, strings.Join(failures, ", at ")))
+			}
+		}
+
+		if options.Continue = crs.GetContinue(); options.Continue == "" {
+			return invalid, count, nil
+		}
+	}
+}
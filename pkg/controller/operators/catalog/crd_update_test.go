@@ -0,0 +1,164 @@
+package catalog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1beta1ext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestValidateCRDUpdate(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "cluster.com", Version: "v1alpha1", Kind: "Cluster"}
+
+	crdWithVersions := func(stored []string, versions ...v1beta1ext.CustomResourceDefinitionVersion) *v1beta1ext.CustomResourceDefinition {
+		return &v1beta1ext.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusters.cluster.com"},
+			Spec: v1beta1ext.CustomResourceDefinitionSpec{
+				Group:    gvk.Group,
+				Versions: versions,
+				Names:    v1beta1ext.CustomResourceDefinitionNames{Kind: gvk.Kind, Plural: "clusters"},
+				Scope:    v1beta1ext.NamespaceScoped,
+			},
+			Status: v1beta1ext.CustomResourceDefinitionStatus{StoredVersions: stored},
+		}
+	}
+	maxSize := func(max float64) *v1beta1ext.CustomResourceValidation {
+		return &v1beta1ext.CustomResourceValidation{
+			OpenAPIV3Schema: &v1beta1ext.JSONSchemaProps{
+				Properties: map[string]v1beta1ext.JSONSchemaProps{
+					"spec": {
+						Properties: map[string]v1beta1ext.JSONSchemaProps{
+							"size": {Type: "integer", Maximum: &max},
+						},
+					},
+				},
+			},
+		}
+	}
+	withMaxSize := func(crd *v1beta1ext.CustomResourceDefinition, max float64) *v1beta1ext.CustomResourceDefinition {
+		crd.Spec.Validation = maxSize(max)
+		return crd
+	}
+	cr := func(name string, size int64) *unstructured.Unstructured {
+		return unstructuredObject(gvk, "ns", name, map[string]interface{}{"spec": map[string]interface{}{"size": size}})
+	}
+	v1alpha1 := v1beta1ext.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true, Storage: true}
+	v1alpha2 := v1beta1ext.CustomResourceDefinitionVersion{Name: "v1alpha2", Served: true, Storage: true}
+	v1alpha1Unserved := v1beta1ext.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: false}
+	manyCRs := make([]*unstructured.Unstructured, 2*crListPageSize+1)
+	for i := range manyCRs {
+		manyCRs[i] = cr(fmt.Sprintf("cr-%04d", i), 1)
+	}
+	manyCRs[2*crListPageSize] = cr("last", 5)
+
+	tests := []struct {
+		name           string
+		current        *v1beta1ext.CustomResourceDefinition
+		updated        *v1beta1ext.CustomResourceDefinition
+		versionSchemas map[string]*v1beta1ext.CustomResourceValidation
+		crs            []*unstructured.Unstructured
+		wantErr        string
+		wantLists      int
+	}{
+		{
+			name:    "NewVersion/NoValidation",
+			current: crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated: crdWithVersions(nil, v1alpha1, v1alpha2),
+			crs:     []*unstructured.Unstructured{cr("a", 5)},
+		},
+		{
+			name:    "StoredVersionDropped",
+			current: crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated: crdWithVersions(nil, v1alpha2),
+			wantErr: "refusing to update CRD clusters.cluster.com: stored versions v1alpha1 would no longer be served",
+		},
+		{
+			name:    "StoredVersionNoLongerServed",
+			current: crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated: crdWithVersions(nil, v1alpha1Unserved, v1alpha2),
+			wantErr: "refusing to update CRD clusters.cluster.com: stored versions v1alpha1 would no longer be served",
+		},
+		{
+			name:    "ValidationTightened/CRsValid",
+			current: crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated: withMaxSize(crdWithVersions(nil, v1alpha1), 3),
+			crs:     []*unstructured.Unstructured{cr("a", 1), cr("b", 3)},
+		},
+		{
+			name:    "ValidationTightened/CRsInvalid",
+			current: crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated: withMaxSize(crdWithVersions(nil, v1alpha2), 3),
+			crs:     []*unstructured.Unstructured{cr("a", 1), cr("b", 5)},
+			wantErr: "refusing to update CRD clusters.cluster.com: stored versions v1alpha1 would no longer be served, and " +
+				"existing custom resources would be invalid: ns/b at v1alpha2: validation failure list:\nspec.size in body should be less than or equal to 3",
+		},
+		{
+			name:    "ValidationTightened/ManyCRsInvalid",
+			current: crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated: withMaxSize(crdWithVersions(nil, v1alpha1), 0),
+			crs:     []*unstructured.Unstructured{cr("a", 1), cr("b", 1), cr("c", 1), cr("d", 1), cr("e", 1), cr("f", 1), cr("g", 1)},
+			wantErr: "and 2 more",
+		},
+		{
+			name:           "ValidationTightened/VersionSchema",
+			current:        crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated:        withMaxSize(crdWithVersions(nil, v1alpha1, v1beta1ext.CustomResourceDefinitionVersion{Name: "v1alpha2", Served: true}), 10),
+			versionSchemas: map[string]*v1beta1ext.CustomResourceValidation{"v1alpha2": maxSize(3)},
+			crs:            []*unstructured.Unstructured{cr("a", 1), cr("b", 5)},
+			wantErr: "existing custom resources would be invalid: ns/b at v1alpha2: validation failure list:\n" +
+				"spec.size in body should be less than or equal to 3",
+		},
+		{
+			name:      "ValidationTightened/CRsPaged",
+			current:   crdWithVersions([]string{"v1alpha1"}, v1alpha1),
+			updated:   withMaxSize(crdWithVersions(nil, v1alpha1), 3),
+			crs:       manyCRs,
+			wantErr:   "existing custom resources would be invalid: ns/last at v1alpha1",
+			wantLists: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeUnstructuredClient(tt.crs...)
+			client.namespaced[gvk] = true
+			op := &Operator{unstructuredClient: client}
+
+			err := op.validateCRDUpdate(tt.current, tt.updated, tt.versionSchemas)
+			if tt.wantLists > 0 {
+				require.Equal(t, tt.wantLists, client.lists)
+			}
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCRDVersionSchemas(t *testing.T) {
+	manifest := `{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind": "CustomResourceDefinition",
+		"metadata": {"name": "clusters.cluster.com"},
+		"spec": {
+			"versions": [
+				{"name": "v1alpha1", "served": true, "storage": false},
+				{"name": "v1alpha2", "served": true, "storage": true, "schema": {"openAPIV3Schema": {"required": ["spec"]}}}
+			]
+		}
+	}`
+
+	schemas, err := crdVersionSchemas([]byte(manifest))
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+	require.Equal(t, []string{"spec"}, schemas["v1alpha2"].OpenAPIV3Schema.Required)
+
+	_, err = crdVersionSchemas([]byte("{"))
+	require.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	errorwrap "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver"
+)
+
+// dryRunResult is the outcome of resolving a dry-run Subscription, recorded as json on the Subscription.
+type dryRunResult struct {
+	// Steps are the resources the InstallPlan for the resolution would create or update.
+	Steps []dryRunStep `json:"steps,omitempty"`
+
+	// Bundles are the bundles chosen for each new or updated operator.
+	Bundles []dryRunBundle `json:"bundles,omitempty"`
+
+	// Dropped are the names of operators removed from the resolution because their required apis couldn't be satisfied.
+	Dropped []string `json:"dropped,omitempty"`
+
+	// Problems describe everything that prevented the resolution from fully succeeding.
+	Problems []string `json:"problems,omitempty"`
+
+	// Error is set if the resolution failed.
+	Error string `json:"error,omitempty"`
+}
+
+type dryRunStep struct {
+	Resolving string `json:"resolving"`
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
+type dryRunBundle struct {
+	Package                string `json:"package"`
+	Channel                string `json:"channel,omitempty"`
+	CatalogSource          string `json:"catalogSource"`
+	CatalogSourceNamespace string `json:"catalogSourceNamespace"`
+	Bundle                 string `json:"bundle"`
+}
+
+// newDryRunResult summarizes a resolver preview. Everything is sorted so that repeated resolutions produce identical results.
+func newDryRunResult(preview *resolver.Preview, err error) *dryRunResult {
+	result := &dryRunResult{}
+	if err != nil {
+		result.Error = err.Error()
+		if resErr, ok := errorwrap.Cause(err).(resolver.ResolutionError); ok {
+			result.Problems = resErr.Details()
+		}
+		return result
+	}
+
+	for _, step := range preview.Steps {
+		result.Steps = append(result.Steps, dryRunStep{
+			Resolving: step.Resolving,
+			Group:     step.Resource.Group,
+			Version:   step.Resource.Version,
+			Kind:      step.Resource.Kind,
+			Name:      step.Resource.Name,
+		})
+	}
+	sort.Slice(result.Steps, func(i, j int) bool {
+		a, b := result.Steps[i], result.Steps[j]
+		if a.Resolving != b.Resolving {
+			return a.Resolving < b.Resolving
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	for info, bundle := range preview.Bundles {
+		result.Bundles = append(result.Bundles, dryRunBundle{
+			Package:                info.Package,
+			Channel:                info.Channel,
+			CatalogSource:          info.Catalog.Name,
+			CatalogSourceNamespace: info.Catalog.Namespace,
+			Bundle:                 bundle.Name,
+		})
+	}
+	sort.Slice(result.Bundles, func(i, j int) bool {
+		return result.Bundles[i].Bundle < result.Bundles[j].Bundle
+	})
+
+	for name := range preview.Downgraded {
+		result.Dropped = append(result.Dropped, name)
+	}
+	sort.Strings(result.Dropped)
+
+	if len(preview.Problems) > 0 {
+		result.Problems = resolver.ResolutionError{Problems: preview.Problems}.Details()
+	}
+
+	return result
+}
+
+// splitDryRunSubscriptions separates dry-run Subscriptions from the Subscriptions that should be resolved and installed.
+func splitDryRunSubscriptions(all []*v1alpha1.Subscription) (subs, dryRunSubs []*v1alpha1.Subscription) {
+	for _, sub := range all {
+		if sub.IsDryRun() {
+			dryRunSubs = append(dryRunSubs, sub)
+		} else {
+			subs = append(subs, sub)
+		}
+	}
+	return
+}
+
+// dryRunKey identifies what the resolution of a dry-run Subscription depends on: its generation, the last time the
+// catalogs it's resolved against changed, and the operators installed by the other Subscriptions in its namespace.
+func dryRunKey(sub *v1alpha1.Subscription, sourcesLastUpdate metav1.Time, sources []resolver.CatalogKey, subs []*v1alpha1.Subscription) string {
+	catalogs := make([]string, 0, len(sources))
+	for _, key := range sources {
+		catalogs = append(catalogs, key.String())
+	}
+	sort.Strings(catalogs)
+
+	installed := make([]string, 0, len(subs))
+	for _, s := range subs {
+		installed = append(installed, s.Status.InstalledCSV)
+	}
+	sort.Strings(installed)
+
+	// The key's fields are plain strings and numbers, which always marshal
+	data, _ := json.Marshal(struct {
+		Generation int64
+		LastUpdate string
+		Catalogs   []string
+		Installed  []string
+	}{sub.GetGeneration(), sourcesLastUpdate.UTC().Format(time.RFC3339Nano), catalogs, installed})
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+// syncDryRunSubscriptions previews the resolution of each dry-run Subscription and records the result on it.
+// Subscriptions are only resolved again once their key changes, see dryRunKey.
+// Neither an InstallPlan nor the status of any Subscription is written.
+func (o *Operator) syncDryRunSubscriptions(ctx context.Context, logger *logrus.Entry, namespace string, querier resolver.SourceQuerier, sources []resolver.CatalogKey, subs, dryRunSubs []*v1alpha1.Subscription) {
+	sourcesLastUpdate := o.sources.LastUpdate()
+	for _, sub := range dryRunSubs {
+		logger := logger.WithField("sub", sub.GetName())
+
+		key := dryRunKey(sub, sourcesLastUpdate, sources, subs)
+		if sub.GetAnnotations()[v1alpha1.SubscriptionDryRunKeyAnnotationKey] == key {
+			logger.Debug("dry-run result up to date")
+			continue
+		}
+
+		preview, err := o.resolver.PreviewSteps(ctx, namespace, querier, sub)
+		if err != nil {
+			logger.WithError(err).Debug("dry-run resolution failed")
+		}
+
+		raw, err := json.Marshal(newDryRunResult(preview, err))
+		if err != nil {
+			logger.WithError(err).Warn("couldn't serialize dry-run result")
+			continue
+		}
+
+		out := sub.DeepCopy()
+		annotations := out.GetAnnotations()
+		annotations[v1alpha1.SubscriptionDryRunResultAnnotationKey] = string(raw)
+		annotations[v1alpha1.SubscriptionDryRunKeyAnnotationKey] = key
+		out.SetAnnotations(annotations)
+		if _, err := o.client.OperatorsV1alpha1().Subscriptions(namespace).Update(out); err != nil {
+			logger.WithError(err).Warn("couldn't record dry-run result")
+			continue
+		}
+		logger.Debug("recorded dry-run result")
+	}
+}
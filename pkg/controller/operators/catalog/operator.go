@@ -6,7 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"sync"
+	"strings"
 	"time"
 
 	registryclient "github.com/operator-framework/operator-registry/pkg/client"
@@ -16,22 +16,27 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	v1beta1ext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	extinf "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilclock "k8s.io/apimachinery/pkg/util/clock"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/reference"
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/informers/externalversions"
 	olmerrors "github.com/operator-framework/operator-lifecycle-manager/pkg/controller/errors"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/operators/catalog/subscription"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/reconciler"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver"
 	index "github.com/operator-framework/operator-lifecycle-manager/pkg/lib/index"
@@ -54,31 +59,56 @@ const (
 	generatedByKey         = "olm.generated-by"
 )
 
+// registryDialer connects to registry servers with the client the resolver queries them with.
+var registryDialer = connection.Dialer{
+	Dial: func(address string) (connection.Client, error) {
+		client, err := resolver.NewRegistryClient(address)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	},
+	Unrecoverable: registryclient.IsErrorUnrecoverable,
+}
+
 // Operator represents a Kubernetes operator that executes InstallPlans by
 // resolving dependencies in a catalog.
 type Operator struct {
 	queueinformer.Operator
 
-	logger                 *logrus.Logger
-	clock                  utilclock.Clock
-	opClient               operatorclient.ClientInterface
-	client                 versioned.Interface
-	lister                 operatorlister.OperatorLister
-	catsrcQueueSet         *queueinformer.ResourceQueueSet
-	subQueueSet            *queueinformer.ResourceQueueSet
-	ipQueueSet             *queueinformer.ResourceQueueSet
-	nsResolveQueue         workqueue.RateLimitingInterface
-	namespace              string
-	sources                map[resolver.CatalogKey]resolver.SourceRef
-	sourcesLock            sync.RWMutex
-	sourcesLastUpdate      metav1.Time
-	resolver               resolver.Resolver
-	reconciler             reconciler.RegistryReconcilerFactory
-	csvProvidedAPIsIndexer map[string]cache.Indexer
+	logger         *logrus.Logger
+	clock          utilclock.Clock
+	opClient       operatorclient.ClientInterface
+	client         versioned.Interface
+	lister         operatorlister.OperatorLister
+	catsrcQueueSet *queueinformer.ResourceQueueSet
+	// catsrcValidationQueueSet holds the CatalogSources whose content is waiting to be validated
+	catsrcValidationQueueSet *queueinformer.ResourceQueueSet
+	subQueueSet              *queueinformer.ResourceQueueSet
+	ipQueueSet               *queueinformer.ResourceQueueSet
+	nsResolveQueue           workqueue.RateLimitingInterface
+	namespace                string
+	sources                  *connection.Pool
+	resolver                 resolver.Resolver
+	reconciler               reconciler.RegistryReconcilerFactory
+	csvProvidedAPIsIndexer   map[string]cache.Indexer
+	queryTimeout             time.Duration
+	resolutionTimeout        time.Duration
+	// transactionalInstallPlans rolls back the steps of InstallPlans that fail
+	transactionalInstallPlans bool
+	// unstructuredClient executes the steps of kinds without dedicated handling, as allowed by stepKinds
+	unstructuredClient operatorclient.UnstructuredClient
+	stepKinds          stepKindPolicy
 }
 
 // NewOperator creates a new Catalog Operator.
-func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clock, logger *logrus.Logger, resyncPeriod time.Duration, configmapRegistryImage, operatorNamespace string, watchedNamespaces ...string) (*Operator, error) {
+func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clock, logger *logrus.Logger, resyncPeriod time.Duration, configmapRegistryImage, operatorNamespace string, watchedNamespaces []string, options ...OperatorOption) (*Operator, error) {
+	config := defaultOperatorConfig()
+	config.apply(options)
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
 	// Default to watching all namespaces.
 	if len(watchedNamespaces) == 0 {
 		watchedNamespaces = []string{metav1.NamespaceAll}
@@ -102,20 +132,36 @@ func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clo
 
 	// Allocate the new instance of an Operator.
 	op := &Operator{
-		Operator:               queueOperator,
-		logger:                 logger,
-		clock:                  clock,
-		opClient:               opClient,
-		client:                 crClient,
-		lister:                 lister,
-		namespace:              operatorNamespace,
-		sources:                make(map[resolver.CatalogKey]resolver.SourceRef),
-		resolver:               resolver.NewOperatorsV1alpha1Resolver(lister),
-		catsrcQueueSet:         queueinformer.NewEmptyResourceQueueSet(),
-		subQueueSet:            queueinformer.NewEmptyResourceQueueSet(),
-		csvProvidedAPIsIndexer: map[string]cache.Indexer{},
-	}
-	op.reconciler = reconciler.NewRegistryReconcilerFactory(lister, opClient, configmapRegistryImage, op.now)
+		Operator:                  queueOperator,
+		logger:                    logger,
+		clock:                     clock,
+		opClient:                  opClient,
+		client:                    crClient,
+		lister:                    lister,
+		namespace:                 operatorNamespace,
+		catsrcQueueSet:            queueinformer.NewEmptyResourceQueueSet(),
+		catsrcValidationQueueSet:  queueinformer.NewEmptyResourceQueueSet(),
+		subQueueSet:               queueinformer.NewEmptyResourceQueueSet(),
+		csvProvidedAPIsIndexer:    map[string]cache.Indexer{},
+		queryTimeout:              config.queryTimeout,
+		resolutionTimeout:         config.resolutionTimeout,
+		transactionalInstallPlans: config.transactionalInstallPlans,
+		unstructuredClient:        operatorclient.NewUnstructuredClient(opClient.KubernetesInterface().Discovery(), opClient.KubernetesInterface().CoreV1().RESTClient()),
+		stepKinds:                 newStepKindPolicy(config.allowedStepKinds, config.deniedStepKinds),
+	}
+	switch config.resolver {
+	case SatResolver:
+		op.resolver = resolver.NewSatResolver(lister)
+	default:
+		op.resolver = resolver.NewOperatorsV1alpha1Resolver(lister)
+	}
+	op.sources = connection.NewPool("catalog-operator", op.now, registryDialer)
+	op.reconciler = reconciler.NewRegistryReconcilerFactory(lister, opClient, configmapRegistryImage, op.now,
+		reconciler.WithRegistryReplicas(config.registryReplicas),
+		reconciler.WithShardServerImage(config.shardServerImage),
+		reconciler.WithConnectionPool(op.sources),
+		reconciler.WithCatalogLoader(&inprocess.Loader{Root: config.urlCatalogRoot, Hosts: config.urlCatalogHosts}),
+	)
 
 	// Set up syncing for namespace-scoped resources
 	for _, namespace := range watchedNamespaces {
@@ -159,13 +205,28 @@ func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clo
 			queueinformer.WithLogger(op.logger),
 			queueinformer.WithQueue(catsrcQueue),
 			queueinformer.WithInformer(catsrcInformer.Informer()),
-			queueinformer.WithSyncer(queueinformer.LegacySyncHandler(op.syncCatalogSources).ToSyncerWithDelete(op.handleCatSrcDeletion)),
+			queueinformer.WithSyncer(queueinformer.ContextSyncHandler(op.syncCatalogSources).ToSyncerWithDelete(op.handleCatSrcDeletion)),
 		)
 		if err != nil {
 			return nil, err
 		}
 		op.RegisterQueueInformer(catsrcQueueInformer)
 
+		// Validate catalog content apart from syncing CatalogSources, since walking a large catalog takes a while
+		catsrcValidationQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("%s/catsrc-validations", namespace))
+		op.catsrcValidationQueueSet.Set(namespace, catsrcValidationQueue)
+		catsrcValidationQueueInformer, err := queueinformer.NewQueueInformer(
+			ctx,
+			queueinformer.WithLogger(op.logger),
+			queueinformer.WithQueue(catsrcValidationQueue),
+			queueinformer.WithIndexer(catsrcInformer.Informer().GetIndexer()),
+			queueinformer.WithSyncer(queueinformer.LegacySyncHandler(op.syncCatalogContentValidation).ToSyncer()),
+		)
+		if err != nil {
+			return nil, err
+		}
+		op.RegisterQueueInformer(catsrcValidationQueueInformer)
+
 		// Wire Subscriptions
 		subInformer := crInformerFactory.Operators().V1alpha1().Subscriptions()
 		op.lister.OperatorsV1alpha1().RegisterSubscriptionLister(namespace, subInformer.Lister())
@@ -180,7 +241,7 @@ func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clo
 			subscription.WithCatalogInformer(catsrcInformer.Informer()),
 			subscription.WithSubscriptionQueue(subQueue),
 			subscription.WithAppendedReconcilers(subscription.ReconcilerFromLegacySyncHandler(op.syncSubscriptions, nil)),
-			subscription.WithRegistryReconcilerFactory(op.reconciler),
+			subscription.WithRegistryReconcilerFactory(reconciler.WithRecordedHealth(op.reconciler)),
 			subscription.WithGlobalCatalogNamespace(op.namespace),
 		)
 		if err != nil {
@@ -233,6 +294,11 @@ func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clo
 		op.lister.CoreV1().RegisterConfigMapLister(namespace, configMapInformer.Lister())
 		informers = append(informers, configMapInformer.Informer())
 
+		// Wire Deployments
+		deploymentInformer := k8sInformerFactory.Apps().V1().Deployments()
+		op.lister.AppsV1().RegisterDeploymentLister(namespace, deploymentInformer.Lister())
+		informers = append(informers, deploymentInformer.Informer())
+
 		// Generate and register QueueInformers for k8s resources
 		k8sSyncer := queueinformer.LegacySyncHandler(op.syncObject).ToSyncerWithDelete(op.handleDeletion)
 		for _, informer := range informers {
@@ -253,6 +319,20 @@ func NewOperator(ctx context.Context, kubeconfigPath string, clock utilclock.Clo
 
 	}
 
+	// Register CatalogPolicy QueueInformer, since catalog policies restrict the catalogs namespaces resolve against
+	catalogPolicyInformer := externalversions.NewSharedInformerFactory(op.client, resyncPeriod).Operators().V1().CatalogPolicies()
+	op.lister.OperatorsV1().RegisterCatalogPolicyLister(catalogPolicyInformer.Lister())
+	catalogPolicyQueueInformer, err := queueinformer.NewQueueInformer(
+		ctx,
+		queueinformer.WithLogger(op.logger),
+		queueinformer.WithInformer(catalogPolicyInformer.Informer()),
+		queueinformer.WithSyncer(queueinformer.LegacySyncHandler(op.syncCatalogPolicies).ToSyncerWithDelete(op.handleCatalogPolicyDeletion)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	op.RegisterQueueInformer(catalogPolicyQueueInformer)
+
 	// Register CustomResourceDefinition QueueInformer
 	crdInformer := extinf.NewSharedInformerFactory(op.opClient.ApiextensionsV1beta1Interface(), resyncPeriod).Apiextensions().V1beta1().CustomResourceDefinitions()
 	op.lister.APIExtensionsV1beta1().RegisterCustomResourceDefinitionLister(crdInformer.Lister())
@@ -379,20 +459,75 @@ func (o *Operator) handleCatSrcDeletion(obj interface{}) {
 		}
 	}
 	sourceKey := resolver.CatalogKey{Name: catsrc.GetName(), Namespace: catsrc.GetNamespace()}
-	func() {
-		o.sourcesLock.Lock()
-		defer o.sourcesLock.Unlock()
-		if s, ok := o.sources[sourceKey]; ok {
-			if err := s.Client.Close(); err != nil {
-				o.logger.WithError(err).Warn("error closing client")
+	if err := o.sources.Remove(connection.Key(catsrc)); err != nil {
+		o.logger.WithError(err).Warn("error closing client")
+	}
+	o.logger.WithField("source", sourceKey).Info("removed client for deleted catalogsource")
+
+	// Registry servers that aren't owned by the catalog source are removed explicitly
+	if source, ok := catsrc.(*v1alpha1.CatalogSource); ok {
+		if remover, ok := o.reconciler.ReconcilerForSource(source).(reconciler.RegistryRemover); ok {
+			if err := remover.RemoveRegistryServer(source); err != nil {
+				o.logger.WithError(err).WithField("source", sourceKey).Warn("error removing registry server")
 			}
 		}
-		delete(o.sources, sourceKey)
-	}()
-	o.logger.WithField("source", sourceKey).Info("removed client for deleted catalogsource")
+	}
 }
 
-func (o *Operator) syncCatalogSources(obj interface{}) (syncError error) {
+// syncCatalogPolicies resolves the namespaces a CatalogPolicy applies to again, since it may have changed.
+func (o *Operator) syncCatalogPolicies(obj interface{}) error {
+	policy, ok := obj.(*operatorsv1.CatalogPolicy)
+	if !ok {
+		o.logger.Debugf("wrong type: %#v", obj)
+		return fmt.Errorf("casting CatalogPolicy failed")
+	}
+
+	return o.requeueCatalogPolicyNamespaces(policy)
+}
+
+func (o *Operator) handleCatalogPolicyDeletion(obj interface{}) {
+	policy, ok := obj.(*operatorsv1.CatalogPolicy)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("Couldn't get object from tombstone %#v", obj))
+			return
+		}
+
+		policy, ok = tombstone.Obj.(*operatorsv1.CatalogPolicy)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("Tombstone contained object that is not a CatalogPolicy %#v", obj))
+			return
+		}
+	}
+
+	// the namespaces may resolve against catalogs the CatalogPolicy didn't allow
+	if err := o.requeueCatalogPolicyNamespaces(policy); err != nil {
+		utilruntime.HandleError(err)
+	}
+}
+
+// requeueCatalogPolicyNamespaces resolves every namespace with subscriptions that the given CatalogPolicy applies to.
+func (o *Operator) requeueCatalogPolicyNamespaces(policy *operatorsv1.CatalogPolicy) error {
+	subs, err := o.lister.OperatorsV1alpha1().SubscriptionLister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	namespaces := map[string]struct{}{}
+	for _, sub := range subs {
+		namespaces[sub.GetNamespace()] = struct{}{}
+	}
+	for namespace := range namespaces {
+		if policy.AppliesTo(namespace) {
+			o.nsResolveQueue.Add(namespace)
+		}
+	}
+
+	return nil
+}
+
+func (o *Operator) syncCatalogSources(ctx context.Context, obj interface{}) (syncError error) {
 	catsrc, ok := obj.(*v1alpha1.CatalogSource)
 	if !ok {
 		o.logger.Debugf("wrong type: %#v", obj)
@@ -410,30 +545,27 @@ func (o *Operator) syncCatalogSources(obj interface{}) (syncError error) {
 	if catsrc.Spec.SourceType == v1alpha1.SourceTypeInternal || catsrc.Spec.SourceType == v1alpha1.SourceTypeConfigmap {
 		logger.Debug("checking catsrc configmap state")
 
-		// Get the catalog source's config map
-		configMap, err := o.lister.CoreV1().ConfigMapLister().ConfigMaps(catsrc.GetNamespace()).Get(catsrc.Spec.ConfigMap)
-		if err != nil {
-			return fmt.Errorf("failed to get catalog config map %s: %s", catsrc.Spec.ConfigMap, err)
+		// Get the catalog source's config maps
+		configMaps, err := reconciler.ConfigMapShards(o.lister, catsrc)
+		if shardErr, ok := err.(*reconciler.ConfigMapShardError); ok {
+			return fmt.Errorf("failed to get catalog config map %s: %s", shardErr.Name, shardErr.Err)
+		} else if err != nil {
+			return fmt.Errorf("failed to get catalog config maps: %s", err)
 		}
 
-		if wasOwned := ownerutil.EnsureOwner(configMap, catsrc); !wasOwned {
-			configMap, err = o.opClient.KubernetesInterface().CoreV1().ConfigMaps(configMap.GetNamespace()).Update(configMap)
-			if err != nil {
-				return fmt.Errorf("unable to write owner onto catalog source configmap")
+		for _, configMap := range configMaps {
+			if wasOwned := ownerutil.EnsureOwner(configMap, catsrc); !wasOwned {
+				_, err = o.opClient.KubernetesInterface().CoreV1().ConfigMaps(configMap.GetNamespace()).Update(configMap)
+				if err != nil {
+					return fmt.Errorf("unable to write owner onto catalog source configmap %s", configMap.GetName())
+				}
+				logger.WithField("configmap", configMap.GetName()).Debug("adopted configmap")
 			}
-			logger.Debug("adopted configmap")
 		}
 
-		if catsrc.Status.ConfigMapResource == nil || catsrc.Status.ConfigMapResource.UID != configMap.GetUID() || catsrc.Status.ConfigMapResource.ResourceVersion != configMap.GetResourceVersion() {
+		if reconciler.RecordConfigMapShards(out, configMaps) {
 			logger.Debug("updating catsrc configmap state")
-			// configmap ref nonexistent or updated, write out the new configmap ref to status and exit
-			out.Status.ConfigMapResource = &v1alpha1.ConfigMapResourceReference{
-				Name:            configMap.GetName(),
-				Namespace:       configMap.GetNamespace(),
-				UID:             configMap.GetUID(),
-				ResourceVersion: configMap.GetResourceVersion(),
-			}
-
+			// configmap refs nonexistent or updated, write out the new configmap refs to status and exit
 			out.Status.LastSync = o.now()
 			if _, err := o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out); err != nil {
 				return err
@@ -449,125 +581,96 @@ func (o *Operator) syncCatalogSources(obj interface{}) (syncError error) {
 		return fmt.Errorf("no reconciler for source type %s", catsrc.Spec.SourceType)
 	}
 
-	healthy, err := srcReconciler.CheckRegistryServer(catsrc)
+	healthy, err := srcReconciler.CheckRegistryServer(out)
 	if err != nil {
 		return err
 	}
 	logger.Debugf("check registry server healthy: %t", healthy)
 
+	// Write out a changed connection state, the update requeues the catalog source
+	if healthy && !reflect.DeepEqual(catsrc.Status.GRPCConnectionState, out.Status.GRPCConnectionState) {
+		logger.Debug("updating catsrc connection state")
+		if _, err := o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
 	// If registry pod hasn't been created or hasn't been updated since the last configmap update, recreate it
 	if !healthy || catsrc.Status.RegistryServiceStatus == nil {
-		return func() error {
-			o.sourcesLock.Lock()
-			defer o.sourcesLock.Unlock()
-
-			logger.Debug("ensuring registry server")
-			if err := srcReconciler.EnsureRegistryServer(out); err != nil {
-				logger.WithError(err).Warn("couldn't ensure registry server")
-				return err
-			}
-			logger.Debug("ensured registry server")
+		logger.Debug("ensuring registry server")
+		if err := srcReconciler.EnsureRegistryServer(out); err != nil {
+			logger.WithError(err).Warn("couldn't ensure registry server")
+			return err
+		}
+		logger.Debug("ensured registry server")
 
-			if s, ok := o.sources[sourceKey]; ok {
-				if err := s.Client.Close(); err != nil {
-					logger.WithError(err).Debug("error closing client connection")
-				}
-			}
-			delete(o.sources, sourceKey)
-			o.sourcesLastUpdate = out.Status.LastSync
+		if err := o.sources.Remove(connection.Key(catsrc)); err != nil {
+			logger.WithError(err).Debug("error closing client connection")
+		}
 
-			logger.Debug("updating catsrc status")
-			if _, err := o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out); err != nil {
-				return err
-			}
-			logger.Debug("registry server recreated")
+		logger.Debug("updating catsrc status")
+		if _, err := o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out); err != nil {
+			return err
+		}
+		logger.Debug("registry server recreated")
 
-			return nil
-		}()
+		return nil
 	}
 	logger.Debug("registry state good")
 
-	// update operator's view of sources
-	sourcesUpdated := false
-	func() {
-		o.sourcesLock.Lock()
-		defer o.sourcesLock.Unlock()
-		address := catsrc.Address()
-		currentSource, ok := o.sources[sourceKey]
-		logger = logger.WithField("currentSource", sourceKey)
-
-		connect := false
-
-		// this connection is out of date, close and reconnect
-		if ok && (currentSource.Address != address || catsrc.Status.LastSync.After(currentSource.LastConnect.Time)) {
-			logger.Info("rebuilding connection to registry")
-			if currentSource.Client != nil {
-				if err := currentSource.Client.Close(); err != nil {
-					logger.WithError(err).Warn("couldn't close outdated connection to registry")
-					return
-				}
+	// Poll the registry for updated content, and requeue for the next poll
+	if interval, ok := catsrc.PollInterval(); ok {
+		if poller, ok := srcReconciler.(reconciler.RegistryPoller); ok {
+			logger.Debug("polling registry server")
+			if err := poller.PollRegistryServer(out); err != nil {
+				logger.WithError(err).Warn("couldn't poll registry server")
+				return err
 			}
-			delete(o.sources, sourceKey)
-			o.sourcesLastUpdate = o.now()
-
-			connect = true
-		} else if !ok {
-			// have never made a connection, so need to build a new one
-			connect = true
-		}
 
-		logger := logger.WithField("address", address)
-		if connect {
-			logger.Info("building connection to registry")
-			c, err := registryclient.NewClient(address)
-			if err != nil {
-				logger.WithError(err).Warn("couldn't connect to registry")
-			}
-			sourceRef := resolver.SourceRef{
-				Address:     address,
-				Client:      c,
-				LastConnect: o.now(),
-				LastHealthy: metav1.Time{}, // haven't detected healthy yet
-			}
-			o.sources[sourceKey] = sourceRef
-			currentSource = sourceRef
-			sourcesUpdated = true
-			o.sourcesLastUpdate = sourceRef.LastConnect
-		}
-
-		if currentSource.LastHealthy.IsZero() {
-			logger.Info("client hasn't yet become healthy, attempt a health check")
-			healthy, err := currentSource.Client.HealthCheck(context.TODO(), 2*time.Second)
-			if err != nil || !healthy {
-				if registryclient.IsErrorUnrecoverable(err) {
-					logger.Debug("state didn't change, trigger reconnect. this may happen when cached dns is wrong.")
-					if err := currentSource.Client.Close(); err != nil {
-						logger.WithError(err).Warn("couldn't close outdated connection to registry")
-						return
-					}
-					delete(o.sources, sourceKey)
-					o.sourcesLastUpdate = o.now()
-				}
-				if err := o.catsrcQueueSet.Requeue(sourceKey.Namespace, sourceKey.Name); err != nil {
-					logger.WithError(err).Debug("error requeuing")
+			if !reflect.DeepEqual(catsrc.Status, out.Status) {
+				logger.Debug("updating catsrc registry poll state")
+				if _, err := o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out); err != nil {
+					return err
 				}
-				return
+
+				return nil
 			}
+		}
 
-			logger.Debug("client has become healthy!")
-			currentSource.LastHealthy = currentSource.LastConnect
-			o.sourcesLastUpdate = currentSource.LastHealthy
-			o.sources[sourceKey] = currentSource
-			sourcesUpdated = true
+		next := interval
+		if last := out.Status.LatestImageRegistryPoll; last != nil {
+			next = last.Add(interval).Sub(o.now().Time)
 		}
-	}()
+		if err := o.catsrcQueueSet.RequeueAfter(catsrc.GetNamespace(), catsrc.GetName(), next); err != nil {
+			logger.WithError(err).Warn("couldn't requeue catsrc for the next registry poll")
+		}
+	}
 
-	if !sourcesUpdated {
+	// update operator's view of sources
+	result, err := o.sources.Sync(ctx, logger, catsrc)
+	if err != nil {
+		logger.WithError(err).Warn("couldn't sync connection to registry")
+		return err
+	}
+	if !result.Source.Healthy() {
+		if err := o.catsrcQueueSet.Requeue(sourceKey.Namespace, sourceKey.Name); err != nil {
+			logger.WithError(err).Debug("error requeuing")
+		}
+	} else if contentValidationOutdated(catsrc) {
+		// validate the content of a healthy source whose registry server was (re)created since it was last validated
+		if err := o.catsrcValidationQueueSet.Requeue(sourceKey.Namespace, sourceKey.Name); err != nil {
+			logger.WithError(err).Debug("error queuing catalog content validation")
+		}
+	}
+	if !result.Updated() {
 		return nil
 	}
 
 	// record that we've done work here onto the status
 	out.Status.LastSync = o.now()
+
 	if _, err := o.client.OperatorsV1alpha1().CatalogSources(out.GetNamespace()).UpdateStatus(out); err != nil {
 		return err
 	}
@@ -591,21 +694,48 @@ func (o *Operator) syncResolvingNamespace(obj interface{}) error {
 		"id":        queueinformer.NewLoopID(),
 	})
 
+	// catalog policies restrict the sources that may be used for resolution
+	policies, err := o.lister.OperatorsV1().CatalogPolicyLister().List(labels.Everything())
+	if err != nil {
+		logger.WithError(err).Debug("couldn't list catalogpolicies")
+		return err
+	}
+
+	ctx := context.Background()
+	if o.resolutionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.resolutionTimeout)
+		defer cancel()
+	}
+
 	// get the set of sources that should be used for resolution and best-effort get their connections working
-	resolverSources := o.ensureResolverSources(logger, namespace)
+	resolverSources := o.ensureResolverSources(ctx, logger, namespace, policies)
 	logger.Debugf("resolved sources: %#v", resolverSources)
-	querier := resolver.NewNamespaceSourceQuerier(resolverSources)
+	querier := resolver.NewNamespaceSourceQuerier(resolverSources,
+		resolver.WithQueryTimeout(o.queryTimeout),
+		resolver.WithSourcePriorities(o.sourcePriorities(resolverSources)),
+		resolver.WithGlobalNamespace(o.namespace),
+	)
 
 	logger.Debug("checking if subscriptions need update")
 
-	subs, err := o.lister.OperatorsV1alpha1().SubscriptionLister().Subscriptions(namespace).List(labels.Everything())
+	allSubs, err := o.lister.OperatorsV1alpha1().SubscriptionLister().Subscriptions(namespace).List(labels.Everything())
 	if err != nil {
 		logger.WithError(err).Debug("couldn't list subscriptions")
 		return err
 	}
 
+	// dry-run subscriptions are only previewed, they never take part in installation
+	subs, dryRunSubs := splitDryRunSubscriptions(allSubs)
+	sources := make([]resolver.CatalogKey, 0, len(resolverSources))
+	for key := range resolverSources {
+		sources = append(sources, key)
+	}
+	o.syncDryRunSubscriptions(ctx, logger, namespace, querier, sources, subs, dryRunSubs)
+
 	// TODO: parallel
 	subscriptionUpdated := false
+	catalogsDenied := false
 	for _, sub := range subs {
 		logger := logger.WithFields(logrus.Fields{
 			"sub":     sub.GetName(),
@@ -614,6 +744,14 @@ func (o *Operator) syncResolvingNamespace(obj interface{}) error {
 			"channel": sub.Spec.Channel,
 		})
 
+		// record whether the catalog policy allows the subscription's catalog
+		sub, denied, changedPolicy, err := o.ensureSubscriptionCatalogAllowed(logger, sub, policies)
+		if err != nil {
+			return err
+		}
+		catalogsDenied = catalogsDenied || denied
+		subscriptionUpdated = subscriptionUpdated || changedPolicy
+
 		// ensure the installplan reference is correct
 		sub, changedIP, err := o.ensureSubscriptionInstallPlanState(logger, sub)
 		if err != nil {
@@ -622,17 +760,27 @@ func (o *Operator) syncResolvingNamespace(obj interface{}) error {
 		subscriptionUpdated = subscriptionUpdated || changedIP
 
 		// record the current state of the desired corresponding CSV in the status. no-op if we don't know the csv yet.
-		sub, changedCSV, err := o.ensureSubscriptionCSVState(logger, sub, querier)
+		sub, changedCSV, err := o.ensureSubscriptionCSVState(ctx, logger, sub, querier)
 		if err != nil {
 			return err
 		}
 
 		subscriptionUpdated = subscriptionUpdated || changedCSV
+
+		// carry the deployment overrides of the subscription over to its current csv
+		if err := o.ensureCSVOverrides(logger, sub); err != nil {
+			return err
+		}
 	}
 	if subscriptionUpdated {
 		logger.Debug("subscriptions were updated, wait for a new resolution")
 		return nil
 	}
+	if catalogsDenied {
+		// the subscriptions can't be resolved together until every one of them uses an allowed catalog
+		logger.Debug("subscriptions use catalogs denied by the catalog policy, skipping resolution")
+		return nil
+	}
 
 	shouldUpdate := false
 	for _, sub := range subs {
@@ -646,8 +794,27 @@ func (o *Operator) syncResolvingNamespace(obj interface{}) error {
 	logger.Debug("resolving subscriptions in namespace")
 
 	// resolve a set of steps to apply to a cluster, a set of subscriptions to create/update, and any errors
-	steps, updatedSubs, err := o.resolver.ResolveSteps(namespace, querier)
+	steps, updatedSubs, err := o.resolver.ResolveSteps(ctx, namespace, querier)
+	if timedOut := querier.TimedOut(); len(timedOut) > 0 {
+		logger.WithField("catalogs", timedOut).Warn("catalogs timed out during resolution")
+	}
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			logger.WithError(err).Warnf("resolution didn't finish within %s", o.resolutionTimeout)
+		}
+		// nothing in the namespace can progress until the problems are fixed, so every subscription is affected
+		var failed *resolver.ResolutionError
+		if resErr, ok := errorwrap.Cause(err).(resolver.ResolutionError); ok {
+			failed = &resErr
+		}
+		if recordErr := o.recordResolution(namespace, subs, nil, failed, querier.TimedOut()); recordErr != nil {
+			logger.WithError(recordErr).Debug("error recording resolution failure on subscriptions")
+		}
+		return err
+	}
+
+	if err := o.recordResolution(namespace, subs, updatedSubs, nil, querier.TimedOut()); err != nil {
+		logger.WithError(err).Debug("error recording resolution on subscriptions")
 		return err
 	}
 
@@ -691,31 +858,41 @@ func (o *Operator) syncSubscriptions(obj interface{}) error {
 	return nil
 }
 
-func (o *Operator) ensureResolverSources(logger *logrus.Entry, namespace string) map[resolver.CatalogKey]registryclient.Interface {
+func (o *Operator) ensureResolverSources(ctx context.Context, logger *logrus.Entry, namespace string, policies []*operatorsv1.CatalogPolicy) map[resolver.CatalogKey]resolver.SourceClient {
 	// TODO: record connection status onto an object
-	resolverSources := map[resolver.CatalogKey]registryclient.Interface{}
-	func() {
-		o.sourcesLock.RLock()
-		defer o.sourcesLock.RUnlock()
-		for k, ref := range o.sources {
-			if ref.LastHealthy.IsZero() {
-				logger = logger.WithField("source", k)
-				logger.Debug("omitting source, hasn't yet become healthy")
-				if err := o.catsrcQueueSet.Requeue(k.Namespace, k.Name); err != nil {
-					logger.Warn("error requeueing")
-				}
-				continue
-			}
-			// only resolve in namespace local + global catalogs
-			if k.Namespace == namespace || k.Namespace == o.namespace {
-				resolverSources[k] = ref.Client
+	resolverSources := map[resolver.CatalogKey]resolver.SourceClient{}
+	for key, ref := range o.sources.Sources() {
+		k := resolver.CatalogKey{Name: key.Name, Namespace: key.Namespace}
+		client, ok := ref.Client.(resolver.SourceClient)
+		if !ok || !ref.Healthy() {
+			logger = logger.WithField("source", k)
+			logger.Debug("omitting source, hasn't yet become healthy")
+			if err := o.catsrcQueueSet.Requeue(k.Namespace, k.Name); err != nil {
+				logger.Warn("error requeueing")
 			}
+			continue
+		}
+		// only resolve in namespace local + global catalogs
+		if k.Namespace != namespace && k.Namespace != o.namespace {
+			continue
 		}
-	}()
+
+		// that the catalog policies of the namespace allow
+		var catalogLabels map[string]string
+		if ref.CatalogSource != nil {
+			catalogLabels = ref.CatalogSource.GetLabels()
+		}
+		catalog := operatorsv1.CatalogSourceReference{Name: k.Name, Namespace: k.Namespace}
+		if deniedBy, err := operatorsv1.CatalogDeniedBy(policies, namespace, catalog, catalogLabels); err != nil || deniedBy != nil {
+			logger.WithField("source", k).WithError(err).Debug("omitting source, denied by catalog policy")
+			continue
+		}
+		resolverSources[k] = client
+	}
 
 	for k, s := range resolverSources {
 		logger = logger.WithField("resolverSource", k)
-		if healthy, err := s.HealthCheck(context.TODO(), 2*time.Second); err != nil || !healthy {
+		if healthy, err := s.HealthCheck(ctx, 2*time.Second); err != nil || !healthy {
 			logger.WithError(err).Debug("omitting unhealthy source")
 			if err := o.catsrcQueueSet.Requeue(k.Namespace, k.Name); err != nil {
 				logger.Warn("error requeueing")
@@ -727,12 +904,22 @@ func (o *Operator) ensureResolverSources(logger *logrus.Entry, namespace string)
 	return resolverSources
 }
 
-func (o *Operator) nothingToUpdate(logger *logrus.Entry, sub *v1alpha1.Subscription) bool {
-	o.sourcesLock.RLock()
-	defer o.sourcesLock.RUnlock()
+// sourcePriorities returns the priority of each of the given catalogs. Catalogs that aren't in the cache default to zero.
+func (o *Operator) sourcePriorities(sources map[resolver.CatalogKey]resolver.SourceClient) map[resolver.CatalogKey]int32 {
+	priorities := make(map[resolver.CatalogKey]int32, len(sources))
+	for key := range sources {
+		catsrc, err := o.lister.OperatorsV1alpha1().CatalogSourceLister().CatalogSources(key.Namespace).Get(key.Name)
+		if err != nil {
+			continue
+		}
+		priorities[key] = catsrc.Spec.Priority
+	}
+	return priorities
+}
 
+func (o *Operator) nothingToUpdate(logger *logrus.Entry, sub *v1alpha1.Subscription) bool {
 	// Only sync if catalog has been updated since last sync time
-	if o.sourcesLastUpdate.Before(&sub.Status.LastUpdated) && sub.Status.State != v1alpha1.SubscriptionStateNone && sub.Status.State != v1alpha1.SubscriptionStateUpgradeAvailable {
+	if sourcesLastUpdate := o.sources.LastUpdate(); sourcesLastUpdate.Before(&sub.Status.LastUpdated) && sub.Status.State != v1alpha1.SubscriptionStateNone && sub.Status.State != v1alpha1.SubscriptionStateUpgradeAvailable {
 		logger.Debugf("skipping update: no new updates to catalog since last sync at %s", sub.Status.LastUpdated.String())
 		return true
 	}
@@ -784,7 +971,56 @@ func (o *Operator) ensureSubscriptionInstallPlanState(logger *logrus.Entry, sub
 	return updated, true, nil
 }
 
-func (o *Operator) ensureSubscriptionCSVState(logger *logrus.Entry, sub *v1alpha1.Subscription, querier resolver.SourceQuerier) (*v1alpha1.Subscription, bool, error) {
+// ensureSubscriptionCatalogAllowed records whether the given catalog policies allow the catalog of the given
+// subscription in its CatalogNotAllowed condition. It returns true if the catalog is denied, and true if the
+// subscription was updated.
+func (o *Operator) ensureSubscriptionCatalogAllowed(logger *logrus.Entry, sub *v1alpha1.Subscription, policies []*operatorsv1.CatalogPolicy) (*v1alpha1.Subscription, bool, bool, error) {
+	catsrc, err := o.lister.OperatorsV1alpha1().CatalogSourceLister().CatalogSources(sub.GetCatalogSourceNamespace()).Get(sub.Spec.CatalogSource)
+	if err != nil {
+		// a missing catalog is reported by resolution
+		logger.WithError(err).Debug("couldn't get catalogsource, not checking catalog policy")
+		return sub, false, false, nil
+	}
+	catalog := operatorsv1.CatalogSourceReference{Name: catsrc.GetName(), Namespace: catsrc.GetNamespace()}
+	deniedBy, err := operatorsv1.CatalogDeniedBy(policies, sub.GetNamespace(), catalog, catsrc.GetLabels())
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	denied := deniedBy != nil
+	cond := v1alpha1.SubscriptionCondition{
+		Type:    v1alpha1.SubscriptionCatalogNotAllowed,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.CatalogAllowedByPolicy,
+		Message: fmt.Sprintf("catalogsource %s/%s is allowed by the catalog policy", catsrc.GetNamespace(), catsrc.GetName()),
+	}
+	if denied {
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = v1alpha1.CatalogDeniedByPolicy
+		cond.Message = fmt.Sprintf("catalogsource %s/%s is denied by catalogpolicy %s", catsrc.GetNamespace(), catsrc.GetName(), deniedBy.GetName())
+	} else if sub.Status.GetCondition(cond.Type).Status != corev1.ConditionTrue {
+		// only a previous denial is cleared
+		return sub, false, false, nil
+	}
+	if cond.Equals(sub.Status.GetCondition(cond.Type)) {
+		return sub, denied, false, nil
+	}
+
+	now := o.now()
+	out := sub.DeepCopy()
+	cond.LastTransitionTime = &now
+	out.Status.SetCondition(cond)
+	out.Status.LastUpdated = now
+
+	updated, err := o.client.OperatorsV1alpha1().Subscriptions(sub.GetNamespace()).UpdateStatus(out)
+	if err != nil {
+		logger.WithError(err).Info("error updating subscription status")
+		return nil, false, false, fmt.Errorf("error updating Subscription status: " + err.Error())
+	}
+	return updated, denied, true, nil
+}
+
+func (o *Operator) ensureSubscriptionCSVState(ctx context.Context, logger *logrus.Entry, sub *v1alpha1.Subscription, querier resolver.SourceQuerier) (*v1alpha1.Subscription, bool, error) {
 	if sub.Status.CurrentCSV == "" {
 		return sub, false, nil
 	}
@@ -794,16 +1030,28 @@ func (o *Operator) ensureSubscriptionCSVState(logger *logrus.Entry, sub *v1alpha
 	if err != nil {
 		logger.WithError(err).WithField("currentCSV", sub.Status.CurrentCSV).Debug("error fetching csv listed in subscription status")
 		out.Status.State = v1alpha1.SubscriptionStateUpgradePending
+	} else if csv.IsRolledBack() {
+		// The current csv failed to install, and the csv it replaces is installed instead
+		out.Status.State = v1alpha1.SubscriptionStateUpgradeRolledBack
+		out.Status.InstalledCSV = csv.Spec.Replaces
 	} else {
-		// Check if an update is available for the current csv
+		// Check if an update is available for the current csv, looking past upgrades that were rolled back
 		if err := querier.Queryable(); err != nil {
 			return nil, false, err
 		}
-		bundle, _, _ := querier.FindReplacement(&csv.Spec.Version.Version, sub.Status.CurrentCSV, sub.Spec.Package, sub.Spec.Channel, resolver.CatalogKey{Name: sub.Spec.CatalogSource, Namespace: sub.Spec.CatalogSourceNamespace})
-		if bundle != nil {
+		csvs, err := o.lister.OperatorsV1alpha1().ClusterServiceVersionLister().ClusterServiceVersions(sub.GetNamespace()).List(labels.Everything())
+		if err != nil {
+			return nil, false, err
+		}
+		bundle, _, err := resolver.SkipRolledBack(querier, csvs).FindReplacement(ctx, &csv.Spec.Version.Version, sub.Status.CurrentCSV, sub.Spec.Package, sub.Spec.Channel, sub.Spec.VersionRange, resolver.CatalogKey{Name: sub.Spec.CatalogSource, Namespace: sub.GetCatalogSourceNamespace()})
+		if rangeErr, ok := err.(resolver.VersionNotInRangeError); ok {
+			logger.WithField("bundle", rangeErr.Bundle).Debug("replacement outside of the subscription's version range")
+			out.Status.State = v1alpha1.SubscriptionStateUpgradeBlockedByConstraint
+		} else if bundle != nil {
 			o.logger.Tracef("replacement %s bundle found for current bundle %s", bundle.Name, sub.Status.CurrentCSV)
 			out.Status.State = v1alpha1.SubscriptionStateUpgradeAvailable
-		} else {
+		} else if sub.Status.State != v1alpha1.SubscriptionStateUpgradeRolledBack {
+			// A rolled back upgrade stays reported until there's another one
 			out.Status.State = v1alpha1.SubscriptionStateAtLatest
 		}
 
@@ -827,6 +1075,48 @@ func (o *Operator) ensureSubscriptionCSVState(logger *logrus.Entry, sub *v1alpha
 	return updatedSub, true, nil
 }
 
+// ensureCSVOverrides updates the deployment overrides of the current csv of the given subscription to match its own.
+func (o *Operator) ensureCSVOverrides(logger *logrus.Entry, sub *v1alpha1.Subscription) error {
+	if sub.Status.CurrentCSV == "" {
+		return nil
+	}
+
+	csv, err := o.lister.OperatorsV1alpha1().ClusterServiceVersionLister().ClusterServiceVersions(sub.GetNamespace()).Get(sub.Status.CurrentCSV)
+	if err != nil {
+		// the csv hasn't been installed yet, it's given the overrides when it is
+		logger.WithError(err).WithField("currentCSV", sub.Status.CurrentCSV).Debug("couldn't get csv to carry overrides over to")
+		return nil
+	}
+	if equality.Semantic.DeepEqual(csv.Spec.Overrides, sub.Spec.Overrides) {
+		return nil
+	}
+
+	out := csv.DeepCopy()
+	out.Spec.Overrides = sub.Spec.Overrides.DeepCopy()
+	if _, err := o.client.OperatorsV1alpha1().ClusterServiceVersions(out.GetNamespace()).Update(out); err != nil {
+		logger.WithError(err).Info("error updating csv overrides")
+		return fmt.Errorf("error updating ClusterServiceVersion overrides: " + err.Error())
+	}
+	logger.WithField("currentCSV", out.GetName()).Debug("updated csv overrides")
+
+	return nil
+}
+
+// overridesForCSV returns the deployment overrides of the subscription progressing to the csv with the given name, if any.
+func (o *Operator) overridesForCSV(namespace, name string) (*v1alpha1.DeploymentOverrides, error) {
+	subs, err := o.lister.OperatorsV1alpha1().SubscriptionLister().Subscriptions(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.Status.CurrentCSV == name && sub.Spec != nil {
+			return sub.Spec.Overrides.DeepCopy(), nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (o *Operator) updateSubscriptionStatus(namespace string, subs []*v1alpha1.Subscription, installPlanRef *corev1.ObjectReference) error {
 	// TODO: parallel, sync waitgroup
 	var err error
@@ -844,6 +1134,117 @@ func (o *Operator) updateSubscriptionStatus(namespace string, subs []*v1alpha1.S
 	return err
 }
 
+// resolutionFailedCondition describes the problems that caused a failed resolution.
+func resolutionFailedCondition(resErr resolver.ResolutionError) v1alpha1.SubscriptionCondition {
+	cond := v1alpha1.SubscriptionCondition{
+		Type:    v1alpha1.SubscriptionResolutionFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1alpha1.MultipleResolutionProblems,
+		Message: strings.Join(resErr.Details(), "\n"),
+	}
+	types := map[resolver.ProblemType]struct{}{}
+	for _, p := range resErr.Problems {
+		types[p.Type] = struct{}{}
+	}
+	if len(resErr.Problems) > 0 && len(types) == 1 {
+		cond.Reason = string(resErr.Problems[0].Type)
+	}
+	return cond
+}
+
+// recordResolution records the outcome of a namespace resolution on each of the given subscriptions: the problems that
+// caused it to fail, if any, and the catalogs that timed out while being queried. Subscriptions that are about to be
+// updated with the result of the resolution are changed in place, the rest are updated immediately.
+func (o *Operator) recordResolution(namespace string, subs, updatedSubs []*v1alpha1.Subscription, resErr *resolver.ResolutionError, timedOut []resolver.CatalogKey) error {
+	var cond *v1alpha1.SubscriptionCondition
+	if resErr != nil {
+		failed := resolutionFailedCondition(*resErr)
+		cond = &failed
+	}
+
+	updated := make(map[string]*v1alpha1.Subscription, len(updatedSubs))
+	for _, sub := range updatedSubs {
+		updated[sub.GetName()] = sub
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		now := o.now()
+		out := sub.DeepCopy()
+		changed := false
+
+		switch {
+		case cond != nil:
+			if !cond.Equals(out.Status.GetCondition(cond.Type)) {
+				c := *cond
+				c.LastTransitionTime = &now
+				out.Status.SetCondition(c)
+				changed = true
+			}
+		case out.Status.GetCondition(v1alpha1.SubscriptionResolutionFailed).Status == corev1.ConditionTrue:
+			out.Status.SetCondition(v1alpha1.SubscriptionCondition{
+				Type:               v1alpha1.SubscriptionResolutionFailed,
+				Status:             corev1.ConditionFalse,
+				Reason:             v1alpha1.ResolutionSucceeded,
+				Message:            "all subscriptions in the namespace were resolved",
+				LastTransitionTime: &now,
+			})
+			changed = true
+		}
+
+		if timeouts, ok := catalogQueryTimeouts(out.Status.CatalogQueryTimeouts, timedOut, now); ok {
+			out.Status.CatalogQueryTimeouts = timeouts
+			changed = true
+		}
+
+		if !changed {
+			continue
+		}
+		if u, ok := updated[sub.GetName()]; ok {
+			u.Status.Conditions = out.Status.Conditions
+			u.Status.CatalogQueryTimeouts = out.Status.CatalogQueryTimeouts
+			continue
+		}
+		out.Status.LastUpdated = now
+		if _, err := o.client.OperatorsV1alpha1().Subscriptions(namespace).UpdateStatus(out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// catalogQueryTimeouts returns the timeouts to record for the given timed out catalogs, keeping the time each catalog
+// was first seen to time out. The second return value is false if the recorded timeouts don't need to change.
+func catalogQueryTimeouts(current []v1alpha1.SubscriptionCatalogQueryTimeout, timedOut []resolver.CatalogKey, now metav1.Time) ([]v1alpha1.SubscriptionCatalogQueryTimeout, bool) {
+	since := make(map[resolver.CatalogKey]*metav1.Time, len(current))
+	for _, t := range current {
+		if t.CatalogSourceRef == nil {
+			continue
+		}
+		since[resolver.CatalogKey{Name: t.CatalogSourceRef.Name, Namespace: t.CatalogSourceRef.Namespace}] = t.Since
+	}
+
+	changed := len(current) != len(timedOut)
+	var timeouts []v1alpha1.SubscriptionCatalogQueryTimeout
+	for _, key := range timedOut {
+		s, ok := since[key]
+		if !ok {
+			s = now.DeepCopy()
+			changed = true
+		}
+		timeouts = append(timeouts, v1alpha1.SubscriptionCatalogQueryTimeout{
+			CatalogSourceRef: &corev1.ObjectReference{
+				APIVersion: v1alpha1.CatalogSourceCRDAPIVersion,
+				Kind:       v1alpha1.CatalogSourceKind,
+				Name:       key.Name,
+				Namespace:  key.Namespace,
+			},
+			Since: s,
+		})
+	}
+	return timeouts, changed
+}
+
 func (o *Operator) ensureInstallPlan(logger *logrus.Entry, namespace string, subs []*v1alpha1.Subscription, installPlanApproval v1alpha1.Approval, steps []*v1alpha1.Step) (*corev1.ObjectReference, error) {
 	if len(steps) == 0 {
 		return nil, nil
@@ -1021,12 +1422,34 @@ func (o *Operator) ResolvePlan(plan *v1alpha1.InstallPlan) error {
 	return nil
 }
 
-// ExecutePlan applies a planned InstallPlan to a namespace.
+// ExecutePlan applies a planned InstallPlan to a namespace. When InstallPlans are executed transactionally, the steps
+// applied before a failure are rolled back.
 func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 	if plan.Status.Phase != v1alpha1.InstallPlanPhaseInstalling {
 		panic("attempted to install a plan that wasn't in the installing phase")
 	}
 
+	pending := pendingSteps(plan)
+	err := o.executePlan(plan)
+	if !o.transactionalInstallPlans {
+		return err
+	}
+	if err == nil {
+		// the plan is done, so it won't be rolled back anymore
+		return o.deleteSnapshots(plan)
+	}
+
+	if rollbackErr := o.rollbackPlan(plan, pending); rollbackErr != nil {
+		// keep the snapshots around for whoever has to clean up after the plan
+		return fmt.Errorf("%s, and rolling back the plan failed: %s", err, rollbackErr)
+	}
+	if deleteErr := o.deleteSnapshots(plan); deleteErr != nil {
+		o.logger.WithError(deleteErr).Warn("error deleting rollback snapshots")
+	}
+	return err
+}
+
+func (o *Operator) executePlan(plan *v1alpha1.InstallPlan) error {
 	namespace := plan.GetNamespace()
 
 	// Get the set of initial installplan csv names
@@ -1068,6 +1491,22 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 							return errorwrap.Wrapf(err, "error find matched CSV: %s", step.Resource.Name)
 						}
 						if len(matchedCSV) == 1 {
+							// Refuse updates that would break the CRD's existing custom resources
+							versionSchemas, err := crdVersionSchemas([]byte(step.Resource.Manifest))
+							if err != nil {
+								return errorwrap.Wrapf(err, "error parsing step manifest: %s", step.Resource.Name)
+							}
+							if err := o.validateCRDUpdate(currentCRD, &crd, versionSchemas); err != nil {
+								return err
+							}
+
+							err = o.recordPrevious(plan, i, func() (metav1.Object, error) {
+								return currentCRD.DeepCopy(), nil
+							})
+							if err != nil {
+								return err
+							}
+
 							// Attempt to update CRD
 							crd.SetResourceVersion(currentCRD.GetResourceVersion())
 							_, err = o.opClient.ApiextensionsV1beta1Interface().ApiextensionsV1beta1().CustomResourceDefinitions().Update(&crd)
@@ -1110,6 +1549,11 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 					}
 				}
 
+				// Carry over the deployment overrides of the subscription installing it
+				if csv.Spec.Overrides, err = o.overridesForCSV(namespace, csv.GetName()); err != nil {
+					return errorwrap.Wrapf(err, "error getting overrides for csv %s", csv.GetName())
+				}
+
 				// Attempt to create the CSV.
 				csv.SetNamespace(namespace)
 				_, err = o.client.OperatorsV1alpha1().ClusterServiceVersions(csv.GetNamespace()).Create(&csv)
@@ -1193,6 +1637,12 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				if k8serrors.IsAlreadyExists(err) {
 					// if we're updating, point owner to the newest csv
 					cr.Labels[ownerutil.OwnerKey] = step.Resolving
+					err := o.recordPrevious(plan, i, func() (metav1.Object, error) {
+						return o.opClient.GetClusterRole(cr.GetName())
+					})
+					if err != nil {
+						return err
+					}
 					_, err = o.opClient.UpdateClusterRole(&cr)
 					if err != nil {
 						return errorwrap.Wrapf(err, "error updating clusterrole %s", cr.GetName())
@@ -1218,6 +1668,12 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				if k8serrors.IsAlreadyExists(err) {
 					// if we're updating, point owner to the newest csv
 					rb.Labels[ownerutil.OwnerKey] = step.Resolving
+					err := o.recordPrevious(plan, i, func() (metav1.Object, error) {
+						return o.opClient.GetClusterRoleBinding(rb.GetName())
+					})
+					if err != nil {
+						return err
+					}
 					_, err = o.opClient.UpdateClusterRoleBinding(&rb)
 					if err != nil {
 						return errorwrap.Wrapf(err, "error updating clusterrolebinding %s", rb.GetName())
@@ -1253,6 +1709,12 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				if k8serrors.IsAlreadyExists(err) {
 					// If it already existed, mark the step as Present.
 					r.SetNamespace(plan.Namespace)
+					err := o.recordPrevious(plan, i, func() (metav1.Object, error) {
+						return o.opClient.GetRole(r.GetNamespace(), r.GetName())
+					})
+					if err != nil {
+						return err
+					}
 					_, err = o.opClient.UpdateRole(&r)
 					if err != nil {
 						return errorwrap.Wrapf(err, "error updating role %s", r.GetName())
@@ -1286,6 +1748,12 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				_, err = o.opClient.KubernetesInterface().RbacV1().RoleBindings(plan.Namespace).Create(&rb)
 				if k8serrors.IsAlreadyExists(err) {
 					rb.SetNamespace(plan.Namespace)
+					err := o.recordPrevious(plan, i, func() (metav1.Object, error) {
+						return o.opClient.GetRoleBinding(rb.GetNamespace(), rb.GetName())
+					})
+					if err != nil {
+						return err
+					}
 					_, err = o.opClient.UpdateRoleBinding(&rb)
 					if err != nil {
 						return errorwrap.Wrapf(err, "error updating rolebinding %s", rb.GetName())
@@ -1321,6 +1789,12 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				if k8serrors.IsAlreadyExists(err) {
 					// If it already exists we need to patch the existing SA with the new OwnerReferences
 					sa.SetNamespace(plan.Namespace)
+					err := o.recordPrevious(plan, i, func() (metav1.Object, error) {
+						return o.opClient.GetServiceAccount(sa.GetNamespace(), sa.GetName())
+					})
+					if err != nil {
+						return err
+					}
 					_, err = o.opClient.UpdateServiceAccount(&sa)
 					if err != nil {
 						return errorwrap.Wrapf(err, "error updating service account: %s", sa.GetName())
@@ -1356,6 +1830,12 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				if k8serrors.IsAlreadyExists(err) {
 					// If it already exists we need to patch the existing SA with the new OwnerReferences
 					s.SetNamespace(plan.Namespace)
+					err := o.recordPrevious(plan, i, func() (metav1.Object, error) {
+						return o.opClient.GetService(s.GetNamespace(), s.GetName())
+					})
+					if err != nil {
+						return err
+					}
 					_, err = o.opClient.UpdateService(&s)
 					if err != nil {
 						return errorwrap.Wrapf(err, "error updating service: %s", s.GetName())
@@ -1371,7 +1851,9 @@ func (o *Operator) ExecutePlan(plan *v1alpha1.InstallPlan) error {
 				}
 
 			default:
-				return v1alpha1.ErrInvalidInstallPlan
+				if err := o.executeUnstructuredStep(plan, i); err != nil {
+					return err
+				}
 			}
 
 		default:
@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/ghodss/yaml"
+	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
@@ -17,7 +19,9 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilclock "k8s.io/apimachinery/pkg/util/clock"
@@ -27,18 +31,22 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	apiregistrationfake "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/fake"
 
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/informers/externalversions"
 	olmerrors "github.com/operator-framework/operator-lifecycle-manager/pkg/controller/errors"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/reconciler"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver"
+	resolverfakes "github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver/fakes"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/fakes"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/clientfake"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/queueinformer"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/version"
 )
 
 type mockTransitioner struct {
@@ -213,6 +221,139 @@ func TestExecutePlan(t *testing.T) {
 	}
 }
 
+func TestExecutePlanTransactional(t *testing.T) {
+	namespace := "ns"
+
+	existing := service("service", namespace)
+	existing.Spec.ClusterIP = "10.0.0.1"
+	updated := service("service", namespace)
+	updated.Spec.ClusterIP = "10.0.0.2"
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: namespace}}
+
+	step := func(kind, name, manifest string) *v1alpha1.Step {
+		return &v1alpha1.Step{
+			Resource: v1alpha1.StepResource{
+				CatalogSource:          "catalog",
+				CatalogSourceNamespace: namespace,
+				Version:                "v1",
+				Kind:                   kind,
+				Name:                   name,
+				Manifest:               manifest,
+			},
+			Status: v1alpha1.StepStatusUnknown,
+		}
+	}
+	plan := withSteps(installPlan("p", namespace, v1alpha1.InstallPlanPhaseInstalling),
+		[]*v1alpha1.Step{
+			step(serviceKind, "service", toManifest(updated)),
+			step(serviceAccountKind, "sa", toManifest(sa)),
+			// the pull secret doesn't exist, so this step fails
+			step(secretKind, "secret", ""),
+		},
+	)
+
+	tests := []struct {
+		name          string
+		transactional bool
+		wantStatuses  []v1alpha1.StepStatus
+		wantClusterIP string
+		wantSA        bool
+	}{
+		{
+			name:          "NotTransactional/StepsKept",
+			transactional: false,
+			wantStatuses:  []v1alpha1.StepStatus{v1alpha1.StepStatusPresent, v1alpha1.StepStatusCreated, v1alpha1.StepStatusUnknown},
+			wantClusterIP: updated.Spec.ClusterIP,
+			wantSA:        true,
+		},
+		{
+			name:          "Transactional/StepsRolledBack",
+			transactional: true,
+			wantStatuses:  []v1alpha1.StepStatus{v1alpha1.StepStatusRolledBack, v1alpha1.StepStatusRolledBack, v1alpha1.StepStatusUnknown},
+			wantClusterIP: existing.Spec.ClusterIP,
+			wantSA:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			in := plan.DeepCopy()
+			op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(in), withK8sObjs(existing))
+			require.NoError(t, err)
+			op.transactionalInstallPlans = tt.transactional
+
+			err = op.ExecutePlan(in)
+			require.EqualError(t, err, "secret secret does not exist")
+
+			var statuses []v1alpha1.StepStatus
+			for _, s := range in.Status.Plan {
+				statuses = append(statuses, s.Status)
+			}
+			require.Equal(t, tt.wantStatuses, statuses)
+
+			// the snapshot the service was restored from is cleaned up once the plan has been rolled back
+			require.Empty(t, in.Status.Plan[0].PreviousSnapshot)
+			_, err = op.opClient.GetSecret(namespace, snapshotName(in, 0))
+			require.True(t, k8serrors.IsNotFound(err))
+
+			fetched, err := op.opClient.GetService(namespace, "service")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantClusterIP, fetched.Spec.ClusterIP)
+
+			_, err = op.opClient.GetServiceAccount(namespace, "sa")
+			if tt.wantSA {
+				require.NoError(t, err)
+			} else {
+				require.True(t, k8serrors.IsNotFound(err))
+			}
+		})
+	}
+}
+
+func TestRecordPrevious(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	namespace := "ns"
+	existing := service("service", namespace)
+	existing.SetResourceVersion("42")
+	existing.Spec.ClusterIP = "10.0.0.1"
+	plan := withSteps(installPlan("p", namespace, v1alpha1.InstallPlanPhaseInstalling), []*v1alpha1.Step{
+		{Resource: v1alpha1.StepResource{Kind: serviceKind, Name: "service"}, Status: v1alpha1.StepStatusUnknown},
+	})
+
+	op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(plan))
+	require.NoError(t, err)
+	op.transactionalInstallPlans = true
+
+	require.NoError(t, op.recordPrevious(plan, 0, func() (metav1.Object, error) {
+		return existing.DeepCopy(), nil
+	}))
+
+	// the step only refers to the snapshot, which is owned by the plan
+	require.Equal(t, snapshotName(plan, 0), plan.Status.Plan[0].PreviousSnapshot)
+	snapshot, err := op.opClient.GetSecret(namespace, plan.Status.Plan[0].PreviousSnapshot)
+	require.NoError(t, err)
+	require.True(t, ownerutil.IsOwnedBy(snapshot, plan))
+
+	var previous corev1.Service
+	require.NoError(t, json.Unmarshal(snapshot.Data[snapshotManifestKey], &previous))
+	require.Empty(t, previous.GetResourceVersion())
+	require.Equal(t, existing.Spec.ClusterIP, previous.Spec.ClusterIP)
+
+	// recording the step again replaces the snapshot
+	existing.Spec.ClusterIP = "10.0.0.2"
+	require.NoError(t, op.recordPrevious(plan, 0, func() (metav1.Object, error) {
+		return existing.DeepCopy(), nil
+	}))
+	snapshot, err = op.opClient.GetSecret(namespace, plan.Status.Plan[0].PreviousSnapshot)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(snapshot.Data[snapshotManifestKey], &previous))
+	require.Equal(t, existing.Spec.ClusterIP, previous.Spec.ClusterIP)
+}
+
 func TestSyncCatalogSources(t *testing.T) {
 	clockFake := utilclock.NewFakeClock(time.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC))
 	now := metav1.NewTime(clockFake.Now())
@@ -304,6 +445,60 @@ func TestSyncCatalogSources(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			testName:  "CatalogSourceWithBackingConfigMapShards",
+			namespace: "cool-namespace",
+			catalogSource: &v1alpha1.CatalogSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cool-catalog",
+					Namespace: "cool-namespace",
+					UID:       types.UID("catalog-uid"),
+				},
+				Spec: v1alpha1.CatalogSourceSpec{
+					ConfigMaps: []string{"cool-configmap-b", "cool-configmap-a"},
+					SourceType: v1alpha1.SourceTypeConfigmap,
+				},
+			},
+			k8sObjs: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "cool-configmap-a",
+						Namespace:       "cool-namespace",
+						UID:             types.UID("configmap-a-uid"),
+						ResourceVersion: "resource-version-a",
+					},
+					Data: fakeConfigMapData(),
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "cool-configmap-b",
+						Namespace:       "cool-namespace",
+						UID:             types.UID("configmap-b-uid"),
+						ResourceVersion: "resource-version-b",
+					},
+					Data: fakeConfigMapData(),
+				},
+			},
+			expectedStatus: &v1alpha1.CatalogSourceStatus{
+				ConfigMapShards: []v1alpha1.ConfigMapResourceReference{
+					{
+						Name:            "cool-configmap-b",
+						Namespace:       "cool-namespace",
+						UID:             types.UID("configmap-b-uid"),
+						ResourceVersion: "resource-version-b",
+					},
+					{
+						Name:            "cool-configmap-a",
+						Namespace:       "cool-namespace",
+						UID:             types.UID("configmap-a-uid"),
+						ResourceVersion: "resource-version-a",
+					},
+				},
+				RegistryServiceStatus: nil,
+				LastSync:              now,
+			},
+			expectedError: nil,
+		},
 		{
 			testName:  "CatalogSourceUpdatedByDifferentCatalogOperator",
 			namespace: "cool-namespace",
@@ -432,7 +627,7 @@ func TestSyncCatalogSources(t *testing.T) {
 			require.NoError(t, err)
 
 			// Run sync
-			err = op.syncCatalogSources(tt.catalogSource)
+			err = op.syncCatalogSources(ctx, tt.catalogSource)
 			if tt.expectedError != nil {
 				require.EqualError(t, err, tt.expectedError.Error())
 			} else {
@@ -545,6 +740,538 @@ func TestCompetingCRDOwnersExist(t *testing.T) {
 	}
 }
 
+func TestSyncResolvingNamespaceResolutionFailed(t *testing.T) {
+	namespace := "ns"
+	now := metav1.NewTime(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	failedCond := v1alpha1.SubscriptionCondition{
+		Type:               v1alpha1.SubscriptionResolutionFailed,
+		Status:             corev1.ConditionTrue,
+		Reason:             string(resolver.PackageNotFound),
+		Message:            "PackageNotFound: b/beta in catalog/ns not found: no bundle found",
+		LastTransitionTime: &now,
+	}
+	resolvedCond := v1alpha1.SubscriptionCondition{
+		Type:               v1alpha1.SubscriptionResolutionFailed,
+		Status:             corev1.ConditionFalse,
+		Reason:             v1alpha1.ResolutionSucceeded,
+		Message:            "all subscriptions in the namespace were resolved",
+		LastTransitionTime: &now,
+	}
+	resolutionErr := resolver.ResolutionError{Problems: []resolver.Problem{
+		{
+			Type:       resolver.PackageNotFound,
+			SourceInfo: &resolver.OperatorSourceInfo{Package: "b", Channel: "beta", Catalog: resolver.CatalogKey{Name: "catalog", Namespace: namespace}},
+			Message:    "b/beta in catalog/ns not found: no bundle found",
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		subs       []*v1alpha1.Subscription
+		resolveErr error
+		wantErr    error
+		wantConds  map[string][]v1alpha1.SubscriptionCondition
+	}{
+		{
+			name: "ResolutionFailed/AllSubscriptionsMarked",
+			subs: []*v1alpha1.Subscription{
+				newSubscription("a", namespace, "a", "alpha"),
+				newSubscription("b", namespace, "b", "beta"),
+			},
+			resolveErr: resolutionErr,
+			wantErr:    resolutionErr,
+			wantConds: map[string][]v1alpha1.SubscriptionCondition{
+				"a": {failedCond},
+				"b": {failedCond},
+			},
+		},
+		{
+			name: "OtherError/SubscriptionsNotMarked",
+			subs: []*v1alpha1.Subscription{
+				newSubscription("a", namespace, "a", "alpha"),
+			},
+			resolveErr: fmt.Errorf("no catalog sources available"),
+			wantErr:    fmt.Errorf("no catalog sources available"),
+			wantConds: map[string][]v1alpha1.SubscriptionCondition{
+				"a": nil,
+			},
+		},
+		{
+			name: "ResolutionSucceeded/FailureCleared",
+			subs: []*v1alpha1.Subscription{
+				withConditions(newSubscription("a", namespace, "a", "alpha"), failedCond),
+				newSubscription("b", namespace, "b", "beta"),
+			},
+			wantConds: map[string][]v1alpha1.SubscriptionCondition{
+				"a": {resolvedCond},
+				"b": nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			clientObjs := make([]runtime.Object, 0, len(tt.subs))
+			for _, sub := range tt.subs {
+				clientObjs = append(clientObjs, sub)
+			}
+			op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(clientObjs...), withClock(utilclock.NewFakeClock(now.Time)))
+			require.NoError(t, err)
+			fakeResolver := &fakes.FakeResolver{}
+			fakeResolver.ResolveStepsReturns(nil, nil, tt.resolveErr)
+			op.resolver = fakeResolver
+
+			err = op.syncResolvingNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+			require.Equal(t, tt.wantErr, err)
+
+			for name, conds := range tt.wantConds {
+				sub, err := op.client.OperatorsV1alpha1().Subscriptions(namespace).Get(name, metav1.GetOptions{})
+				require.NoError(t, err)
+				require.Equal(t, conds, sub.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestSyncResolvingNamespaceCatalogQueryTimeouts(t *testing.T) {
+	namespace := "ns"
+	now := metav1.NewTime(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	catalogKey := resolver.CatalogKey{Name: "catalog", Namespace: namespace}
+	timeout := func(since metav1.Time) v1alpha1.SubscriptionCatalogQueryTimeout {
+		return v1alpha1.SubscriptionCatalogQueryTimeout{
+			CatalogSourceRef: &corev1.ObjectReference{
+				APIVersion: v1alpha1.CatalogSourceCRDAPIVersion,
+				Kind:       v1alpha1.CatalogSourceKind,
+				Name:       catalogKey.Name,
+				Namespace:  catalogKey.Namespace,
+			},
+			Since: &since,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		sub          *v1alpha1.Subscription
+		slow         bool
+		wantTimeouts []v1alpha1.SubscriptionCatalogQueryTimeout
+	}{
+		{
+			name:         "SlowCatalog/Recorded",
+			sub:          newSubscription("a", namespace, "a", "alpha"),
+			slow:         true,
+			wantTimeouts: []v1alpha1.SubscriptionCatalogQueryTimeout{timeout(now)},
+		},
+		{
+			name:         "SlowCatalog/AlreadyRecorded/SinceKept",
+			sub:          withCatalogQueryTimeouts(newSubscription("a", namespace, "a", "alpha"), timeout(earlier)),
+			slow:         true,
+			wantTimeouts: []v1alpha1.SubscriptionCatalogQueryTimeout{timeout(earlier)},
+		},
+		{
+			name:         "FastCatalog/Cleared",
+			sub:          withCatalogQueryTimeouts(newSubscription("a", namespace, "a", "alpha"), timeout(earlier)),
+			wantTimeouts: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(tt.sub), withClock(utilclock.NewFakeClock(now.Time)))
+			require.NoError(t, err)
+			op.queryTimeout = time.Millisecond
+
+			source := &resolverfakes.FakeSourceClient{}
+			source.HealthCheckReturns(true, nil)
+			source.GetBundleInPackageChannelStub = func(ctx context.Context, pkgName, channelName string) (*opregistry.Bundle, error) {
+				if tt.slow {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return opregistry.NewBundle("a.v1", pkgName, channelName), nil
+			}
+			op.sources.Add(types.NamespacedName{Name: catalogKey.Name, Namespace: catalogKey.Namespace}, connection.Source{Client: source, LastHealthy: now})
+
+			fakeResolver := &fakes.FakeResolver{}
+			fakeResolver.ResolveStepsStub = func(ctx context.Context, namespace string, querier resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
+				querier.FindLatestBundle(ctx, "a", "alpha", "", catalogKey)
+				return nil, nil, nil
+			}
+			op.resolver = fakeResolver
+
+			require.NoError(t, op.syncResolvingNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}))
+
+			sub, err := op.client.OperatorsV1alpha1().Subscriptions(namespace).Get(tt.sub.GetName(), metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTimeouts, sub.Status.CatalogQueryTimeouts)
+		})
+	}
+}
+
+func TestSyncResolvingNamespaceCatalogPolicy(t *testing.T) {
+	namespace := "ns"
+	now := metav1.NewTime(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))
+	catalog := func(name string) *v1alpha1.CatalogSource {
+		return &v1alpha1.CatalogSource{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1alpha1.CatalogSourceSpec{SourceType: v1alpha1.SourceTypeGrpc},
+		}
+	}
+	policy := &operatorsv1.CatalogPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: operatorsv1.CatalogPolicySpec{
+			Namespaces: []string{namespace},
+			Allowed:    []operatorsv1.CatalogSourceReference{{Name: "curated", Namespace: namespace}},
+		},
+	}
+	withCatalog := func(sub *v1alpha1.Subscription, name string) *v1alpha1.Subscription {
+		sub.Spec.CatalogSource = name
+		return sub
+	}
+	withoutCatalogNamespace := func(sub *v1alpha1.Subscription) *v1alpha1.Subscription {
+		sub.Spec.CatalogSourceNamespace = ""
+		return sub
+	}
+
+	deniedCond := v1alpha1.SubscriptionCondition{
+		Type:               v1alpha1.SubscriptionCatalogNotAllowed,
+		Status:             corev1.ConditionTrue,
+		Reason:             v1alpha1.CatalogDeniedByPolicy,
+		Message:            "catalogsource ns/catalog is denied by catalogpolicy policy",
+		LastTransitionTime: &now,
+	}
+	allowedCond := v1alpha1.SubscriptionCondition{
+		Type:               v1alpha1.SubscriptionCatalogNotAllowed,
+		Status:             corev1.ConditionFalse,
+		Reason:             v1alpha1.CatalogAllowedByPolicy,
+		Message:            "catalogsource ns/curated is allowed by the catalog policy",
+		LastTransitionTime: &now,
+	}
+
+	tests := []struct {
+		name        string
+		sub         *v1alpha1.Subscription
+		wantConds   []v1alpha1.SubscriptionCondition
+		wantResolve bool
+	}{
+		{
+			name:      "Denied/ConditionSet",
+			sub:       newSubscription("a", namespace, "a", "alpha"),
+			wantConds: []v1alpha1.SubscriptionCondition{deniedCond},
+		},
+		{
+			name:      "Denied/AlreadyRecorded/ResolutionSkipped",
+			sub:       withConditions(newSubscription("a", namespace, "a", "alpha"), deniedCond),
+			wantConds: []v1alpha1.SubscriptionCondition{deniedCond},
+		},
+		{
+			name:      "Denied/NoCatalogNamespace/ConditionSet",
+			sub:       withoutCatalogNamespace(newSubscription("a", namespace, "a", "alpha")),
+			wantConds: []v1alpha1.SubscriptionCondition{deniedCond},
+		},
+		{
+			name:        "Allowed/Resolved",
+			sub:         withCatalog(newSubscription("a", namespace, "a", "alpha"), "curated"),
+			wantResolve: true,
+		},
+		{
+			name:      "Allowed/DenialCleared",
+			sub:       withConditions(withCatalog(newSubscription("a", namespace, "a", "alpha"), "curated"), deniedCond),
+			wantConds: []v1alpha1.SubscriptionCondition{allowedCond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(tt.sub, policy, catalog("catalog"), catalog("curated")), withClock(utilclock.NewFakeClock(now.Time)))
+			require.NoError(t, err)
+			fakeResolver := &fakes.FakeResolver{}
+			op.resolver = fakeResolver
+
+			require.NoError(t, op.syncResolvingNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}))
+			require.Equal(t, tt.wantResolve, fakeResolver.ResolveStepsCallCount() > 0)
+
+			sub, err := op.client.OperatorsV1alpha1().Subscriptions(namespace).Get(tt.sub.GetName(), metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantConds, sub.Status.Conditions)
+		})
+	}
+}
+
+func TestSyncDryRunSubscriptions(t *testing.T) {
+	namespace := "ns"
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	dryRun := newSubscription("dry-run", namespace, "a", "alpha")
+	dryRun.SetAnnotations(map[string]string{v1alpha1.SubscriptionDryRunAnnotationKey: "true"})
+	installed := newSubscription("installed", namespace, "b", "alpha")
+	op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(dryRun, installed))
+	require.NoError(t, err)
+	fakeResolver := &fakes.FakeResolver{}
+	fakeResolver.PreviewStepsReturns(&resolver.Preview{}, nil)
+	op.resolver = fakeResolver
+
+	sources := []resolver.CatalogKey{{Name: "catalog", Namespace: namespace}}
+	sync := func(sub *v1alpha1.Subscription) *v1alpha1.Subscription {
+		op.syncDryRunSubscriptions(ctx, logrus.NewEntry(op.logger), namespace, nil, sources, []*v1alpha1.Subscription{installed}, []*v1alpha1.Subscription{sub})
+		out, err := op.client.OperatorsV1alpha1().Subscriptions(namespace).Get(sub.GetName(), metav1.GetOptions{})
+		require.NoError(t, err)
+		return out
+	}
+
+	// The result is recorded along with what it was resolved from
+	out := sync(dryRun)
+	require.Equal(t, 1, fakeResolver.PreviewStepsCallCount())
+	require.Equal(t, "{}", out.GetAnnotations()[v1alpha1.SubscriptionDryRunResultAnnotationKey])
+	require.NotEmpty(t, out.GetAnnotations()[v1alpha1.SubscriptionDryRunKeyAnnotationKey])
+
+	// Nothing is resolved again until that changes
+	out = sync(out)
+	require.Equal(t, 1, fakeResolver.PreviewStepsCallCount())
+
+	out.SetGeneration(out.GetGeneration() + 1)
+	out = sync(out)
+	require.Equal(t, 2, fakeResolver.PreviewStepsCallCount())
+
+	installed.Status.InstalledCSV = "b.v1"
+	out = sync(out)
+	require.Equal(t, 3, fakeResolver.PreviewStepsCallCount())
+
+	sources = append(sources, resolver.CatalogKey{Name: "other", Namespace: namespace})
+	sync(out)
+	require.Equal(t, 4, fakeResolver.PreviewStepsCallCount())
+}
+
+func TestEnsureResolverSourcesCatalogPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	namespace := "ns"
+	op, err := NewFakeOperator(ctx, "olm", []string{namespace})
+	require.NoError(t, err)
+
+	now := metav1.Now()
+	for _, key := range []types.NamespacedName{
+		{Name: "curated", Namespace: "olm"},
+		{Name: "community", Namespace: "olm"},
+		{Name: "local", Namespace: namespace},
+		{Name: "other", Namespace: "other"},
+	} {
+		source := &resolverfakes.FakeSourceClient{}
+		source.HealthCheckReturns(true, nil)
+		catsrc := &v1alpha1.CatalogSource{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, Labels: map[string]string{"catalog": key.Name}}}
+		op.sources.Add(key, connection.Source{Client: source, LastHealthy: now, CatalogSource: catsrc})
+	}
+
+	keys := func(sources map[resolver.CatalogKey]resolver.SourceClient) []resolver.CatalogKey {
+		var keys []resolver.CatalogKey
+		for key := range sources {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+	logger := logrus.NewEntry(logrus.New())
+
+	// Without a policy, the namespace's catalogs and the global ones are used
+	require.ElementsMatch(t, []resolver.CatalogKey{
+		{Name: "curated", Namespace: "olm"},
+		{Name: "community", Namespace: "olm"},
+		{Name: "local", Namespace: namespace},
+	}, keys(op.ensureResolverSources(ctx, logger, namespace, nil)))
+
+	// A policy restricts them further
+	policies := []*operatorsv1.CatalogPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: operatorsv1.CatalogPolicySpec{
+			AllowedSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "catalog", Operator: metav1.LabelSelectorOpIn, Values: []string{"curated", "local"}},
+			}},
+			Denied: []operatorsv1.CatalogSourceReference{{Name: "local", Namespace: namespace}},
+		},
+	}}
+	require.ElementsMatch(t, []resolver.CatalogKey{
+		{Name: "curated", Namespace: "olm"},
+	}, keys(op.ensureResolverSources(ctx, logger, namespace, policies)))
+
+	// Unless it applies to other namespaces only
+	policies[0].Spec.Namespaces = []string{"other"}
+	require.ElementsMatch(t, []resolver.CatalogKey{
+		{Name: "curated", Namespace: "olm"},
+		{Name: "community", Namespace: "olm"},
+		{Name: "local", Namespace: namespace},
+	}, keys(op.ensureResolverSources(ctx, logger, namespace, policies)))
+}
+
+func TestEnsureSubscriptionCSVStateVersionRange(t *testing.T) {
+	namespace := "ns"
+	catalogKey := resolver.CatalogKey{Name: "catalog", Namespace: namespace}
+	installed := withVersion(csv("a.v1", namespace, nil, nil), "1.0.0")
+
+	tests := []struct {
+		name             string
+		versionRange     string
+		catalogNamespace string
+		wantState        v1alpha1.SubscriptionState
+	}{
+		{
+			name:             "Unconstrained/UpgradeAvailable",
+			catalogNamespace: namespace,
+			wantState:        v1alpha1.SubscriptionStateUpgradeAvailable,
+		},
+		{
+			name:      "DefaultCatalogNamespace/UpgradeAvailable",
+			wantState: v1alpha1.SubscriptionStateUpgradeAvailable,
+		},
+		{
+			name:             "HeadInRange/UpgradeAvailable",
+			versionRange:     ">=1.0.0",
+			catalogNamespace: namespace,
+			wantState:        v1alpha1.SubscriptionStateUpgradeAvailable,
+		},
+		{
+			name:             "HeadOutOfRange/UpgradeBlocked",
+			versionRange:     "<2.0.0",
+			catalogNamespace: namespace,
+			wantState:        v1alpha1.SubscriptionStateUpgradeBlockedByConstraint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			sub := newSubscription("a", namespace, "a", "alpha")
+			sub.Spec.VersionRange = tt.versionRange
+			sub.Spec.CatalogSourceNamespace = tt.catalogNamespace
+			sub.Status.CurrentCSV = installed.GetName()
+			op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(sub, installed))
+			require.NoError(t, err)
+
+			head := bundle("a.v2", "a", "alpha", withReplaces(withVersion(csv("a.v2", namespace, nil, nil), "2.0.0"), installed.GetName()))
+			source := &resolverfakes.FakeSourceClient{}
+			source.GetBundleInPackageChannelReturns(head, nil)
+			source.GetReplacementBundleInPackageChannelReturns(head, nil)
+			querier := resolver.NewNamespaceSourceQuerier(map[resolver.CatalogKey]resolver.SourceClient{catalogKey: source})
+
+			out, _, err := op.ensureSubscriptionCSVState(ctx, logrus.NewEntry(op.logger), sub, querier)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantState, out.Status.State)
+		})
+	}
+}
+
+func TestEnsureSubscriptionCSVStateRolledBack(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	namespace := "ns"
+	catalogKey := resolver.CatalogKey{Name: "catalog", Namespace: namespace}
+	installed := withVersion(csv("a.v1", namespace, nil, nil), "1.0.0")
+	upgrade := withReplaces(withVersion(csv("a.v2", namespace, nil, nil), "2.0.0"), installed.GetName())
+	upgrade.Status.Phase = v1alpha1.CSVPhaseFailed
+	upgrade.Status.Reason = v1alpha1.CSVReasonRolledBack
+
+	sub := newSubscription("a", namespace, "a", "alpha")
+	sub.Status.CurrentCSV = installed.GetName()
+	sub.Status.InstalledCSV = installed.GetName()
+	sub.Status.State = v1alpha1.SubscriptionStateUpgradeRolledBack
+	op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(sub, installed, upgrade))
+	require.NoError(t, err)
+
+	replacements := map[string]*opregistry.Bundle{
+		installed.GetName(): bundle(upgrade.GetName(), "a", "alpha", upgrade),
+	}
+	source := &resolverfakes.FakeSourceClient{}
+	source.GetBundleInPackageChannelReturns(replacements[installed.GetName()], nil)
+	source.GetReplacementBundleInPackageChannelStub = func(ctx context.Context, bundleName, pkgName, channelName string) (*opregistry.Bundle, error) {
+		return replacements[bundleName], nil
+	}
+	querier := resolver.NewNamespaceSourceQuerier(map[resolver.CatalogKey]resolver.SourceClient{catalogKey: source})
+
+	// The rolled back upgrade isn't available again
+	out, changed, err := op.ensureSubscriptionCSVState(ctx, logrus.NewEntry(op.logger), sub, querier)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, v1alpha1.SubscriptionState(v1alpha1.SubscriptionStateUpgradeRolledBack), out.Status.State)
+
+	// The upgrade that replaces it is
+	next := withReplaces(withVersion(csv("a.v3", namespace, nil, nil), "3.0.0"), upgrade.GetName())
+	replacements[upgrade.GetName()] = bundle(next.GetName(), "a", "alpha", next)
+	out, changed, err = op.ensureSubscriptionCSVState(ctx, logrus.NewEntry(op.logger), sub, querier)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, v1alpha1.SubscriptionState(v1alpha1.SubscriptionStateUpgradeAvailable), out.Status.State)
+	require.Equal(t, installed.GetName(), out.Status.InstalledCSV)
+}
+
+func TestEnsureCSVOverrides(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	namespace := "ns"
+	installed := csv("a.v1", namespace, nil, nil)
+	sub := newSubscription("a", namespace, "a", "alpha")
+	sub.Spec.Overrides = &v1alpha1.DeploymentOverrides{
+		Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+	}
+	sub.Status.CurrentCSV = installed.GetName()
+	op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(sub, installed))
+	require.NoError(t, err)
+	logger := logrus.NewEntry(op.logger)
+
+	// The overrides of the subscription are carried over to its csv
+	overrides, err := op.overridesForCSV(namespace, installed.GetName())
+	require.NoError(t, err)
+	require.Equal(t, sub.Spec.Overrides, overrides)
+	overrides, err = op.overridesForCSV(namespace, "b.v1")
+	require.NoError(t, err)
+	require.Nil(t, overrides)
+
+	require.NoError(t, op.ensureCSVOverrides(logger, sub))
+	out, err := op.client.OperatorsV1alpha1().ClusterServiceVersions(namespace).Get(installed.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, sub.Spec.Overrides, out.Spec.Overrides)
+
+	// Subscriptions without a csv yet are skipped
+	pending := sub.DeepCopy()
+	pending.Status.CurrentCSV = "a.v2"
+	require.NoError(t, op.ensureCSVOverrides(logger, pending))
+}
+
+func newSubscription(name, namespace, pkg, channel string) *v1alpha1.Subscription {
+	return &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: &v1alpha1.SubscriptionSpec{
+			CatalogSource:          "catalog",
+			CatalogSourceNamespace: namespace,
+			Package:                pkg,
+			Channel:                channel,
+		},
+	}
+}
+
+func withConditions(sub *v1alpha1.Subscription, conds ...v1alpha1.SubscriptionCondition) *v1alpha1.Subscription {
+	sub.Status.Conditions = conds
+	return sub
+}
+
+func withCatalogQueryTimeouts(sub *v1alpha1.Subscription, timeouts ...v1alpha1.SubscriptionCatalogQueryTimeout) *v1alpha1.Subscription {
+	sub.Status.CatalogQueryTimeouts = timeouts
+	return sub
+}
+
 func fakeConfigMapData() map[string]string {
 	data := make(map[string]string)
 	yaml, err := yaml.Marshal([]v1beta1.CustomResourceDefinition{crd("fake-crd")})
@@ -626,7 +1353,7 @@ func NewFakeOperator(ctx context.Context, namespace string, watchedNamespaces []
 	var sharedInformers []cache.SharedIndexInformer
 	for _, ns := range watchedNamespaces {
 		if ns != namespace {
-			_, err := opClientFake.KubernetesInterface().CoreV1().Namespaces().Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+			_, err := opClientFake.KubernetesInterface().CoreV1().Namespaces().Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
 			if err != nil {
 				return nil, err
 			}
@@ -663,6 +1390,10 @@ func NewFakeOperator(ctx context.Context, namespace string, watchedNamespaces []
 
 	}
 
+	catalogPolicyInformer := externalversions.NewSharedInformerFactory(clientFake, wakeupInterval).Operators().V1().CatalogPolicies()
+	sharedInformers = append(sharedInformers, catalogPolicyInformer.Informer())
+	lister.OperatorsV1().RegisterCatalogPolicyLister(catalogPolicyInformer.Lister())
+
 	// Create the new operator
 	queueOperator, err := queueinformer.NewOperator(opClientFake.KubernetesInterface().Discovery())
 	for _, informer := range sharedInformers {
@@ -683,9 +1414,12 @@ func NewFakeOperator(ctx context.Context, namespace string, watchedNamespaces []
 				// 1 qps, 100 bucket size.  This is only for retry speed and its only the overall factor (not per item)
 				&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(1), 100)},
 			), "resolver"),
-		sources:  make(map[resolver.CatalogKey]resolver.SourceRef),
-		resolver: &fakes.FakeResolver{},
+		resolver:                 &fakes.FakeResolver{},
+		unstructuredClient:       newFakeUnstructuredClient(),
+		stepKinds:                newStepKindPolicy(nil, nil),
+		catsrcValidationQueueSet: queueinformer.NewEmptyResourceQueueSet(),
 	}
+	op.sources = connection.NewPool("catalog-operator", op.now, registryDialer)
 	op.reconciler = reconciler.NewRegistryReconcilerFactory(lister, op.opClient, "test:pod", op.now)
 
 	op.RunInformers(ctx)
@@ -749,6 +1483,26 @@ func csv(name, namespace string, owned, required []string) *v1alpha1.ClusterServ
 	}
 }
 
+func withVersion(csv *v1alpha1.ClusterServiceVersion, v string) *v1alpha1.ClusterServiceVersion {
+	csv.Spec.Version = version.OperatorVersion{Version: semver.MustParse(v)}
+	return csv
+}
+
+func withReplaces(csv *v1alpha1.ClusterServiceVersion, replaces string) *v1alpha1.ClusterServiceVersion {
+	csv.Spec.Replaces = replaces
+	return csv
+}
+
+func bundle(name, pkg, channel string, csv *v1alpha1.ClusterServiceVersion) *opregistry.Bundle {
+	csv = csv.DeepCopy()
+	csv.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.ClusterServiceVersionKind))
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(csv)
+	if err != nil {
+		panic(err)
+	}
+	return opregistry.NewBundle(name, pkg, channel, &unstructured.Unstructured{Object: obj})
+}
+
 func crd(name string) v1beta1.CustomResourceDefinition {
 	return v1beta1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
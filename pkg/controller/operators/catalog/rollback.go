@@ -0,0 +1,240 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	errorwrap "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	v1beta1ext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+// pendingSteps returns the indices of the steps of a plan that haven't been applied yet.
+func pendingSteps(plan *v1alpha1.InstallPlan) []int {
+	var pending []int
+	for i, step := range plan.Status.Plan {
+		switch step.Status {
+		case v1alpha1.StepStatusUnknown, v1alpha1.StepStatusNotPresent:
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// snapshotManifestKey is the key of the manifest in a rollback snapshot Secret.
+const snapshotManifestKey = "manifest"
+
+// snapshotName returns the name of the Secret that holds the rollback snapshot of a plan's step.
+func snapshotName(plan *v1alpha1.InstallPlan, index int) string {
+	return fmt.Sprintf("%s-rollback-%d", plan.GetName(), index)
+}
+
+// recordPrevious stores the object a step of the plan is about to update, without its resource version, in a Secret
+// owned by the plan so that a failed plan can restore it. Objects such as CRDs can be too large to fit in the plan's
+// status along with everything else, so only the name of the Secret is recorded in the step. It does nothing unless
+// InstallPlans are executed transactionally.
+func (o *Operator) recordPrevious(plan *v1alpha1.InstallPlan, index int, get func() (metav1.Object, error)) error {
+	if !o.transactionalInstallPlans {
+		return nil
+	}
+
+	step := plan.Status.Plan[index]
+	previous, err := get()
+	if err != nil {
+		return errorwrap.Wrapf(err, "error getting %s %s before updating it", step.Resource.Kind, step.Resource.Name)
+	}
+	previous.SetResourceVersion("")
+
+	manifest, err := json.Marshal(previous)
+	if err != nil {
+		return errorwrap.Wrapf(err, "error recording %s %s before updating it", step.Resource.Kind, step.Resource.Name)
+	}
+
+	snapshot := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName(plan, index),
+			Namespace: plan.GetNamespace(),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{snapshotManifestKey: manifest},
+	}
+	ownerutil.AddNonBlockingOwner(snapshot, plan)
+	_, err = o.opClient.CreateSecret(snapshot)
+	if k8serrors.IsAlreadyExists(err) {
+		// left behind by an earlier attempt to execute the step
+		_, err = o.opClient.UpdateSecret(snapshot)
+	}
+	if err != nil {
+		return errorwrap.Wrapf(err, "error recording %s %s before updating it", step.Resource.Kind, step.Resource.Name)
+	}
+	step.PreviousSnapshot = snapshot.GetName()
+
+	return nil
+}
+
+// deleteSnapshots deletes the rollback snapshots recorded by the steps of a plan, once they're no longer needed.
+func (o *Operator) deleteSnapshots(plan *v1alpha1.InstallPlan) error {
+	for _, step := range plan.Status.Plan {
+		if step.PreviousSnapshot == "" {
+			continue
+		}
+		if err := o.opClient.DeleteSecret(plan.GetNamespace(), step.PreviousSnapshot, &metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return errorwrap.Wrapf(err, "error deleting rollback snapshot %s", step.PreviousSnapshot)
+		}
+		step.PreviousSnapshot = ""
+	}
+	return nil
+}
+
+// rollbackPlan undoes the given steps of a plan in reverse order: the resources they created are deleted, and the ones
+// they updated are restored to their previous manifests. Steps that didn't change anything are left as they are.
+// The outcome is recorded in the status of each step that is rolled back.
+func (o *Operator) rollbackPlan(plan *v1alpha1.InstallPlan, steps []int) error {
+	var failed []string
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := plan.Status.Plan[steps[i]]
+
+		var err error
+		switch {
+		case step.Status == v1alpha1.StepStatusCreated:
+			err = o.deleteStepResource(plan.GetNamespace(), step.Resource)
+		case step.Status == v1alpha1.StepStatusPresent && step.PreviousSnapshot != "":
+			err = o.restoreStepResource(plan.GetNamespace(), step)
+		default:
+			continue
+		}
+
+		logger := o.logger.WithField("kind", step.Resource.Kind).WithField("name", step.Resource.Name)
+		if err != nil {
+			logger.WithError(err).Warn("error rolling back step")
+			step.Status = v1alpha1.StepStatusRollbackFailed
+			failed = append(failed, err.Error())
+			continue
+		}
+		logger.Debug("rolled back step")
+		step.Status = v1alpha1.StepStatusRolledBack
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// deleteStepResource deletes a resource created by a step. A resource that is already gone counts as deleted.
+func (o *Operator) deleteStepResource(namespace string, resource v1alpha1.StepResource) error {
+	options := &metav1.DeleteOptions{}
+
+	var err error
+	switch resource.Kind {
+	case crdKind:
+		err = o.opClient.ApiextensionsV1beta1Interface().ApiextensionsV1beta1().CustomResourceDefinitions().Delete(resource.Name, options)
+	case v1alpha1.ClusterServiceVersionKind:
+		err = o.client.OperatorsV1alpha1().ClusterServiceVersions(namespace).Delete(resource.Name, options)
+	case v1alpha1.SubscriptionKind:
+		err = o.client.OperatorsV1alpha1().Subscriptions(namespace).Delete(resource.Name, options)
+	case secretKind:
+		err = o.opClient.DeleteSecret(namespace, resource.Name, options)
+	case clusterRoleKind:
+		err = o.opClient.DeleteClusterRole(resource.Name, options)
+	case clusterRoleBindingKind:
+		err = o.opClient.DeleteClusterRoleBinding(resource.Name, options)
+	case roleKind:
+		err = o.opClient.DeleteRole(namespace, resource.Name, options)
+	case roleBindingKind:
+		err = o.opClient.DeleteRoleBinding(namespace, resource.Name, options)
+	case serviceAccountKind:
+		err = o.opClient.DeleteServiceAccount(namespace, resource.Name, options)
+	case serviceKind:
+		err = o.opClient.DeleteService(namespace, resource.Name, options)
+	default:
+		err = o.unstructuredClient.DeleteUnstructured(stepGroupVersionKind(resource), namespace, resource.Name, options)
+	}
+
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errorwrap.Wrapf(err, "error deleting %s %s", resource.Kind, resource.Name)
+	}
+	return nil
+}
+
+// restoreStepResource updates a resource changed by a step back to the manifest recorded before the change.
+func (o *Operator) restoreStepResource(namespace string, step *v1alpha1.Step) error {
+	snapshot, err := o.opClient.GetSecret(namespace, step.PreviousSnapshot)
+	if err != nil {
+		return errorwrap.Wrapf(err, "error getting rollback snapshot of %s %s", step.Resource.Kind, step.Resource.Name)
+	}
+	manifest := snapshot.Data[snapshotManifestKey]
+
+	switch step.Resource.Kind {
+	case crdKind:
+		var crd v1beta1ext.CustomResourceDefinition
+		if err = json.Unmarshal(manifest, &crd); err != nil {
+			break
+		}
+		client := o.opClient.ApiextensionsV1beta1Interface().ApiextensionsV1beta1().CustomResourceDefinitions()
+		var current *v1beta1ext.CustomResourceDefinition
+		if current, err = client.Get(crd.GetName(), metav1.GetOptions{}); err != nil {
+			break
+		}
+		crd.SetResourceVersion(current.GetResourceVersion())
+		_, err = client.Update(&crd)
+	case clusterRoleKind:
+		var cr rbacv1.ClusterRole
+		if err = json.Unmarshal(manifest, &cr); err == nil {
+			_, err = o.opClient.UpdateClusterRole(&cr)
+		}
+	case clusterRoleBindingKind:
+		var rb rbacv1.ClusterRoleBinding
+		if err = json.Unmarshal(manifest, &rb); err == nil {
+			_, err = o.opClient.UpdateClusterRoleBinding(&rb)
+		}
+	case roleKind:
+		var r rbacv1.Role
+		if err = json.Unmarshal(manifest, &r); err == nil {
+			r.SetNamespace(namespace)
+			_, err = o.opClient.UpdateRole(&r)
+		}
+	case roleBindingKind:
+		var rb rbacv1.RoleBinding
+		if err = json.Unmarshal(manifest, &rb); err == nil {
+			rb.SetNamespace(namespace)
+			_, err = o.opClient.UpdateRoleBinding(&rb)
+		}
+	case serviceAccountKind:
+		var sa corev1.ServiceAccount
+		if err = json.Unmarshal(manifest, &sa); err == nil {
+			sa.SetNamespace(namespace)
+			_, err = o.opClient.UpdateServiceAccount(&sa)
+		}
+	case serviceKind:
+		var s corev1.Service
+		if err = json.Unmarshal(manifest, &s); err == nil {
+			s.SetNamespace(namespace)
+			_, err = o.opClient.UpdateService(&s)
+		}
+	default:
+		previous := &unstructured.Unstructured{}
+		if err = previous.UnmarshalJSON(manifest); err != nil {
+			break
+		}
+		var current *unstructured.Unstructured
+		if current, err = o.unstructuredClient.GetUnstructured(previous.GroupVersionKind(), namespace, previous.GetName()); err != nil {
+			break
+		}
+		previous.SetResourceVersion(current.GetResourceVersion())
+		_, err = o.unstructuredClient.UpdateUnstructured(previous)
+	}
+
+	if err != nil {
+		return errorwrap.Wrapf(err, "error restoring %s %s", step.Resource.Kind, step.Resource.Name)
+	}
+	return nil
+}
@@ -148,7 +148,9 @@ func (c *catalogHealthReconciler) health(now *metav1.Time, catalog *v1alpha1.Cat
 // healthy returns true if the given catalog is healthy, false otherwise, and any error encountered
 // while checking the catalog's registry server.
 func (c *catalogHealthReconciler) healthy(catalog *v1alpha1.CatalogSource) (bool, error) {
-	return c.registryReconcilerFactory.ReconcilerForSource(catalog).CheckRegistryServer(catalog)
+	// The catalog operator's factory reads the connection state recorded by the catalog's sync rather than health
+	// checking the registry server. Checking records that state on the catalog again, so check a copy of the cached one.
+	return c.registryReconcilerFactory.ReconcilerForSource(catalog).CheckRegistryServer(catalog.DeepCopy())
 }
 
 // ReconcilerFromLegacySyncHandler returns a reconciler that invokes the given legacy sync handler and on delete funcs.
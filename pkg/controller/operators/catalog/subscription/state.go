@@ -176,7 +176,7 @@ func (c *catalogHealthState) UpdateHealth(now *metav1.Time, client clientv1alpha
 		healthSet[ref.UID] = h
 		healthy = healthy && h.Healthy
 
-		if ref.Namespace == in.Spec.CatalogSourceNamespace && ref.Name == in.Spec.CatalogSource {
+		if ref.Namespace == in.GetCatalogSourceNamespace() && ref.Name == in.Spec.CatalogSource {
 			missingTargeted = false
 			if !h.Healthy {
 				cond.Message = fmt.Sprintf("targeted catalogsource %s/%s unhealthy", ref.Namespace, ref.Name)
@@ -188,7 +188,7 @@ func (c *catalogHealthState) UpdateHealth(now *metav1.Time, client clientv1alpha
 	switch {
 	case missingTargeted:
 		healthy = false
-		cond.Message = fmt.Sprintf("targeted catalogsource %s/%s missing", in.Spec.CatalogSourceNamespace, in.Spec.CatalogSource)
+		cond.Message = fmt.Sprintf("targeted catalogsource %s/%s missing", in.GetCatalogSourceNamespace(), in.Spec.CatalogSource)
 		fallthrough
 	case !healthy:
 		cond.Status = corev1.ConditionTrue
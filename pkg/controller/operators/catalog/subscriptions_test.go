@@ -25,13 +25,12 @@ func TestSyncSubscriptions(t *testing.T) {
 	testNamespace := "testNamespace"
 
 	type fields struct {
-		clientOptions     []clientfake.Option
-		sourcesLastUpdate metav1.Time
-		resolveSteps      []*v1alpha1.Step
-		resolveSubs       []*v1alpha1.Subscription
-		resolveErr        error
-		existingOLMObjs   []runtime.Object
-		existingObjects   []runtime.Object
+		clientOptions   []clientfake.Option
+		resolveSteps    []*v1alpha1.Step
+		resolveSubs     []*v1alpha1.Subscription
+		resolveErr      error
+		existingOLMObjs []runtime.Object
+		existingObjects []runtime.Object
 	}
 	type args struct {
 		obj interface{}
@@ -658,9 +657,8 @@ func TestSyncSubscriptions(t *testing.T) {
 				},
 			}
 
-			o.sourcesLastUpdate = tt.fields.sourcesLastUpdate
 			o.resolver = &fakes.FakeResolver{
-				ResolveStepsStub: func(string, resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
+				ResolveStepsStub: func(context.Context, string, resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
 					return tt.fields.resolveSteps, tt.fields.resolveSubs, tt.fields.resolveErr
 				},
 			}
@@ -0,0 +1,146 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	errorwrap "github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+// defaultAllowedStepKinds are the kinds without dedicated handling that InstallPlan steps may create or update unless
+// they're denied. They're all namespaced, so that an InstallPlan can't reach beyond its namespace unless an admin
+// allows more kinds.
+var defaultAllowedStepKinds = []string{
+	"ConfigMap",
+	"PodDisruptionBudget.policy",
+	"PrometheusRule.monitoring.coreos.com",
+	"ServiceMonitor.monitoring.coreos.com",
+}
+
+// stepKindPolicy decides which kinds without dedicated handling InstallPlan steps may create or update.
+type stepKindPolicy struct {
+	allowed map[schema.GroupKind]struct{}
+	denied  map[schema.GroupKind]struct{}
+}
+
+// stepKindPattern matches the CamelCase names of kinds.
+var stepKindPattern = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+
+// validStepKinds returns whether every kind is written as Kind.group, or just Kind for the core group, where Kind is a
+// CamelCase kind name and group a DNS subdomain.
+func validStepKinds(kinds []string) bool {
+	for _, kind := range kinds {
+		gk := schema.ParseGroupKind(kind)
+		if !stepKindPattern.MatchString(gk.Kind) || strings.HasSuffix(kind, ".") {
+			return false
+		}
+		if gk.Group != "" && len(validation.IsDNS1123Subdomain(gk.Group)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// newStepKindPolicy returns a policy that allows the default kinds and the given ones, except for the denied kinds.
+func newStepKindPolicy(allowed, denied []string) stepKindPolicy {
+	policy := stepKindPolicy{
+		allowed: map[schema.GroupKind]struct{}{},
+		denied:  map[schema.GroupKind]struct{}{},
+	}
+	for _, kind := range append(append([]string{}, defaultAllowedStepKinds...), allowed...) {
+		policy.allowed[schema.ParseGroupKind(kind)] = struct{}{}
+	}
+	for _, kind := range denied {
+		policy.denied[schema.ParseGroupKind(kind)] = struct{}{}
+	}
+	return policy
+}
+
+// allows returns whether steps may create or update objects of the given kind: it must be allowed and not denied.
+func (p stepKindPolicy) allows(gk schema.GroupKind) bool {
+	if _, ok := p.denied[gk]; ok {
+		return false
+	}
+	_, ok := p.allowed[gk]
+	return ok
+}
+
+// executeUnstructuredStep creates or updates the resource of a step whose kind has no dedicated handling. Namespaced
+// resources are placed in the InstallPlan's namespace and owned by the CSV the step resolves; cluster-scoped resources
+// get owner labels pointing at the CSV instead.
+func (o *Operator) executeUnstructuredStep(plan *v1alpha1.InstallPlan, index int) error {
+	namespace := plan.GetNamespace()
+	step := plan.Status.Plan[index]
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON([]byte(step.Resource.Manifest)); err != nil {
+		return errorwrap.Wrapf(err, "error parsing step manifest: %s", step.Resource.Name)
+	}
+
+	gvk := obj.GroupVersionKind()
+	if gk := gvk.GroupKind(); !o.stepKinds.allows(gk) {
+		return fmt.Errorf("%s %s isn't allowed to be installed by InstallPlans", gk.String(), obj.GetName())
+	}
+
+	namespaced, err := o.unstructuredClient.Namespaced(gvk)
+	if err != nil {
+		return errorwrap.Wrapf(err, "error finding the resource for %s", gvk)
+	}
+
+	csv, err := o.client.OperatorsV1alpha1().ClusterServiceVersions(namespace).Get(step.Resolving, metav1.GetOptions{})
+	if err != nil {
+		return errorwrap.Wrapf(err, "error getting owner csv %s for %s %s", step.Resolving, gvk.Kind, obj.GetName())
+	}
+	if namespaced {
+		obj.SetNamespace(namespace)
+		ownerutil.AddNonBlockingOwner(obj, csv)
+	} else {
+		obj.SetNamespace("")
+		if err := ownerutil.AddOwnerLabels(obj, csv); err != nil {
+			return errorwrap.Wrapf(err, "error adding owner labels to %s %s", gvk.Kind, obj.GetName())
+		}
+	}
+
+	// Attempt to create the object
+	_, err = o.unstructuredClient.CreateUnstructured(obj)
+	if k8serrors.IsAlreadyExists(err) {
+		current, err := o.unstructuredClient.GetUnstructured(gvk, obj.GetNamespace(), obj.GetName())
+		if err != nil {
+			return errorwrap.Wrapf(err, "error getting %s %s", gvk.Kind, obj.GetName())
+		}
+		err = o.recordPrevious(plan, index, func() (metav1.Object, error) {
+			return current.DeepCopy(), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// If it already exists, replace it with the step's manifest
+		obj.SetResourceVersion(current.GetResourceVersion())
+		if _, err := o.unstructuredClient.UpdateUnstructured(obj); err != nil {
+			return errorwrap.Wrapf(err, "error updating %s %s", gvk.Kind, obj.GetName())
+		}
+
+		// Mark as present
+		step.Status = v1alpha1.StepStatusPresent
+	} else if err != nil {
+		return errorwrap.Wrapf(err, "error creating %s %s", gvk.Kind, obj.GetName())
+	} else {
+		// If no error occurred, mark the step as Created
+		step.Status = v1alpha1.StepStatusCreated
+	}
+
+	return nil
+}
+
+func stepGroupVersionKind(resource v1alpha1.StepResource) schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: resource.Group, Version: resource.Version, Kind: resource.Kind}
+}
@@ -0,0 +1,306 @@
+package catalog
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+var (
+	configMapGVK     = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	priorityClassGVK = schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}
+)
+
+// fakeUnstructuredClient keeps objects in memory. It serves the kinds in namespaced, which by default holds
+// ConfigMaps as namespaced and PriorityClasses as cluster-scoped objects. It counts the lists it has served.
+type fakeUnstructuredClient struct {
+	objects    map[string]*unstructured.Unstructured
+	namespaced map[schema.GroupVersionKind]bool
+	lists      int
+}
+
+var _ operatorclient.UnstructuredClient = &fakeUnstructuredClient{}
+
+func newFakeUnstructuredClient(objs ...*unstructured.Unstructured) *fakeUnstructuredClient {
+	c := &fakeUnstructuredClient{
+		objects: map[string]*unstructured.Unstructured{},
+		namespaced: map[schema.GroupVersionKind]bool{
+			configMapGVK:     true,
+			priorityClassGVK: false,
+		},
+	}
+	for _, obj := range objs {
+		c.objects[c.key(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())] = obj.DeepCopy()
+	}
+	return c
+}
+
+func (c *fakeUnstructuredClient) key(gvk schema.GroupVersionKind, namespace, name string) string {
+	if !c.namespaced[gvk] {
+		namespace = ""
+	}
+	return gvk.String() + "/" + namespace + "/" + name
+}
+
+func (c *fakeUnstructuredClient) Namespaced(gvk schema.GroupVersionKind) (bool, error) {
+	namespaced, ok := c.namespaced[gvk]
+	if !ok {
+		return false, k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group}, gvk.Kind)
+	}
+	return namespaced, nil
+}
+
+// ListUnstructured lists objects by key. Pages continue from the index of the first object they don't include.
+func (c *fakeUnstructuredClient) ListUnstructured(gvk schema.GroupVersionKind, namespace string, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.lists++
+	var keys []string
+	for key, obj := range c.objects {
+		if obj.GroupVersionKind() == gvk && (namespace == "" || obj.GetNamespace() == namespace) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if options.Continue != "" {
+		var err error
+		if start, err = strconv.Atoi(options.Continue); err != nil {
+			return nil, k8serrors.NewBadRequest(err.Error())
+		}
+	}
+	end := len(keys)
+	list := &unstructured.UnstructuredList{}
+	if options.Limit > 0 && start+int(options.Limit) < end {
+		end = start + int(options.Limit)
+		list.SetContinue(strconv.Itoa(end))
+	}
+	for _, key := range keys[start:end] {
+		list.Items = append(list.Items, *c.objects[key].DeepCopy())
+	}
+	return list, nil
+}
+
+func (c *fakeUnstructuredClient) GetUnstructured(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, ok := c.objects[c.key(gvk, namespace, name)]
+	if !ok {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeUnstructuredClient) CreateUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	key := c.key(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	if _, ok := c.objects[key]; ok {
+		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: obj.GroupVersionKind().Group, Resource: obj.GetKind()}, obj.GetName())
+	}
+	c.objects[key] = obj.DeepCopy()
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeUnstructuredClient) UpdateUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	key := c.key(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	if _, ok := c.objects[key]; !ok {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: obj.GroupVersionKind().Group, Resource: obj.GetKind()}, obj.GetName())
+	}
+	c.objects[key] = obj.DeepCopy()
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeUnstructuredClient) DeleteUnstructured(gvk schema.GroupVersionKind, namespace, name string, options *metav1.DeleteOptions) error {
+	key := c.key(gvk, namespace, name)
+	if _, ok := c.objects[key]; !ok {
+		return k8serrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, name)
+	}
+	delete(c.objects, key)
+	return nil
+}
+
+func unstructuredObject(gvk schema.GroupVersionKind, namespace, name string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range data {
+		obj.Object[k] = v
+	}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func unstructuredStep(obj *unstructured.Unstructured) *v1alpha1.Step {
+	gvk := obj.GroupVersionKind()
+	return &v1alpha1.Step{
+		Resolving: "csv",
+		Resource: v1alpha1.StepResource{
+			CatalogSource:          "catalog",
+			CatalogSourceNamespace: "ns",
+			Group:                  gvk.Group,
+			Version:                gvk.Version,
+			Kind:                   gvk.Kind,
+			Name:                   obj.GetName(),
+			Manifest:               toManifest(obj),
+		},
+		Status: v1alpha1.StepStatusUnknown,
+	}
+}
+
+func TestStepKindPolicy(t *testing.T) {
+	configMap := schema.GroupKind{Kind: "ConfigMap"}
+	priorityClass := schema.GroupKind{Group: "scheduling.k8s.io", Kind: "PriorityClass"}
+	webhook := schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}
+
+	tests := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		want    map[schema.GroupKind]bool
+	}{
+		{
+			name: "Empty/AllowsDefaults",
+			want: map[schema.GroupKind]bool{configMap: true, priorityClass: false, webhook: false},
+		},
+		{
+			name:    "Allowed/AddedToDefaults",
+			allowed: []string{"PriorityClass.scheduling.k8s.io"},
+			want:    map[schema.GroupKind]bool{configMap: true, priorityClass: true, webhook: false},
+		},
+		{
+			name:   "Denied/OverDefaults",
+			denied: []string{"ConfigMap"},
+			want:   map[schema.GroupKind]bool{configMap: false, priorityClass: false},
+		},
+		{
+			name:    "DeniedOverAllowed",
+			allowed: []string{"ConfigMap", "PriorityClass.scheduling.k8s.io"},
+			denied:  []string{"PriorityClass.scheduling.k8s.io"},
+			want:    map[schema.GroupKind]bool{configMap: true, priorityClass: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newStepKindPolicy(tt.allowed, tt.denied)
+			for gk, want := range tt.want {
+				require.Equal(t, want, policy.allows(gk), gk.String())
+			}
+		})
+	}
+
+	require.True(t, validStepKinds([]string{"ConfigMap", "PriorityClass.scheduling.k8s.io"}))
+	require.False(t, validStepKinds([]string{".scheduling.k8s.io"}))
+	require.False(t, validStepKinds([]string{"ConfigMap."}))
+	require.False(t, validStepKinds([]string{"configmap"}))
+	require.False(t, validStepKinds([]string{" ConfigMap"}))
+	require.False(t, validStepKinds([]string{"PriorityClass.Scheduling_k8s"}))
+}
+
+func TestExecutePlanUnstructuredSteps(t *testing.T) {
+	namespace := "ns"
+	owner := csv("csv", namespace, nil, nil)
+
+	configMap := unstructuredObject(configMapGVK, "", "cm", map[string]interface{}{"data": map[string]interface{}{"key": "new"}})
+	priorityClass := unstructuredObject(priorityClassGVK, "", "pc", map[string]interface{}{"value": int64(1000)})
+	existingConfigMap := unstructuredObject(configMapGVK, namespace, "cm", map[string]interface{}{"data": map[string]interface{}{"key": "old"}})
+
+	tests := []struct {
+		name          string
+		existing      []*unstructured.Unstructured
+		steps         []*v1alpha1.Step
+		allowed       []string
+		denied        []string
+		transactional bool
+		wantErr       string
+		wantStatuses  []v1alpha1.StepStatus
+		wantObjects   map[string]interface{}
+	}{
+		{
+			name:         "Created",
+			steps:        []*v1alpha1.Step{unstructuredStep(configMap), unstructuredStep(priorityClass)},
+			allowed:      []string{"PriorityClass.scheduling.k8s.io"},
+			wantStatuses: []v1alpha1.StepStatus{v1alpha1.StepStatusCreated, v1alpha1.StepStatusCreated},
+			wantObjects:  map[string]interface{}{"cm": "new", "pc": int64(1000)},
+		},
+		{
+			name:         "Existing/Updated",
+			existing:     []*unstructured.Unstructured{existingConfigMap},
+			steps:        []*v1alpha1.Step{unstructuredStep(configMap)},
+			wantStatuses: []v1alpha1.StepStatus{v1alpha1.StepStatusPresent},
+			wantObjects:  map[string]interface{}{"cm": "new"},
+		},
+		{
+			name:         "NotAllowedByDefault",
+			steps:        []*v1alpha1.Step{unstructuredStep(configMap), unstructuredStep(priorityClass)},
+			wantErr:      "PriorityClass.scheduling.k8s.io pc isn't allowed to be installed by InstallPlans",
+			wantStatuses: []v1alpha1.StepStatus{v1alpha1.StepStatusCreated, v1alpha1.StepStatusUnknown},
+			wantObjects:  map[string]interface{}{"cm": "new"},
+		},
+		{
+			name:          "Denied/Transactional/RolledBack",
+			existing:      []*unstructured.Unstructured{existingConfigMap},
+			steps:         []*v1alpha1.Step{unstructuredStep(configMap), unstructuredStep(priorityClass)},
+			allowed:       []string{"PriorityClass.scheduling.k8s.io"},
+			denied:        []string{"PriorityClass.scheduling.k8s.io"},
+			transactional: true,
+			wantErr:       "PriorityClass.scheduling.k8s.io pc isn't allowed to be installed by InstallPlans",
+			wantStatuses:  []v1alpha1.StepStatus{v1alpha1.StepStatusRolledBack, v1alpha1.StepStatusUnknown},
+			wantObjects:   map[string]interface{}{"cm": "old"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			plan := withSteps(installPlan("p", namespace, v1alpha1.InstallPlanPhaseInstalling), tt.steps)
+			op, err := NewFakeOperator(ctx, namespace, []string{namespace}, withClientObjs(plan, owner))
+			require.NoError(t, err)
+			client := newFakeUnstructuredClient(tt.existing...)
+			op.unstructuredClient = client
+			op.stepKinds = newStepKindPolicy(tt.allowed, tt.denied)
+			op.transactionalInstallPlans = tt.transactional
+
+			err = op.ExecutePlan(plan)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var statuses []v1alpha1.StepStatus
+			for _, step := range plan.Status.Plan {
+				statuses = append(statuses, step.Status)
+			}
+			require.Equal(t, tt.wantStatuses, statuses)
+
+			objects := map[string]interface{}{}
+			for _, obj := range client.objects {
+				switch obj.GroupVersionKind() {
+				case configMapGVK:
+					objects[obj.GetName()], _, _ = unstructured.NestedString(obj.Object, "data", "key")
+					if tt.wantObjects[obj.GetName()] == "new" {
+						require.Equal(t, namespace, obj.GetNamespace())
+						require.Len(t, obj.GetOwnerReferences(), 1)
+						require.Equal(t, owner.GetName(), obj.GetOwnerReferences()[0].Name)
+						require.Equal(t, v1alpha1.ClusterServiceVersionKind, obj.GetOwnerReferences()[0].Kind)
+					}
+				case priorityClassGVK:
+					objects[obj.GetName()], _, _ = unstructured.NestedInt64(obj.Object, "value")
+					require.Empty(t, obj.GetNamespace())
+					require.Empty(t, obj.GetOwnerReferences())
+					require.Equal(t, owner.GetName(), obj.GetLabels()[ownerutil.OwnerKey])
+					require.Equal(t, namespace, obj.GetLabels()[ownerutil.OwnerNamespaceKey])
+				}
+			}
+			require.Equal(t, tt.wantObjects, objects)
+		})
+	}
+}
@@ -285,17 +285,17 @@ func (a *Operator) installOwnedAPIServiceRequirements(csv *v1alpha1.ClusterServi
 		"namespace": csv.GetNamespace(),
 	})
 
-	// Assume the strategy is for a deployment
+	// Return early if there are no owned APIServices
+	if len(csv.Spec.APIServiceDefinitions.Owned) == 0 {
+		return strategy, nil
+	}
+
+	// Owned APIServices are served from deployments
 	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
 	if !ok {
 		return nil, fmt.Errorf("unsupported InstallStrategy type")
 	}
 
-	// Return early if there are no owned APIServices
-	if len(csv.Spec.APIServiceDefinitions.Owned) == 0 {
-		return strategyDetailsDeployment, nil
-	}
-
 	// Create the CA
 	expiration := time.Now().Add(DefaultCertValidFor)
 	ca, err := certs.GenerateCA(expiration, Organization)
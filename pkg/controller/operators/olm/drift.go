@@ -0,0 +1,285 @@
+package olm
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/install"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+const (
+	componentKindDeployment         = "Deployment"
+	componentKindServiceAccount     = "ServiceAccount"
+	componentKindRole               = "Role"
+	componentKindRoleBinding        = "RoleBinding"
+	componentKindClusterRole        = "ClusterRole"
+	componentKindClusterRoleBinding = "ClusterRoleBinding"
+)
+
+// specHash returns a hash of the given spec.
+func specHash(spec interface{}) string {
+	// The specs are plain API types, which always marshal
+	data, _ := json.Marshal(spec)
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+// componentHashes returns the spec hashes of the deployments, roles and role bindings owned by the given CSV, of the
+// cluster roles and cluster role bindings labeled as owned by it, and of the service accounts its install strategy runs
+// as, sorted by kind and name.
+// The pod template annotations and replicas of deployments aren't hashed, since OLM updates the former when operator
+// groups change and the latter may be scaled.
+func (a *Operator) componentHashes(csv *v1alpha1.ClusterServiceVersion, strategy install.Strategy) ([]v1alpha1.ComponentHash, error) {
+	components := []v1alpha1.ComponentHash{}
+	ownerSelector := ownerutil.CSVOwnerSelector(csv)
+
+	deployments, err := a.lister.AppsV1().DeploymentLister().Deployments(csv.GetNamespace()).List(ownerSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		if !ownerutil.IsOwnedBy(d, csv) {
+			continue
+		}
+		components = append(components, v1alpha1.ComponentHash{
+			Kind: componentKindDeployment,
+			Name: d.GetName(),
+			Hash: specHash(struct {
+				Selector *metav1.LabelSelector
+				Labels   map[string]string
+				Spec     corev1.PodSpec
+			}{d.Spec.Selector, d.Spec.Template.GetLabels(), d.Spec.Template.Spec}),
+		})
+	}
+
+	if strategyWithPermissions, ok := strategy.(install.StrategyWithPermissions); ok {
+		names := map[string]struct{}{}
+		for _, perm := range strategyWithPermissions.GetPermissions() {
+			names[perm.ServiceAccountName] = struct{}{}
+		}
+		for _, perm := range strategyWithPermissions.GetClusterPermissions() {
+			names[perm.ServiceAccountName] = struct{}{}
+		}
+		for name := range names {
+			sa, err := a.lister.CoreV1().ServiceAccountLister().ServiceAccounts(csv.GetNamespace()).Get(name)
+			if k8serrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			components = append(components, v1alpha1.ComponentHash{
+				Kind: componentKindServiceAccount,
+				Name: sa.GetName(),
+				Hash: specHash(sa.AutomountServiceAccountToken),
+			})
+		}
+	}
+
+	roles, err := a.lister.RbacV1().RoleLister().Roles(csv.GetNamespace()).List(ownerSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		if !ownerutil.IsOwnedBy(r, csv) {
+			continue
+		}
+		components = append(components, v1alpha1.ComponentHash{
+			Kind: componentKindRole,
+			Name: r.GetName(),
+			Hash: specHash(r.Rules),
+		})
+	}
+
+	roleBindings, err := a.lister.RbacV1().RoleBindingLister().RoleBindings(csv.GetNamespace()).List(ownerSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range roleBindings {
+		if !ownerutil.IsOwnedBy(rb, csv) {
+			continue
+		}
+		components = append(components, v1alpha1.ComponentHash{
+			Kind: componentKindRoleBinding,
+			Name: rb.GetName(),
+			Hash: specHash(struct {
+				RoleRef  rbacv1.RoleRef
+				Subjects []rbacv1.Subject
+			}{rb.RoleRef, rb.Subjects}),
+		})
+	}
+
+	// Cluster roles and bindings can't have owner references to a CSV, so they're only found by their owner labels
+	clusterRoles, err := a.lister.RbacV1().ClusterRoleLister().List(ownerSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, cr := range clusterRoles {
+		components = append(components, v1alpha1.ComponentHash{
+			Kind: componentKindClusterRole,
+			Name: cr.GetName(),
+			Hash: specHash(cr.Rules),
+		})
+	}
+
+	clusterRoleBindings, err := a.lister.RbacV1().ClusterRoleBindingLister().List(ownerSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, crb := range clusterRoleBindings {
+		components = append(components, v1alpha1.ComponentHash{
+			Kind: componentKindClusterRoleBinding,
+			Name: crb.GetName(),
+			Hash: specHash(struct {
+				RoleRef  rbacv1.RoleRef
+				Subjects []rbacv1.Subject
+			}{crb.RoleRef, crb.Subjects}),
+		})
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Kind != components[j].Kind {
+			return components[i].Kind < components[j].Kind
+		}
+		return components[i].Name < components[j].Name
+	})
+
+	return components, nil
+}
+
+// driftedComponents returns the components recorded in the status of the given CSV that are now missing or whose spec
+// no longer matches the recorded hash.
+func (a *Operator) driftedComponents(csv *v1alpha1.ClusterServiceVersion, strategy install.Strategy) ([]v1alpha1.ComponentHash, error) {
+	if len(csv.Status.Components) == 0 {
+		return nil, nil
+	}
+
+	components, err := a.componentHashes(csv, strategy)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]string{}
+	for _, c := range components {
+		current[c.Kind+"/"+c.Name] = c.Hash
+	}
+
+	var drifted []v1alpha1.ComponentHash
+	for _, recorded := range csv.Status.Components {
+		if hash, ok := current[recorded.Kind+"/"+recorded.Name]; !ok || hash != recorded.Hash {
+			drifted = append(drifted, recorded)
+		}
+	}
+	return drifted, nil
+}
+
+// driftMessage describes the given drifted components.
+func driftMessage(drifted []v1alpha1.ComponentHash) string {
+	names := make([]string, 0, len(drifted))
+	for _, c := range drifted {
+		names = append(names, c.Kind+"/"+c.Name)
+	}
+	return fmt.Sprintf("components drifted from their installed spec: %s", strings.Join(names, ", "))
+}
+
+// reinstallPermissions restores the drifted service accounts of the given CSV, and replaces all of the roles, role
+// bindings, cluster roles and cluster role bindings recorded in its status with ones generated from its install strategy
+// if any of them drifted. Deployments are reinstalled by the install strategy.
+func (a *Operator) reinstallPermissions(csv *v1alpha1.ClusterServiceVersion, drifted []v1alpha1.ComponentHash) error {
+	permissions, err := resolver.RBACForClusterServiceVersion(csv)
+	if err != nil {
+		return err
+	}
+
+	reinstallRoles := false
+	for _, c := range drifted {
+		switch c.Kind {
+		case componentKindServiceAccount:
+			if _, ok := permissions[c.Name]; !ok {
+				continue
+			}
+			if err := a.reinstallServiceAccount(csv, c.Name); err != nil {
+				return err
+			}
+		case componentKindRole, componentKindRoleBinding, componentKindClusterRole, componentKindClusterRoleBinding:
+			reinstallRoles = true
+		}
+	}
+	if !reinstallRoles {
+		return nil
+	}
+
+	// Create the new roles and bindings before deleting the old ones, so that the operator keeps its permissions
+	for _, perms := range permissions {
+		for _, role := range perms.Roles {
+			if _, err := a.opClient.CreateRole(role); err != nil {
+				return err
+			}
+		}
+		for _, roleBinding := range perms.RoleBindings {
+			if _, err := a.opClient.CreateRoleBinding(roleBinding); err != nil {
+				return err
+			}
+		}
+		for _, clusterRole := range perms.ClusterRoles {
+			if _, err := a.opClient.CreateClusterRole(clusterRole); err != nil {
+				return err
+			}
+		}
+		for _, clusterRoleBinding := range perms.ClusterRoleBindings {
+			// Cluster role bindings are generated in the CSV's namespace, which a cluster scoped object can't be in
+			clusterRoleBinding.SetNamespace("")
+			if _, err := a.opClient.CreateClusterRoleBinding(clusterRoleBinding); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range csv.Status.Components {
+		var err error
+		switch c.Kind {
+		case componentKindRole:
+			err = a.opClient.DeleteRole(csv.GetNamespace(), c.Name, &metav1.DeleteOptions{})
+		case componentKindRoleBinding:
+			err = a.opClient.DeleteRoleBinding(csv.GetNamespace(), c.Name, &metav1.DeleteOptions{})
+		case componentKindClusterRole:
+			err = a.opClient.DeleteClusterRole(c.Name, &metav1.DeleteOptions{})
+		case componentKindClusterRoleBinding:
+			err = a.opClient.DeleteClusterRoleBinding(c.Name, &metav1.DeleteOptions{})
+		}
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reinstallServiceAccount creates the named service account of the given CSV if it's missing, or resets its spec.
+func (a *Operator) reinstallServiceAccount(csv *v1alpha1.ClusterServiceVersion, name string) error {
+	existing, err := a.opClient.GetServiceAccount(csv.GetNamespace(), name)
+	if k8serrors.IsNotFound(err) {
+		sa := &corev1.ServiceAccount{}
+		sa.SetName(name)
+		sa.SetNamespace(csv.GetNamespace())
+		ownerutil.AddNonBlockingOwner(sa, csv)
+		_, err = a.opClient.CreateServiceAccount(sa)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	existing.AutomountServiceAccountToken = nil
+	_, err = a.opClient.UpdateServiceAccount(existing)
+	return err
+}
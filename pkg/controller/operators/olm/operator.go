@@ -11,11 +11,11 @@ import (
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	extinf "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilclock "k8s.io/apimachinery/pkg/util/clock"
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
@@ -172,6 +172,13 @@ func newOperatorWithConfig(ctx context.Context, config *operatorConfig) (*Operat
 		}
 		op.RegisterQueueInformer(csvGCQueueInformer)
 
+		// Wire Subscriptions, which failed upgrades are rolled back for
+		subInformer := extInformerFactory.Operators().V1alpha1().Subscriptions()
+		op.lister.OperatorsV1alpha1().RegisterSubscriptionLister(namespace, subInformer.Lister())
+		if err := op.RegisterInformer(subInformer.Informer()); err != nil {
+			return nil, err
+		}
+
 		// Wire OperatorGroup reconciliation
 		operatorGroupInformer := extInformerFactory.Operators().V1().OperatorGroups()
 		op.lister.OperatorsV1().RegisterOperatorGroupLister(namespace, operatorGroupInformer.Lister())
@@ -204,6 +211,34 @@ func newOperatorWithConfig(ctx context.Context, config *operatorConfig) (*Operat
 		}
 		op.RegisterQueueInformer(depQueueInformer)
 
+		// Wire StatefulSets
+		stsInformer := k8sInformerFactory.Apps().V1().StatefulSets()
+		op.lister.AppsV1().RegisterStatefulSetLister(namespace, stsInformer.Lister())
+		stsQueueInformer, err := queueinformer.NewQueueInformer(
+			ctx,
+			queueinformer.WithLogger(op.logger),
+			queueinformer.WithInformer(stsInformer.Informer()),
+			queueinformer.WithSyncer(k8sSyncer),
+		)
+		if err != nil {
+			return nil, err
+		}
+		op.RegisterQueueInformer(stsQueueInformer)
+
+		// Wire DaemonSets
+		dsInformer := k8sInformerFactory.Apps().V1().DaemonSets()
+		op.lister.AppsV1().RegisterDaemonSetLister(namespace, dsInformer.Lister())
+		dsQueueInformer, err := queueinformer.NewQueueInformer(
+			ctx,
+			queueinformer.WithLogger(op.logger),
+			queueinformer.WithInformer(dsInformer.Informer()),
+			queueinformer.WithSyncer(k8sSyncer),
+		)
+		if err != nil {
+			return nil, err
+		}
+		op.RegisterQueueInformer(dsQueueInformer)
+
 		// Set up RBAC informers
 		roleInformer := k8sInformerFactory.Rbac().V1().Roles()
 		op.lister.RbacV1().RegisterRoleLister(namespace, roleInformer.Lister())
@@ -673,7 +708,8 @@ func (a *Operator) syncClusterServiceVersion(obj interface{}) (syncError error)
 	if !(outCSV.Status.LastUpdateTime == clusterServiceVersion.Status.LastUpdateTime &&
 		outCSV.Status.Phase == clusterServiceVersion.Status.Phase &&
 		outCSV.Status.Reason == clusterServiceVersion.Status.Reason &&
-		outCSV.Status.Message == clusterServiceVersion.Status.Message) {
+		outCSV.Status.Message == clusterServiceVersion.Status.Message &&
+		equality.Semantic.DeepEqual(outCSV.Status.Components, clusterServiceVersion.Status.Components)) {
 
 		// Update CSV with status of transition. Log errors if we can't write them to the status.
 		_, err := a.client.OperatorsV1alpha1().ClusterServiceVersions(outCSV.GetNamespace()).UpdateStatus(outCSV)
@@ -867,6 +903,18 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 	out = in.DeepCopy()
 	now := a.now()
 
+	// A rolled back upgrade is left as it is while the CSV it was rolled back to is around, and is deleted once that CSV
+	// has been replaced
+	if out.IsRolledBack() {
+		if prev := a.isReplacing(out); prev != nil {
+			logger.Debug("upgrade was rolled back, skipping")
+			return
+		}
+		logger.Info("deleting rolled back upgrade")
+		syncError = a.client.OperatorsV1alpha1().ClusterServiceVersions(out.GetNamespace()).Delete(out.GetName(), metav1.NewDeleteOptions(0))
+		return
+	}
+
 	operatorSurface, err := resolver.NewOperatorFromV1Alpha1CSV(out)
 	if err != nil {
 		// TODO: Add failure status to CSV
@@ -941,7 +989,7 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 		if out.Status.Reason != v1alpha1.CSVReasonInterOperatorGroupOwnerConflict {
 			logger.WithField("apis", providedAPIs).Warn("cannot modify provided apis of static provided api operatorgroup")
 			out.SetPhaseWithEvent(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonCannotModifyStaticOperatorGroupProvidedAPIs, "static provided api operatorgroup cannot be modified by these apis", now, a.recorder)
-			a.cleanupCSVDeployments(logger, out)
+			a.cleanupCSVDeployments(logger, out, nil)
 		}
 		return
 	case result == resolver.APIConflict:
@@ -949,7 +997,7 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 		if out.Status.Reason != v1alpha1.CSVReasonInterOperatorGroupOwnerConflict {
 			logger.WithField("apis", providedAPIs).Warn("intersecting operatorgroups provide the same apis")
 			out.SetPhaseWithEvent(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonInterOperatorGroupOwnerConflict, "intersecting operatorgroups provide the same apis", now, a.recorder)
-			a.cleanupCSVDeployments(logger, out)
+			a.cleanupCSVDeployments(logger, out, nil)
 		}
 		return
 	case result == resolver.AddAPIs:
@@ -1044,6 +1092,7 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 		if strategy == nil {
 			return
 		}
+		startInstallAttempt(out, now)
 
 		// Install owned APIServices and update strategy with serving cert data
 		strategy, syncError = a.installOwnedAPIServiceRequirements(out, strategy)
@@ -1052,8 +1101,10 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 			return
 		}
 
+		// The installed components change, so their spec hashes are recorded again once the CSV succeeds
+		out.Status.Components = nil
 		if syncError = installer.Install(strategy); syncError != nil {
-			out.SetPhaseWithEvent(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonComponentFailed, fmt.Sprintf("install strategy failed: %s", syncError), now, a.recorder)
+			a.failInstallAttempt(out, v1alpha1.CSVReasonComponentFailed, fmt.Sprintf("install strategy failed: %s", syncError), now)
 			return
 		}
 
@@ -1070,13 +1121,15 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 			return
 		}
 
+		// Retry a failed install attempt once its backoff has passed
+		if a.waitForInstallRetry(out, now) {
+			return
+		}
+
 		if installErr := a.updateInstallStatus(out, installer, strategy, v1alpha1.CSVPhaseInstalling, v1alpha1.CSVReasonWaiting); installErr == nil {
 			logger.WithField("strategy", out.Spec.InstallStrategy.StrategyName).Infof("install strategy successful")
-		} else {
-			// Set phase to failed if it's been a long time since the last transition (5 minutes)
-			if metav1.Now().Sub(out.Status.LastTransitionTime.Time) >= 5*time.Minute {
-				out.SetPhaseWithEvent(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonInstallCheckFailed, fmt.Sprintf("install timeout"), now, a.recorder)
-			}
+		} else if out.Status.Phase == v1alpha1.CSVPhaseInstalling {
+			a.checkInstallAttempt(out, installErr, now)
 		}
 
 	case v1alpha1.CSVPhaseSucceeded:
@@ -1103,6 +1156,23 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 			return
 		}
 
+		// Reinstall any components that drifted from the specs recorded when the CSV succeeded
+		drifted, err := a.driftedComponents(out, strategy)
+		if err != nil {
+			logger.WithError(err).Info("couldn't check components for drift")
+			syncError = err
+			return
+		}
+		if len(drifted) > 0 {
+			if syncError = a.reinstallPermissions(out, drifted); syncError != nil {
+				logger.WithError(syncError).Info("couldn't reinstall drifted permissions")
+				return
+			}
+			out.Status.Components = nil
+			out.SetPhaseWithEvent(v1alpha1.CSVPhasePending, v1alpha1.CSVReasonComponentDrifted, driftMessage(drifted), now, a.recorder)
+			return
+		}
+
 		// Ensure requirements are still present
 		met, statuses, err := a.requirementAndPermissionStatus(out)
 		if err != nil {
@@ -1128,7 +1198,21 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 			return
 		}
 
+		// Record the spec hashes of the installed components to detect drift from them
+		if len(out.Status.Components) == 0 {
+			if out.Status.Components, syncError = a.componentHashes(out, strategy); syncError != nil {
+				logger.WithError(syncError).Info("couldn't record the spec hashes of installed components")
+				return
+			}
+		}
+
 	case v1alpha1.CSVPhaseFailed:
+		// Roll back a failed upgrade if its Subscription asks for it
+		if rolledBack, err := a.rollbackUpgrade(logger, out, now); err != nil || rolledBack {
+			syncError = err
+			return
+		}
+
 		installer, strategy := a.parseStrategiesAndUpdateStatus(out)
 		if strategy == nil {
 			return
@@ -1230,6 +1314,79 @@ func (a *Operator) transitionCSVState(in v1alpha1.ClusterServiceVersion) (out *v
 	return
 }
 
+// rollbackReasons are the reasons for failing to install that roll back an upgrade
+var rollbackReasons = map[v1alpha1.ConditionReason]struct{}{
+	v1alpha1.CSVReasonComponentFailed:         {},
+	v1alpha1.CSVReasonInstallCheckFailed:      {},
+	v1alpha1.CSVReasonAPIServiceInstallFailed: {},
+}
+
+// rollbackUpgrade rolls back the upgrade to the given failed CSV if it failed to install within the rollback window of
+// its Subscription. The CSV it replaces goes back to InstallReady to have its install strategy applied again, and the
+// failed CSV is marked as rolled back so that it no longer replaces it. The Subscription progresses from the CSV it
+// replaces again, so that the next upgrade replaces that CSV. Returns true if the upgrade was rolled back.
+func (a *Operator) rollbackUpgrade(logger *logrus.Entry, csv *v1alpha1.ClusterServiceVersion, now metav1.Time) (bool, error) {
+	if _, ok := rollbackReasons[csv.Status.Reason]; !ok {
+		return false, nil
+	}
+
+	prev := a.isReplacing(csv)
+	if prev == nil || prev.Status.Phase != v1alpha1.CSVPhaseReplacing {
+		return false, nil
+	}
+
+	sub, err := a.subscriptionForCSV(csv)
+	if err != nil {
+		return false, err
+	}
+	if sub == nil || sub.Spec == nil || sub.Spec.RollbackPolicy == nil {
+		return false, nil
+	}
+	if now.Sub(csv.GetCreationTimestamp().Time) > sub.Spec.RollbackPolicy.GetWindow() {
+		logger.Debug("upgrade failed outside of its rollback window")
+		return false, nil
+	}
+
+	logger = logger.WithField("replaced", prev.GetName())
+	logger.Info("rolling back failed upgrade")
+	failure := csv.Status.Message
+	prev.SetPhaseWithEvent(v1alpha1.CSVPhaseInstallReady, v1alpha1.CSVReasonUpgradeRolledBack, fmt.Sprintf("upgrade to %s was rolled back: %s", csv.GetName(), failure), now, a.recorder)
+	if _, err := a.client.OperatorsV1alpha1().ClusterServiceVersions(prev.GetNamespace()).UpdateStatus(prev); err != nil {
+		return false, err
+	}
+	csv.SetPhaseWithEvent(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonRolledBack, fmt.Sprintf("rolled back to %s: %s", prev.GetName(), failure), now, a.recorder)
+
+	// Remove the failed CSV's components, except the ones the CSV it replaced installs again
+	a.cleanupCSVDeployments(logger, csv, prev)
+
+	// Let automation watching the Subscription know right away
+	out := sub.DeepCopy()
+	out.Status.State = v1alpha1.SubscriptionStateUpgradeRolledBack
+	out.Status.CurrentCSV = prev.GetName()
+	out.Status.InstalledCSV = prev.GetName()
+	out.Status.LastUpdated = now
+	if _, err := a.client.OperatorsV1alpha1().Subscriptions(out.GetNamespace()).UpdateStatus(out); err != nil {
+		logger.WithError(err).Warn("couldn't record rolled back upgrade on subscription")
+	}
+
+	return true, nil
+}
+
+// subscriptionForCSV returns the Subscription progressing to the given CSV, or nil if there isn't one.
+func (a *Operator) subscriptionForCSV(csv *v1alpha1.ClusterServiceVersion) (*v1alpha1.Subscription, error) {
+	subs, err := a.lister.OperatorsV1alpha1().SubscriptionLister().Subscriptions(csv.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.Status.CurrentCSV == csv.GetName() {
+			return sub, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // csvSet gathers all CSVs in the given namespace into a map keyed by CSV name; if metav1.NamespaceAll gets the set across all namespaces
 func (a *Operator) csvSet(namespace string, phase v1alpha1.ClusterServiceVersionPhase) map[string]*v1alpha1.ClusterServiceVersion {
 	return a.csvSetGenerator.WithNamespace(namespace, phase)
@@ -1262,8 +1419,9 @@ func (a *Operator) updateInstallStatus(csv *v1alpha1.ClusterServiceVersion, inst
 		return nil
 	}
 
-	// installcheck determined we can't progress (e.g. deployment failed to come up in time)
-	if install.IsErrorUnrecoverable(strategyErr) {
+	// installcheck determined we can't progress (e.g. deployment failed to come up in time). Components that time out
+	// while the CSV is installing are left to its install policy.
+	if install.IsErrorUnrecoverable(strategyErr) && !(requeuePhase == v1alpha1.CSVPhaseInstalling && install.IsErrorTimeout(strategyErr)) {
 		csv.SetPhaseWithEventIfChanged(v1alpha1.CSVPhaseFailed, v1alpha1.CSVReasonInstallCheckFailed, fmt.Sprintf("install failed: %s", strategyErr), now, a.recorder)
 		return strategyErr
 	}
@@ -1282,6 +1440,12 @@ func (a *Operator) updateInstallStatus(csv *v1alpha1.ClusterServiceVersion, inst
 		return fmt.Errorf("APIServices not installed")
 	}
 
+	// a succeeded CSV whose overrides changed is installed again, rather than failing until its components match them
+	if csv.Status.Phase == v1alpha1.CSVPhaseSucceeded && install.IsErrorOverridesMissing(strategyErr) {
+		csv.SetPhaseWithEvent(v1alpha1.CSVPhasePending, v1alpha1.CSVReasonOverridesChanged, fmt.Sprintf("overrides changed: %s", strategyErr), now, a.recorder)
+		return strategyErr
+	}
+
 	if strategyErr != nil {
 		csv.SetPhaseWithEventIfChanged(requeuePhase, requeueConditionReason, fmt.Sprintf("installing: %s", strategyErr), now, a.recorder)
 		if err := a.csvQueueSet.Requeue(csv.GetNamespace(), csv.GetName()); err != nil {
@@ -1294,6 +1458,83 @@ func (a *Operator) updateInstallStatus(csv *v1alpha1.ClusterServiceVersion, inst
 	return nil
 }
 
+// startInstallAttempt records the start of an install attempt for the given CSV. Attempts only count up while its
+// install is being retried.
+func startInstallAttempt(csv *v1alpha1.ClusterServiceVersion, now metav1.Time) {
+	attempt := &v1alpha1.InstallAttemptStatus{Attempt: 1, StartTime: now}
+	if previous := csv.Status.Install; previous != nil && csv.Status.Reason == v1alpha1.CSVReasonInstallRetrying {
+		attempt.Attempt = previous.Attempt + 1
+		attempt.LastFailure = previous.LastFailure
+	}
+	csv.Status.Install = attempt
+}
+
+// installAttempt returns the current install attempt of the given CSV. CSVs that started installing before attempts
+// were tracked are on their first, started when they last transitioned.
+func installAttempt(csv *v1alpha1.ClusterServiceVersion) *v1alpha1.InstallAttemptStatus {
+	if csv.Status.Install == nil {
+		csv.Status.Install = &v1alpha1.InstallAttemptStatus{Attempt: 1, StartTime: csv.Status.LastTransitionTime}
+	}
+	return csv.Status.Install
+}
+
+// checkInstallAttempt fails the current install attempt of a CSV whose components aren't ready once it's timed out,
+// or once a component has timed out on its own if the CSV doesn't set an install timeout.
+func (a *Operator) checkInstallAttempt(csv *v1alpha1.ClusterServiceVersion, installErr error, now metav1.Time) {
+	policy := csv.Spec.InstallPolicy
+	if install.IsErrorTimeout(installErr) && !policy.HasTimeout() {
+		a.failInstallAttempt(csv, v1alpha1.CSVReasonInstallCheckFailed, fmt.Sprintf("install failed: %s", installErr), now)
+		return
+	}
+
+	if now.Sub(installAttempt(csv).StartTime.Time) >= policy.GetTimeout() {
+		a.failInstallAttempt(csv, v1alpha1.CSVReasonInstallCheckFailed, "install timeout", now)
+	}
+}
+
+// failInstallAttempt fails the current install attempt of a CSV. The install is retried after a backoff while the
+// install policy of the CSV has retries left, and the CSV fails with the given reason once it doesn't.
+func (a *Operator) failInstallAttempt(csv *v1alpha1.ClusterServiceVersion, reason v1alpha1.ConditionReason, message string, now metav1.Time) {
+	attempt := installAttempt(csv)
+	attempt.LastFailure = message
+
+	policy := csv.Spec.InstallPolicy
+	if attempt.Attempt > policy.GetRetries() {
+		csv.SetPhaseWithEvent(v1alpha1.CSVPhaseFailed, reason, message, now, a.recorder)
+		return
+	}
+
+	backoff := policy.RetryBackoff(attempt.Attempt)
+	retryTime := metav1.NewTime(now.Add(backoff))
+	attempt.RetryTime = &retryTime
+	csv.SetPhaseWithEvent(v1alpha1.CSVPhaseInstalling, v1alpha1.CSVReasonInstallAttemptFailed, fmt.Sprintf("install attempt %d of %d failed, retrying in %s: %s", attempt.Attempt, policy.GetRetries()+1, backoff, message), now, a.recorder)
+	if err := a.csvQueueSet.RequeueAfter(csv.GetNamespace(), csv.GetName(), backoff); err != nil {
+		a.logger.Warn(err.Error())
+	}
+}
+
+// waitForInstallRetry returns true while the given CSV is waiting to retry a failed install attempt, and moves it back
+// to InstallReady once its backoff has passed.
+func (a *Operator) waitForInstallRetry(csv *v1alpha1.ClusterServiceVersion, now metav1.Time) bool {
+	attempt := csv.Status.Install
+	if attempt == nil || attempt.RetryTime == nil {
+		return false
+	}
+
+	if wait := attempt.RetryTime.Sub(now.Time); wait > 0 {
+		if err := a.csvQueueSet.RequeueAfter(csv.GetNamespace(), csv.GetName(), wait); err != nil {
+			a.logger.Warn(err.Error())
+		}
+		return true
+	}
+
+	csv.SetPhaseWithEvent(v1alpha1.CSVPhaseInstallReady, v1alpha1.CSVReasonInstallRetrying, fmt.Sprintf("retrying install, attempt %d of %d", attempt.Attempt+1, csv.Spec.InstallPolicy.GetRetries()+1), now, a.recorder)
+	if err := a.csvQueueSet.Requeue(csv.GetNamespace(), csv.GetName()); err != nil {
+		a.logger.Warn(err.Error())
+	}
+	return true
+}
+
 // parseStrategiesAndUpdateStatus returns a StrategyInstaller and a Strategy for a CSV if it can, else it sets a status on the CSV and returns
 func (a *Operator) parseStrategiesAndUpdateStatus(csv *v1alpha1.ClusterServiceVersion) (install.StrategyInstaller, install.Strategy) {
 	strategy, err := a.resolver.UnmarshalStrategy(csv.Spec.InstallStrategy)
@@ -1503,7 +1744,9 @@ func (a *Operator) requeueOwnerCSVs(ownee metav1.Object) {
 	}
 }
 
-func (a *Operator) cleanupCSVDeployments(logger *logrus.Entry, csv *v1alpha1.ClusterServiceVersion) {
+// cleanupCSVDeployments deletes the components of a CSV. If the CSV it replaced is given, the components that CSV also
+// has are left in place for it.
+func (a *Operator) cleanupCSVDeployments(logger *logrus.Entry, csv, replaced *v1alpha1.ClusterServiceVersion) {
 	// Extract the InstallStrategy for the deployment
 	strategy, err := a.resolver.UnmarshalStrategy(csv.Spec.InstallStrategy)
 	if err != nil {
@@ -1511,20 +1754,19 @@ func (a *Operator) cleanupCSVDeployments(logger *logrus.Entry, csv *v1alpha1.Clu
 		return
 	}
 
-	// Assume the strategy is for a deployment
-	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
-	if !ok {
-		logger.Warnf("could not cast install strategy as type %T", strategyDetailsDeployment)
-		return
+	var previousStrategy install.Strategy
+	if replaced != nil {
+		if previousStrategy, err = a.resolver.UnmarshalStrategy(replaced.Spec.InstallStrategy); err != nil {
+			logger.Warn("could not parse install strategy of replaced CSV while cleaning up CSV deployment")
+			return
+		}
 	}
 
-	// Delete deployments
-	for _, spec := range strategyDetailsDeployment.DeploymentSpecs {
-		logger := logger.WithField("deployment", spec.Name)
-		logger.Debug("cleaning up CSV deployment")
-		if err := a.opClient.DeleteDeployment(csv.GetNamespace(), spec.Name, &metav1.DeleteOptions{}); err != nil {
-			logger.WithField("err", err).Warn("error cleaning up CSV deployment")
-		}
+	// Delete the strategy's components
+	logger.Debug("cleaning up CSV components")
+	installer := a.resolver.InstallerForStrategy(strategy.GetStrategyName(), a.opClient, a.lister, csv, nil, previousStrategy)
+	if err := installer.Uninstall(strategy); err != nil {
+		logger.WithField("err", err).Warn("error cleaning up CSV components")
 	}
 }
 
@@ -1543,43 +1785,14 @@ func (a *Operator) ensureDeploymentAnnotations(logger *logrus.Entry, csv *v1alph
 		return nil
 	}
 
-	// Assume the strategy is for a deployment
-	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
-	if !ok {
-		logger.Warnf("could not cast install strategy as type %T", strategyDetailsDeployment)
-		return nil
-	}
-
-	existingDeployments, err := a.lister.AppsV1().DeploymentLister().Deployments(csv.GetNamespace()).List(ownerutil.CSVOwnerSelector(csv))
-	if err != nil {
+	// update the annotations of any components that are missing them
+	installer := a.resolver.InstallerForStrategy(strategy.GetStrategyName(), a.opClient, a.lister, csv, annotations, nil)
+	if err := installer.UpdateTemplateAnnotations(); err != nil {
 		return err
 	}
-
-	// compare deployments to see if any need to be created/updated
-	updateErrs := []error{}
-	for _, dep := range existingDeployments {
-		if dep.Spec.Template.Annotations == nil {
-			dep.Spec.Template.Annotations = map[string]string{}
-		}
-
-		changed := false
-		for key, value := range annotations {
-			if v, ok := dep.Spec.Template.Annotations[key]; !ok || v != value {
-				dep.Spec.Template.Annotations[key] = value
-				changed = true
-			}
-		}
-
-		if changed {
-			if _, _, err := a.opClient.UpdateDeployment(dep); err != nil {
-				logger.Info("annotations updated!")
-				updateErrs = append(updateErrs, err)
-			}
-		}
-	}
 	logger.Info("updated annotations to match current operatorgroup")
 
-	return utilerrors.NewAggregate(updateErrs)
+	return nil
 }
 
 // ensureLabels merges a label set with a CSV's labels and attempts to update the CSV if the merged set differs from the CSV's original labels.
@@ -88,6 +88,14 @@ func (i *TestInstaller) CheckInstalled(s install.Strategy) (bool, error) {
 	return true, nil
 }
 
+func (i *TestInstaller) Uninstall(s install.Strategy) error {
+	return nil
+}
+
+func (i *TestInstaller) UpdateTemplateAnnotations() error {
+	return nil
+}
+
 func ownerLabelFromCSV(name, namespace string) map[string]string {
 	return map[string]string{
 		ownerutil.OwnerKey:          name,
@@ -651,6 +659,21 @@ func withCertInfo(csv *v1alpha1.ClusterServiceVersion, rotateAt metav1.Time, las
 	return csv
 }
 
+func withInstallPolicy(csv *v1alpha1.ClusterServiceVersion, policy *v1alpha1.InstallPolicy) *v1alpha1.ClusterServiceVersion {
+	csv.Spec.InstallPolicy = policy
+	return csv
+}
+
+func withInstallAttempt(csv *v1alpha1.ClusterServiceVersion, attempt *v1alpha1.InstallAttemptStatus) *v1alpha1.ClusterServiceVersion {
+	csv.Status.Install = attempt
+	return csv
+}
+
+func withComponents(csv *v1alpha1.ClusterServiceVersion, components []v1alpha1.ComponentHash) *v1alpha1.ClusterServiceVersion {
+	csv.Status.Components = components
+	return csv
+}
+
 func withAPIServices(csv *v1alpha1.ClusterServiceVersion, owned, required []v1alpha1.APIServiceDescription) *v1alpha1.ClusterServiceVersion {
 	csv.Spec.APIServiceDefinitions = v1alpha1.APIServiceDefinitions{
 		Owned:    owned,
@@ -1376,6 +1399,131 @@ func TestTransitionCSV(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SingleCSVInstallingToInstalling/InstallPolicy/Waiting",
+			initial: initial{
+				csvs: []runtime.Object{
+					csvWithAnnotations(withInstallAttempt(withInstallPolicy(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseInstalling,
+					), &v1alpha1.InstallPolicy{Timeout: &metav1.Duration{Duration: time.Hour}}), &v1alpha1.InstallAttemptStatus{Attempt: 1, StartTime: metav1.Now()}), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{addAnnotation(defaultOperatorGroup, v1.OperatorGroupProvidedAPIsAnnotationKey, "c1.v1.g1")},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhaseInstalling, reason: v1alpha1.CSVReasonWaiting},
+				},
+			},
+		},
+		{
+			name: "SingleCSVInstallingToInstalling/InstallPolicy/AttemptFailed",
+			initial: initial{
+				csvs: []runtime.Object{
+					csvWithAnnotations(withInstallPolicy(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseInstalling,
+					), &v1alpha1.InstallPolicy{Retries: 1}), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{addAnnotation(defaultOperatorGroup, v1.OperatorGroupProvidedAPIsAnnotationKey, "c1.v1.g1")},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhaseInstalling, reason: v1alpha1.CSVReasonInstallAttemptFailed},
+				},
+			},
+		},
+		{
+			name: "SingleCSVInstallingToInstalling/InstallPolicy/RetryBackoff",
+			initial: initial{
+				csvs: []runtime.Object{
+					csvWithAnnotations(withInstallAttempt(withInstallPolicy(withConditionReason(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseInstalling,
+					), v1alpha1.CSVReasonInstallAttemptFailed), &v1alpha1.InstallPolicy{Retries: 1}), &v1alpha1.InstallAttemptStatus{Attempt: 1, StartTime: metav1.NewTime(time.Now().Add(-time.Hour)), RetryTime: &metav1.Time{Time: time.Now().Add(time.Hour)}}), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{addAnnotation(defaultOperatorGroup, v1.OperatorGroupProvidedAPIsAnnotationKey, "c1.v1.g1")},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhaseInstalling, reason: v1alpha1.CSVReasonInstallAttemptFailed},
+				},
+			},
+		},
+		{
+			name: "SingleCSVInstallingToInstallReady/InstallPolicy/Retrying",
+			initial: initial{
+				csvs: []runtime.Object{
+					csvWithAnnotations(withInstallAttempt(withInstallPolicy(withConditionReason(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseInstalling,
+					), v1alpha1.CSVReasonInstallAttemptFailed), &v1alpha1.InstallPolicy{Retries: 1}), &v1alpha1.InstallAttemptStatus{Attempt: 1, StartTime: metav1.NewTime(time.Now().Add(-time.Hour)), RetryTime: &metav1.Time{Time: time.Now().Add(-time.Minute)}}), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{addAnnotation(defaultOperatorGroup, v1.OperatorGroupProvidedAPIsAnnotationKey, "c1.v1.g1")},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhaseInstallReady, reason: v1alpha1.CSVReasonInstallRetrying},
+				},
+			},
+		},
+		{
+			name: "SingleCSVInstallingToFailed/InstallPolicy/RetriesExhausted",
+			initial: initial{
+				csvs: []runtime.Object{
+					csvWithAnnotations(withInstallAttempt(withInstallPolicy(withConditionReason(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseInstalling,
+					), v1alpha1.CSVReasonInstallRetrying), &v1alpha1.InstallPolicy{Retries: 1}), &v1alpha1.InstallAttemptStatus{Attempt: 2, StartTime: metav1.NewTime(time.Now().Add(-time.Hour))}), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{addAnnotation(defaultOperatorGroup, v1.OperatorGroupProvidedAPIsAnnotationKey, "c1.v1.g1")},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhaseFailed, reason: v1alpha1.CSVReasonInstallCheckFailed},
+				},
+			},
+		},
 		{
 			name: "SingleCSVInstallReadyToInstalling/APIService/Owned",
 			initial: initial{
@@ -2305,6 +2453,77 @@ func TestTransitionCSV(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SingleCSVSucceededToPending/ComponentDrifted",
+			initial: initial{
+				csvs: []runtime.Object{
+					withComponents(withConditionReason(csvWithAnnotations(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseSucceeded,
+					), defaultTemplateAnnotations), v1alpha1.CSVReasonInstallSuccessful), []v1alpha1.ComponentHash{
+						{Kind: "Role", Name: "csv1-role", Hash: "deleted"},
+					}),
+				},
+				clientObjs: []runtime.Object{defaultOperatorGroup},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+				objs: []runtime.Object{
+					deployment("csv1-dep1", namespace, "sa", defaultTemplateAnnotations),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhasePending, reason: v1alpha1.CSVReasonComponentDrifted},
+				},
+			},
+		},
+		{
+			name: "SingleCSVSucceededToPending/OverridesChanged",
+			initial: initial{
+				csvs: []runtime.Object{
+					withConditionReason(csvWithAnnotations(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseSucceeded,
+					), defaultTemplateAnnotations), v1alpha1.CSVReasonInstallSuccessful),
+				},
+				clientObjs: []runtime.Object{defaultOperatorGroup},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+				objs: []runtime.Object{
+					// The deployment still has overrides that were removed from the CSV
+					withLabels(
+						deployment("csv1-dep1", namespace, "sa", map[string]string{
+							v1.OperatorGroupTargetsAnnotationKey:   namespace,
+							v1.OperatorGroupNamespaceAnnotationKey: namespace,
+							v1.OperatorGroupAnnotationKey:          defaultOperatorGroup.GetName(),
+							install.OverridesHashAnnotationKey:     "removed",
+						}),
+						map[string]string{
+							ownerutil.OwnerKey:          "csv1",
+							ownerutil.OwnerNamespaceKey: namespace,
+							ownerutil.OwnerKind:         "ClusterServiceVersion",
+						},
+					),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhasePending, reason: v1alpha1.CSVReasonOverridesChanged},
+				},
+			},
+		},
 		{
 			name: "SingleCSVSucceededToFailed/CRD",
 			initial: initial{
@@ -2365,6 +2584,95 @@ func TestTransitionCSV(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "CSVSucceededToReplacing/UpgradeRolledBack",
+			initial: initial{
+				csvs: []runtime.Object{
+					withAnnotations(csv("csv1",
+						namespace,
+						"0.0.0",
+						"",
+						installStrategy("csv1-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseSucceeded,
+					), defaultTemplateAnnotations),
+					withConditionReason(csvWithAnnotations(csv("csv2",
+						namespace,
+						"0.0.0",
+						"csv1",
+						installStrategy("csv2-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseFailed,
+					), defaultTemplateAnnotations), v1alpha1.CSVReasonRolledBack),
+					csvWithAnnotations(csv("csv3",
+						namespace,
+						"0.0.0",
+						"csv1",
+						installStrategy("csv3-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseNone,
+					), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{defaultOperatorGroup},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+				objs: []runtime.Object{
+					deployment("csv1-dep1", namespace, "sa", defaultTemplateAnnotations),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv1": {exists: true, phase: v1alpha1.CSVPhaseReplacing},
+					"csv2": {exists: true, phase: v1alpha1.CSVPhaseFailed, reason: v1alpha1.CSVReasonRolledBack},
+					"csv3": {exists: true, phase: v1alpha1.CSVPhasePending},
+				},
+			},
+		},
+		{
+			name: "CSVRolledBackToGone",
+			initial: initial{
+				csvs: []runtime.Object{
+					withConditionReason(csvWithAnnotations(csv("csv2",
+						namespace,
+						"0.0.0",
+						"csv1",
+						installStrategy("csv2-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseFailed,
+					), defaultTemplateAnnotations), v1alpha1.CSVReasonRolledBack),
+					csvWithAnnotations(csv("csv3",
+						namespace,
+						"0.0.0",
+						"csv1",
+						installStrategy("csv3-dep1", nil, nil),
+						[]*v1beta1.CustomResourceDefinition{crd("c1", "v1", "g1")},
+						[]*v1beta1.CustomResourceDefinition{},
+						v1alpha1.CSVPhaseSucceeded,
+					), defaultTemplateAnnotations),
+				},
+				clientObjs: []runtime.Object{defaultOperatorGroup},
+				crds: []runtime.Object{
+					crd("c1", "v1", "g1"),
+				},
+				objs: []runtime.Object{
+					withLabels(
+						deployment("csv3-dep1", namespace, "sa", defaultTemplateAnnotations),
+						ownerLabelFromCSV("csv3", namespace),
+					),
+				},
+			},
+			expected: expected{
+				csvStates: map[string]csvState{
+					"csv2": {exists: false, phase: v1alpha1.CSVPhaseNone},
+					"csv3": {exists: true, phase: v1alpha1.CSVPhaseSucceeded},
+				},
+			},
+		},
 		{
 			name: "CSVReplacingToDeleted",
 			initial: initial{
@@ -4466,6 +4774,16 @@ func TestIsBeingReplaced(t *testing.T) {
 			},
 			expected: csv("csv2", namespace, "0.0.0", "csv1", installStrategy("dep", nil, nil), nil, nil, v1alpha1.CSVPhaseSucceeded),
 		},
+		{
+			name: "CSVInCluster/RolledBack",
+			in:   csv("csv1", namespace, "0.0.0", "", installStrategy("dep", nil, nil), nil, nil, v1alpha1.CSVPhaseSucceeded),
+			initial: initial{
+				csvs: map[string]*v1alpha1.ClusterServiceVersion{
+					"csv2": withConditionReason(csv("csv2", namespace, "0.0.0", "csv1", installStrategy("dep", nil, nil), nil, nil, v1alpha1.CSVPhaseFailed), v1alpha1.CSVReasonRolledBack),
+				},
+			},
+			expected: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -4528,6 +4846,136 @@ func TestCheckReplacement(t *testing.T) {
 	}
 }
 
+func TestRollbackUpgrade(t *testing.T) {
+	namespace := "ns"
+	now := metav1.Now()
+
+	failed := func(reason v1alpha1.ConditionReason, created metav1.Time) *v1alpha1.ClusterServiceVersion {
+		strategy := installStrategy("dep", nil, nil)
+		strategy.StrategySpecRaw = []byte(`{"deployments":[{"name":"dep"},{"name":"dep2"}]}`)
+		out := withPhase(csv("csv2", namespace, "0.0.2", "csv1", strategy, nil, nil, v1alpha1.CSVPhaseFailed), v1alpha1.CSVPhaseFailed, reason, "install timeout", now)
+		out.SetCreationTimestamp(created)
+		return out
+	}
+	replaced := func(phase v1alpha1.ClusterServiceVersionPhase) *v1alpha1.ClusterServiceVersion {
+		return withPhase(csv("csv1", namespace, "0.0.1", "", installStrategy("dep", nil, nil), nil, nil, phase), phase, v1alpha1.CSVReasonBeingReplaced, "being replaced by csv: csv2", now)
+	}
+	subscription := func(policy *v1alpha1.RollbackPolicy) *v1alpha1.Subscription {
+		return &v1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: namespace},
+			Spec:       &v1alpha1.SubscriptionSpec{Package: "pkg", RollbackPolicy: policy},
+			Status: v1alpha1.SubscriptionStatus{
+				CurrentCSV:   "csv2",
+				InstalledCSV: "csv2",
+				State:        v1alpha1.SubscriptionStateAtLatest,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		csv        *v1alpha1.ClusterServiceVersion
+		replaced   *v1alpha1.ClusterServiceVersion
+		sub        *v1alpha1.Subscription
+		rolledBack bool
+	}{
+		{
+			name:       "RolledBack",
+			csv:        failed(v1alpha1.CSVReasonInstallCheckFailed, now),
+			replaced:   replaced(v1alpha1.CSVPhaseReplacing),
+			sub:        subscription(&v1alpha1.RollbackPolicy{}),
+			rolledBack: true,
+		},
+		{
+			name:       "RolledBack/Window",
+			csv:        failed(v1alpha1.CSVReasonComponentFailed, metav1.NewTime(now.Add(-time.Hour))),
+			replaced:   replaced(v1alpha1.CSVPhaseReplacing),
+			sub:        subscription(&v1alpha1.RollbackPolicy{Window: &metav1.Duration{Duration: 2 * time.Hour}}),
+			rolledBack: true,
+		},
+		{
+			name:     "NoPolicy",
+			csv:      failed(v1alpha1.CSVReasonInstallCheckFailed, now),
+			replaced: replaced(v1alpha1.CSVPhaseReplacing),
+			sub:      subscription(nil),
+		},
+		{
+			name:     "NoSubscription",
+			csv:      failed(v1alpha1.CSVReasonInstallCheckFailed, now),
+			replaced: replaced(v1alpha1.CSVPhaseReplacing),
+		},
+		{
+			name:     "OutsideWindow",
+			csv:      failed(v1alpha1.CSVReasonInstallCheckFailed, metav1.NewTime(now.Add(-time.Hour))),
+			replaced: replaced(v1alpha1.CSVPhaseReplacing),
+			sub:      subscription(&v1alpha1.RollbackPolicy{}),
+		},
+		{
+			name:     "NotAnInstallFailure",
+			csv:      failed(v1alpha1.CSVReasonRequirementsNotMet, now),
+			replaced: replaced(v1alpha1.CSVPhaseReplacing),
+			sub:      subscription(&v1alpha1.RollbackPolicy{}),
+		},
+		{
+			name:     "ReplacedNotReplacing",
+			csv:      failed(v1alpha1.CSVReasonInstallCheckFailed, now),
+			replaced: replaced(v1alpha1.CSVPhaseDeleting),
+			sub:      subscription(&v1alpha1.RollbackPolicy{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			clientObjs := []runtime.Object{tt.csv, tt.replaced}
+			if tt.sub != nil {
+				clientObjs = append(clientObjs, tt.sub)
+			}
+			k8sObjs := []runtime.Object{deployment("dep", namespace, "sa", nil), deployment("dep2", namespace, "sa", nil)}
+			op, err := NewFakeOperator(ctx, withNamespaces(namespace), withClientObjs(clientObjs...), withK8sObjs(k8sObjs...))
+			require.NoError(t, err)
+
+			out := tt.csv.DeepCopy()
+			rolledBack, err := op.rollbackUpgrade(logrus.NewEntry(op.logger), out, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.rolledBack, rolledBack)
+
+			deployments, err := op.opClient.KubernetesInterface().AppsV1().Deployments(namespace).List(metav1.ListOptions{})
+			require.NoError(t, err)
+			var deploymentNames []string
+			for _, dep := range deployments.Items {
+				deploymentNames = append(deploymentNames, dep.GetName())
+			}
+
+			prev, err := op.client.OperatorsV1alpha1().ClusterServiceVersions(namespace).Get("csv1", metav1.GetOptions{})
+			require.NoError(t, err)
+			if !tt.rolledBack {
+				require.Equal(t, tt.csv.Status, out.Status)
+				require.Equal(t, tt.replaced.Status.Phase, prev.Status.Phase)
+				require.ElementsMatch(t, []string{"dep", "dep2"}, deploymentNames)
+				return
+			}
+
+			// Only the failed CSV's deployment that the replaced CSV doesn't also have is removed
+			require.Equal(t, []string{"dep"}, deploymentNames)
+
+			// The failed CSV no longer replaces the one it replaced, which is installed again
+			require.True(t, out.IsRolledBack())
+			require.Equal(t, v1alpha1.CSVPhaseInstallReady, prev.Status.Phase)
+			require.Equal(t, v1alpha1.CSVReasonUpgradeRolledBack, prev.Status.Reason)
+			require.False(t, prev.IsObsolete())
+
+			sub, err := op.client.OperatorsV1alpha1().Subscriptions(namespace).Get("sub", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, v1alpha1.SubscriptionState(v1alpha1.SubscriptionStateUpgradeRolledBack), sub.Status.State)
+			require.Equal(t, "csv1", sub.Status.InstalledCSV)
+
+			// The Subscription progresses from the CSV it was rolled back to, so the next upgrade replaces it
+			require.Equal(t, "csv1", sub.Status.CurrentCSV)
+		})
+	}
+}
+
 func TestAPIServiceResourceErrorActionable(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -4570,3 +5018,157 @@ func TestAPIServiceResourceErrorActionable(t *testing.T) {
 	}
 
 }
+
+func TestComponentDrift(t *testing.T) {
+	namespace := "ns"
+	rules := []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}}
+	permissions := []install.StrategyDeploymentPermissions{{ServiceAccountName: "sa", Rules: rules}}
+	clusterRules := []rbacv1.PolicyRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"namespaces"}}}
+	clusterPermissions := []install.StrategyDeploymentPermissions{{ServiceAccountName: "sa", Rules: clusterRules}}
+
+	succeeded := csv("csv1", namespace, "0.0.0", "", installStrategy("dep", permissions, clusterPermissions), nil, nil, v1alpha1.CSVPhaseSucceeded)
+	succeeded.SetUID("csv1-uid")
+	owned := func(obj metav1.Object) runtime.Object {
+		ownerutil.AddNonBlockingOwner(obj, succeeded)
+		require.NoError(t, ownerutil.AddOwnerLabels(obj, succeeded))
+		return obj.(runtime.Object)
+	}
+	labeled := func(obj metav1.Object) runtime.Object {
+		require.NoError(t, ownerutil.AddOwnerLabels(obj, succeeded))
+		return obj.(runtime.Object)
+	}
+	components := func(mutate func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object) []runtime.Object {
+		dep := deployment("dep", namespace, "sa", nil)
+		sa := serviceAccount("sa", namespace)
+		r := role("role", namespace, rules)
+		cr := clusterRole("clusterrole", clusterRules)
+		objs := mutate(dep, sa, r, cr)
+		return append(objs, owned(roleBinding("rolebinding", namespace, "role", "sa", namespace)),
+			labeled(clusterRoleBinding("clusterrolebinding", "clusterrole", "sa", namespace)))
+	}
+	installed := func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+		return []runtime.Object{owned(dep), sa, owned(r), labeled(cr)}
+	}
+
+	tests := []struct {
+		name           string
+		k8sObjs        []runtime.Object
+		drifted        []string
+		reinstallRoles bool
+	}{
+		{
+			name:    "NoDrift",
+			k8sObjs: components(installed),
+		},
+		{
+			name: "DeploymentImageChanged",
+			k8sObjs: components(func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+				dep.Spec.Template.Spec.Containers[0].Image = "nginx:latest"
+				return installed(dep, sa, r, cr)
+			}),
+			drifted: []string{"Deployment/dep"},
+		},
+		{
+			name: "DeploymentAnnotationsChanged",
+			k8sObjs: components(func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+				dep.Spec.Template.SetAnnotations(map[string]string{"olm.targetNamespaces": namespace})
+				return installed(dep, sa, r, cr)
+			}),
+		},
+		{
+			name: "RoleRulesChanged",
+			k8sObjs: components(func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+				r.Rules = []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}
+				return installed(dep, sa, r, cr)
+			}),
+			drifted:        []string{"Role/role"},
+			reinstallRoles: true,
+		},
+		{
+			name: "RoleAndServiceAccountDeleted",
+			k8sObjs: components(func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+				return []runtime.Object{owned(dep), labeled(cr)}
+			}),
+			drifted:        []string{"Role/role", "ServiceAccount/sa"},
+			reinstallRoles: true,
+		},
+		{
+			name: "ClusterRoleRulesChanged",
+			k8sObjs: components(func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+				cr.Rules = []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}
+				return installed(dep, sa, r, cr)
+			}),
+			drifted:        []string{"ClusterRole/clusterrole"},
+			reinstallRoles: true,
+		},
+		{
+			name: "ClusterRoleDeleted",
+			k8sObjs: components(func(dep *appsv1.Deployment, sa *corev1.ServiceAccount, r *rbacv1.Role, cr *rbacv1.ClusterRole) []runtime.Object {
+				return []runtime.Object{owned(dep), sa, owned(r)}
+			}),
+			drifted:        []string{"ClusterRole/clusterrole"},
+			reinstallRoles: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			// Record the hashes of the components as installed
+			op, err := NewFakeOperator(ctx, withNamespaces(namespace), withClientObjs(succeeded), withK8sObjs(components(installed)...))
+			require.NoError(t, err)
+			strategy, err := op.resolver.UnmarshalStrategy(succeeded.Spec.InstallStrategy)
+			require.NoError(t, err)
+			recorded, err := op.componentHashes(succeeded, strategy)
+			require.NoError(t, err)
+			require.Len(t, recorded, 6)
+
+			out := succeeded.DeepCopy()
+			out.Status.Components = recorded
+			op, err = NewFakeOperator(ctx, withNamespaces(namespace), withClientObjs(out), withK8sObjs(tt.k8sObjs...))
+			require.NoError(t, err)
+
+			drifted, err := op.driftedComponents(out, strategy)
+			require.NoError(t, err)
+			var names []string
+			for _, c := range drifted {
+				names = append(names, c.Kind+"/"+c.Name)
+			}
+			require.Equal(t, tt.drifted, names)
+			if len(drifted) == 0 {
+				return
+			}
+
+			// Drifted permissions are reinstalled, replacing the recorded roles and bindings
+			require.NoError(t, op.reinstallPermissions(out, drifted))
+			_, err = op.opClient.GetServiceAccount(namespace, "sa")
+			require.NoError(t, err)
+			roles, err := op.opClient.KubernetesInterface().RbacV1().Roles(namespace).List(metav1.ListOptions{})
+			require.NoError(t, err)
+			bindings, err := op.opClient.KubernetesInterface().RbacV1().RoleBindings(namespace).List(metav1.ListOptions{})
+			require.NoError(t, err)
+			clusterRoles, err := op.opClient.KubernetesInterface().RbacV1().ClusterRoles().List(metav1.ListOptions{})
+			require.NoError(t, err)
+			clusterBindings, err := op.opClient.KubernetesInterface().RbacV1().ClusterRoleBindings().List(metav1.ListOptions{})
+			require.NoError(t, err)
+			if !tt.reinstallRoles {
+				require.Len(t, roles.Items, 1)
+				require.Equal(t, "role", roles.Items[0].GetName())
+				require.Len(t, clusterRoles.Items, 1)
+				require.Equal(t, "clusterrole", clusterRoles.Items[0].GetName())
+				return
+			}
+			require.Len(t, roles.Items, 1)
+			require.NotEqual(t, "role", roles.Items[0].GetName())
+			require.Equal(t, rules, roles.Items[0].Rules)
+			require.Len(t, bindings.Items, 1)
+			require.Equal(t, roles.Items[0].GetName(), bindings.Items[0].RoleRef.Name)
+			require.Len(t, clusterRoles.Items, 1)
+			require.NotEqual(t, "clusterrole", clusterRoles.Items[0].GetName())
+			require.Equal(t, clusterRules, clusterRoles.Items[0].Rules)
+			require.Len(t, clusterBindings.Items, 1)
+			require.Equal(t, clusterRoles.Items[0].GetName(), clusterBindings.Items[0].RoleRef.Name)
+		})
+	}
+}
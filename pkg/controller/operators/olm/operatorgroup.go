@@ -309,9 +309,9 @@ func (a *Operator) ensureRBACInTargetNamespace(csv *v1alpha1.ClusterServiceVersi
 	if err != nil {
 		return err
 	}
-	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
+	strategyWithPermissions, ok := strategy.(install.StrategyWithPermissions)
 	if !ok {
-		return fmt.Errorf("could not cast install strategy as type %T", strategyDetailsDeployment)
+		return fmt.Errorf("install strategy %s doesn't declare its permissions", strategy.GetStrategyName())
 	}
 	ruleChecker := install.NewCSVRuleChecker(a.lister.RbacV1().RoleLister(), a.lister.RbacV1().RoleBindingLister(), a.lister.RbacV1().ClusterRoleLister(), a.lister.RbacV1().ClusterRoleBindingLister(), csv)
 
@@ -321,12 +321,8 @@ func (a *Operator) ensureRBACInTargetNamespace(csv *v1alpha1.ClusterServiceVersi
 	if len(targetNamespaces) == 1 && targetNamespaces[0] == corev1.NamespaceAll {
 		logger.Debug("opgroup is global")
 
-		// synthesize cluster permissions to verify rbac
-		for _, p := range strategyDetailsDeployment.Permissions {
-			strategyDetailsDeployment.ClusterPermissions = append(strategyDetailsDeployment.ClusterPermissions, p)
-		}
-		strategyDetailsDeployment.Permissions = nil
-		permMet, _, err := a.permissionStatus(strategyDetailsDeployment, ruleChecker, corev1.NamespaceAll, csv.GetNamespace())
+		// verify rbac with every permission at the cluster scope
+		permMet, _, err := a.permissionStatus(install.ClusterScoped(strategyWithPermissions), ruleChecker, corev1.NamespaceAll, csv.GetNamespace())
 		if err != nil {
 			return err
 		}
@@ -533,9 +529,9 @@ func (a *Operator) ensureCSVsInNamespaces(csv *v1alpha1.ClusterServiceVersion, o
 	if err != nil {
 		return err
 	}
-	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
+	strategyWithPermissions, ok := strategy.(install.StrategyWithPermissions)
 	if !ok {
-		return fmt.Errorf("could not cast install strategy as type %T", strategyDetailsDeployment)
+		return fmt.Errorf("install strategy %s doesn't declare its permissions", strategy.GetStrategyName())
 	}
 	ruleChecker := install.NewCSVRuleChecker(a.lister.RbacV1().RoleLister(), a.lister.RbacV1().RoleBindingLister(), a.lister.RbacV1().ClusterRoleLister(), a.lister.RbacV1().ClusterRoleBindingLister(), csv)
 
@@ -571,7 +567,7 @@ func (a *Operator) ensureCSVsInNamespaces(csv *v1alpha1.ClusterServiceVersion, o
 	}
 	for _, ns := range targetNamespaces {
 		// create roles/rolebindings for each target namespace
-		permMet, _, err := a.permissionStatus(strategyDetailsDeployment, ruleChecker, ns, csv.GetNamespace())
+		permMet, _, err := a.permissionStatus(strategyWithPermissions, ruleChecker, ns, csv.GetNamespace())
 		if err != nil {
 			logger.WithError(err).Debug("permission status")
 			return err
@@ -74,7 +74,7 @@ func (a *Operator) minKubeVersionStatus(name string, minKubeVersion string) (met
 	return
 }
 
-func (a *Operator) requirementStatus(strategyDetailsDeployment *install.StrategyDetailsDeployment, crdDescs []v1alpha1.CRDDescription,
+func (a *Operator) requirementStatus(strategy install.Strategy, crdDescs []v1alpha1.CRDDescription,
 	ownedAPIServiceDescs []v1alpha1.APIServiceDescription, requiredAPIServiceDescs []v1alpha1.APIServiceDescription,
 	requiredNativeAPIs []metav1.GroupVersionKind) (met bool, statuses []v1alpha1.RequirementStatus) {
 	met = true
@@ -198,13 +198,16 @@ func (a *Operator) requirementStatus(strategyDetailsDeployment *install.Strategy
 			Name:    name,
 		}
 
+		// only deployments can back an owned API service
 		found := false
-		for _, spec := range strategyDetailsDeployment.DeploymentSpecs {
-			if spec.Name == r.DeploymentName {
-				status.Status = "DeploymentFound"
-				statuses = append(statuses, status)
-				found = true
-				break
+		if strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment); ok {
+			for _, spec := range strategyDetailsDeployment.DeploymentSpecs {
+				if spec.Name == r.DeploymentName {
+					status.Status = "DeploymentFound"
+					statuses = append(statuses, status)
+					found = true
+					break
+				}
 			}
 		}
 
@@ -242,7 +245,7 @@ func (a *Operator) requirementStatus(strategyDetailsDeployment *install.Strategy
 }
 
 // permissionStatus checks whether the given CSV's RBAC requirements are met in its namespace
-func (a *Operator) permissionStatus(strategyDetailsDeployment *install.StrategyDetailsDeployment, ruleChecker install.RuleChecker, targetNamespace, serviceAccountNamespace string) (bool, []v1alpha1.RequirementStatus, error) {
+func (a *Operator) permissionStatus(strategy install.StrategyWithPermissions, ruleChecker install.RuleChecker, targetNamespace, serviceAccountNamespace string) (bool, []v1alpha1.RequirementStatus, error) {
 	statusesSet := map[string]v1alpha1.RequirementStatus{}
 
 	checkPermissions := func(permissions []install.StrategyDeploymentPermissions, namespace string) (bool, error) {
@@ -320,11 +323,11 @@ func (a *Operator) permissionStatus(strategyDetailsDeployment *install.StrategyD
 		return met, nil
 	}
 
-	permMet, err := checkPermissions(strategyDetailsDeployment.Permissions, targetNamespace)
+	permMet, err := checkPermissions(strategy.GetPermissions(), targetNamespace)
 	if err != nil {
 		return false, nil, err
 	}
-	clusterPermMet, err := checkPermissions(strategyDetailsDeployment.ClusterPermissions, metav1.NamespaceAll)
+	clusterPermMet, err := checkPermissions(strategy.GetClusterPermissions(), metav1.NamespaceAll)
 	if err != nil {
 		return false, nil, err
 	}
@@ -347,15 +350,14 @@ func (a *Operator) requirementAndPermissionStatus(csv *v1alpha1.ClusterServiceVe
 		return false, nil, err
 	}
 
-	// Assume the strategy is for a deployment
-	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
+	strategyWithPermissions, ok := strategy.(install.StrategyWithPermissions)
 	if !ok {
-		return false, nil, fmt.Errorf("could not cast install strategy as type %T", strategyDetailsDeployment)
+		return false, nil, fmt.Errorf("install strategy %s doesn't declare its permissions", strategy.GetStrategyName())
 	}
 
 	// Check kubernetes version requirement between CSV and server
 	minKubeMet, minKubeStatus := a.minKubeVersionStatus(csv.GetName(), csv.Spec.MinKubeVersion)
-	reqMet, reqStatuses := a.requirementStatus(strategyDetailsDeployment, csv.GetAllCRDDescriptions(), csv.GetOwnedAPIServiceDescriptions(), csv.GetRequiredAPIServiceDescriptions(), csv.Spec.NativeAPIs)
+	reqMet, reqStatuses := a.requirementStatus(strategy, csv.GetAllCRDDescriptions(), csv.GetOwnedAPIServiceDescriptions(), csv.GetRequiredAPIServiceDescriptions(), csv.Spec.NativeAPIs)
 	allReqStatuses := append(minKubeStatus, reqStatuses...)
 
 	rbacLister := a.lister.RbacV1()
@@ -365,7 +367,7 @@ func (a *Operator) requirementAndPermissionStatus(csv *v1alpha1.ClusterServiceVe
 	clusterRoleBindingLister := rbacLister.ClusterRoleBindingLister()
 
 	ruleChecker := install.NewCSVRuleChecker(roleLister, roleBindingLister, clusterRoleLister, clusterRoleBindingLister, csv)
-	permMet, permStatuses, err := a.permissionStatus(strategyDetailsDeployment, ruleChecker, csv.GetNamespace(), csv.GetNamespace())
+	permMet, permStatuses, err := a.permissionStatus(strategyWithPermissions, ruleChecker, csv.GetNamespace(), csv.GetNamespace())
 	if err != nil {
 		return false, nil, err
 	}
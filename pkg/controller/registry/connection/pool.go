@@ -0,0 +1,305 @@
+// Package connection manages the connections to the registry servers of CatalogSources, so that every component
+// querying them connects, health checks and reconnects the same way.
+package connection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/metrics"
+)
+
+const (
+	// healthCheckTimeout is how long a health check waits for a connection to recover from a transient failure.
+	healthCheckTimeout = 2 * time.Second
+
+	stateConnecting = "connecting"
+	stateHealthy    = "healthy"
+)
+
+// Client is a connection to a registry server.
+type Client interface {
+	HealthCheck(ctx context.Context, reconnectTimeout time.Duration) (bool, error)
+	Close() error
+}
+
+// Dialer connects to registry servers. It's provided by each component, since they use different registry clients.
+type Dialer struct {
+	// Dial connects to the registry server at the given address.
+	Dial func(address string) (Client, error)
+
+	// Unrecoverable returns true if the given health check error means the connection has to be made again.
+	Unrecoverable func(err error) bool
+}
+
+// Source is a connection to the registry server of a CatalogSource.
+type Source struct {
+	Address     string
+	Client      Client
+	LastConnect metav1.Time
+	LastHealthy metav1.Time
+
+	// CatalogSource is the CatalogSource the connection was last synced with.
+	CatalogSource *v1alpha1.CatalogSource
+}
+
+// Healthy returns true if the connection has passed a health check since it was made.
+func (s Source) Healthy() bool {
+	return !s.LastHealthy.IsZero()
+}
+
+// SyncResult describes what syncing a CatalogSource did to the connection to its registry server.
+type SyncResult struct {
+	// Connected is true if a new connection was made.
+	Connected bool
+
+	// BecameHealthy is true if the connection passed its first health check.
+	BecameHealthy bool
+
+	// Source is the connection after the sync. It's unset if the connection was dropped.
+	Source Source
+}
+
+// Updated returns true if the connection changed in a way that's worth recording.
+func (r SyncResult) Updated() bool {
+	return r.Connected || r.BecameHealthy
+}
+
+// Pool holds a connection to the registry server of each CatalogSource synced with it. A connection is rebuilt when
+// the address of its CatalogSource changes or its registry server is synced again, and is health checked until it
+// first becomes healthy.
+type Pool struct {
+	component string
+	now       func() metav1.Time
+	dialer    Dialer
+
+	mu         sync.RWMutex
+	sources    map[types.NamespacedName]Source
+	lastUpdate metav1.Time
+}
+
+// NewPool returns an empty pool. The component names the pool in the connection metrics.
+func NewPool(component string, now func() metav1.Time, dialer Dialer) *Pool {
+	return &Pool{
+		component: component,
+		now:       now,
+		dialer:    dialer,
+		sources:   make(map[types.NamespacedName]Source),
+	}
+}
+
+// Key returns the key of the connection to the registry server of the given CatalogSource.
+func Key(catsrc metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Name: catsrc.GetName(), Namespace: catsrc.GetNamespace()}
+}
+
+// Sync makes sure there's an up to date connection to the registry server of the given CatalogSource, and health
+// checks it if it hasn't become healthy yet. Connections are made and health checked without holding the pool's lock,
+// so that a slow registry server doesn't hold up the syncs of other CatalogSources or readers of the pool.
+func (p *Pool) Sync(ctx context.Context, logger *logrus.Entry, catsrc *v1alpha1.CatalogSource) (result SyncResult, err error) {
+	key := Key(catsrc)
+	address := catsrc.Address()
+	logger = logger.WithFields(logrus.Fields{"currentSource": key, "address": address})
+
+	source, ok, err := p.current(logger, key, catsrc)
+	if err != nil {
+		return result, err
+	}
+
+	if !ok {
+		// have never made a connection, so need to build a new one
+		logger.Info("building connection to registry")
+		client, err := p.dialer.Dial(address)
+		if err != nil {
+			return result, errors.Wrap(err, "couldn't connect to registry")
+		}
+		source = Source{
+			Address:       address,
+			Client:        client,
+			LastConnect:   p.now(),
+			LastHealthy:   metav1.Time{}, // haven't detected healthy yet
+			CatalogSource: catsrc,
+		}
+		if !p.put(key, source, nil) {
+			if err := client.Close(); err != nil {
+				logger.WithError(err).Warn("couldn't close connection to registry")
+			}
+			return result, errors.New("another connection to registry was made while connecting")
+		}
+		result.Connected = true
+	}
+	result.Source = source
+
+	if source.Healthy() {
+		return
+	}
+
+	logger.Info("client hasn't yet become healthy, attempt a health check")
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	healthy, healthErr := source.Client.HealthCheck(ctx, healthCheckTimeout)
+	if healthErr != nil || !healthy {
+		if healthErr != nil && p.dialer.Unrecoverable != nil && p.dialer.Unrecoverable(healthErr) {
+			logger.Debug("state didn't change, trigger reconnect. this may happen when cached dns is wrong.")
+			result.Source = Source{}
+			if err := p.removeIfCurrent(key, source); err != nil {
+				return result, errors.Wrap(err, "couldn't close outdated connection to registry")
+			}
+		}
+		return
+	}
+
+	logger.Debug("client has become healthy!")
+	source.LastHealthy = source.LastConnect
+	if !p.put(key, source, source.Client) {
+		return result, errors.New("connection to registry was dropped while health checking")
+	}
+	result.Source = source
+	result.BecameHealthy = true
+
+	return
+}
+
+// current returns the connection to the registry server of the given CatalogSource, updated to have been last synced
+// with it. A connection that's out of date is closed and dropped, and false is returned if there's no connection.
+func (p *Pool) current(logger *logrus.Entry, key types.NamespacedName, catsrc *v1alpha1.CatalogSource) (Source, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.recordMetrics()
+
+	source, ok := p.sources[key]
+	if !ok {
+		return source, false, nil
+	}
+
+	// this connection is out of date, close and reconnect
+	if source.Address != catsrc.Address() || catsrc.Status.LastSync.After(source.LastConnect.Time) {
+		logger.Info("rebuilding connection to registry")
+		if err := p.remove(key); err != nil {
+			return source, false, errors.Wrap(err, "couldn't close outdated connection to registry")
+		}
+		metrics.CatalogSourceReconnectCount.WithLabelValues(p.component).Inc()
+		return Source{}, false, nil
+	}
+
+	source.CatalogSource = catsrc
+	p.sources[key] = source
+	return source, true, nil
+}
+
+// put puts the given connection in the pool in place of the connection made with the given client, or in place of no
+// connection if the client is nil. It returns false without putting the connection in the pool if another sync made
+// or dropped a connection in the meantime.
+func (p *Pool) put(key types.NamespacedName, source Source, replaced Client) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.recordMetrics()
+
+	var current Client
+	if currentSource, ok := p.sources[key]; ok {
+		current = currentSource.Client
+	}
+	if current != replaced {
+		return false
+	}
+
+	p.sources[key] = source
+	if source.Healthy() {
+		p.lastUpdate = source.LastHealthy
+	} else {
+		p.lastUpdate = source.LastConnect
+	}
+	return true
+}
+
+// removeIfCurrent closes and drops the connection with the given key if it's still the given connection.
+func (p *Pool) removeIfCurrent(key types.NamespacedName, source Source) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.recordMetrics()
+
+	if current, ok := p.sources[key]; !ok || current.Client != source.Client {
+		return nil
+	}
+	return p.remove(key)
+}
+
+// Remove closes and drops the connection with the given key, if any.
+func (p *Pool) Remove(key types.NamespacedName) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.recordMetrics()
+
+	return p.remove(key)
+}
+
+func (p *Pool) remove(key types.NamespacedName) error {
+	source, ok := p.sources[key]
+	if !ok {
+		return nil
+	}
+	delete(p.sources, key)
+	p.lastUpdate = p.now()
+
+	if source.Client == nil {
+		return nil
+	}
+	return source.Client.Close()
+}
+
+// Add adds the given connection to the pool, replacing the one with the same key.
+func (p *Pool) Add(key types.NamespacedName, source Source) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.recordMetrics()
+
+	p.sources[key] = source
+	p.lastUpdate = p.now()
+}
+
+// Get returns the connection with the given key.
+func (p *Pool) Get(key types.NamespacedName) (Source, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	source, ok := p.sources[key]
+	return source, ok
+}
+
+// Sources returns the connections in the pool.
+func (p *Pool) Sources() map[types.NamespacedName]Source {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	sources := make(map[types.NamespacedName]Source, len(p.sources))
+	for key, source := range p.sources {
+		sources[key] = source
+	}
+	return sources
+}
+
+// LastUpdate returns the last time a connection was made, dropped, or became healthy.
+func (p *Pool) LastUpdate() metav1.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.lastUpdate
+}
+
+func (p *Pool) recordMetrics() {
+	healthy := 0
+	for _, source := range p.sources {
+		if source.Healthy() {
+			healthy++
+		}
+	}
+	metrics.CatalogSourceConnections.WithLabelValues(p.component, stateHealthy).Set(float64(healthy))
+	metrics.CatalogSourceConnections.WithLabelValues(p.component, stateConnecting).Set(float64(len(p.sources) - healthy))
+}
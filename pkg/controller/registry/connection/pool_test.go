@@ -0,0 +1,195 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	registryclient "github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver/fakes"
+)
+
+func catalogSource(address string) *v1alpha1.CatalogSource {
+	return &v1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "catalog",
+			Namespace: "ns",
+		},
+		Spec: v1alpha1.CatalogSourceSpec{
+			SourceType: v1alpha1.SourceTypeGrpc,
+			Address:    address,
+		},
+	}
+}
+
+// fakePool returns a pool whose connections are fakes, and the fakes it dialed so far.
+func fakePool(now *metav1.Time, healthy bool) (*Pool, *[]*fakes.FakeInterface) {
+	dialed := &[]*fakes.FakeInterface{}
+	p := NewPool("test", func() metav1.Time { return *now }, Dialer{
+		Dial: func(address string) (Client, error) {
+			client := &fakes.FakeInterface{}
+			client.HealthCheckReturns(healthy, nil)
+			*dialed = append(*dialed, client)
+			return client, nil
+		},
+		Unrecoverable: registryclient.IsErrorUnrecoverable,
+	})
+	return p, dialed
+}
+
+func TestPoolSync(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.NewEntry(logrus.New())
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	key := types.NamespacedName{Name: "catalog", Namespace: "ns"}
+
+	p, dialed := fakePool(&now, false)
+	catsrc := catalogSource("registry:50051")
+
+	// A connection is made, but isn't used until it becomes healthy
+	result, err := p.Sync(ctx, logger, catsrc)
+	require.NoError(t, err)
+	require.True(t, result.Connected)
+	require.False(t, result.BecameHealthy)
+	require.False(t, result.Source.Healthy())
+	require.Equal(t, "registry:50051", result.Source.Address)
+	require.Equal(t, catsrc, result.Source.CatalogSource)
+	require.Equal(t, now, result.Source.LastConnect)
+	require.Equal(t, now, p.LastUpdate())
+	require.Len(t, *dialed, 1)
+
+	client := (*dialed)[0]
+	client.HealthCheckReturns(true, nil)
+	connected := now
+	now = metav1.NewTime(now.Add(time.Minute))
+	result, err = p.Sync(ctx, logger, catsrc)
+	require.NoError(t, err)
+	require.False(t, result.Connected)
+	require.True(t, result.BecameHealthy)
+	require.Equal(t, connected, result.Source.LastHealthy)
+	require.Len(t, *dialed, 1)
+
+	// Healthy connections aren't checked again
+	result, err = p.Sync(ctx, logger, catsrc)
+	require.NoError(t, err)
+	require.False(t, result.Updated())
+	require.Equal(t, 2, client.HealthCheckCallCount())
+
+	source, ok := p.Get(key)
+	require.True(t, ok)
+	require.True(t, source.Healthy())
+	require.Equal(t, map[types.NamespacedName]Source{key: source}, p.Sources())
+
+	// A registry server synced since the connection was made is connected to again
+	now = metav1.NewTime(now.Add(time.Minute))
+	catsrc.Status.LastSync = now
+	now = metav1.NewTime(now.Add(time.Minute))
+	result, err = p.Sync(ctx, logger, catsrc)
+	require.NoError(t, err)
+	require.True(t, result.Connected)
+	require.Equal(t, 1, client.CloseCallCount())
+	require.Len(t, *dialed, 2)
+
+	// So is a changed address
+	moved := catsrc.DeepCopy()
+	moved.Spec.Address = "other:50051"
+	result, err = p.Sync(ctx, logger, moved)
+	require.NoError(t, err)
+	require.True(t, result.Connected)
+	require.Equal(t, "other:50051", result.Source.Address)
+	require.Equal(t, 1, (*dialed)[1].CloseCallCount())
+	require.Len(t, *dialed, 3)
+
+	require.NoError(t, p.Remove(key))
+	require.Equal(t, 1, (*dialed)[2].CloseCallCount())
+	_, ok = p.Get(key)
+	require.False(t, ok)
+	require.NoError(t, p.Remove(key))
+}
+
+func TestPoolSyncUnrecoverable(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.NewEntry(logrus.New())
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	key := types.NamespacedName{Name: "catalog", Namespace: "ns"}
+
+	p, dialed := fakePool(&now, false)
+	p.dialer.Dial = func(address string) (Client, error) {
+		client := &fakes.FakeInterface{}
+		client.HealthCheckReturns(false, registryclient.HealthError{Reason: registryclient.HealthErrReasonUnrecoveredTransient})
+		*dialed = append(*dialed, client)
+		return client, nil
+	}
+
+	// A connection that can't recover is dropped, to be made again on the next sync
+	result, err := p.Sync(ctx, logger, catalogSource("registry:50051"))
+	require.NoError(t, err)
+	require.True(t, result.Connected)
+	require.Equal(t, Source{}, result.Source)
+	require.Equal(t, 1, (*dialed)[0].CloseCallCount())
+	_, ok := p.Get(key)
+	require.False(t, ok)
+}
+
+func TestPoolSyncDialError(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.NewEntry(logrus.New())
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+
+	p, _ := fakePool(&now, true)
+	p.dialer.Dial = func(address string) (Client, error) {
+		return nil, fmt.Errorf("bad address")
+	}
+
+	_, err := p.Sync(ctx, logger, catalogSource("registry:50051"))
+	require.EqualError(t, err, "couldn't connect to registry: bad address")
+	require.Empty(t, p.Sources())
+}
+
+func TestPoolSyncHealthCheckUnlocked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := logrus.NewEntry(logrus.New())
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	key := types.NamespacedName{Name: "catalog", Namespace: "ns"}
+
+	p, dialed := fakePool(&now, false)
+	checking := make(chan struct{})
+	p.dialer.Dial = func(address string) (Client, error) {
+		client := &fakes.FakeInterface{}
+		client.HealthCheckStub = func(ctx context.Context, reconnectTimeout time.Duration) (bool, error) {
+			close(checking)
+			<-ctx.Done()
+			return false, ctx.Err()
+		}
+		*dialed = append(*dialed, client)
+		return client, nil
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := p.Sync(ctx, logger, catalogSource("registry:50051"))
+		done <- err
+	}()
+
+	// The pool can be read and synced while a connection is health checked
+	<-checking
+	source, ok := p.Get(key)
+	require.True(t, ok)
+	require.False(t, source.Healthy())
+	require.NoError(t, p.Remove(key))
+
+	// The health check gives up with the sync's context, and the connection dropped in the meantime isn't put back
+	cancel()
+	require.NoError(t, <-done)
+	_, ok = p.Get(key)
+	require.False(t, ok)
+	require.Len(t, *dialed, 1)
+}
@@ -0,0 +1,481 @@
+package inprocess
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+)
+
+// channelEntry is a node in the graph of a channel, like a row of the channel_entry table of a registry database.
+// Entries are numbered by their index in the catalog.
+type channelEntry struct {
+	pkg      string
+	channel  string
+	bundle   string
+	replaces int
+	depth    int
+	provides map[apiKey]struct{}
+}
+
+// apiKey identifies a provided API, which is looked up by group, version and kind only.
+type apiKey struct {
+	group, version, kind string
+}
+
+// noEntry is the replaces index of a channel entry that replaces nothing.
+const noEntry = -1
+
+// Catalog is the content of a catalog held in memory. It answers the queries of a registry database loaded from the
+// same manifests.
+type Catalog struct {
+	// URL is the location the catalog was loaded from.
+	URL string
+
+	// Digest identifies the content the catalog was loaded from.
+	Digest string
+
+	bundles  map[string]*bundle
+	packages map[string]*registry.PackageManifest
+	entries  []channelEntry
+}
+
+var _ registry.Query = &Catalog{}
+
+type bundle struct {
+	str      string
+	replaces string
+	skips    []string
+	provides map[apiKey]struct{}
+}
+
+// LoadDirectory loads the manifest directory at the given path the way the registry's directory loader does. Every
+// directory containing a ClusterServiceVersion is a bundle of the objects in its files, and every file containing a
+// package manifest adds a package. Hidden files and directories are skipped.
+func LoadDirectory(dir string) (*Catalog, error) {
+	c := &Catalog{
+		bundles:  map[string]*bundle{},
+		packages: map[string]*registry.PackageManifest{},
+	}
+
+	var manifests []registry.PackageManifest
+	bundleDirs := map[string]struct{}{}
+	err := filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(f.Name(), ".") && path != dir {
+			if f.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if f.IsDir() {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to load file %s: %v", path, err)
+		}
+
+		csv := v1alpha1.ClusterServiceVersion{}
+		if err := decode(data, &csv); err == nil && csv.Kind == v1alpha1.ClusterServiceVersionKind {
+			bundleDir := filepath.Dir(path)
+			if _, ok := bundleDirs[bundleDir]; ok {
+				return nil
+			}
+			bundleDirs[bundleDir] = struct{}{}
+			if err := c.addBundle(bundleDir); err != nil {
+				return fmt.Errorf("error loading bundle in %s: %v", bundleDir, err)
+			}
+			return nil
+		}
+
+		manifest := registry.PackageManifest{}
+		if err := decode(data, &manifest); err == nil && manifest.PackageName != "" {
+			manifests = append(manifests, manifest)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Channels are built once every bundle is known, since they follow replaces across bundle directories
+	for _, manifest := range manifests {
+		if err := c.addPackage(manifest); err != nil {
+			return nil, fmt.Errorf("error loading package %s: %v", manifest.PackageName, err)
+		}
+	}
+	return c, nil
+}
+
+func decode(data []byte, into interface{}) error {
+	return yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 30).Decode(into)
+}
+
+// addBundle adds the objects in the files of the given directory as a bundle.
+func (c *Catalog) addBundle(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	b := &registry.Bundle{}
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := decode(data, obj); err != nil {
+			return fmt.Errorf("could not decode contents of file %s: %v", f.Name(), err)
+		}
+		b.Add(obj)
+	}
+
+	if err := b.AllProvidedAPIsInBundle(); err != nil {
+		return err
+	}
+	return c.add(b)
+}
+
+// add adds the given bundle to the catalog.
+func (c *Catalog) add(b *registry.Bundle) error {
+	csv, err := b.ClusterServiceVersion()
+	if err != nil {
+		return err
+	}
+	name, _, bundleBytes, err := b.Serialize()
+	if err != nil {
+		return err
+	}
+	if _, ok := c.bundles[name]; ok {
+		return fmt.Errorf("duplicate bundle %s", name)
+	}
+
+	// Provided APIs are those the CSV owns, as in the registry database
+	provides := map[apiKey]struct{}{}
+	for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+		parts := strings.SplitN(crd.Name, ".", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("can't split bad CRD name %s", crd.Name)
+		}
+		provides[apiKey{group: parts[1], version: crd.Version, kind: crd.Kind}] = struct{}{}
+	}
+	for _, api := range csv.Spec.APIServiceDefinitions.Owned {
+		provides[apiKey{group: api.Group, version: api.Version, kind: api.Kind}] = struct{}{}
+	}
+
+	c.bundles[name] = &bundle{
+		str:      string(bundleBytes),
+		replaces: csv.Spec.Replaces,
+		skips:    skips(b),
+		provides: provides,
+	}
+	return nil
+}
+
+// skips returns the names of the bundles the CSV of the given bundle skips. They are read from the CSV object, since
+// the field isn't part of the CSV type.
+func skips(b *registry.Bundle) []string {
+	for _, obj := range b.Objects {
+		if obj.GetKind() == v1alpha1.ClusterServiceVersionKind {
+			skips, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "skips")
+			return skips
+		}
+	}
+	return nil
+}
+
+// addPackage adds the given package, and the entries of its channels: the head of each channel, an entry replacing
+// every bundle it skips, and an entry for every bundle down its replaces chain.
+func (c *Catalog) addPackage(manifest registry.PackageManifest) error {
+	if _, ok := c.packages[manifest.PackageName]; ok {
+		return fmt.Errorf("duplicate package")
+	}
+
+	pkg := &registry.PackageManifest{PackageName: manifest.PackageName}
+	for _, channel := range manifest.Channels {
+		if channel.IsDefaultChannel(manifest) {
+			pkg.DefaultChannelName = channel.Name
+		}
+		pkg.Channels = append(pkg.Channels, channel)
+	}
+	// Channels are listed by name, like the primary key order of the channel table
+	sort.Slice(pkg.Channels, func(i, j int) bool {
+		return pkg.Channels[i].Name < pkg.Channels[j].Name
+	})
+	if pkg.DefaultChannelName == "" {
+		return fmt.Errorf("no default channel specified for %s", manifest.PackageName)
+	}
+
+	var entries []channelEntry
+	add := func(channel, bundleName string, depth int) int {
+		entries = append(entries, channelEntry{
+			pkg:      manifest.PackageName,
+			channel:  channel,
+			bundle:   bundleName,
+			replaces: noEntry,
+			depth:    depth,
+		})
+		return len(c.entries) + len(entries) - 1
+	}
+	at := func(index int) *channelEntry {
+		return &entries[index-len(c.entries)]
+	}
+
+	for _, channel := range manifest.Channels {
+		current := add(channel.Name, channel.CurrentCSVName, 0)
+		visited := map[string]struct{}{}
+		for name, depth := channel.CurrentCSVName, 1; ; {
+			b, ok := c.bundles[name]
+			if !ok {
+				return fmt.Errorf("%s specifies replacement that couldn't be found", channel.CurrentCSVName)
+			}
+			if _, ok := visited[name]; ok {
+				return fmt.Errorf("channel %s replaces cycle through %s", channel.Name, name)
+			}
+			visited[name] = struct{}{}
+
+			for _, skipped := range b.skips {
+				skippedEntry := add(channel.Name, skipped, depth)
+				at(add(channel.Name, name, depth)).replaces = skippedEntry
+				depth++
+			}
+
+			if b.replaces == "" {
+				break
+			}
+			replaced := add(channel.Name, b.replaces, depth)
+			at(current).replaces = replaced
+			current, name = replaced, b.replaces
+			depth++
+		}
+	}
+
+	for i := range entries {
+		if b, ok := c.bundles[entries[i].bundle]; ok {
+			entries[i].provides = b.provides
+		}
+	}
+	c.packages[pkg.PackageName] = pkg
+	c.entries = append(c.entries, entries...)
+	return nil
+}
+
+// bundleString returns the serialized bundle of the given channel entry, and false if the bundle isn't in the catalog.
+func (c *Catalog) bundleString(entry channelEntry) (string, bool) {
+	b, ok := c.bundles[entry.bundle]
+	if !ok {
+		return "", false
+	}
+	return b.str, true
+}
+
+// replacedName returns the name of the bundle the given channel entry replaces, if any.
+func (c *Catalog) replacedName(entry channelEntry) string {
+	if entry.replaces == noEntry {
+		return ""
+	}
+	return c.entries[entry.replaces].bundle
+}
+
+// ListTables returns no tables, since the catalog isn't a database.
+func (c *Catalog) ListTables(ctx context.Context) ([]string, error) {
+	return []string{}, nil
+}
+
+// ListPackages returns the names of the packages in the catalog.
+func (c *Catalog) ListPackages(ctx context.Context) ([]string, error) {
+	packages := []string{}
+	for name := range c.packages {
+		packages = append(packages, name)
+	}
+	sort.Strings(packages)
+	return packages, nil
+}
+
+func (c *Catalog) GetPackage(ctx context.Context, name string) (*registry.PackageManifest, error) {
+	pkg, ok := c.packages[name]
+	if !ok {
+		return nil, fmt.Errorf("package %s not found", name)
+	}
+	out := *pkg
+	out.Channels = append([]registry.PackageChannel(nil), pkg.Channels...)
+	return &out, nil
+}
+
+func (c *Catalog) GetBundle(ctx context.Context, pkgName, channelName, csvName string) (string, error) {
+	for _, entry := range c.entries {
+		if entry.pkg == pkgName && entry.channel == channelName && entry.bundle == csvName {
+			if str, ok := c.bundleString(entry); ok {
+				return str, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no bundle found for csv %s", csvName)
+}
+
+func (c *Catalog) GetBundleForChannel(ctx context.Context, pkgName string, channelName string) (string, error) {
+	if pkg, ok := c.packages[pkgName]; ok {
+		for _, channel := range pkg.Channels {
+			if channel.Name != channelName {
+				continue
+			}
+			if b, ok := c.bundles[channel.CurrentCSVName]; ok {
+				return b.str, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no bundle found for %s %s", pkgName, channelName)
+}
+
+func (c *Catalog) GetChannelEntriesThatReplace(ctx context.Context, name string) ([]*registry.ChannelEntry, error) {
+	entries := distinctEntries{}
+	for _, entry := range c.entries {
+		if entry.replaces != noEntry && c.replacedName(entry) == name {
+			entries.add(registry.ChannelEntry{
+				PackageName: entry.pkg,
+				ChannelName: entry.channel,
+				BundleName:  entry.bundle,
+				Replaces:    name,
+			})
+		}
+	}
+	if len(entries.list) == 0 {
+		return nil, fmt.Errorf("no channel entries found that replace %s", name)
+	}
+	return entries.list, nil
+}
+
+func (c *Catalog) GetBundleThatReplaces(ctx context.Context, name, pkgName, channelName string) (string, error) {
+	for _, entry := range c.entries {
+		if entry.replaces == noEntry {
+			continue
+		}
+		replaced := c.entries[entry.replaces]
+		if replaced.bundle != name || replaced.pkg != pkgName || replaced.channel != channelName {
+			continue
+		}
+		if str, ok := c.bundleString(entry); ok {
+			return str, nil
+		}
+	}
+	return "", fmt.Errorf("no bundle found that replaces %s", name)
+}
+
+func (c *Catalog) GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) ([]*registry.ChannelEntry, error) {
+	key := apiKey{group: group, version: version, kind: kind}
+	entries := distinctEntries{}
+	for _, entry := range c.entries {
+		if _, ok := entry.provides[key]; ok {
+			entries.add(registry.ChannelEntry{
+				PackageName: entry.pkg,
+				ChannelName: entry.channel,
+				BundleName:  entry.bundle,
+				Replaces:    c.replacedName(entry),
+			})
+		}
+	}
+	if len(entries.list) == 0 {
+		return nil, fmt.Errorf("no channel entries found that provide %s %s %s", group, version, kind)
+	}
+	return entries.list, nil
+}
+
+func (c *Catalog) GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) ([]*registry.ChannelEntry, error) {
+	var entries []*registry.ChannelEntry
+	for _, entry := range c.latestEntriesThatProvide(apiKey{group: group, version: version, kind: kind}) {
+		entries = append(entries, &registry.ChannelEntry{
+			PackageName: entry.pkg,
+			ChannelName: entry.channel,
+			BundleName:  entry.bundle,
+			Replaces:    c.replacedName(entry),
+		})
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no channel entries found that provide %s %s %s", group, version, kind)
+	}
+	return entries, nil
+}
+
+func (c *Catalog) GetBundleThatProvides(ctx context.Context, group, version, kind string) (string, *registry.ChannelEntry, error) {
+	for _, entry := range c.latestEntriesThatProvide(apiKey{group: group, version: version, kind: kind}) {
+		if entry.channel != c.packages[entry.pkg].DefaultChannelName {
+			continue
+		}
+		if str, ok := c.bundleString(entry); ok {
+			return str, &registry.ChannelEntry{
+				PackageName: entry.pkg,
+				ChannelName: entry.channel,
+				BundleName:  entry.bundle,
+			}, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no bundle found that provides %s %s %s", group, version, kind)
+}
+
+// latestEntriesThatProvide returns the least deep entry providing the given API in each channel, ordered by package
+// and channel.
+func (c *Catalog) latestEntriesThatProvide(key apiKey) []channelEntry {
+	type channelKey struct{ pkg, channel string }
+	latest := map[channelKey]channelEntry{}
+	var order []channelKey
+	for _, entry := range c.entries {
+		if _, ok := entry.provides[key]; !ok {
+			continue
+		}
+		k := channelKey{entry.pkg, entry.channel}
+		current, ok := latest[k]
+		if !ok {
+			order = append(order, k)
+		}
+		if !ok || entry.depth < current.depth {
+			latest[k] = entry
+		}
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].pkg != order[j].pkg {
+			return order[i].pkg < order[j].pkg
+		}
+		return order[i].channel < order[j].channel
+	})
+	entries := make([]channelEntry, 0, len(order))
+	for _, k := range order {
+		entries = append(entries, latest[k])
+	}
+	return entries
+}
+
+// distinctEntries collects channel entries, leaving out duplicates.
+type distinctEntries struct {
+	seen map[registry.ChannelEntry]struct{}
+	list []*registry.ChannelEntry
+}
+
+func (d *distinctEntries) add(entry registry.ChannelEntry) {
+	if d.seen == nil {
+		d.seen = map[registry.ChannelEntry]struct{}{}
+	}
+	if _, ok := d.seen[entry]; ok {
+		return
+	}
+	d.seen[entry] = struct{}{}
+	d.list = append(d.list, &entry)
+}
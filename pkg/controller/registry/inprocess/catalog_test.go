@@ -0,0 +1,174 @@
+package inprocess
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifests = "testdata/manifests"
+
+// requireBundle requires the given bundle string to be the bundle of the given CSV, as served over the registry API.
+func requireBundle(t *testing.T, csvName, bundleString string) {
+	bundle, err := api.BundleStringToAPIBundle(bundleString, &registry.ChannelEntry{})
+	require.NoError(t, err)
+	require.Equal(t, csvName, bundle.GetCsvName())
+	require.Len(t, bundle.GetObject(), 2)
+}
+
+func TestLoadDirectory(t *testing.T) {
+	ctx := context.Background()
+	catalog, err := LoadDirectory(testManifests)
+	require.NoError(t, err)
+
+	packages, err := catalog.ListPackages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"test"}, packages)
+
+	pkg, err := catalog.GetPackage(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, &registry.PackageManifest{
+		PackageName:        "test",
+		DefaultChannelName: "stable",
+		Channels: []registry.PackageChannel{
+			{Name: "alpha", CurrentCSVName: "testoperator.v3.0.0"},
+			{Name: "stable", CurrentCSVName: "testoperator.v2.0.0"},
+		},
+	}, pkg)
+	_, err = catalog.GetPackage(ctx, "missing")
+	require.EqualError(t, err, "package missing not found")
+
+	bundle, err := catalog.GetBundleForChannel(ctx, "test", "alpha")
+	require.NoError(t, err)
+	requireBundle(t, "testoperator.v3.0.0", bundle)
+
+	bundle, err = catalog.GetBundle(ctx, "test", "stable", "testoperator.v1.0.0")
+	require.NoError(t, err)
+	requireBundle(t, "testoperator.v1.0.0", bundle)
+	_, err = catalog.GetBundle(ctx, "test", "stable", "testoperator.v3.0.0")
+	require.EqualError(t, err, "no bundle found for csv testoperator.v3.0.0")
+	_, err = catalog.GetBundle(ctx, "test", "alpha", "testoperator.v2.0.1")
+	require.EqualError(t, err, "no bundle found for csv testoperator.v2.0.1")
+
+	entries, err := catalog.GetChannelEntriesThatReplace(ctx, "testoperator.v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, []*registry.ChannelEntry{
+		{PackageName: "test", ChannelName: "stable", BundleName: "testoperator.v2.0.0", Replaces: "testoperator.v1.0.0"},
+		{PackageName: "test", ChannelName: "alpha", BundleName: "testoperator.v2.0.0", Replaces: "testoperator.v1.0.0"},
+	}, entries)
+
+	// Skipped bundles are replaced by the bundle that skips them
+	entries, err = catalog.GetChannelEntriesThatReplace(ctx, "testoperator.v2.0.1")
+	require.NoError(t, err)
+	require.Equal(t, []*registry.ChannelEntry{
+		{PackageName: "test", ChannelName: "alpha", BundleName: "testoperator.v3.0.0", Replaces: "testoperator.v2.0.1"},
+	}, entries)
+	_, err = catalog.GetChannelEntriesThatReplace(ctx, "testoperator.v3.0.0")
+	require.EqualError(t, err, "no channel entries found that replace testoperator.v3.0.0")
+
+	bundle, err = catalog.GetBundleThatReplaces(ctx, "testoperator.v2.0.0", "test", "alpha")
+	require.NoError(t, err)
+	requireBundle(t, "testoperator.v3.0.0", bundle)
+	_, err = catalog.GetBundleThatReplaces(ctx, "testoperator.v2.0.0", "test", "stable")
+	require.EqualError(t, err, "no bundle found that replaces testoperator.v2.0.0")
+
+	entries, err = catalog.GetChannelEntriesThatProvide(ctx, "example.com", "v1", "Test")
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+
+	entries, err = catalog.GetLatestChannelEntriesThatProvide(ctx, "example.com", "v1", "Test")
+	require.NoError(t, err)
+	require.Equal(t, []*registry.ChannelEntry{
+		{PackageName: "test", ChannelName: "alpha", BundleName: "testoperator.v3.0.0", Replaces: "testoperator.v2.0.0"},
+		{PackageName: "test", ChannelName: "stable", BundleName: "testoperator.v2.0.0", Replaces: "testoperator.v1.0.0"},
+	}, entries)
+	_, err = catalog.GetLatestChannelEntriesThatProvide(ctx, "example.com", "v2", "Test")
+	require.EqualError(t, err, "no channel entries found that provide example.com v2 Test")
+
+	bundle, entry, err := catalog.GetBundleThatProvides(ctx, "example.com", "v1", "Test")
+	require.NoError(t, err)
+	requireBundle(t, "testoperator.v2.0.0", bundle)
+	require.Equal(t, &registry.ChannelEntry{PackageName: "test", ChannelName: "stable", BundleName: "testoperator.v2.0.0"}, entry)
+}
+
+func TestLoadDirectoryInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(t *testing.T, dir string)
+		expected string
+	}{
+		{
+			name: "MissingReplacement",
+			modify: func(t *testing.T, dir string) {
+				require.NoError(t, os.RemoveAll(filepath.Join(dir, "test", "1.0.0")))
+			},
+			expected: "error loading package test: testoperator.v2.0.0 specifies replacement that couldn't be found",
+		},
+		{
+			name: "NoDefaultChannel",
+			modify: func(t *testing.T, dir string) {
+				writeTestFile(t, filepath.Join(dir, "test", "test.package.yaml"), `
+packageName: test
+channels:
+- name: stable
+  currentCSV: testoperator.v2.0.0
+- name: alpha
+  currentCSV: testoperator.v3.0.0
+`)
+			},
+			expected: "error loading package test: no default channel specified for test",
+		},
+		{
+			name: "MissingCRD",
+			modify: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, "test", "1.0.0", "test.crd.yaml")))
+			},
+			expected: "couldn't find",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := copyManifests(t)
+			defer os.RemoveAll(dir)
+			tt.modify(t, dir)
+
+			_, err := LoadDirectory(dir)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+// copyManifests copies the test manifests to a temporary directory.
+func copyManifests(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "manifests-")
+	require.NoError(t, err)
+	require.NoError(t, filepath.Walk(testManifests, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(testManifests, path)
+		if err != nil {
+			return err
+		}
+		if f.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0755)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dir, rel), data, 0644)
+	}))
+	return dir
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
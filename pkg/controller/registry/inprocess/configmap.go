@@ -0,0 +1,138 @@
+package inprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	olmregistry "github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry"
+)
+
+// configMapKeys are the keys of the lists a ConfigMap backing a catalog holds.
+var configMapKeys = []string{olmregistry.ConfigMapCRDName, olmregistry.ConfigMapCSVName, olmregistry.ConfigMapPackageName}
+
+// LoadConfigMapDirectories loads the catalog held by the ConfigMaps mounted in the given directories, in order. Each
+// directory holds the lists of one ConfigMap in files named by their keys.
+func LoadConfigMapDirectories(dirs ...string) (*Catalog, error) {
+	data := make([]map[string]string, 0, len(dirs))
+	for _, dir := range dirs {
+		lists := map[string]string{}
+		for _, key := range configMapKeys {
+			value, err := ioutil.ReadFile(filepath.Join(dir, key))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unable to load file %s: %v", filepath.Join(dir, key), err)
+			}
+			lists[key] = string(value)
+		}
+		data = append(data, lists)
+	}
+	return LoadConfigMaps(data...)
+}
+
+// LoadConfigMaps loads the catalog held by the given ConfigMap data the way the registry's configmap loader loads a
+// single ConfigMap. The lists under each key are concatenated in order, so a package may list the bundles of any of
+// the ConfigMaps. Every CSV is a bundle of itself and the CRDs it owns.
+func LoadConfigMaps(data ...map[string]string) (*Catalog, error) {
+	c := &Catalog{
+		bundles:  map[string]*bundle{},
+		packages: map[string]*registry.PackageManifest{},
+	}
+
+	var crdList []v1beta1.CustomResourceDefinition
+	if err := decodeLists(data, olmregistry.ConfigMapCRDName, &crdList); err != nil {
+		return nil, err
+	}
+	crds := map[registry.APIKey]*unstructured.Unstructured{}
+	for _, crd := range crdList {
+		if crd.Spec.Versions == nil && crd.Spec.Version != "" {
+			crd.Spec.Versions = []v1beta1.CustomResourceDefinitionVersion{{Name: crd.Spec.Version, Served: true, Storage: true}}
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&crd)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range crd.Spec.Versions {
+			key := registry.APIKey{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind, Plural: crd.Spec.Names.Plural}
+			if _, ok := crds[key]; ok {
+				return nil, fmt.Errorf("can't add the same CRD twice: %s", crd.GetName())
+			}
+			crds[key] = &unstructured.Unstructured{Object: obj}
+		}
+	}
+
+	// CSVs are kept as they're listed, since the CSV type leaves out fields such as skips
+	var csvList []map[string]interface{}
+	if err := decodeLists(data, olmregistry.ConfigMapCSVName, &csvList); err != nil {
+		return nil, err
+	}
+	for _, obj := range csvList {
+		csv := v1alpha1.ClusterServiceVersion{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &csv); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", olmregistry.ConfigMapCSVName, err)
+		}
+		b := registry.NewBundle(csv.GetName(), "", "", &unstructured.Unstructured{Object: obj})
+		for _, owned := range csv.Spec.CustomResourceDefinitions.Owned {
+			parts := strings.SplitN(owned.Name, ".", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("can't split bad CRD name %s", owned.Name)
+			}
+			// CRDs missing from the lists are left out of the bundle, like in the registry database
+			if crd, ok := crds[registry.APIKey{Group: parts[1], Version: owned.Version, Kind: owned.Kind, Plural: parts[0]}]; ok {
+				b.Add(crd)
+			}
+		}
+		if err := c.add(b); err != nil {
+			return nil, fmt.Errorf("error loading bundle %s: %v", csv.GetName(), err)
+		}
+	}
+
+	var manifests []registry.PackageManifest
+	if err := decodeLists(data, olmregistry.ConfigMapPackageName, &manifests); err != nil {
+		return nil, err
+	}
+	for _, manifest := range manifests {
+		if err := c.addPackage(manifest); err != nil {
+			return nil, fmt.Errorf("error loading package %s: %v", manifest.PackageName, err)
+		}
+	}
+	return c, nil
+}
+
+// decodeLists decodes the YAML lists under the given key of the given ConfigMap data, concatenated in order, into the
+// given slice pointer.
+func decodeLists(data []map[string]string, key string, into interface{}) error {
+	items := []json.RawMessage{}
+	for i, lists := range data {
+		value, ok := lists[key]
+		if !ok {
+			continue
+		}
+		var list []json.RawMessage
+		if err := yaml.Unmarshal([]byte(value), &list); err != nil {
+			return fmt.Errorf("error reading %s of configmap %d: %v", key, i, err)
+		}
+		items = append(items, list...)
+	}
+
+	concatenated, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(concatenated, into); err != nil {
+		return fmt.Errorf("error parsing %s: %v", key, err)
+	}
+	return nil
+}
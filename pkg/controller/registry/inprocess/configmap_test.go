@@ -0,0 +1,96 @@
+package inprocess
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+
+	olmregistry "github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry"
+)
+
+// manifestList returns a YAML list of the test manifests in the given files.
+func manifestList(t *testing.T, files ...string) string {
+	items := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(filepath.Join(testManifests, "test", file))
+		require.NoError(t, err)
+		item, err := yaml.YAMLToJSON(data)
+		require.NoError(t, err)
+		items = append(items, string(item))
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+// testShards returns the test manifests split across two ConfigMaps, with the package listing bundles of both.
+func testShards(t *testing.T) []map[string]string {
+	return []map[string]string{
+		{
+			olmregistry.ConfigMapCRDName: manifestList(t, "1.0.0/test.crd.yaml"),
+			olmregistry.ConfigMapCSVName: manifestList(t, "1.0.0/testoperator.v1.0.0.clusterserviceversion.yaml", "2.0.0/testoperator.v2.0.0.clusterserviceversion.yaml"),
+		},
+		{
+			olmregistry.ConfigMapCSVName:     manifestList(t, "3.0.0/testoperator.v3.0.0.clusterserviceversion.yaml"),
+			olmregistry.ConfigMapPackageName: manifestList(t, "test.package.yaml"),
+		},
+	}
+}
+
+func TestLoadConfigMaps(t *testing.T) {
+	ctx := context.Background()
+	expected, err := LoadDirectory(testManifests)
+	require.NoError(t, err)
+
+	catalog, err := LoadConfigMaps(testShards(t)...)
+	require.NoError(t, err)
+
+	// The channels are the same as those of the same manifests loaded from a directory
+	require.Equal(t, expected.packages, catalog.packages)
+	require.Equal(t, expected.entries, catalog.entries)
+
+	// Bundles hold their CSV and the CRD it owns, even if it's in another ConfigMap
+	bundle, err := catalog.GetBundleForChannel(ctx, "test", "alpha")
+	require.NoError(t, err)
+	requireBundle(t, "testoperator.v3.0.0", bundle)
+
+	// A package listing a bundle that's in none of the ConfigMaps can't be loaded
+	shards := testShards(t)
+	_, err = LoadConfigMaps(shards[1])
+	require.EqualError(t, err, "error loading package test: testoperator.v2.0.0 specifies replacement that couldn't be found")
+
+	// Neither can a CRD listed twice
+	shards[1][olmregistry.ConfigMapCRDName] = shards[0][olmregistry.ConfigMapCRDName]
+	_, err = LoadConfigMaps(shards...)
+	require.EqualError(t, err, "can't add the same CRD twice: tests.example.com")
+}
+
+func TestLoadConfigMapDirectories(t *testing.T) {
+	root, err := ioutil.TempDir("", "configmaps")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	var dirs []string
+	for i, shard := range testShards(t) {
+		dir := filepath.Join(root, string('a'+rune(i)))
+		require.NoError(t, os.Mkdir(dir, 0755))
+		for key, value := range shard {
+			writeTestFile(t, filepath.Join(dir, key), value)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	catalog, err := LoadConfigMapDirectories(dirs...)
+	require.NoError(t, err)
+	packages, err := catalog.ListPackages(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"test"}, packages)
+
+	// The ConfigMaps are loaded in the given order, so a package can't be loaded before the bundles it lists
+	_, err = LoadConfigMapDirectories(dirs[1])
+	require.Error(t, err)
+}
@@ -0,0 +1,291 @@
+package inprocess
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// fetchTimeout bounds the download of a catalog archive.
+	fetchTimeout = 2 * time.Minute
+
+	// maxCatalogSize bounds the size of the files extracted from a catalog archive.
+	maxCatalogSize = 256 << 20
+
+	// maxRedirects bounds the redirects followed when downloading a catalog archive.
+	maxRedirects = 10
+)
+
+// Loader loads catalogs from the locations it's configured to allow. The zero value doesn't allow any location.
+type Loader struct {
+	// Root is the local directory catalogs can be loaded from. Local catalogs must be within it, including the targets
+	// of any links in them. Local catalogs can't be loaded if it's empty.
+	Root string
+
+	// Hosts are the hosts catalogs can be downloaded from, either host names or host:port pairs. Redirects must stay
+	// within them too. Catalogs can't be downloaded if it's empty.
+	Hosts []string
+}
+
+// Load loads the catalog at the given location, which is either an http(s) URL of a gzipped tarball of a manifest
+// directory, or a file URL or absolute path of a manifest directory or of such a tarball.
+func (l *Loader) Load(location string) (*Catalog, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog url %s: %v", location, err)
+	}
+
+	var catalog *Catalog
+	switch {
+	case u.Scheme == "http" || u.Scheme == "https":
+		catalog, err = l.loadHTTP(u)
+	case u.Scheme == "file":
+		catalog, err = l.loadFile(u.Path)
+	case u.Scheme == "" && filepath.IsAbs(location):
+		catalog, err = l.loadFile(location)
+	default:
+		return nil, fmt.Errorf("unsupported catalog url %s", location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading catalog from %s: %v", location, err)
+	}
+	catalog.URL = location
+	return catalog, nil
+}
+
+// hostAllowed returns true if catalogs can be downloaded from the host of the given URL.
+func (l *Loader) hostAllowed(u *url.URL) bool {
+	for _, host := range l.Hosts {
+		if strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Loader) loadHTTP(u *url.URL) (*Catalog, error) {
+	if !l.hostAllowed(u) {
+		return nil, fmt.Errorf("downloading catalogs from %s isn't allowed", u.Host)
+	}
+	client := &http.Client{
+		Timeout: fetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if !l.hostAllowed(req.URL) {
+				return fmt.Errorf("redirect to %s isn't allowed", req.URL.Host)
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return loadArchive(resp.Body)
+}
+
+// localPath resolves the links in the given path, and returns it if it's within the root directory.
+func (l *Loader) localPath(path string) (string, error) {
+	if l.Root == "" {
+		return "", fmt.Errorf("loading local catalogs isn't allowed")
+	}
+	root, err := filepath.EvalSymlinks(l.Root)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s isn't within %s", path, l.Root)
+	}
+	return resolved, nil
+}
+
+func (l *Loader) loadFile(path string) (*Catalog, error) {
+	path, err := l.localPath(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return loadArchive(f)
+	}
+
+	if err := l.checkLinks(path); err != nil {
+		return nil, err
+	}
+	digest, err := directoryDigest(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := LoadDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog.Digest = digest
+	return catalog, nil
+}
+
+// checkLinks returns an error if any link in the given directory resolves outside of the root directory.
+func (l *Loader) checkLinks(dir string) error {
+	return filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+		_, err = l.localPath(path)
+		return err
+	})
+}
+
+// loadArchive extracts the given gzipped tarball to a temporary directory, and loads the catalog in it. The digest of
+// the catalog is the digest of the archive.
+func loadArchive(r io.Reader) (*Catalog, error) {
+	dir, err := ioutil.TempDir("", "catalog-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	h := sha256.New()
+	tee := io.TeeReader(r, h)
+	if err := extract(tee, dir); err != nil {
+		return nil, err
+	}
+	// Hash anything left after the end of the archive too
+	if _, err := io.Copy(ioutil.Discard, tee); err != nil {
+		return nil, err
+	}
+
+	catalog, err := LoadDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+	catalog.Digest = digest(h)
+	return catalog, nil
+}
+
+// extract extracts the directories and regular files of the given gzipped tarball into the given directory. Other
+// entries, such as links, are skipped.
+func extract(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("error reading catalog archive: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	var size int64
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading catalog archive: %v", err)
+		}
+
+		target := filepath.Join(dir, filepath.Clean("/"+header.Name))
+		if !strings.HasPrefix(target, dir+string(filepath.Separator)) {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			size += header.Size
+			if size > maxCatalogSize {
+				return fmt.Errorf("catalog archive exceeds %d bytes", maxCatalogSize)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(target, io.LimitReader(tr, header.Size)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// directoryDigest returns a digest of the paths and contents of the files of the given directory that are loaded into
+// a catalog.
+func directoryDigest(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(f.Name(), ".") && path != dir {
+			if f.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if f.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", rel, len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest(h), nil
+}
+
+func digest(h hash.Hash) string {
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
@@ -0,0 +1,263 @@
+package inprocess
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// archive returns a gzipped tarball of the given directory, with the given extra files added.
+func archive(t *testing.T, dir string, extra map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	add := func(name string, data []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, filepath.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil || f.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		add(filepath.Join("manifests", rel), data)
+		return nil
+	}))
+	for name, data := range extra {
+		add(name, []byte(data))
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestLoad(t *testing.T) {
+	// Serve an archive of the test manifests from a local file server, and keep a copy of the manifests next to it
+	root, err := ioutil.TempDir("", "root-")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "catalog.tar.gz"), archive(t, testManifests, nil), 0644))
+	manifests := copyManifests(t)
+	defer os.RemoveAll(manifests)
+	dir := filepath.Join(root, "manifests")
+	require.NoError(t, os.Rename(manifests, dir))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "test"), filepath.Join(root, "linked")))
+
+	// A directory outside of the root, and links to it from within the root
+	outside, err := ioutil.TempDir("", "outside-")
+	require.NoError(t, err)
+	defer os.RemoveAll(outside)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outside, "catalog.tar.gz"), archive(t, testManifests, nil), 0644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "outside")))
+	leaky := filepath.Join(root, "leaky")
+	require.NoError(t, os.Rename(copyManifests(t), leaky))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "catalog.tar.gz"), filepath.Join(leaky, "test", "secret.yaml")))
+
+	fileServer := httptest.NewServer(http.FileServer(http.Dir(root)))
+	defer fileServer.Close()
+	fileServerURL, err := url.Parse(fileServer.URL)
+	require.NoError(t, err)
+	redirectServer := httptest.NewServer(http.RedirectHandler(fileServer.URL+"/catalog.tar.gz", http.StatusFound))
+	defer redirectServer.Close()
+	redirectServerURL, err := url.Parse(redirectServer.URL)
+	require.NoError(t, err)
+
+	allowAll := &Loader{Root: root, Hosts: []string{fileServerURL.Host, redirectServerURL.Host}}
+	tests := []struct {
+		name     string
+		loader   *Loader
+		url      string
+		expected string
+	}{
+		{
+			name:   "HTTP",
+			loader: allowAll,
+			url:    fileServer.URL + "/catalog.tar.gz",
+		},
+		{
+			name:   "HTTPAllowedHostName",
+			loader: &Loader{Hosts: []string{fileServerURL.Hostname()}},
+			url:    fileServer.URL + "/catalog.tar.gz",
+		},
+		{
+			name:   "HTTPRedirect",
+			loader: allowAll,
+			url:    redirectServer.URL,
+		},
+		{
+			name:   "FileDirectory",
+			loader: allowAll,
+			url:    "file://" + dir,
+		},
+		{
+			name:   "PathDirectory",
+			loader: allowAll,
+			url:    dir,
+		},
+		{
+			name:   "PathArchive",
+			loader: allowAll,
+			url:    filepath.Join(root, "catalog.tar.gz"),
+		},
+		{
+			name:     "HTTPNotFound",
+			loader:   allowAll,
+			url:      fileServer.URL + "/missing.tar.gz",
+			expected: "error loading catalog from " + fileServer.URL + "/missing.tar.gz: unexpected status 404 Not Found",
+		},
+		{
+			name:     "HTTPNotAllowed",
+			loader:   &Loader{Root: root},
+			url:      fileServer.URL + "/catalog.tar.gz",
+			expected: "error loading catalog from " + fileServer.URL + "/catalog.tar.gz: downloading catalogs from " + fileServerURL.Host + " isn't allowed",
+		},
+		{
+			name:     "LocalNotAllowed",
+			loader:   &Loader{Hosts: []string{fileServerURL.Host}},
+			url:      dir,
+			expected: "error loading catalog from " + dir + ": loading local catalogs isn't allowed",
+		},
+		{
+			name:     "OutsideRoot",
+			loader:   allowAll,
+			url:      "file://" + outside + "/catalog.tar.gz",
+			expected: "error loading catalog from file://" + outside + "/catalog.tar.gz: " + outside + "/catalog.tar.gz isn't within " + root,
+		},
+		{
+			name:     "DotDotOutsideRoot",
+			loader:   allowAll,
+			url:      root + "/../" + filepath.Base(outside) + "/catalog.tar.gz",
+			expected: "error loading catalog from " + root + "/../" + filepath.Base(outside) + "/catalog.tar.gz: " + root + "/../" + filepath.Base(outside) + "/catalog.tar.gz isn't within " + root,
+		},
+		{
+			name:     "LinkOutsideRoot",
+			loader:   allowAll,
+			url:      root + "/outside/catalog.tar.gz",
+			expected: "error loading catalog from " + root + "/outside/catalog.tar.gz: " + root + "/outside/catalog.tar.gz isn't within " + root,
+		},
+		{
+			name:     "LinkInDirectoryOutsideRoot",
+			loader:   allowAll,
+			url:      leaky,
+			expected: "error loading catalog from " + leaky + ": " + leaky + "/test/secret.yaml isn't within " + root,
+		},
+		{
+			name:     "NotAnArchive",
+			loader:   allowAll,
+			url:      dir + "/test/test.package.yaml",
+			expected: "error loading catalog from " + dir + "/test/test.package.yaml: error reading catalog archive: gzip: invalid header",
+		},
+		{
+			name:     "UnsupportedScheme",
+			loader:   allowAll,
+			url:      "ftp://example.com/catalog.tar.gz",
+			expected: "unsupported catalog url ftp://example.com/catalog.tar.gz",
+		},
+		{
+			name:     "RelativePath",
+			loader:   allowAll,
+			url:      testManifests,
+			expected: "unsupported catalog url " + testManifests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := tt.loader.Load(tt.url)
+			if tt.expected != "" {
+				require.EqualError(t, err, tt.expected)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.url, catalog.URL)
+			require.NotEmpty(t, catalog.Digest)
+
+			packages, err := catalog.ListPackages(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []string{"test"}, packages)
+		})
+	}
+}
+
+func TestLoadRedirectNotAllowed(t *testing.T) {
+	fileServer := httptest.NewServer(http.FileServer(http.Dir(testManifests)))
+	defer fileServer.Close()
+	redirectServer := httptest.NewServer(http.RedirectHandler(fileServer.URL+"/test/test.package.yaml", http.StatusFound))
+	defer redirectServer.Close()
+	redirectServerURL, err := url.Parse(redirectServer.URL)
+	require.NoError(t, err)
+	fileServerURL, err := url.Parse(fileServer.URL)
+	require.NoError(t, err)
+
+	_, err = (&Loader{Hosts: []string{redirectServerURL.Host}}).Load(redirectServer.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "redirect to "+fileServerURL.Host+" isn't allowed")
+}
+
+func TestLoadDigest(t *testing.T) {
+	dir := copyManifests(t)
+	defer os.RemoveAll(dir)
+
+	loader := &Loader{Root: dir}
+	first, err := loader.Load(dir)
+	require.NoError(t, err)
+	second, err := loader.Load(dir)
+	require.NoError(t, err)
+	require.Equal(t, first.Digest, second.Digest)
+
+	// Hidden files aren't part of the catalog
+	writeTestFile(t, filepath.Join(dir, ".notes"), "ignored")
+	unchanged, err := loader.Load(dir)
+	require.NoError(t, err)
+	require.Equal(t, first.Digest, unchanged.Digest)
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "test", "3.0.0")))
+	writeTestFile(t, filepath.Join(dir, "test", "test.package.yaml"), `
+packageName: test
+channels:
+- name: stable
+  currentCSV: testoperator.v2.0.0
+`)
+	changed, err := loader.Load(dir)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Digest, changed.Digest)
+}
+
+func TestLoadArchiveOutsideDirectory(t *testing.T) {
+	parent, err := ioutil.TempDir("", "archive-")
+	require.NoError(t, err)
+	defer os.RemoveAll(parent)
+
+	path := filepath.Join(parent, "catalog.tar.gz")
+	require.NoError(t, ioutil.WriteFile(path, archive(t, testManifests, map[string]string{
+		"../../escaped.yaml": "escaped",
+	}), 0644))
+
+	_, err = (&Loader{Root: parent}).Load(path)
+	require.NoError(t, err)
+
+	// The entry is extracted within the temporary directory instead
+	_, err = os.Stat(filepath.Join(os.TempDir(), "escaped.yaml"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(parent, "escaped.yaml"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,185 @@
+// Package paritytest checks that in-process catalogs answer queries the way registry databases loaded from the same
+// manifests do. It's kept apart from the inprocess package, since the registry database needs cgo.
+package paritytest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
+)
+
+type gvk struct {
+	group, version, kind string
+}
+
+// registryDatabase loads the given manifest directory into a registry database in the given directory, the way
+// registry pods serve it.
+func registryDatabase(t *testing.T, dir, manifests string) registry.Query {
+	dbName := filepath.Join(dir, "bundles.db")
+	load, err := sqlite.NewSQLLiteLoader(dbName)
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewSQLLoaderForDirectory(load, manifests).Populate())
+	load.Close()
+
+	store, err := sqlite.NewSQLLiteQuerier(dbName)
+	require.NoError(t, err)
+	return store
+}
+
+// csvContent returns the names of the ClusterServiceVersions in the given manifest directory, and the APIs they own.
+func csvContent(t *testing.T, manifests string) (names []string, apis []gvk) {
+	seen := map[gvk]struct{}{}
+	require.NoError(t, filepath.Walk(manifests, func(path string, f os.FileInfo, err error) error {
+		if err != nil || !strings.HasSuffix(path, ".clusterserviceversion.yaml") {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		csv := &v1alpha1.ClusterServiceVersion{}
+		if err := yaml.Unmarshal(data, csv); err != nil {
+			return err
+		}
+		names = append(names, csv.GetName())
+		for _, owned := range csv.Spec.CustomResourceDefinitions.Owned {
+			key := gvk{version: owned.Version, kind: owned.Kind}
+			if i := strings.Index(owned.Name, "."); i >= 0 {
+				key.group = owned.Name[i+1:]
+			}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				apis = append(apis, key)
+			}
+		}
+		return nil
+	}))
+	return
+}
+
+// requireSameResult requires the results of the same query of the catalog and the registry database to match.
+// Errors only need to match in whether they happened, since the database reports its own.
+func requireSameResult(t *testing.T, query string, expected, actual interface{}, expectedErr, actualErr error) {
+	if expectedErr != nil {
+		require.Error(t, actualErr, query)
+		return
+	}
+	require.NoError(t, actualErr, query)
+	require.Equal(t, expected, actual, query)
+}
+
+// sortedEntries sorts the given channel entries, whose order isn't part of the registry API.
+func sortedEntries(entries []*registry.ChannelEntry) []*registry.ChannelEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		return fmt.Sprint(*entries[i]) < fmt.Sprint(*entries[j])
+	})
+	return entries
+}
+
+func TestCatalogParity(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests string
+	}{
+		{
+			name:      "Channels",
+			manifests: "../testdata/manifests",
+		},
+		{
+			name:      "PackageServerManifests",
+			manifests: "../../../../package-server/provider/manifests",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir, err := ioutil.TempDir("", "registry-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			db := registryDatabase(t, dir, tt.manifests)
+			catalog, err := inprocess.LoadDirectory(tt.manifests)
+			require.NoError(t, err)
+
+			// Every CSV and owned API of the manifests, plus ones they don't have
+			csvNames, apis := csvContent(t, tt.manifests)
+			require.NotEmpty(t, csvNames)
+			require.NotEmpty(t, apis)
+			csvNames = append(csvNames, "missing.v1.0.0")
+			apis = append(apis, gvk{group: "missing.example.com", version: "v1", kind: "Missing"})
+
+			expectedPackages, err := db.ListPackages(ctx)
+			require.NoError(t, err)
+			packages, err := catalog.ListPackages(ctx)
+			require.NoError(t, err)
+			sort.Strings(expectedPackages)
+			require.Equal(t, expectedPackages, packages)
+
+			for _, pkgName := range append(packages, "missing") {
+				expected, expectedErr := db.GetPackage(ctx, pkgName)
+				actual, err := catalog.GetPackage(ctx, pkgName)
+				requireSameResult(t, "GetPackage "+pkgName, expected, actual, expectedErr, err)
+
+				channels := []string{"missing"}
+				if expected != nil {
+					for _, ch := range expected.Channels {
+						channels = append(channels, ch.Name)
+					}
+				}
+				for _, channel := range channels {
+					expectedBundle, expectedErr := db.GetBundleForChannel(ctx, pkgName, channel)
+					bundle, err := catalog.GetBundleForChannel(ctx, pkgName, channel)
+					requireSameResult(t, "GetBundleForChannel "+pkgName+" "+channel, expectedBundle, bundle, expectedErr, err)
+
+					for _, csvName := range csvNames {
+						query := fmt.Sprintf("GetBundle %s %s %s", pkgName, channel, csvName)
+						expectedBundle, expectedErr = db.GetBundle(ctx, pkgName, channel, csvName)
+						bundle, err = catalog.GetBundle(ctx, pkgName, channel, csvName)
+						requireSameResult(t, query, expectedBundle, bundle, expectedErr, err)
+
+						query = fmt.Sprintf("GetBundleThatReplaces %s %s %s", csvName, pkgName, channel)
+						expectedBundle, expectedErr = db.GetBundleThatReplaces(ctx, csvName, pkgName, channel)
+						bundle, err = catalog.GetBundleThatReplaces(ctx, csvName, pkgName, channel)
+						requireSameResult(t, query, expectedBundle, bundle, expectedErr, err)
+					}
+				}
+			}
+
+			for _, csvName := range csvNames {
+				expected, expectedErr := db.GetChannelEntriesThatReplace(ctx, csvName)
+				actual, err := catalog.GetChannelEntriesThatReplace(ctx, csvName)
+				requireSameResult(t, "GetChannelEntriesThatReplace "+csvName, sortedEntries(expected), sortedEntries(actual), expectedErr, err)
+			}
+
+			for _, api := range apis {
+				query := fmt.Sprintf("%s/%s %s", api.group, api.version, api.kind)
+
+				expected, expectedErr := db.GetChannelEntriesThatProvide(ctx, api.group, api.version, api.kind)
+				actual, err := catalog.GetChannelEntriesThatProvide(ctx, api.group, api.version, api.kind)
+				requireSameResult(t, "GetChannelEntriesThatProvide "+query, sortedEntries(expected), sortedEntries(actual), expectedErr, err)
+
+				expected, expectedErr = db.GetLatestChannelEntriesThatProvide(ctx, api.group, api.version, api.kind)
+				actual, err = catalog.GetLatestChannelEntriesThatProvide(ctx, api.group, api.version, api.kind)
+				requireSameResult(t, "GetLatestChannelEntriesThatProvide "+query, sortedEntries(expected), sortedEntries(actual), expectedErr, err)
+
+				expectedBundle, expectedEntry, expectedErr := db.GetBundleThatProvides(ctx, api.group, api.version, api.kind)
+				bundle, entry, err := catalog.GetBundleThatProvides(ctx, api.group, api.version, api.kind)
+				requireSameResult(t, "GetBundleThatProvides "+query, expectedBundle, bundle, expectedErr, err)
+				requireSameResult(t, "GetBundleThatProvides "+query, expectedEntry, entry, expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package inprocess
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/server"
+	"google.golang.org/grpc"
+)
+
+// Server serves catalogs over the registry API from within the current process. Each catalog is served by its own
+// gRPC server on a port of the loopback interface, so it is reached the same way as a registry pod.
+type Server struct {
+	mu     sync.Mutex
+	served map[string]*servedCatalog
+}
+
+type servedCatalog struct {
+	store  *store
+	server *grpc.Server
+	port   string
+}
+
+// NewServer returns a Server that serves no catalogs.
+func NewServer() *Server {
+	return &Server{served: map[string]*servedCatalog{}}
+}
+
+// Serve serves the given catalog under the given key, and returns the port it is served on. If a catalog is already
+// served under the key, its content is replaced without interrupting the server.
+func (s *Server) Serve(key string, catalog *Catalog) (port string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if served, ok := s.served[key]; ok {
+		served.store.set(catalog)
+		return served.port, nil
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	_, port, err = net.SplitHostPort(lis.Addr().String())
+	if err != nil {
+		lis.Close()
+		return "", err
+	}
+
+	store := &store{catalog: catalog}
+	served := &servedCatalog{
+		store:  store,
+		server: newGRPCServer(store),
+		port:   port,
+	}
+	go served.server.Serve(lis)
+
+	s.served[key] = served
+	return port, nil
+}
+
+// NewGRPCServer returns a gRPC server that serves the given catalog over the registry API, with a health server.
+func NewGRPCServer(catalog *Catalog) *grpc.Server {
+	return newGRPCServer(catalog)
+}
+
+func newGRPCServer(query registry.Query) *grpc.Server {
+	s := grpc.NewServer()
+	api.RegisterRegistryServer(s, server.NewRegistryServer(query))
+	health.RegisterHealthServer(s, server.NewHealthServer())
+	return s
+}
+
+// Catalog returns the catalog served under the given key and the port it is served on, or false if none is served.
+func (s *Server) Catalog(key string) (catalog *Catalog, port string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	served, ok := s.served[key]
+	if !ok {
+		return nil, "", false
+	}
+	return served.store.get(), served.port, true
+}
+
+// Stop stops serving the catalog served under the given key, if any.
+func (s *Server) Stop(key string) {
+	s.mu.Lock()
+	served, ok := s.served[key]
+	delete(s.served, key)
+	s.mu.Unlock()
+
+	if ok {
+		served.server.Stop()
+	}
+}
+
+// store is a registry.Query whose catalog can be replaced while it is queried.
+type store struct {
+	mu      sync.RWMutex
+	catalog *Catalog
+}
+
+var _ registry.Query = &store{}
+
+func (s *store) get() *Catalog {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalog
+}
+
+func (s *store) set(catalog *Catalog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catalog = catalog
+}
+
+func (s *store) ListTables(ctx context.Context) ([]string, error) {
+	return s.get().ListTables(ctx)
+}
+
+func (s *store) ListPackages(ctx context.Context) ([]string, error) {
+	return s.get().ListPackages(ctx)
+}
+
+func (s *store) GetPackage(ctx context.Context, name string) (*registry.PackageManifest, error) {
+	return s.get().GetPackage(ctx, name)
+}
+
+func (s *store) GetBundle(ctx context.Context, pkgName, channelName, csvName string) (string, error) {
+	return s.get().GetBundle(ctx, pkgName, channelName, csvName)
+}
+
+func (s *store) GetBundleForChannel(ctx context.Context, pkgName string, channelName string) (string, error) {
+	return s.get().GetBundleForChannel(ctx, pkgName, channelName)
+}
+
+func (s *store) GetChannelEntriesThatReplace(ctx context.Context, name string) ([]*registry.ChannelEntry, error) {
+	return s.get().GetChannelEntriesThatReplace(ctx, name)
+}
+
+func (s *store) GetBundleThatReplaces(ctx context.Context, name, pkgName, channelName string) (string, error) {
+	return s.get().GetBundleThatReplaces(ctx, name, pkgName, channelName)
+}
+
+func (s *store) GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) ([]*registry.ChannelEntry, error) {
+	return s.get().GetChannelEntriesThatProvide(ctx, group, version, kind)
+}
+
+func (s *store) GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) ([]*registry.ChannelEntry, error) {
+	return s.get().GetLatestChannelEntriesThatProvide(ctx, group, version, kind)
+}
+
+func (s *store) GetBundleThatProvides(ctx context.Context, group, version, kind string) (string, *registry.ChannelEntry, error) {
+	return s.get().GetBundleThatProvides(ctx, group, version, kind)
+}
@@ -0,0 +1,72 @@
+package inprocess
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	registryclient "github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	catalog, err := LoadDirectory(testManifests)
+	require.NoError(t, err)
+
+	s := NewServer()
+	port, err := s.Serve("ns/catalog", catalog)
+	require.NoError(t, err)
+
+	served, servedPort, ok := s.Catalog("ns/catalog")
+	require.True(t, ok)
+	require.Equal(t, catalog, served)
+	require.Equal(t, port, servedPort)
+	_, _, ok = s.Catalog("ns/other")
+	require.False(t, ok)
+
+	client, err := registryclient.NewClient(net.JoinHostPort("localhost", port))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	serving, err := client.HealthCheck(ctx, 5*time.Second)
+	require.NoError(t, err)
+	require.True(t, serving)
+
+	bundle, err := client.GetBundleInPackageChannel(ctx, "test", "alpha")
+	require.NoError(t, err)
+	require.Equal(t, "testoperator.v3.0.0", bundle.Name)
+
+	// Updated content is served on the same port
+	dir := copyManifests(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "test", "3.0.0")))
+	writeTestFile(t, filepath.Join(dir, "test", "test.package.yaml"), `
+packageName: test
+channels:
+- name: alpha
+  currentCSV: testoperator.v2.0.0
+`)
+	updated, err := LoadDirectory(dir)
+	require.NoError(t, err)
+	updatedPort, err := s.Serve("ns/catalog", updated)
+	require.NoError(t, err)
+	require.Equal(t, port, updatedPort)
+
+	bundle, err = client.GetBundleInPackageChannel(ctx, "test", "alpha")
+	require.NoError(t, err)
+	require.Equal(t, "testoperator.v2.0.0", bundle.Name)
+
+	s.Stop("ns/catalog")
+	_, _, ok = s.Catalog("ns/catalog")
+	require.False(t, ok)
+	_, err = client.GetBundleInPackageChannel(ctx, "test", "alpha")
+	require.Error(t, err)
+
+	// Stopping a catalog that isn't served does nothing
+	s.Stop("ns/catalog")
+}
@@ -3,10 +3,13 @@ package reconciler
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -52,21 +55,11 @@ func (s *configMapCatalogSourceDecorator) Labels() map[string]string {
 		CatalogSourceLabelKey: s.GetName(),
 	}
 	if s.Spec.SourceType == v1alpha1.SourceTypeInternal || s.Spec.SourceType == v1alpha1.SourceTypeConfigmap {
-		labels[ConfigMapRVLabelKey] = s.Status.ConfigMapResource.ResourceVersion
+		labels[ConfigMapRVLabelKey] = configMapVersion(s.CatalogSource)
 	}
 	return labels
 }
 
-func (s *configMapCatalogSourceDecorator) ConfigMapChanges(configMap *v1.ConfigMap) bool {
-	if s.Status.ConfigMapResource == nil {
-		return true
-	}
-	if s.Status.ConfigMapResource.ResourceVersion == configMap.GetResourceVersion() {
-		return false
-	}
-	return true
-}
-
 func (s *configMapCatalogSourceDecorator) Service() *v1.Service {
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -88,7 +81,42 @@ func (s *configMapCatalogSourceDecorator) Service() *v1.Service {
 	return svc
 }
 
+// sharded returns true if the CatalogSource is backed by several ConfigMaps rather than a single one.
+func (s *configMapCatalogSourceDecorator) sharded() bool {
+	return s.Spec.ConfigMap == ""
+}
+
 func (s *configMapCatalogSourceDecorator) Pod(image string) *v1.Pod {
+	command := []string{"configmap-server", "-c", s.Spec.ConfigMap, "-n", s.GetNamespace()}
+	probe := v1.Handler{
+		Exec: &v1.ExecAction{
+			Command: []string{"grpc_health_probe", "-addr=localhost:50051"},
+		},
+	}
+
+	// Shards are mounted into a shard server rather than read from the API, since together they may not fit in the
+	// single ConfigMap a configmap-server loads. It listens once it has loaded them, so its port is probed.
+	var volumes []v1.Volume
+	var mounts []v1.VolumeMount
+	if s.sharded() {
+		command = []string{"configmap-shard-server"}
+		for i, ref := range s.Status.ConfigMapShards {
+			name := fmt.Sprintf("configmap-%d", i)
+			path := fmt.Sprintf("/configmaps/%d", i)
+			volumes = append(volumes, v1.Volume{
+				Name: name,
+				VolumeSource: v1.VolumeSource{
+					ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: ref.Name}},
+				},
+			})
+			mounts = append(mounts, v1.VolumeMount{Name: name, MountPath: path, ReadOnly: true})
+			command = append(command, path)
+		}
+		probe = v1.Handler{
+			TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(50051)},
+		}
+	}
+
 	pod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: s.GetName() + "-",
@@ -100,7 +128,7 @@ func (s *configMapCatalogSourceDecorator) Pod(image string) *v1.Pod {
 				{
 					Name:    "configmap-registry-server",
 					Image:   image,
-					Command: []string{"configmap-server", "-c", s.Spec.ConfigMap, "-n", s.GetNamespace()},
+					Command: command,
 					Ports: []v1.ContainerPort{
 						{
 							Name:          "grpc",
@@ -108,23 +136,17 @@ func (s *configMapCatalogSourceDecorator) Pod(image string) *v1.Pod {
 						},
 					},
 					ReadinessProbe: &v1.Probe{
-						Handler: v1.Handler{
-							Exec: &v1.ExecAction{
-								Command: []string{"grpc_health_probe", "-addr=localhost:50051"},
-							},
-						},
+						Handler:             probe,
 						InitialDelaySeconds: 1,
 					},
 					LivenessProbe: &v1.Probe{
-						Handler: v1.Handler{
-							Exec: &v1.ExecAction{
-								Command: []string{"grpc_health_probe", "-addr=localhost:50051"},
-							},
-						},
+						Handler:             probe,
 						InitialDelaySeconds: 2,
 					},
+					VolumeMounts: mounts,
 				},
 			},
+			Volumes: volumes,
 			Tolerations: []v1.Toleration{
 				{
 					Operator: v1.TolerationOpExists,
@@ -133,10 +155,20 @@ func (s *configMapCatalogSourceDecorator) Pod(image string) *v1.Pod {
 			ServiceAccountName: s.GetName() + ConfigMapServerPostfix,
 		},
 	}
+	applyPodConfig(pod, s.CatalogSource)
 	ownerutil.AddOwner(pod, s.CatalogSource, false, false)
 	return pod
 }
 
+// Deployment returns a Deployment that runs the given number of replicas of the registry pod.
+func (s *configMapCatalogSourceDecorator) Deployment(image string, replicas int32) *appsv1.Deployment {
+	return registryDeployment(s.CatalogSource, s.Pod(image), s.Selector(), replicas)
+}
+
+func (s *configMapCatalogSourceDecorator) PodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	return registryPodDisruptionBudget(s.CatalogSource, s.Selector())
+}
+
 func (s *configMapCatalogSourceDecorator) ServiceAccount() *v1.ServiceAccount {
 	sa := &v1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -148,20 +180,24 @@ func (s *configMapCatalogSourceDecorator) ServiceAccount() *v1.ServiceAccount {
 	return sa
 }
 
+// Role returns the Role of the registry server. Only a configmap-server reads its ConfigMap from the API, so the Role
+// of a shard server grants nothing.
 func (s *configMapCatalogSourceDecorator) Role() *rbacv1.Role {
 	role := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      s.roleName(),
 			Namespace: s.GetNamespace(),
 		},
-		Rules: []rbacv1.PolicyRule{
+	}
+	if !s.sharded() {
+		role.Rules = []rbacv1.PolicyRule{
 			{
 				Verbs:         []string{"get"},
 				APIGroups:     []string{""},
 				Resources:     []string{"configmaps"},
 				ResourceNames: []string{s.Spec.ConfigMap},
 			},
-		},
+		}
 	}
 	ownerutil.AddOwner(role, s.CatalogSource, false, false)
 	return role
@@ -195,6 +231,14 @@ type ConfigMapRegistryReconciler struct {
 	Lister   operatorlister.OperatorLister
 	OpClient operatorclient.ClientInterface
 	Image    string
+
+	// ShardImage is the image of the registry servers of CatalogSources backed by several ConfigMaps, which runs
+	// configmap-shard-server.
+	ShardImage string
+
+	// Replicas is the number of registry servers run by a Deployment for CatalogSources that don't set their own.
+	// Registry servers run as single pods when zero.
+	Replicas int32
 }
 
 var _ RegistryEnsurer = &ConfigMapRegistryReconciler{}
@@ -264,17 +308,40 @@ func (c *ConfigMapRegistryReconciler) currentPodsWithCorrectResourceVersion(sour
 	if len(pods) > 1 {
 		logrus.WithField("selector", source.Labels()).Debug("multiple pods found for selector")
 	}
-	return pods
+	found := []*v1.Pod{}
+	for _, p := range pods {
+		if podConfigCurrent(p, source.CatalogSource) {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// currentServers returns true if registry servers serve the latest configmap with the latest settings, either in pods
+// or in a deployment depending on the number of replicas.
+func (c *ConfigMapRegistryReconciler) currentServers(source configMapCatalogSourceDecorator, image string) bool {
+	if replicas := registryReplicas(source.CatalogSource, c.Replicas); replicas > 0 {
+		return deploymentCurrent(currentDeployment(c.Lister, source.GetNamespace(), source.GetName()), source.Deployment(image, replicas))
+	}
+	return len(c.currentPodsWithCorrectResourceVersion(source, image)) > 0
+}
+
+// image returns the image of the registry servers of the given CatalogSource.
+func (c *ConfigMapRegistryReconciler) image(source configMapCatalogSourceDecorator) string {
+	switch {
+	case source.Spec.SourceType == "grpc":
+		return source.Spec.Image
+	case source.sharded():
+		return c.ShardImage
+	}
+	return c.Image
 }
 
 // EnsureRegistryServer ensures that all components of registry server are up to date.
 func (c *ConfigMapRegistryReconciler) EnsureRegistryServer(catalogSource *v1alpha1.CatalogSource) error {
 	source := configMapCatalogSourceDecorator{catalogSource}
 
-	image := c.Image
-	if source.Spec.SourceType == "grpc" {
-		image = source.Spec.Image
-	}
+	image := c.image(source)
 	if image == "" {
 		return fmt.Errorf("no image for registry")
 	}
@@ -282,28 +349,22 @@ func (c *ConfigMapRegistryReconciler) EnsureRegistryServer(catalogSource *v1alph
 	// if service status is nil, we force create every object to ensure they're created the first time
 	overwrite := source.Status.RegistryServiceStatus == nil
 	overwritePod := overwrite
+	replicas := registryReplicas(catalogSource, c.Replicas)
 
 	if source.Spec.SourceType == v1alpha1.SourceTypeConfigmap || source.Spec.SourceType == v1alpha1.SourceTypeInternal {
-		// fetch configmap first, exit early if we can't find it
-		configMap, err := c.Lister.CoreV1().ConfigMapLister().ConfigMaps(source.GetNamespace()).Get(source.Spec.ConfigMap)
+		// fetch configmaps first, exit early if we can't find them
+		shards, err := configMapShards(c.Lister, catalogSource)
 		if err != nil {
-			return fmt.Errorf("unable to get configmap %s/%s from cache", source.GetNamespace(), source.Spec.ConfigMap)
+			return err
 		}
 
-		if source.ConfigMapChanges(configMap) {
-			catalogSource.Status.ConfigMapResource = &v1alpha1.ConfigMapResourceReference{
-				Name:            configMap.GetName(),
-				Namespace:       configMap.GetNamespace(),
-				UID:             configMap.GetUID(),
-				ResourceVersion: configMap.GetResourceVersion(),
-			}
-
+		if RecordConfigMapShards(catalogSource, shards) {
 			// recreate the pod if there are configmap changes; this causes the db to be rebuilt
 			overwritePod = true
 		}
 
-		// recreate the pod if no existing pod is serving the latest image
-		if len(c.currentPodsWithCorrectResourceVersion(source, image)) == 0 {
+		// recreate the pod if no existing pod is serving the latest configmap with the latest settings
+		if replicas == 0 && len(c.currentPodsWithCorrectResourceVersion(source, image)) == 0 {
 			overwritePod = true
 		}
 	}
@@ -318,8 +379,20 @@ func (c *ConfigMapRegistryReconciler) EnsureRegistryServer(catalogSource *v1alph
 	if err := c.ensureRoleBinding(source, overwrite); err != nil {
 		return errors.Wrapf(err, "error ensuring rolebinding: %s", source.RoleBinding().GetName())
 	}
-	if err := c.ensurePod(source, overwritePod); err != nil {
-		return errors.Wrapf(err, "error ensuring pod: %s", source.Pod(image).GetName())
+	if replicas > 0 {
+		// roll out the deployment if it doesn't serve the latest configmap with the latest settings
+		rolled, err := ensureDeployment(c.Lister, c.OpClient, source.Deployment(image, replicas), source.PodDisruptionBudget())
+		if err != nil {
+			return errors.Wrapf(err, "error ensuring deployment: %s", source.GetName())
+		}
+		overwritePod = overwritePod || rolled
+	} else {
+		if err := removeDeployment(c.Lister, c.OpClient, source.CatalogSource); err != nil {
+			return err
+		}
+		if err := c.ensurePod(source, image, overwritePod); err != nil {
+			return errors.Wrapf(err, "error ensuring pod: %s", source.Pod(image).GetName())
+		}
 	}
 	if err := c.ensureService(source, overwrite); err != nil {
 		return errors.Wrapf(err, "error ensuring service: %s", source.Service().GetName())
@@ -355,8 +428,9 @@ func (c *ConfigMapRegistryReconciler) ensureServiceAccount(source configMapCatal
 
 func (c *ConfigMapRegistryReconciler) ensureRole(source configMapCatalogSourceDecorator, overwrite bool) error {
 	role := source.Role()
-	if c.currentRole(source) != nil {
-		if !overwrite {
+	if current := c.currentRole(source); current != nil {
+		// the role is recreated when its rules change, since it grants access to the configmap by name
+		if !overwrite && reflect.DeepEqual(current.Rules, role.Rules) {
 			return nil
 		}
 		if err := c.OpClient.DeleteRole(role.GetNamespace(), role.GetName(), metav1.NewDeleteOptions(0)); err != nil {
@@ -381,9 +455,9 @@ func (c *ConfigMapRegistryReconciler) ensureRoleBinding(source configMapCatalogS
 	return err
 }
 
-func (c *ConfigMapRegistryReconciler) ensurePod(source configMapCatalogSourceDecorator, overwrite bool) error {
-	pod := source.Pod(c.Image)
-	currentPods := c.currentPods(source, c.Image)
+func (c *ConfigMapRegistryReconciler) ensurePod(source configMapCatalogSourceDecorator, image string, overwrite bool) error {
+	pod := source.Pod(image)
+	currentPods := c.currentPods(source, image)
 	if len(currentPods) > 0 {
 		if !overwrite {
 			return nil
@@ -419,27 +493,24 @@ func (c *ConfigMapRegistryReconciler) ensureService(source configMapCatalogSourc
 func (c *ConfigMapRegistryReconciler) CheckRegistryServer(catalogSource *v1alpha1.CatalogSource) (healthy bool, err error) {
 	source := configMapCatalogSourceDecorator{catalogSource}
 
-	image := c.Image
-	if source.Spec.SourceType == "grpc" {
-		image = source.Spec.Image
-	}
+	image := c.image(source)
 	if image == "" {
 		err = fmt.Errorf("no image for registry")
 		return
 	}
 
 	if source.Spec.SourceType == v1alpha1.SourceTypeConfigmap || source.Spec.SourceType == v1alpha1.SourceTypeInternal {
-		configMap, err := c.Lister.CoreV1().ConfigMapLister().ConfigMaps(source.GetNamespace()).Get(source.Spec.ConfigMap)
+		shards, err := configMapShards(c.Lister, catalogSource)
 		if err != nil {
-			return false, fmt.Errorf("unable to get configmap %s/%s from cache", source.GetNamespace(), source.Spec.ConfigMap)
+			return false, err
 		}
 
-		if source.ConfigMapChanges(configMap) {
+		if ConfigMapShardsChanged(catalogSource, shards) {
 			return false, nil
 		}
 
-		// recreate the pod if no existing pod is serving the latest image
-		if len(c.currentPodsWithCorrectResourceVersion(source, image)) == 0 {
+		// recreate the pod if no existing pod is serving the latest configmap with the latest settings
+		if !c.currentServers(source, image) {
 			return false, nil
 		}
 	}
@@ -447,11 +518,13 @@ func (c *ConfigMapRegistryReconciler) CheckRegistryServer(catalogSource *v1alpha
 	// Check on registry resources
 	// TODO: more complex checks for resources
 	// TODO: add gRPC health check
+	replicas := registryReplicas(catalogSource, c.Replicas)
 	if c.currentServiceAccount(source) == nil ||
 		c.currentRole(source) == nil ||
 		c.currentRoleBinding(source) == nil ||
 		c.currentService(source) == nil ||
-		len(c.currentPods(source, c.Image)) < 1 {
+		(replicas == 0 && len(c.currentPods(source, image)) < 1) ||
+		(replicas > 0 && currentDeployment(c.Lister, source.GetNamespace(), source.GetName()) == nil) {
 		healthy = false
 		return
 	}
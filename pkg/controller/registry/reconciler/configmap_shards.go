@@ -0,0 +1,120 @@
+package reconciler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
+)
+
+// ConfigMapShardError is returned by ConfigMapShards when a named ConfigMap can't be read.
+type ConfigMapShardError struct {
+	Namespace string
+	Name      string
+	Err       error
+}
+
+func (e *ConfigMapShardError) Error() string {
+	return fmt.Sprintf("unable to get configmap %s/%s: %s", e.Namespace, e.Name, e.Err)
+}
+
+// configMapShards wraps ConfigMapShards, reporting ConfigMaps that can't be read like the reconcilers always have.
+func configMapShards(lister operatorlister.OperatorLister, source *v1alpha1.CatalogSource) ([]*v1.ConfigMap, error) {
+	shards, err := ConfigMapShards(lister, source)
+	if shardErr, ok := err.(*ConfigMapShardError); ok {
+		return nil, fmt.Errorf("unable to get configmap %s/%s from cache", shardErr.Namespace, shardErr.Name)
+	}
+	return shards, err
+}
+
+// ConfigMapShards returns the ConfigMaps backing the given configmap CatalogSource, in the order their entries are
+// loaded: its single ConfigMap, its list of ConfigMaps, or the ConfigMaps its selector selects, ordered by name.
+func ConfigMapShards(lister operatorlister.OperatorLister, source *v1alpha1.CatalogSource) ([]*v1.ConfigMap, error) {
+	namespace := source.GetNamespace()
+	configMaps := lister.CoreV1().ConfigMapLister().ConfigMaps(namespace)
+
+	names := source.Spec.ConfigMaps
+	if source.Spec.ConfigMap != "" || (len(names) == 0 && source.Spec.ConfigMapSelector == nil) {
+		names = []string{source.Spec.ConfigMap}
+	} else if len(names) == 0 {
+		selector, err := metav1.LabelSelectorAsSelector(source.Spec.ConfigMapSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configmap selector: %s", err)
+		}
+		selected, err := configMaps.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no configmaps in %s match selector %s", namespace, selector)
+		}
+		sort.Slice(selected, func(i, j int) bool {
+			return selected[i].GetName() < selected[j].GetName()
+		})
+		return selected, nil
+	}
+
+	shards := make([]*v1.ConfigMap, 0, len(names))
+	for _, name := range names {
+		configMap, err := configMaps.Get(name)
+		if err != nil {
+			return nil, &ConfigMapShardError{Namespace: namespace, Name: name, Err: err}
+		}
+		shards = append(shards, configMap)
+	}
+	return shards, nil
+}
+
+// configMapReferences returns the status references to the given shards of the given CatalogSource. A single
+// ConfigMap is referenced as the ConfigMapResource, like before catalogs could be sharded.
+func configMapReferences(source *v1alpha1.CatalogSource, shards []*v1.ConfigMap) (*v1alpha1.ConfigMapResourceReference, []v1alpha1.ConfigMapResourceReference) {
+	var refs []v1alpha1.ConfigMapResourceReference
+	for _, configMap := range shards {
+		refs = append(refs, v1alpha1.ConfigMapResourceReference{
+			Name:            configMap.GetName(),
+			Namespace:       configMap.GetNamespace(),
+			UID:             configMap.GetUID(),
+			ResourceVersion: configMap.GetResourceVersion(),
+		})
+	}
+	if source.Spec.ConfigMap != "" && len(refs) == 1 {
+		return &refs[0], nil
+	}
+	return nil, refs
+}
+
+// ConfigMapShardsChanged returns true if the given shards aren't the ones recorded in the status of the given
+// CatalogSource, or have changed since.
+func ConfigMapShardsChanged(source *v1alpha1.CatalogSource, shards []*v1.ConfigMap) bool {
+	resource, refs := configMapReferences(source, shards)
+	return !reflect.DeepEqual(source.Status.ConfigMapResource, resource) || !reflect.DeepEqual(source.Status.ConfigMapShards, refs)
+}
+
+// RecordConfigMapShards records references to the given shards in the status of the given CatalogSource. It returns
+// true if they changed.
+func RecordConfigMapShards(source *v1alpha1.CatalogSource, shards []*v1.ConfigMap) bool {
+	if !ConfigMapShardsChanged(source, shards) {
+		return false
+	}
+	source.Status.ConfigMapResource, source.Status.ConfigMapShards = configMapReferences(source, shards)
+	return true
+}
+
+// configMapVersion returns a label value that changes whenever any of the ConfigMaps recorded as backing the given
+// CatalogSource changes. It's the resource version of a single ConfigMap, or a hash of those of the shards.
+func configMapVersion(source *v1alpha1.CatalogSource) string {
+	if source.Status.ConfigMapResource != nil {
+		return source.Status.ConfigMapResource.ResourceVersion
+	}
+	hash := fnv.New64a()
+	for _, ref := range source.Status.ConfigMapShards {
+		fmt.Fprintf(hash, "%s\x00%s\x00", ref.Name, ref.ResourceVersion)
+	}
+	return fmt.Sprintf("%x", hash.Sum64())
+}
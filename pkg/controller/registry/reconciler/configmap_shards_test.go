@@ -0,0 +1,215 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/clientfake"
+)
+
+func configMapShard(name string, shardLabels map[string]string) *corev1.ConfigMap {
+	configMap := validConfigMap()
+	configMap.SetName(name)
+	configMap.SetUID(types.UID(name + "-uid"))
+	configMap.SetLabels(shardLabels)
+	return configMap
+}
+
+func shardedCatalogSource() *v1alpha1.CatalogSource {
+	return &v1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cool-catalog",
+			Namespace: testNamespace,
+			UID:       types.UID("catalog-uid"),
+		},
+		Spec: v1alpha1.CatalogSourceSpec{
+			SourceType: v1alpha1.SourceTypeConfigmap,
+		},
+	}
+}
+
+func shardReference(configMap *corev1.ConfigMap) v1alpha1.ConfigMapResourceReference {
+	return v1alpha1.ConfigMapResourceReference{
+		Name:            configMap.GetName(),
+		Namespace:       configMap.GetNamespace(),
+		UID:             configMap.GetUID(),
+		ResourceVersion: configMap.GetResourceVersion(),
+	}
+}
+
+func TestConfigMapShards(t *testing.T) {
+	catalogLabels := map[string]string{"catalog": "cool"}
+	second := configMapShard("cool-configmap-b", catalogLabels)
+	first := configMapShard("cool-configmap-a", catalogLabels)
+	other := configMapShard("other-configmap", map[string]string{"catalog": "other"})
+
+	tests := []struct {
+		testName string
+		spec     v1alpha1.CatalogSourceSpec
+		expected []*corev1.ConfigMap
+		err      string
+	}{
+		{
+			testName: "List",
+			spec:     v1alpha1.CatalogSourceSpec{ConfigMaps: []string{"cool-configmap-b", "cool-configmap-a"}},
+			expected: []*corev1.ConfigMap{second, first},
+		},
+		{
+			testName: "List/Missing",
+			spec:     v1alpha1.CatalogSourceSpec{ConfigMaps: []string{"cool-configmap-a", "missing"}},
+			err:      "unable to get configmap testns/missing from cache",
+		},
+		{
+			testName: "Selector",
+			spec:     v1alpha1.CatalogSourceSpec{ConfigMapSelector: &metav1.LabelSelector{MatchLabels: catalogLabels}},
+			expected: []*corev1.ConfigMap{first, second},
+		},
+		{
+			testName: "Selector/Everything",
+			spec:     v1alpha1.CatalogSourceSpec{ConfigMapSelector: &metav1.LabelSelector{}},
+			expected: []*corev1.ConfigMap{first, second, other},
+		},
+		{
+			testName: "Selector/NoMatch",
+			spec:     v1alpha1.CatalogSourceSpec{ConfigMapSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"catalog": "none"}}},
+			err:      "no configmaps in testns match selector catalog=none",
+		},
+		{
+			testName: "Single",
+			spec:     v1alpha1.CatalogSourceSpec{ConfigMap: "other-configmap"},
+			expected: []*corev1.ConfigMap{other},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			stopc := make(chan struct{})
+			defer close(stopc)
+
+			factory, _ := fakeReconcilerFactory(t, stopc, withK8sObjs(first, second, other))
+			catsrc := shardedCatalogSource()
+			catsrc.Spec.ConfigMap = tt.spec.ConfigMap
+			catsrc.Spec.ConfigMaps = tt.spec.ConfigMaps
+			catsrc.Spec.ConfigMapSelector = tt.spec.ConfigMapSelector
+
+			shards, err := configMapShards(factory.(*registryReconcilerFactory).Lister, catsrc)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, shards)
+		})
+	}
+}
+
+func TestRecordConfigMapShards(t *testing.T) {
+	first := configMapShard("cool-configmap-a", nil)
+	second := configMapShard("cool-configmap-b", nil)
+
+	// A single ConfigMap is recorded as the ConfigMapResource
+	single := shardedCatalogSource()
+	single.Spec.ConfigMap = "cool-configmap-a"
+	require.True(t, RecordConfigMapShards(single, []*corev1.ConfigMap{first}))
+	require.Equal(t, &v1alpha1.ConfigMapResourceReference{
+		Name:            "cool-configmap-a",
+		Namespace:       testNamespace,
+		UID:             "cool-configmap-a-uid",
+		ResourceVersion: "resource-version",
+	}, single.Status.ConfigMapResource)
+	require.Nil(t, single.Status.ConfigMapShards)
+	require.Equal(t, "resource-version", configMapVersion(single))
+	require.False(t, RecordConfigMapShards(single, []*corev1.ConfigMap{first}))
+
+	// Shards are recorded in order
+	sharded := shardedCatalogSource()
+	sharded.Spec.ConfigMaps = []string{"cool-configmap-b", "cool-configmap-a"}
+	require.True(t, RecordConfigMapShards(sharded, []*corev1.ConfigMap{second, first}))
+	require.Nil(t, sharded.Status.ConfigMapResource)
+	require.Equal(t, []v1alpha1.ConfigMapResourceReference{shardReference(second), shardReference(first)}, sharded.Status.ConfigMapShards)
+	require.False(t, RecordConfigMapShards(sharded, []*corev1.ConfigMap{second, first}))
+
+	// A change to any shard changes the version
+	version := configMapVersion(sharded)
+	updated := first.DeepCopy()
+	updated.SetResourceVersion("updated-resource-version")
+	require.True(t, ConfigMapShardsChanged(sharded, []*corev1.ConfigMap{second, updated}))
+	require.True(t, RecordConfigMapShards(sharded, []*corev1.ConfigMap{second, updated}))
+	require.NotEqual(t, version, configMapVersion(sharded))
+
+	// So does a reordering
+	version = configMapVersion(sharded)
+	require.True(t, RecordConfigMapShards(sharded, []*corev1.ConfigMap{updated, second}))
+	require.NotEqual(t, version, configMapVersion(sharded))
+}
+
+func TestConfigMapRegistryReconcilerShards(t *testing.T) {
+	now := func() metav1.Time { return metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC) }
+
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	catalogLabels := map[string]string{"catalog": "cool"}
+	first := configMapShard("cool-configmap-a", catalogLabels)
+	second := configMapShard("cool-configmap-b", catalogLabels)
+	factory, client := fakeReconcilerFactory(t, stopc, withNow(now), withK8sObjs(first, second), withK8sClientOptions(clientfake.WithNameGeneration(t)))
+
+	catsrc := shardedCatalogSource()
+	catsrc.Spec.ConfigMapSelector = &metav1.LabelSelector{MatchLabels: catalogLabels}
+	rec := factory.ReconcilerForSource(catsrc)
+
+	healthy, err := rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+	require.Equal(t, []v1alpha1.ConfigMapResourceReference{shardReference(first), shardReference(second)}, catsrc.Status.ConfigMapShards)
+
+	// The registry server is a shard server that loads the shards mounted into it in order
+	listOptions := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{CatalogSourceLabelKey: catsrc.GetName()}).String()}
+	pods, err := client.KubernetesInterface().CoreV1().Pods(testNamespace).List(listOptions)
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	pod := pods.Items[0]
+	require.Equal(t, configMapVersion(catsrc), pod.GetLabels()[ConfigMapRVLabelKey])
+	container := pod.Spec.Containers[0]
+	require.Equal(t, shardServerImageName, container.Image)
+	require.Equal(t, []string{"configmap-shard-server", "/configmaps/0", "/configmaps/1"}, container.Command)
+	require.Equal(t, []corev1.VolumeMount{
+		{Name: "configmap-0", MountPath: "/configmaps/0", ReadOnly: true},
+		{Name: "configmap-1", MountPath: "/configmaps/1", ReadOnly: true},
+	}, container.VolumeMounts)
+	require.Len(t, pod.Spec.Volumes, 2)
+	for i, shard := range []*corev1.ConfigMap{first, second} {
+		require.Equal(t, container.VolumeMounts[i].Name, pod.Spec.Volumes[i].Name)
+		require.Equal(t, shard.GetName(), pod.Spec.Volumes[i].ConfigMap.Name)
+	}
+	require.NotNil(t, container.ReadinessProbe.TCPSocket)
+
+	// It doesn't read ConfigMaps from the API, so its role grants nothing
+	decorated := configMapCatalogSourceDecorator{catsrc}
+	role, err := client.KubernetesInterface().RbacV1().Roles(testNamespace).Get(decorated.Role().GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, role.Rules)
+
+	// No ConfigMap is written for the shards
+	configMaps, err := client.KubernetesInterface().CoreV1().ConfigMaps(testNamespace).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, configMaps.Items, 2)
+
+	// A shard that changed since it was recorded makes the registry server stale
+	stale := catsrc.DeepCopy()
+	stale.Status.ConfigMapShards[1].ResourceVersion = "old-resource-version"
+	healthy, err = rec.CheckRegistryServer(stale)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	// Its pod no longer matches the recorded shards, so it's rolled on the next ensure
+	require.NotEqual(t, configMapVersion(stale), pods.Items[0].GetLabels()[ConfigMapRVLabelKey])
+}
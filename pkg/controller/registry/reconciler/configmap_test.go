@@ -22,21 +22,27 @@ import (
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/clientfake"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
 )
 
 const (
-	registryImageName = "test:image"
-	testNamespace     = "testns"
+	registryImageName    = "test:image"
+	shardServerImageName = "test:shard-image"
+	testNamespace        = "testns"
 )
 
 type fakeReconcilerConfig struct {
 	now                  nowFunc
+	healthCheck          healthCheckFunc
 	k8sObjs              []runtime.Object
 	k8sClientOptions     []clientfake.Option
 	configMapServerImage string
+	shardServerImage     string
+	registryReplicas     int32
+	catalogLoader        *inprocess.Loader
 }
 
 type fakeReconcilerOption func(*fakeReconcilerConfig)
@@ -47,6 +53,12 @@ func withNow(now nowFunc) fakeReconcilerOption {
 	}
 }
 
+func withHealthCheck(healthCheck healthCheckFunc) fakeReconcilerOption {
+	return func(config *fakeReconcilerConfig) {
+		config.healthCheck = healthCheck
+	}
+}
+
 func withK8sObjs(k8sObjs ...runtime.Object) fakeReconcilerOption {
 	return func(config *fakeReconcilerConfig) {
 		config.k8sObjs = k8sObjs
@@ -65,10 +77,25 @@ func withConfigMapServerImage(configMapServerImage string) fakeReconcilerOption
 	}
 }
 
+func withRegistryReplicas(replicas int32) fakeReconcilerOption {
+	return func(config *fakeReconcilerConfig) {
+		config.registryReplicas = replicas
+	}
+}
+
+func withCatalogLoader(loader *inprocess.Loader) fakeReconcilerOption {
+	return func(config *fakeReconcilerConfig) {
+		config.catalogLoader = loader
+	}
+}
+
 func fakeReconcilerFactory(t *testing.T, stopc <-chan struct{}, options ...fakeReconcilerOption) (RegistryReconcilerFactory, operatorclient.ClientInterface) {
 	config := &fakeReconcilerConfig{
 		now:                  metav1.Now,
+		healthCheck:          servingHealthCheck,
 		configMapServerImage: registryImageName,
+		shardServerImage:     shardServerImageName,
+		catalogLoader:        &inprocess.Loader{},
 	}
 
 	// Apply all config options
@@ -86,6 +113,7 @@ func fakeReconcilerFactory(t *testing.T, stopc <-chan struct{}, options ...fakeR
 	serviceInformer := informerFactory.Core().V1().Services()
 	podInformer := informerFactory.Core().V1().Pods()
 	configMapInformer := informerFactory.Core().V1().ConfigMaps()
+	deploymentInformer := informerFactory.Apps().V1().Deployments()
 
 	registryInformers := []cache.SharedIndexInformer{
 		roleInformer.Informer(),
@@ -94,6 +122,7 @@ func fakeReconcilerFactory(t *testing.T, stopc <-chan struct{}, options ...fakeR
 		serviceInformer.Informer(),
 		podInformer.Informer(),
 		configMapInformer.Informer(),
+		deploymentInformer.Informer(),
 	}
 
 	lister := operatorlister.NewLister()
@@ -103,12 +132,18 @@ func fakeReconcilerFactory(t *testing.T, stopc <-chan struct{}, options ...fakeR
 	lister.CoreV1().RegisterServiceLister(testNamespace, serviceInformer.Lister())
 	lister.CoreV1().RegisterPodLister(testNamespace, podInformer.Lister())
 	lister.CoreV1().RegisterConfigMapLister(testNamespace, configMapInformer.Lister())
+	lister.AppsV1().RegisterDeploymentLister(testNamespace, deploymentInformer.Lister())
 
 	rec := &registryReconcilerFactory{
 		now:                  config.now,
+		healthCheck:          config.healthCheck,
 		OpClient:             opClientFake,
 		Lister:               lister,
 		ConfigMapServerImage: config.configMapServerImage,
+		ShardServerImage:     config.shardServerImage,
+		RegistryReplicas:     config.registryReplicas,
+		inProcess:            inprocess.NewServer(),
+		loader:               config.catalogLoader,
 	}
 
 	var hasSyncedCheckFns []cache.InformerSynced
@@ -261,6 +296,11 @@ func TestConfigMapRegistryReconciler(t *testing.T) {
 	validCatalogSource := validConfigMapCatalogSource(validConfigMap)
 	outdatedCatalogSource := validCatalogSource.DeepCopy()
 	outdatedCatalogSource.Status.ConfigMapResource.ResourceVersion = "old"
+	reconfiguredCatalogSource := validCatalogSource.DeepCopy()
+	reconfiguredCatalogSource.Spec.RegistryPodConfig = &v1alpha1.RegistryPodConfig{
+		NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+		PriorityClassName: "system-cluster-critical",
+	}
 	type cluster struct {
 		k8sObjs []runtime.Object
 	}
@@ -417,6 +457,24 @@ func TestConfigMapRegistryReconciler(t *testing.T) {
 				},
 			},
 		},
+		{
+			testName: "ExistingRegistry/ReconfiguredPod",
+			in: in{
+				cluster: cluster{
+					k8sObjs: append(objectsForCatalogSource(validCatalogSource), validConfigMap),
+				},
+				catsrc: reconfiguredCatalogSource,
+			},
+			out: out{
+				status: &v1alpha1.RegistryServiceStatus{
+					CreatedAt:        now(),
+					Protocol:         "grpc",
+					ServiceName:      "cool-catalog",
+					ServiceNamespace: testNamespace,
+					Port:             "50051",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
@@ -446,6 +504,7 @@ func TestConfigMapRegistryReconciler(t *testing.T) {
 			outPod := outPods.Items[0]
 			require.Equal(t, pod.GetGenerateName(), outPod.GetGenerateName())
 			require.Equal(t, pod.GetLabels(), outPod.GetLabels())
+			require.Equal(t, pod.GetAnnotations(), outPod.GetAnnotations())
 			require.Equal(t, pod.Spec, outPod.Spec)
 
 			service := decorated.Service()
@@ -0,0 +1,250 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
+)
+
+const (
+	// DeploymentSpecHashAnnotationKey is the key for an annotation containing a hash of the spec a registry Deployment
+	// was last configured with.
+	DeploymentSpecHashAnnotationKey string = "olm.deploymentSpecHash"
+
+	// RestartedAtAnnotationKey is the key for a pod template annotation containing the last time a registry Deployment
+	// was restarted to pull updated content.
+	RestartedAtAnnotationKey string = "olm.restartedAt"
+)
+
+// registryReplicas returns the number of registry servers to run for the given CatalogSource in a Deployment, or zero
+// to run a single registry pod. The CatalogSource's registry pod config overrides the given default.
+func registryReplicas(source *v1alpha1.CatalogSource, defaultReplicas int32) int32 {
+	if config := source.Spec.RegistryPodConfig; config != nil && config.Replicas != nil {
+		return *config.Replicas
+	}
+	return defaultReplicas
+}
+
+// registryDeployment returns a Deployment that runs the given number of replicas of the given registry pod, selected
+// by the given labels. Changes to the pod are rolled out without reducing the number of available replicas.
+func registryDeployment(source *v1alpha1.CatalogSource, pod *v1.Pod, selector map[string]string, replicas int32) *appsv1.Deployment {
+	maxUnavailable := intstr.FromInt(0)
+	maxSurge := intstr.FromInt(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      source.GetName(),
+			Namespace: source.GetNamespace(),
+			Labels:    selector,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxUnavailable: &maxUnavailable,
+					MaxSurge:       &maxSurge,
+				},
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      pod.GetLabels(),
+					Annotations: pod.GetAnnotations(),
+				},
+				Spec: pod.Spec,
+			},
+		},
+	}
+
+	// The server defaults the spec, so changes are detected with a hash of the spec as it was built
+	data, _ := json.Marshal(deployment.Spec)
+	hash := fnv.New64a()
+	hash.Write(data)
+	deployment.SetAnnotations(map[string]string{
+		DeploymentSpecHashAnnotationKey: fmt.Sprintf("%x", hash.Sum64()),
+	})
+
+	ownerutil.AddOwner(deployment, source, false, false)
+	return deployment
+}
+
+// registryPodDisruptionBudget returns a PodDisruptionBudget that lets only one of the registry pods selected by the
+// given labels be disrupted at a time.
+func registryPodDisruptionBudget(source *v1alpha1.CatalogSource, selector map[string]string) *policyv1beta1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      source.GetName(),
+			Namespace: source.GetNamespace(),
+			Labels:    selector,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		},
+	}
+	ownerutil.AddOwner(pdb, source, false, false)
+	return pdb
+}
+
+// currentDeployment returns the existing registry Deployment, or nil if there is none.
+func currentDeployment(lister operatorlister.OperatorLister, namespace, name string) *appsv1.Deployment {
+	deployment, err := lister.AppsV1().DeploymentLister().Deployments(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return deployment
+}
+
+// deploymentCurrent returns true if the given existing registry Deployment is configured as the desired one.
+func deploymentCurrent(current, desired *appsv1.Deployment) bool {
+	return current != nil &&
+		current.GetAnnotations()[DeploymentSpecHashAnnotationKey] == desired.GetAnnotations()[DeploymentSpecHashAnnotationKey]
+}
+
+// deploymentAvailable returns true if every replica of the given registry Deployment runs its latest spec and is
+// available.
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.GetGeneration() &&
+		status.UpdatedReplicas >= replicas &&
+		status.AvailableReplicas >= replicas
+}
+
+// ensurePodDisruptionBudget creates the desired registry PodDisruptionBudget, or updates the existing one if it isn't
+// configured as the desired one.
+func ensurePodDisruptionBudget(client operatorclient.ClientInterface, desired *policyv1beta1.PodDisruptionBudget) error {
+	pdbs := client.KubernetesInterface().PolicyV1beta1().PodDisruptionBudgets(desired.GetNamespace())
+	_, err := pdbs.Create(desired)
+	if err == nil {
+		return nil
+	}
+	if !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "error creating pod disruption budget: %s", desired.GetName())
+	}
+
+	current, err := pdbs.Get(desired.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "error getting pod disruption budget: %s", desired.GetName())
+	}
+	if equality.Semantic.DeepEqual(current.Spec, desired.Spec) && equality.Semantic.DeepEqual(current.GetLabels(), desired.GetLabels()) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.SetLabels(desired.GetLabels())
+	updated.SetOwnerReferences(desired.GetOwnerReferences())
+	updated.Spec = desired.Spec
+	if _, err := pdbs.Update(updated); err != nil {
+		return errors.Wrapf(err, "error updating pod disruption budget: %s", desired.GetName())
+	}
+	return nil
+}
+
+// ensureDeployment creates the desired registry Deployment and its PodDisruptionBudget, or updates them if they aren't
+// configured as the desired ones, which rolls out the Deployment's pods. Once the Deployment is available, pods
+// matching the selector that it doesn't run, such as the bare registry pods it replaces, are deleted; until then they
+// keep serving the catalog, and are deleted by a later sync. It returns true if the Deployment was created or updated.
+func ensureDeployment(lister operatorlister.OperatorLister, client operatorclient.ClientInterface, desired *appsv1.Deployment, pdb *policyv1beta1.PodDisruptionBudget) (bool, error) {
+	namespace := desired.GetNamespace()
+
+	if err := ensurePodDisruptionBudget(client, pdb); err != nil {
+		return false, err
+	}
+
+	current := currentDeployment(lister, namespace, desired.GetName())
+	if current == nil {
+		// The deployment may have been created since the cache was last updated
+		if _, err := client.CreateDeployment(desired); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return false, errors.Wrapf(err, "error creating deployment: %s", desired.GetName())
+		} else if err == nil {
+			return true, nil
+		}
+		return false, nil
+	}
+	if !deploymentCurrent(current, desired) {
+		updated := current.DeepCopy()
+		updated.SetLabels(desired.GetLabels())
+		updated.SetAnnotations(desired.GetAnnotations())
+		updated.SetOwnerReferences(desired.GetOwnerReferences())
+		updated.Spec = desired.Spec
+		if _, err := client.KubernetesInterface().AppsV1().Deployments(namespace).Update(updated); err != nil {
+			return false, errors.Wrapf(err, "error updating deployment: %s", desired.GetName())
+		}
+		return true, nil
+	}
+	if !deploymentAvailable(current) {
+		return false, nil
+	}
+
+	pods, err := lister.CoreV1().PodLister().Pods(namespace).List(labels.SelectorFromSet(desired.Spec.Selector.MatchLabels))
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		if ownerutil.IsOwnedByKind(pod, "ReplicaSet") {
+			continue
+		}
+		if err := client.KubernetesInterface().CoreV1().Pods(namespace).Delete(pod.GetName(), metav1.NewDeleteOptions(0)); err != nil && !k8serrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "error deleting old pod: %s", pod.GetName())
+		}
+	}
+
+	return false, nil
+}
+
+// removeDeployment deletes the registry Deployment and PodDisruptionBudget of the given CatalogSource, if they exist.
+func removeDeployment(lister operatorlister.OperatorLister, client operatorclient.ClientInterface, source *v1alpha1.CatalogSource) error {
+	if currentDeployment(lister, source.GetNamespace(), source.GetName()) == nil {
+		return nil
+	}
+	if err := client.DeleteDeployment(source.GetNamespace(), source.GetName(), metav1.NewDeleteOptions(0)); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting deployment: %s", source.GetName())
+	}
+	if err := client.KubernetesInterface().PolicyV1beta1().PodDisruptionBudgets(source.GetNamespace()).Delete(source.GetName(), metav1.NewDeleteOptions(0)); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting pod disruption budget: %s", source.GetName())
+	}
+	return nil
+}
+
+// restartDeployment rolls out new pods for the existing registry Deployment of the given CatalogSource, without
+// changing its spec as far as deploymentCurrent is concerned.
+func restartDeployment(lister operatorlister.OperatorLister, client operatorclient.ClientInterface, source *v1alpha1.CatalogSource, now metav1.Time) error {
+	current := currentDeployment(lister, source.GetNamespace(), source.GetName())
+	if current == nil {
+		return fmt.Errorf("no deployment to restart: %s", source.GetName())
+	}
+
+	restarted := current.DeepCopy()
+	if restarted.Spec.Template.Annotations == nil {
+		restarted.Spec.Template.Annotations = map[string]string{}
+	}
+	restarted.Spec.Template.Annotations[RestartedAtAnnotationKey] = now.UTC().Format(time.RFC3339)
+	if _, err := client.KubernetesInterface().AppsV1().Deployments(source.GetNamespace()).Update(restarted); err != nil {
+		return errors.Wrapf(err, "error restarting deployment: %s", source.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/clientfake"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
+)
+
+// waitForDeployment waits until the informer cache of the given reconciler observes the registry Deployment of the
+// given CatalogSource in the given state.
+func waitForDeployment(t *testing.T, factory RegistryReconcilerFactory, catsrc *v1alpha1.CatalogSource, observed func(*appsv1.Deployment) bool) {
+	lister := factory.(*registryReconcilerFactory).Lister
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return observed(currentDeployment(lister, catsrc.GetNamespace(), catsrc.GetName())), nil
+	}))
+}
+
+func deploymentExists(deployment *appsv1.Deployment) bool {
+	return deployment != nil
+}
+
+func TestGrpcRegistryReconcilerDeployment(t *testing.T) {
+	now := func() metav1.Time { return metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC) }
+
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	// Start with the bare registry pod of a CatalogSource that ran before replicas were configured
+	catsrc := validGrpcCatalogSource("old-img", "")
+	factory, client := fakeReconcilerFactory(t, stopc, withNow(now), withRegistryReplicas(3),
+		withK8sObjs(objectsForCatalogSource(catsrc)...), withK8sClientOptions(clientfake.WithNameGeneration(t)))
+	kubeClient := client.KubernetesInterface()
+
+	rec := factory.ReconcilerForSource(catsrc)
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+	require.Equal(t, &v1alpha1.RegistryServiceStatus{
+		CreatedAt:        now(),
+		Protocol:         "grpc",
+		ServiceName:      "img-catalog",
+		ServiceNamespace: testNamespace,
+		Port:             "50051",
+	}, catsrc.Status.RegistryServiceStatus)
+
+	// The registry servers run in a deployment instead of the bare pod
+	decorated := grpcCatalogSourceDecorator{catsrc}
+	deployment, err := kubeClient.AppsV1().Deployments(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), *deployment.Spec.Replicas)
+	require.Equal(t, decorated.Labels(), deployment.Spec.Selector.MatchLabels)
+	require.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	require.Equal(t, 0, deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+	require.Equal(t, decorated.Pod().Spec, deployment.Spec.Template.Spec)
+
+	pdb, err := kubeClient.PolicyV1beta1().PodDisruptionBudgets(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+	require.Equal(t, decorated.Labels(), pdb.Spec.Selector.MatchLabels)
+
+	// The bare pod keeps serving until the deployment is available
+	pods, err := kubeClient.CoreV1().Pods(testNamespace).List(metav1.ListOptions{LabelSelector: decorated.Selector().String()})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.GetGeneration(), UpdatedReplicas: 3, AvailableReplicas: 3}
+	_, err = kubeClient.AppsV1().Deployments(testNamespace).UpdateStatus(deployment)
+	require.NoError(t, err)
+	waitForDeployment(t, factory, catsrc, func(deployment *appsv1.Deployment) bool {
+		return deployment != nil && deploymentAvailable(deployment)
+	})
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+	pods, err = kubeClient.CoreV1().Pods(testNamespace).List(metav1.ListOptions{LabelSelector: decorated.Selector().String()})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 0)
+
+	healthy, err := rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.True(t, healthy)
+
+	// A new image rolls out through the deployment
+	catsrc.Spec.Image = "new-img"
+	healthy, err = rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+	deployment, err = kubeClient.AppsV1().Deployments(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "new-img", deployment.Spec.Template.Spec.Containers[0].Image)
+
+	waitForDeployment(t, factory, catsrc, func(deployment *appsv1.Deployment) bool {
+		return deployment != nil && deployment.Spec.Template.Spec.Containers[0].Image == "new-img"
+	})
+	healthy, err = rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.True(t, healthy)
+}
+
+func TestGrpcRegistryReconcilerDeploymentRemoved(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	// A deployment left behind by a previous configuration with replicas
+	catsrc := validGrpcCatalogSource("test-img", "")
+	decorated := grpcCatalogSourceDecorator{catsrc}
+	factory, client := fakeReconcilerFactory(t, stopc, withK8sObjs(decorated.Deployment(2), decorated.PodDisruptionBudget()),
+		withK8sClientOptions(clientfake.WithNameGeneration(t)))
+	kubeClient := client.KubernetesInterface()
+
+	rec := factory.ReconcilerForSource(catsrc)
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+
+	_, err := kubeClient.AppsV1().Deployments(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+	_, err = kubeClient.PolicyV1beta1().PodDisruptionBudgets(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+
+	pods, err := kubeClient.CoreV1().Pods(testNamespace).List(metav1.ListOptions{LabelSelector: decorated.Selector().String()})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+}
+
+func TestConfigMapRegistryReconcilerDeployment(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	configMap := validConfigMap()
+	catsrc := validConfigMapCatalogSource(configMap)
+	factory, client := fakeReconcilerFactory(t, stopc, withRegistryReplicas(2), withK8sObjs(configMap),
+		withK8sClientOptions(clientfake.WithNameGeneration(t)))
+	kubeClient := client.KubernetesInterface()
+
+	rec := factory.ReconcilerForSource(catsrc)
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+	require.NotNil(t, catsrc.Status.RegistryServiceStatus)
+
+	decorated := configMapCatalogSourceDecorator{catsrc}
+	deployment, err := kubeClient.AppsV1().Deployments(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), *deployment.Spec.Replicas)
+	require.Equal(t, decorated.Selector(), deployment.Spec.Selector.MatchLabels)
+	require.Equal(t, decorated.Labels(), deployment.Spec.Template.GetLabels())
+
+	_, err = kubeClient.PolicyV1beta1().PodDisruptionBudgets(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+
+	pods, err := kubeClient.CoreV1().Pods(testNamespace).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 0)
+
+	waitForDeployment(t, factory, catsrc, deploymentExists)
+	healthy, err := rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.True(t, healthy)
+}
+
+func TestEnsureDeploymentKeepsReplicaSetPods(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	catsrc := validGrpcCatalogSource("test-img", "")
+	decorated := grpcCatalogSourceDecorator{catsrc}
+	bare := decorated.Pod()
+	bare.SetName("bare")
+	replica := decorated.Pod()
+	replica.SetName("replica")
+	replica.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "img-catalog-1", UID: "rs-uid"}})
+
+	factory, client := fakeReconcilerFactory(t, stopc, withK8sObjs(bare, replica))
+	rec := factory.(*registryReconcilerFactory)
+
+	changed, err := ensureDeployment(rec.Lister, client, decorated.Deployment(1), decorated.PodDisruptionBudget())
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// The bare pod keeps serving until the deployment is available
+	waitForDeployment(t, factory, catsrc, deploymentExists)
+	changed, err = ensureDeployment(rec.Lister, client, decorated.Deployment(1), decorated.PodDisruptionBudget())
+	require.NoError(t, err)
+	require.False(t, changed)
+	requirePods(t, client, decorated, "bare", "replica")
+
+	deployment, err := client.KubernetesInterface().AppsV1().Deployments(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.GetGeneration(), UpdatedReplicas: 1, AvailableReplicas: 1}
+	_, err = client.KubernetesInterface().AppsV1().Deployments(testNamespace).UpdateStatus(deployment)
+	require.NoError(t, err)
+	waitForDeployment(t, factory, catsrc, func(deployment *appsv1.Deployment) bool {
+		return deployment != nil && deploymentAvailable(deployment)
+	})
+
+	// Nothing changes once the deployment is current, and the bare pod is retired once it's available
+	changed, err = ensureDeployment(rec.Lister, client, decorated.Deployment(1), decorated.PodDisruptionBudget())
+	require.NoError(t, err)
+	require.False(t, changed)
+	requirePods(t, client, decorated, "replica")
+}
+
+func TestEnsureDeploymentUpdatesPodDisruptionBudget(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	catsrc := validGrpcCatalogSource("test-img", "")
+	decorated := grpcCatalogSourceDecorator{catsrc}
+	outdated := decorated.PodDisruptionBudget()
+	maxUnavailable := intstr.FromInt(2)
+	outdated.Spec.MaxUnavailable = &maxUnavailable
+
+	factory, client := fakeReconcilerFactory(t, stopc, withK8sObjs(outdated))
+	rec := factory.(*registryReconcilerFactory)
+
+	_, err := ensureDeployment(rec.Lister, client, decorated.Deployment(2), decorated.PodDisruptionBudget())
+	require.NoError(t, err)
+
+	pdb, err := client.KubernetesInterface().PolicyV1beta1().PodDisruptionBudgets(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+}
+
+func TestGrpcRegistryReconcilerSourceReplicas(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	// The CatalogSource asks for a deployment even though the reconciler runs single pods by default
+	catsrc := validGrpcCatalogSource("test-img", "")
+	replicas := int32(2)
+	catsrc.Spec.RegistryPodConfig = &v1alpha1.RegistryPodConfig{Replicas: &replicas}
+	factory, client := fakeReconcilerFactory(t, stopc, withK8sClientOptions(clientfake.WithNameGeneration(t)))
+
+	require.NoError(t, factory.ReconcilerForSource(catsrc).EnsureRegistryServer(catsrc))
+	deployment, err := client.KubernetesInterface().AppsV1().Deployments(testNamespace).Get(catsrc.GetName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+	// Scaling doesn't change the configuration of the registry pods
+	scaled := catsrc.DeepCopy()
+	replicas = 3
+	scaled.Spec.RegistryPodConfig.Replicas = &replicas
+	require.Equal(t, podConfigHash(catsrc), podConfigHash(scaled))
+}
+
+func requirePods(t *testing.T, client operatorclient.ClientInterface, source grpcCatalogSourceDecorator, names ...string) {
+	pods, err := client.KubernetesInterface().CoreV1().Pods(testNamespace).List(metav1.ListOptions{LabelSelector: source.Selector().String()})
+	require.NoError(t, err)
+	var found []string
+	for _, pod := range pods.Items {
+		found = append(found, pod.GetName())
+	}
+	require.Equal(t, names, found)
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -33,6 +35,12 @@ func (s *grpcCatalogSourceDecorator) Labels() map[string]string {
 	}
 }
 
+func (s *grpcCatalogSourceDecorator) UpdateLabels() map[string]string {
+	return map[string]string{
+		CatalogSourceUpdateKey: s.GetName(),
+	}
+}
+
 func (s *grpcCatalogSourceDecorator) Service() *v1.Service {
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -97,17 +105,48 @@ func (s *grpcCatalogSourceDecorator) Pod() *v1.Pod {
 			},
 		},
 	}
+	applyPodConfig(pod, s.CatalogSource)
 	ownerutil.AddOwner(pod, s.CatalogSource, false, false)
 	return pod
 }
 
+// UpdatePod returns a registry pod that pulls the latest content of the registry image. It isn't selected by the
+// registry service until it is rolled out.
+func (s *grpcCatalogSourceDecorator) UpdatePod() *v1.Pod {
+	pod := s.Pod()
+	pod.SetGenerateName(s.GetName() + "-update-")
+	pod.SetLabels(s.UpdateLabels())
+	pod.Spec.Containers[0].ImagePullPolicy = v1.PullAlways
+	return pod
+}
+
+// Deployment returns a Deployment that runs the given number of replicas of the registry pod.
+func (s *grpcCatalogSourceDecorator) Deployment(replicas int32) *appsv1.Deployment {
+	pod := s.Pod()
+	if _, ok := s.PollInterval(); ok {
+		// Restarted pods pull the registry image again
+		pod.Spec.Containers[0].ImagePullPolicy = v1.PullAlways
+	}
+	return registryDeployment(s.CatalogSource, pod, s.Labels(), replicas)
+}
+
+func (s *grpcCatalogSourceDecorator) PodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	return registryPodDisruptionBudget(s.CatalogSource, s.Labels())
+}
+
 type GrpcRegistryReconciler struct {
-	now      nowFunc
-	Lister   operatorlister.OperatorLister
-	OpClient operatorclient.ClientInterface
+	now         nowFunc
+	healthCheck healthCheckFunc
+	Lister      operatorlister.OperatorLister
+	OpClient    operatorclient.ClientInterface
+
+	// Replicas is the number of registry servers run by a Deployment for CatalogSources that don't set their own.
+	// Registry servers run as single pods when zero.
+	Replicas int32
 }
 
 var _ RegistryReconciler = &GrpcRegistryReconciler{}
+var _ RegistryPoller = &GrpcRegistryReconciler{}
 
 func (c *GrpcRegistryReconciler) currentService(source grpcCatalogSourceDecorator) *v1.Service {
 	serviceName := source.Service().GetName()
@@ -131,7 +170,7 @@ func (c *GrpcRegistryReconciler) currentPods(source grpcCatalogSourceDecorator)
 	return pods
 }
 
-func (c *GrpcRegistryReconciler) currentPodsWithCorrectImage(source grpcCatalogSourceDecorator) []*v1.Pod {
+func (c *GrpcRegistryReconciler) currentPodsWithCorrectImageAndSpec(source grpcCatalogSourceDecorator) []*v1.Pod {
 	pods, err := c.Lister.CoreV1().PodLister().Pods(source.GetNamespace()).List(labels.SelectorFromValidatedSet(source.Labels()))
 	if err != nil {
 		logrus.WithError(err).Warn("couldn't find pod in cache")
@@ -139,25 +178,59 @@ func (c *GrpcRegistryReconciler) currentPodsWithCorrectImage(source grpcCatalogS
 	}
 	found := []*v1.Pod{}
 	for _, p := range pods {
-		if p.Spec.Containers[0].Image == source.Spec.Image {
+		if p.Spec.Containers[0].Image == source.Spec.Image && podConfigCurrent(p, source.CatalogSource) {
 			found = append(found, p)
 		}
 	}
 	return found
 }
 
+func (c *GrpcRegistryReconciler) currentUpdatePods(source grpcCatalogSourceDecorator) []*v1.Pod {
+	pods, err := c.Lister.CoreV1().PodLister().Pods(source.GetNamespace()).List(labels.SelectorFromValidatedSet(source.UpdateLabels()))
+	if err != nil {
+		logrus.WithError(err).Warn("couldn't find update pod in cache")
+		return nil
+	}
+	if len(pods) > 1 {
+		logrus.WithField("selector", source.UpdateLabels()).Warn("multiple update pods found for selector")
+	}
+	return pods
+}
+
+// currentServers returns true if registry servers run the latest image with the latest settings, either in pods or in
+// a deployment depending on the number of replicas.
+func (c *GrpcRegistryReconciler) currentServers(source grpcCatalogSourceDecorator) bool {
+	if replicas := registryReplicas(source.CatalogSource, c.Replicas); replicas > 0 {
+		return deploymentCurrent(currentDeployment(c.Lister, source.GetNamespace(), source.GetName()), source.Deployment(replicas))
+	}
+	return len(c.currentPodsWithCorrectImageAndSpec(source)) > 0
+}
+
 // EnsureRegistryServer ensures that all components of registry server are up to date.
 func (c *GrpcRegistryReconciler) EnsureRegistryServer(catalogSource *v1alpha1.CatalogSource) error {
 	source := grpcCatalogSourceDecorator{catalogSource}
 
 	// if service status is nil, we force create every object to ensure they're created the first time
 	overwrite := source.Status.RegistryServiceStatus == nil
-	// recreate the pod if no existing pod is serving the latest image
-	overwritePod := overwrite || len(c.currentPodsWithCorrectImage(source)) == 0
+	var overwritePod bool
 
 	//TODO: if any of these error out, we should write a status back (possibly set RegistryServiceStatus to nil so they get recreated)
-	if err := c.ensurePod(source, overwritePod); err != nil {
-		return errors.Wrapf(err, "error ensuring pod: %s", source.Pod().GetName())
+	if replicas := registryReplicas(catalogSource, c.Replicas); replicas > 0 {
+		// roll out the deployment if it doesn't run the latest image with the latest settings
+		rolled, err := ensureDeployment(c.Lister, c.OpClient, source.Deployment(replicas), source.PodDisruptionBudget())
+		if err != nil {
+			return errors.Wrapf(err, "error ensuring deployment: %s", source.GetName())
+		}
+		overwritePod = overwrite || rolled
+	} else {
+		if err := removeDeployment(c.Lister, c.OpClient, source.CatalogSource); err != nil {
+			return err
+		}
+		// recreate the pod if no existing pod is serving the latest image with the latest settings
+		overwritePod = overwrite || len(c.currentPodsWithCorrectImageAndSpec(source)) == 0
+		if err := c.ensurePod(source, overwritePod); err != nil {
+			return errors.Wrapf(err, "error ensuring pod: %s", source.Pod().GetName())
+		}
 	}
 	if err := c.ensureService(source, overwrite); err != nil {
 		return errors.Wrapf(err, "error ensuring service: %s", source.Service().GetName())
@@ -210,18 +283,120 @@ func (c *GrpcRegistryReconciler) ensureService(source grpcCatalogSourceDecorator
 	return err
 }
 
-// CheckRegistryServer returns true if the given CatalogSource is considered healthy; false otherwise.
+// CheckRegistryServer returns true if the given CatalogSource's registry server exists and is serving; false
+// otherwise. The result of the health check is recorded in the CatalogSource's status.
 func (c *GrpcRegistryReconciler) CheckRegistryServer(catalogSource *v1alpha1.CatalogSource) (healthy bool, err error) {
 	source := grpcCatalogSourceDecorator{catalogSource}
 
 	// Check on registry resources
-	// TODO: add gRPC health check
-	if len(c.currentPodsWithCorrectImage(source)) < 1 ||
-		c.currentService(source) == nil {
+	service := c.currentService(source)
+	if !c.currentServers(source) || service == nil {
 		healthy = false
 		return
 	}
 
-	healthy = true
+	// Check that the registry server answers through its service
+	address := fmt.Sprintf("%s.%s.svc.cluster.local:%d", service.GetName(), service.GetNamespace(), source.Service().Spec.Ports[0].Port)
+	healthy = checkConnection(c.healthCheck, c.now, catalogSource, address)
 	return
 }
+
+// PollRegistryServer polls the registry image of the given CatalogSource for updated content once its poll interval
+// has passed. Each poll starts an update pod from the registry image. Once the update pod is ready, it replaces the
+// serving registry pod if its image resolved to a different digest, and is deleted otherwise. Polls and rollouts are
+// recorded in the CatalogSource's status.
+func (c *GrpcRegistryReconciler) PollRegistryServer(catalogSource *v1alpha1.CatalogSource) error {
+	interval, ok := catalogSource.PollInterval()
+	if !ok {
+		return nil
+	}
+	source := grpcCatalogSourceDecorator{catalogSource}
+	pods := c.OpClient.KubernetesInterface().CoreV1().Pods(source.GetNamespace())
+	now := c.now()
+
+	updatePods := c.currentUpdatePods(source)
+	if len(updatePods) == 0 {
+		if last := catalogSource.Status.LatestImageRegistryPoll; last != nil && now.Sub(last.Time) < interval {
+			return nil
+		}
+		if _, err := pods.Create(source.UpdatePod()); err != nil {
+			return errors.Wrapf(err, "error creating update pod: %s", source.UpdatePod().GetGenerateName())
+		}
+		catalogSource.Status.LatestImageRegistryPoll = &now
+		return nil
+	}
+
+	updatePod := updatePods[0]
+	if !podReady(updatePod) {
+		// Give up on update pods that don't become ready within an interval, the next poll starts a new one
+		if now.Sub(updatePod.GetCreationTimestamp().Time) >= interval {
+			if err := pods.Delete(updatePod.GetName(), metav1.NewDeleteOptions(0)); err != nil {
+				return errors.Wrapf(err, "error deleting update pod: %s", updatePod.GetName())
+			}
+		}
+		return nil
+	}
+
+	servingPods := c.currentPodsWithCorrectImageAndSpec(source)
+	if len(servingPods) > 0 && podImageID(servingPods[0]) == podImageID(updatePod) {
+		// The registry image hasn't changed since the serving pod pulled it
+		if err := pods.Delete(updatePod.GetName(), metav1.NewDeleteOptions(0)); err != nil {
+			return errors.Wrapf(err, "error deleting update pod: %s", updatePod.GetName())
+		}
+		return nil
+	}
+
+	if registryReplicas(catalogSource, c.Replicas) > 0 {
+		// Restart the deployment, whose pods pull the registry image again, and retire the update pod
+		if err := restartDeployment(c.Lister, c.OpClient, catalogSource, now); err != nil {
+			return err
+		}
+		if err := pods.Delete(updatePod.GetName(), metav1.NewDeleteOptions(0)); err != nil {
+			return errors.Wrapf(err, "error deleting update pod: %s", updatePod.GetName())
+		}
+		if catalogSource.Status.RegistryServiceStatus != nil {
+			catalogSource.Status.RegistryServiceStatus.CreatedAt = now
+		}
+		catalogSource.Status.LastSync = now
+		return nil
+	}
+
+	// Relabel the update pod so that the registry service selects it, then retire the pods it replaces
+	rollout := updatePod.DeepCopy()
+	rollout.SetLabels(source.Labels())
+	if _, err := pods.Update(rollout); err != nil {
+		return errors.Wrapf(err, "error rolling out update pod: %s", updatePod.GetName())
+	}
+	for _, p := range c.currentPods(source) {
+		if p.GetName() == rollout.GetName() {
+			continue
+		}
+		if err := pods.Delete(p.GetName(), metav1.NewDeleteOptions(0)); err != nil {
+			return errors.Wrapf(err, "error deleting old pod: %s", p.GetName())
+		}
+	}
+
+	if catalogSource.Status.RegistryServiceStatus != nil {
+		catalogSource.Status.RegistryServiceStatus.CreatedAt = now
+	}
+	catalogSource.Status.LastSync = now
+	return nil
+}
+
+// podReady returns true if the given pod is ready to serve.
+func podReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podImageID returns the resolved image of the given registry pod, or an empty string if it hasn't been pulled yet.
+func podImageID(pod *v1.Pod) string {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return ""
+	}
+	return pod.Status.ContainerStatuses[0].ImageID
+}
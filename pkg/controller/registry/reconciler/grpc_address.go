@@ -5,7 +5,8 @@ import (
 )
 
 type GrpcAddressRegistryReconciler struct {
-	now nowFunc
+	now         nowFunc
+	healthCheck healthCheckFunc
 }
 
 var _ RegistryEnsurer = &GrpcAddressRegistryReconciler{}
@@ -24,9 +25,9 @@ func (g *GrpcAddressRegistryReconciler) EnsureRegistryServer(catalogSource *v1al
 	return nil
 }
 
-// CheckRegistryServer returns true if the given CatalogSource is considered healthy; false otherwise.
+// CheckRegistryServer returns true if the registry server at the given CatalogSource's address is serving; false
+// otherwise. The result of the health check is recorded in the CatalogSource's status.
 func (g *GrpcAddressRegistryReconciler) CheckRegistryServer(catalogSource *v1alpha1.CatalogSource) (healthy bool, err error) {
-	// TODO: add gRPC health check
-	healthy = true
+	healthy = checkConnection(g.healthCheck, g.now, catalogSource, catalogSource.Spec.Address)
 	return
 }
@@ -1,6 +1,7 @@
 package reconciler
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -220,12 +221,14 @@ func TestGrpcRegistryChecker(t *testing.T) {
 		k8sObjs []runtime.Object
 	}
 	type in struct {
-		cluster cluster
-		catsrc  *v1alpha1.CatalogSource
+		cluster     cluster
+		healthCheck healthCheckFunc
+		catsrc      *v1alpha1.CatalogSource
 	}
 	type out struct {
-		healthy bool
-		err     error
+		healthy         bool
+		err             error
+		connectionState *v1alpha1.GRPCConnectionState
 	}
 	tests := []struct {
 		testName string
@@ -242,6 +245,43 @@ func TestGrpcRegistryChecker(t *testing.T) {
 			},
 			out: out{
 				healthy: true,
+				connectionState: &v1alpha1.GRPCConnectionState{
+					Address:           "img-catalog.testns.svc.cluster.local:50051",
+					LastObservedState: "SERVING",
+				},
+			},
+		},
+		{
+			testName: "Grpc/ExistingRegistry/Image/NotServing",
+			in: in{
+				cluster: cluster{
+					k8sObjs: objectsForCatalogSource(validGrpcCatalogSource("test-img", "")),
+				},
+				healthCheck: func(*v1alpha1.CatalogSource, string) (string, error) { return "NOT_SERVING", nil },
+				catsrc:      validGrpcCatalogSource("test-img", ""),
+			},
+			out: out{
+				healthy: false,
+				connectionState: &v1alpha1.GRPCConnectionState{
+					Address:           "img-catalog.testns.svc.cluster.local:50051",
+					LastObservedState: "NOT_SERVING",
+				},
+			},
+		},
+		{
+			testName: "Grpc/ExistingRegistry/Image/ReconfiguredPod/NotHealthy",
+			in: in{
+				cluster: cluster{
+					k8sObjs: objectsForCatalogSource(validGrpcCatalogSource("test-img", "")),
+				},
+				catsrc: func() *v1alpha1.CatalogSource {
+					catsrc := validGrpcCatalogSource("test-img", "")
+					catsrc.Spec.Secrets = []string{"pull-secret"}
+					return catsrc
+				}(),
+			},
+			out: out{
+				healthy: false,
 			},
 		},
 		{
@@ -296,6 +336,27 @@ func TestGrpcRegistryChecker(t *testing.T) {
 			},
 			out: out{
 				healthy: true,
+				connectionState: &v1alpha1.GRPCConnectionState{
+					Address:           "catalog.svc.cluster.local:50001",
+					LastObservedState: "SERVING",
+				},
+			},
+		},
+		{
+			testName: "Grpc/NoExistingRegistry/Address/Unreachable",
+			in: in{
+				healthCheck: func(*v1alpha1.CatalogSource, string) (string, error) {
+					return "TRANSIENT_FAILURE", errors.New("connection refused")
+				},
+				catsrc: validGrpcCatalogSource("", "catalog.svc.cluster.local:50001"),
+			},
+			out: out{
+				healthy: false,
+				connectionState: &v1alpha1.GRPCConnectionState{
+					Address:           "catalog.svc.cluster.local:50001",
+					LastObservedState: "TRANSIENT_FAILURE",
+					LastError:         "connection refused",
+				},
 			},
 		},
 		{
@@ -308,6 +369,10 @@ func TestGrpcRegistryChecker(t *testing.T) {
 			},
 			out: out{
 				healthy: true,
+				connectionState: &v1alpha1.GRPCConnectionState{
+					Address:           "img-catalog.testns.svc.cluster.local:50051",
+					LastObservedState: "SERVING",
+				},
 			},
 		},
 		{
@@ -350,7 +415,11 @@ func TestGrpcRegistryChecker(t *testing.T) {
 			stopc := make(chan struct{})
 			defer close(stopc)
 
-			factory, _ := fakeReconcilerFactory(t, stopc, withK8sObjs(tt.in.cluster.k8sObjs...))
+			options := []fakeReconcilerOption{withK8sObjs(tt.in.cluster.k8sObjs...)}
+			if tt.in.healthCheck != nil {
+				options = append(options, withHealthCheck(tt.in.healthCheck))
+			}
+			factory, _ := fakeReconcilerFactory(t, stopc, options...)
 			rec := factory.ReconcilerForSource(tt.in.catsrc)
 
 			healthy, err := rec.CheckRegistryServer(tt.in.catsrc)
@@ -362,6 +431,165 @@ func TestGrpcRegistryChecker(t *testing.T) {
 
 			require.Equal(t, tt.out.healthy, healthy)
 
+			connectionState := tt.in.catsrc.Status.GRPCConnectionState
+			if connectionState != nil {
+				// The transition time comes from the clock, so only check that it was set
+				require.False(t, connectionState.LastTransitionTime.IsZero())
+				connectionState = connectionState.DeepCopy()
+				connectionState.LastTransitionTime = metav1.Time{}
+			}
+			require.Equal(t, tt.out.connectionState, connectionState)
+
+		})
+	}
+}
+
+func TestGrpcRegistryPoller(t *testing.T) {
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	recently := metav1.NewTime(now.Add(-5 * time.Minute))
+	longAgo := metav1.NewTime(now.Add(-time.Hour))
+
+	pollingCatalogSource := func(lastPoll *metav1.Time) *v1alpha1.CatalogSource {
+		catsrc := validGrpcCatalogSource("test-img:latest", "")
+		catsrc.Spec.UpdateStrategy = &v1alpha1.UpdateStrategy{
+			RegistryPoll: &v1alpha1.RegistryPoll{Interval: metav1.Duration{Duration: 15 * time.Minute}},
+		}
+		catsrc.Status.RegistryServiceStatus = &v1alpha1.RegistryServiceStatus{CreatedAt: longAgo}
+		catsrc.Status.LatestImageRegistryPoll = lastPoll
+		return catsrc
+	}
+	withPodStatus := func(pod *corev1.Pod, name, imageID string, ready bool, created metav1.Time) *corev1.Pod {
+		pod.SetName(name)
+		pod.SetCreationTimestamp(created)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{ImageID: imageID}}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return pod
+	}
+	servingPod := func(catsrc *v1alpha1.CatalogSource) *corev1.Pod {
+		source := grpcCatalogSourceDecorator{catsrc}
+		return withPodStatus(source.Pod(), "serving", "test-img@sha256:old", true, longAgo)
+	}
+	updatePod := func(catsrc *v1alpha1.CatalogSource, imageID string, ready bool, created metav1.Time) *corev1.Pod {
+		source := grpcCatalogSourceDecorator{catsrc}
+		return withPodStatus(source.UpdatePod(), "update", imageID, ready, created)
+	}
+
+	type out struct {
+		lastPoll     *metav1.Time
+		lastSync     metav1.Time
+		servingPods  []string
+		updatePods   int
+		rolledOutPod string
+	}
+	tests := []struct {
+		testName string
+		catsrc   *v1alpha1.CatalogSource
+		k8sObjs  func(catsrc *v1alpha1.CatalogSource) []runtime.Object
+		out      out
+	}{
+		{
+			testName: "NeverPolled/StartsUpdatePod",
+			catsrc:   pollingCatalogSource(nil),
+			k8sObjs: func(catsrc *v1alpha1.CatalogSource) []runtime.Object {
+				return []runtime.Object{servingPod(catsrc)}
+			},
+			out: out{
+				lastPoll:    &now,
+				servingPods: []string{"serving"},
+				updatePods:  1,
+			},
+		},
+		{
+			testName: "PolledRecently/Waits",
+			catsrc:   pollingCatalogSource(&recently),
+			k8sObjs: func(catsrc *v1alpha1.CatalogSource) []runtime.Object {
+				return []runtime.Object{servingPod(catsrc)}
+			},
+			out: out{
+				lastPoll:    &recently,
+				servingPods: []string{"serving"},
+			},
+		},
+		{
+			testName: "UpdatePodNotReady/Waits",
+			catsrc:   pollingCatalogSource(&recently),
+			k8sObjs: func(catsrc *v1alpha1.CatalogSource) []runtime.Object {
+				return []runtime.Object{servingPod(catsrc), updatePod(catsrc, "", false, recently)}
+			},
+			out: out{
+				lastPoll:    &recently,
+				servingPods: []string{"serving"},
+				updatePods:  1,
+			},
+		},
+		{
+			testName: "UpdatePodNeverReady/Deleted",
+			catsrc:   pollingCatalogSource(&longAgo),
+			k8sObjs: func(catsrc *v1alpha1.CatalogSource) []runtime.Object {
+				return []runtime.Object{servingPod(catsrc), updatePod(catsrc, "", false, longAgo)}
+			},
+			out: out{
+				lastPoll:    &longAgo,
+				servingPods: []string{"serving"},
+			},
+		},
+		{
+			testName: "UpdatePodReady/SameDigest/Deleted",
+			catsrc:   pollingCatalogSource(&recently),
+			k8sObjs: func(catsrc *v1alpha1.CatalogSource) []runtime.Object {
+				return []runtime.Object{servingPod(catsrc), updatePod(catsrc, "test-img@sha256:old", true, recently)}
+			},
+			out: out{
+				lastPoll:    &recently,
+				servingPods: []string{"serving"},
+			},
+		},
+		{
+			testName: "UpdatePodReady/NewDigest/RolledOut",
+			catsrc:   pollingCatalogSource(&recently),
+			k8sObjs: func(catsrc *v1alpha1.CatalogSource) []runtime.Object {
+				return []runtime.Object{servingPod(catsrc), updatePod(catsrc, "test-img@sha256:new", true, recently)}
+			},
+			out: out{
+				lastPoll:    &recently,
+				lastSync:    now,
+				servingPods: []string{"update"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			stopc := make(chan struct{})
+			defer close(stopc)
+
+			factory, client := fakeReconcilerFactory(t, stopc, withNow(func() metav1.Time { return now }), withK8sObjs(tt.k8sObjs(tt.catsrc)...),
+				withK8sClientOptions(clientfake.WithNameGeneration(t)))
+			rec := factory.ReconcilerForSource(tt.catsrc)
+			poller, ok := rec.(RegistryPoller)
+			require.True(t, ok)
+
+			require.NoError(t, poller.PollRegistryServer(tt.catsrc))
+			require.Equal(t, tt.out.lastPoll, tt.catsrc.Status.LatestImageRegistryPoll)
+			require.Equal(t, tt.out.lastSync, tt.catsrc.Status.LastSync)
+
+			source := grpcCatalogSourceDecorator{tt.catsrc}
+			pods := client.KubernetesInterface().CoreV1().Pods(testNamespace)
+			serving, err := pods.List(metav1.ListOptions{LabelSelector: source.Selector().String()})
+			require.NoError(t, err)
+			var servingNames []string
+			for _, pod := range serving.Items {
+				servingNames = append(servingNames, pod.GetName())
+			}
+			require.Equal(t, tt.out.servingPods, servingNames)
+
+			updates, err := pods.List(metav1.ListOptions{LabelSelector: labels.SelectorFromSet(source.UpdateLabels()).String()})
+			require.NoError(t, err)
+			require.Len(t, updates.Items, tt.out.updatePods)
+			for _, pod := range updates.Items {
+				require.Equal(t, corev1.PullAlways, pod.Spec.Containers[0].ImagePullPolicy)
+			}
 		})
 	}
 }
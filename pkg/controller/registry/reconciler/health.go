@@ -0,0 +1,122 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	registryclient "github.com/operator-framework/operator-registry/pkg/client"
+	"google.golang.org/grpc/connectivity"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+)
+
+const (
+	// healthCheckTimeout bounds each attempt to health check a registry server.
+	healthCheckTimeout = 2 * time.Second
+)
+
+// healthCheckBackoff spaces out the attempts to health check a registry server that can't be reached.
+var healthCheckBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Steps:    3,
+}
+
+// servingState is the observed state of a registry server that reports it is serving.
+var servingState = grpc_health_v1.HealthCheckResponse_SERVING.String()
+
+// healthCheckFunc health checks the registry server of the given CatalogSource at the given address. It returns the
+// serving status the registry server reports, or the state of the connection to it and an error if it can't be reached.
+type healthCheckFunc func(catalogSource *v1alpha1.CatalogSource, address string) (state string, err error)
+
+// grpcHealthCheck dials the registry server at the given address and calls its gRPC health service.
+func grpcHealthCheck(_ *v1alpha1.CatalogSource, address string) (state string, err error) {
+	client, err := registryclient.NewClient(address)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	return checkHealth(client, func() string { return client.Conn.GetState().String() })
+}
+
+// poolHealthCheck returns a healthCheckFunc that calls the gRPC health service of registry servers over the
+// connections the given pool holds to them. It dials registry servers the pool has no connection to at the address.
+func poolHealthCheck(pool *connection.Pool) healthCheckFunc {
+	return func(catalogSource *v1alpha1.CatalogSource, address string) (string, error) {
+		source, ok := pool.Get(connection.Key(catalogSource))
+		if !ok || source.Client == nil || source.Address != address {
+			return grpcHealthCheck(catalogSource, address)
+		}
+
+		// Pooled connections don't expose their state, and are reconnected by the pool when they fail
+		return checkHealth(source.Client, connectivity.TransientFailure.String)
+	}
+}
+
+// recordedHealthCheck takes the result of the last health check of the registry server of the given CatalogSource at the
+// given address from its status, without reaching the registry server.
+func recordedHealthCheck(catalogSource *v1alpha1.CatalogSource, address string) (state string, err error) {
+	last := catalogSource.Status.GRPCConnectionState
+	if last == nil || last.Address != address {
+		return "", fmt.Errorf("registry server at %s hasn't been health checked", address)
+	}
+	if last.LastError != "" {
+		return last.LastObservedState, errors.New(last.LastError)
+	}
+	return last.LastObservedState, nil
+}
+
+// checkHealth calls the gRPC health service of a registry server with the given client, retrying with backoff until
+// the registry server answers. The state of the connection is reported with connState if it never does.
+func checkHealth(client connection.Client, connState func() string) (state string, err error) {
+	var lastErr error
+	err = wait.ExponentialBackoff(healthCheckBackoff, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+
+		serving, err := client.HealthCheck(ctx, healthCheckTimeout)
+		if err != nil {
+			state, lastErr = connState(), err
+			return false, nil
+		}
+		state, lastErr = grpc_health_v1.HealthCheckResponse_NOT_SERVING.String(), nil
+		if serving {
+			state = servingState
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		err = lastErr
+	}
+	return state, err
+}
+
+// checkConnection health checks the registry server of the given CatalogSource at the given address, and records the
+// result in its status. It returns true if the registry server is serving.
+func checkConnection(check healthCheckFunc, now nowFunc, catalogSource *v1alpha1.CatalogSource, address string) bool {
+	connectionState := &v1alpha1.GRPCConnectionState{
+		Address: address,
+	}
+	state, err := check(catalogSource, address)
+	connectionState.LastObservedState = state
+	if err != nil {
+		connectionState.LastError = err.Error()
+	}
+
+	// Keep the transition time while nothing changes, so that repeated checks don't churn the status
+	if last := catalogSource.Status.GRPCConnectionState; last != nil && last.Address == connectionState.Address &&
+		last.LastObservedState == connectionState.LastObservedState && last.LastError == connectionState.LastError {
+		connectionState.LastTransitionTime = last.LastTransitionTime
+	} else {
+		connectionState.LastTransitionTime = now()
+	}
+	catalogSource.Status.GRPCConnectionState = connectionState
+
+	return err == nil && state == servingState
+}
@@ -0,0 +1,177 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	registryclient "github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+)
+
+// servingHealthCheck is a healthCheckFunc for a registry server that is always serving.
+func servingHealthCheck(*v1alpha1.CatalogSource, string) (string, error) {
+	return "SERVING", nil
+}
+
+type fakeHealthServer struct {
+	status grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+func (s *fakeHealthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return &grpc_health_v1.HealthCheckResponse{Status: s.status}, nil
+}
+
+// fakeRegistryServer starts a gRPC server that reports the given serving status, and returns its address.
+func fakeRegistryServer(t *testing.T, status grpc_health_v1.HealthCheckResponse_ServingStatus) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, &fakeHealthServer{status: status})
+	go s.Serve(lis)
+
+	return lis.Addr().String(), s.Stop
+}
+
+func TestGrpcHealthCheck(t *testing.T) {
+	address, stop := fakeRegistryServer(t, grpc_health_v1.HealthCheckResponse_SERVING)
+	defer stop()
+	state, err := grpcHealthCheck(nil, address)
+	require.NoError(t, err)
+	require.Equal(t, "SERVING", state)
+
+	address, stop = fakeRegistryServer(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	defer stop()
+	state, err = grpcHealthCheck(nil, address)
+	require.NoError(t, err)
+	require.Equal(t, "NOT_SERVING", state)
+
+	// Nothing listens on the address once the server is stopped
+	address, stop = fakeRegistryServer(t, grpc_health_v1.HealthCheckResponse_SERVING)
+	stop()
+	start := time.Now()
+	state, err = grpcHealthCheck(nil, address)
+	require.Error(t, err)
+	require.NotEqual(t, "SERVING", state)
+	require.True(t, time.Since(start) < time.Duration(healthCheckBackoff.Steps)*(healthCheckTimeout+time.Second), "health check wasn't bounded")
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	address, stop := fakeRegistryServer(t, grpc_health_v1.HealthCheckResponse_SERVING)
+	defer stop()
+
+	dials := 0
+	pool := connection.NewPool("test", metav1.Now, connection.Dialer{
+		Dial: func(address string) (connection.Client, error) {
+			dials++
+			return registryclient.NewClient(address)
+		},
+	})
+	check := poolHealthCheck(pool)
+
+	// Registry servers the pool has no connection to are dialed for the check
+	catsrc := validGrpcCatalogSource("", address)
+	state, err := check(catsrc, address)
+	require.NoError(t, err)
+	require.Equal(t, "SERVING", state)
+	require.Equal(t, 0, dials)
+
+	// Pooled connections are reused for every check
+	_, err = pool.Sync(context.Background(), logrus.NewEntry(logrus.New()), catsrc)
+	require.NoError(t, err)
+	require.Equal(t, 1, dials)
+	for i := 0; i < 3; i++ {
+		state, err = check(catsrc, address)
+		require.NoError(t, err)
+		require.Equal(t, "SERVING", state)
+	}
+	require.Equal(t, 1, dials)
+}
+
+func TestCheckConnection(t *testing.T) {
+	first := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	second := metav1.Date(2018, time.January, 26, 20, 45, 0, 0, time.UTC)
+	catsrc := validGrpcCatalogSource("", "catalog:50051")
+
+	healthy := checkConnection(servingHealthCheck, func() metav1.Time { return first }, catsrc, "catalog:50051")
+	require.True(t, healthy)
+	require.Equal(t, &v1alpha1.GRPCConnectionState{
+		Address:            "catalog:50051",
+		LastObservedState:  "SERVING",
+		LastTransitionTime: first,
+	}, catsrc.Status.GRPCConnectionState)
+
+	// The transition time is kept while the state doesn't change
+	healthy = checkConnection(servingHealthCheck, func() metav1.Time { return second }, catsrc, "catalog:50051")
+	require.True(t, healthy)
+	require.Equal(t, first, catsrc.Status.GRPCConnectionState.LastTransitionTime)
+
+	unreachable := func(*v1alpha1.CatalogSource, string) (string, error) {
+		return "TRANSIENT_FAILURE", errors.New("connection refused")
+	}
+	healthy = checkConnection(unreachable, func() metav1.Time { return second }, catsrc, "catalog:50051")
+	require.False(t, healthy)
+	require.Equal(t, &v1alpha1.GRPCConnectionState{
+		Address:            "catalog:50051",
+		LastObservedState:  "TRANSIENT_FAILURE",
+		LastError:          "connection refused",
+		LastTransitionTime: second,
+	}, catsrc.Status.GRPCConnectionState)
+}
+
+func TestRecordedHealthCheck(t *testing.T) {
+	catsrc := validGrpcCatalogSource("", "catalog:50051")
+
+	// Registry servers that haven't been health checked at the address aren't reached
+	_, err := recordedHealthCheck(catsrc, "catalog:50051")
+	require.EqualError(t, err, "registry server at catalog:50051 hasn't been health checked")
+
+	checkConnection(servingHealthCheck, metav1.Now, catsrc, "catalog:50051")
+	state, err := recordedHealthCheck(catsrc, "catalog:50051")
+	require.NoError(t, err)
+	require.Equal(t, "SERVING", state)
+	_, err = recordedHealthCheck(catsrc, "moved:50051")
+	require.Error(t, err)
+
+	unreachable := func(*v1alpha1.CatalogSource, string) (string, error) {
+		return "TRANSIENT_FAILURE", errors.New("connection refused")
+	}
+	checkConnection(unreachable, metav1.Now, catsrc, "catalog:50051")
+	state, err = recordedHealthCheck(catsrc, "catalog:50051")
+	require.EqualError(t, err, "connection refused")
+	require.Equal(t, "TRANSIENT_FAILURE", state)
+
+	// Checking with the recorded state keeps it
+	recorded := catsrc.Status.GRPCConnectionState.DeepCopy()
+	healthy := checkConnection(recordedHealthCheck, metav1.Now, catsrc, "catalog:50051")
+	require.False(t, healthy)
+	require.Equal(t, recorded, catsrc.Status.GRPCConnectionState)
+}
+
+func TestWithRecordedHealth(t *testing.T) {
+	// Nothing listens at the address, so only the recorded state can make the registry server healthy
+	catsrc := validGrpcCatalogSource("", "127.0.0.1:1")
+	catsrc.Status.GRPCConnectionState = &v1alpha1.GRPCConnectionState{
+		Address:           "127.0.0.1:1",
+		LastObservedState: "SERVING",
+	}
+
+	factory := NewRegistryReconcilerFactory(nil, nil, "", metav1.Now)
+	healthy, err := WithRecordedHealth(factory).ReconcilerForSource(catsrc).CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.True(t, healthy)
+
+	healthy, err = factory.ReconcilerForSource(catsrc).CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.False(t, healthy)
+}
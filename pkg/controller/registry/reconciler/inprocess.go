@@ -0,0 +1,104 @@
+package reconciler
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
+)
+
+// InProcessRegistryReconciler serves the catalogs of CatalogSources with a URL from within the current process,
+// instead of from registry pods.
+type InProcessRegistryReconciler struct {
+	now         nowFunc
+	healthCheck healthCheckFunc
+	Server      *inprocess.Server
+	Loader      *inprocess.Loader
+}
+
+var _ RegistryEnsurer = &InProcessRegistryReconciler{}
+var _ RegistryChecker = &InProcessRegistryReconciler{}
+var _ RegistryReconciler = &InProcessRegistryReconciler{}
+var _ RegistryPoller = &InProcessRegistryReconciler{}
+var _ RegistryRemover = &InProcessRegistryReconciler{}
+
+func inProcessKey(catalogSource *v1alpha1.CatalogSource) string {
+	return catalogSource.GetNamespace() + "/" + catalogSource.GetName()
+}
+
+// EnsureRegistryServer loads the catalog from the URL of the given CatalogSource, and serves it.
+func (r *InProcessRegistryReconciler) EnsureRegistryServer(catalogSource *v1alpha1.CatalogSource) error {
+	catalog, err := r.Loader.Load(catalogSource.Spec.URL)
+	if err != nil {
+		return err
+	}
+	port, err := r.Server.Serve(inProcessKey(catalogSource), catalog)
+	if err != nil {
+		return errors.Wrapf(err, "error serving catalog: %s", catalogSource.GetName())
+	}
+
+	now := r.now()
+	catalogSource.Status.RegistryServiceStatus = &v1alpha1.RegistryServiceStatus{
+		CreatedAt: now,
+		Protocol:  "grpc",
+		Port:      port,
+	}
+	catalogSource.Status.LastSync = now
+
+	return nil
+}
+
+// CheckRegistryServer returns true if the catalog loaded from the URL of the given CatalogSource is served on the
+// port recorded in its status, and is serving; false otherwise.
+func (r *InProcessRegistryReconciler) CheckRegistryServer(catalogSource *v1alpha1.CatalogSource) (healthy bool, err error) {
+	catalog, port, ok := r.Server.Catalog(inProcessKey(catalogSource))
+	if !ok || catalog.URL != catalogSource.Spec.URL || catalogSource.Status.RegistryServiceStatus == nil ||
+		catalogSource.Status.RegistryServiceStatus.Port != port {
+		return false, nil
+	}
+	healthy = checkConnection(r.healthCheck, r.now, catalogSource, catalogSource.Address())
+	return
+}
+
+// PollRegistryServer loads the catalog from the URL of the given CatalogSource again once its poll interval has
+// passed, and serves it if its content changed. Polls and updates are recorded in the CatalogSource's status.
+func (r *InProcessRegistryReconciler) PollRegistryServer(catalogSource *v1alpha1.CatalogSource) error {
+	interval, ok := catalogSource.PollInterval()
+	if !ok {
+		return nil
+	}
+	now := r.now()
+	if last := catalogSource.Status.LatestImageRegistryPoll; last != nil && now.Sub(last.Time) < interval {
+		return nil
+	}
+
+	key := inProcessKey(catalogSource)
+	served, _, ok := r.Server.Catalog(key)
+	if !ok {
+		return nil
+	}
+	catalog, err := r.Loader.Load(catalogSource.Spec.URL)
+	if err != nil {
+		return err
+	}
+	catalogSource.Status.LatestImageRegistryPoll = &now
+	if catalog.Digest == served.Digest {
+		return nil
+	}
+
+	if _, err := r.Server.Serve(key, catalog); err != nil {
+		return errors.Wrapf(err, "error serving catalog: %s", catalogSource.GetName())
+	}
+	if catalogSource.Status.RegistryServiceStatus != nil {
+		catalogSource.Status.RegistryServiceStatus.CreatedAt = now
+	}
+	catalogSource.Status.LastSync = now
+
+	return nil
+}
+
+// RemoveRegistryServer stops serving the catalog of the given CatalogSource.
+func (r *InProcessRegistryReconciler) RemoveRegistryServer(catalogSource *v1alpha1.CatalogSource) error {
+	r.Server.Stop(inProcessKey(catalogSource))
+	return nil
+}
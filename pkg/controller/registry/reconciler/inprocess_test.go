@@ -0,0 +1,165 @@
+package reconciler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
+)
+
+const inProcessManifests = "../inprocess/testdata/manifests"
+
+func validURLCatalogSource(url string) *v1alpha1.CatalogSource {
+	return &v1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "url-catalog",
+			Namespace: testNamespace,
+			UID:       types.UID("catalog-uid"),
+		},
+		Spec: v1alpha1.CatalogSourceSpec{
+			URL:        url,
+			SourceType: v1alpha1.SourceTypeURL,
+		},
+	}
+}
+
+// copyManifests copies the given manifest directory to a temporary directory.
+func copyManifests(t *testing.T, from string) string {
+	dir, err := ioutil.TempDir("", "manifests-")
+	require.NoError(t, err)
+	require.NoError(t, filepath.Walk(from, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		if f.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0755)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dir, rel), data, 0644)
+	}))
+	return dir
+}
+
+func TestInProcessRegistryReconciler(t *testing.T) {
+	now := metav1.Date(2018, time.January, 26, 20, 40, 0, 0, time.UTC)
+	clock := func() metav1.Time { return now }
+
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	dir := copyManifests(t, inProcessManifests)
+	defer os.RemoveAll(dir)
+
+	factory, _ := fakeReconcilerFactory(t, stopc, withNow(clock), withHealthCheck(grpcHealthCheck), withCatalogLoader(&inprocess.Loader{Root: dir}))
+	catsrc := validURLCatalogSource(dir)
+	catsrc.Spec.UpdateStrategy = &v1alpha1.UpdateStrategy{
+		RegistryPoll: &v1alpha1.RegistryPoll{Interval: metav1.Duration{Duration: 10 * time.Minute}},
+	}
+	rec := factory.ReconcilerForSource(catsrc)
+	require.IsType(t, &InProcessRegistryReconciler{}, rec)
+
+	healthy, err := rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	// The catalog is served from within the process, on the port recorded in the status
+	require.NoError(t, rec.EnsureRegistryServer(catsrc))
+	status := catsrc.Status.RegistryServiceStatus
+	require.NotNil(t, status)
+	require.Equal(t, "grpc", status.Protocol)
+	require.NotEmpty(t, status.Port)
+	require.Equal(t, now, status.CreatedAt)
+	require.Equal(t, now, catsrc.Status.LastSync)
+	require.Equal(t, "localhost:"+status.Port, catsrc.Address())
+
+	healthy, err = rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.True(t, healthy)
+	require.Equal(t, "SERVING", catsrc.Status.GRPCConnectionState.LastObservedState)
+
+	// A poll of unchanged content is only recorded
+	poller := rec.(RegistryPoller)
+	now = metav1.NewTime(now.Add(time.Minute))
+	require.NoError(t, poller.PollRegistryServer(catsrc))
+	require.Equal(t, &now, catsrc.Status.LatestImageRegistryPoll)
+	require.NotEqual(t, now, catsrc.Status.LastSync)
+
+	// Changed content is served once the interval has passed, on the same port
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "test", "3.0.0")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "test", "test.package.yaml"), []byte(`
+packageName: test
+channels:
+- name: stable
+  currentCSV: testoperator.v2.0.0
+`), 0644))
+	lastPoll := now
+	now = metav1.NewTime(now.Add(time.Minute))
+	require.NoError(t, poller.PollRegistryServer(catsrc))
+	require.Equal(t, &lastPoll, catsrc.Status.LatestImageRegistryPoll)
+
+	now = metav1.NewTime(now.Add(10 * time.Minute))
+	require.NoError(t, poller.PollRegistryServer(catsrc))
+	require.Equal(t, &now, catsrc.Status.LatestImageRegistryPoll)
+	require.Equal(t, now, catsrc.Status.LastSync)
+	require.Equal(t, now, catsrc.Status.RegistryServiceStatus.CreatedAt)
+	require.Equal(t, status.Port, catsrc.Status.RegistryServiceStatus.Port)
+
+	healthy, err = rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.True(t, healthy)
+
+	// A changed URL is loaded again
+	moved := catsrc.DeepCopy()
+	moved.Spec.URL = "file://" + dir
+	healthy, err = rec.CheckRegistryServer(moved)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	require.NoError(t, rec.(RegistryRemover).RemoveRegistryServer(catsrc))
+	healthy, err = rec.CheckRegistryServer(catsrc)
+	require.NoError(t, err)
+	require.False(t, healthy)
+}
+
+func TestInProcessRegistryReconcilerInvalidURL(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	factory, _ := fakeReconcilerFactory(t, stopc)
+	catsrc := validURLCatalogSource("relative/manifests")
+	rec := factory.ReconcilerForSource(catsrc)
+
+	require.EqualError(t, rec.EnsureRegistryServer(catsrc), "unsupported catalog url relative/manifests")
+	require.Nil(t, catsrc.Status.RegistryServiceStatus)
+}
+
+func TestInProcessRegistryReconcilerNotAllowed(t *testing.T) {
+	stopc := make(chan struct{})
+	defer close(stopc)
+
+	dir := copyManifests(t, inProcessManifests)
+	defer os.RemoveAll(dir)
+
+	// Local catalogs can't be loaded unless the factory allows them
+	factory, _ := fakeReconcilerFactory(t, stopc)
+	catsrc := validURLCatalogSource(dir)
+	rec := factory.ReconcilerForSource(catsrc)
+
+	require.EqualError(t, rec.EnsureRegistryServer(catsrc), "error loading catalog from "+dir+": loading local catalogs isn't allowed")
+	require.Nil(t, catsrc.Status.RegistryServiceStatus)
+}
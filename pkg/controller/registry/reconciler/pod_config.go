@@ -0,0 +1,73 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+)
+
+const (
+	// PodConfigHashAnnotationKey is the key for an annotation containing a hash of the configuration a registry pod was
+	// created with.
+	PodConfigHashAnnotationKey string = "olm.podConfigHash"
+)
+
+// podConfigHash returns a hash of the settings the given CatalogSource configures its registry pods with, or an empty
+// string if it doesn't configure them.
+func podConfigHash(source *v1alpha1.CatalogSource) string {
+	if source.Spec.RegistryPodConfig == nil && len(source.Spec.Secrets) == 0 {
+		return ""
+	}
+
+	// The number of replicas doesn't configure the pods themselves, so scaling doesn't roll them out
+	config := source.Spec.RegistryPodConfig.DeepCopy()
+	if config != nil {
+		config.Replicas = nil
+	}
+
+	// The settings are plain API types, which always marshal
+	data, _ := json.Marshal(struct {
+		Config  *v1alpha1.RegistryPodConfig
+		Secrets []string
+	}{config, source.Spec.Secrets})
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+// applyPodConfig configures the given registry pod with the settings and pull secrets of the given CatalogSource, and
+// annotates it with their hash.
+func applyPodConfig(pod *v1.Pod, source *v1alpha1.CatalogSource) {
+	for _, secret := range source.Spec.Secrets {
+		pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, v1.LocalObjectReference{Name: secret})
+	}
+
+	if source.Spec.RegistryPodConfig != nil {
+		config := source.Spec.RegistryPodConfig.DeepCopy()
+		pod.Spec.Containers[0].Resources = config.Resources
+		pod.Spec.NodeSelector = config.NodeSelector
+		if config.Tolerations != nil {
+			pod.Spec.Tolerations = config.Tolerations
+		}
+		pod.Spec.Affinity = config.Affinity
+		pod.Spec.PriorityClassName = config.PriorityClassName
+		pod.Spec.SecurityContext = config.SecurityContext
+	}
+
+	if hash := podConfigHash(source); hash != "" {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[PodConfigHashAnnotationKey] = hash
+	}
+}
+
+// podConfigCurrent returns true if the given registry pod was created with the current settings of the given
+// CatalogSource.
+func podConfigCurrent(pod *v1.Pod, source *v1alpha1.CatalogSource) bool {
+	return pod.GetAnnotations()[PodConfigHashAnnotationKey] == podConfigHash(source)
+}
@@ -0,0 +1,61 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+)
+
+func TestApplyPodConfig(t *testing.T) {
+	runAsNonRoot := true
+	config := &v1alpha1.RegistryPodConfig{
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("50Mi"),
+			},
+		},
+		NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:       []v1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}},
+		Affinity:          &v1.Affinity{NodeAffinity: &v1.NodeAffinity{}},
+		PriorityClassName: "system-cluster-critical",
+		SecurityContext:   &v1.PodSecurityContext{RunAsNonRoot: &runAsNonRoot},
+	}
+
+	unconfigured := validGrpcCatalogSource("img", "")
+	configured := validGrpcCatalogSource("img", "")
+	configured.Spec.RegistryPodConfig = config
+	configured.Spec.Secrets = []string{"pull-secret"}
+
+	// Unconfigured catalogs keep the defaults, and their pods carry no hash
+	pod := (&grpcCatalogSourceDecorator{unconfigured}).Pod()
+	require.Empty(t, pod.GetAnnotations())
+	require.Equal(t, []v1.Toleration{{Operator: v1.TolerationOpExists}}, pod.Spec.Tolerations)
+	require.True(t, podConfigCurrent(pod, unconfigured))
+	require.False(t, podConfigCurrent(pod, configured))
+
+	for _, pod := range []*v1.Pod{
+		(&grpcCatalogSourceDecorator{configured}).Pod(),
+		(&configMapCatalogSourceDecorator{configured}).Pod("configmap-server"),
+	} {
+		require.Equal(t, config.Resources, pod.Spec.Containers[0].Resources)
+		require.Equal(t, config.NodeSelector, pod.Spec.NodeSelector)
+		require.Equal(t, config.Tolerations, pod.Spec.Tolerations)
+		require.Equal(t, config.Affinity, pod.Spec.Affinity)
+		require.Equal(t, config.PriorityClassName, pod.Spec.PriorityClassName)
+		require.Equal(t, config.SecurityContext, pod.Spec.SecurityContext)
+		require.Equal(t, []v1.LocalObjectReference{{Name: "pull-secret"}}, pod.Spec.ImagePullSecrets)
+		require.True(t, podConfigCurrent(pod, configured))
+		require.False(t, podConfigCurrent(pod, unconfigured))
+	}
+
+	// Changing the settings outdates the pods created with the old ones
+	pod = (&grpcCatalogSourceDecorator{configured}).Pod()
+	changed := configured.DeepCopy()
+	changed.Spec.RegistryPodConfig.PriorityClassName = "system-node-critical"
+	require.False(t, podConfigCurrent(pod, changed))
+}
@@ -5,6 +5,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/inprocess"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
 )
@@ -14,6 +16,10 @@ type nowFunc func() metav1.Time
 const (
 	// CatalogSourceLabelKey is the key for a label containing a CatalogSource name.
 	CatalogSourceLabelKey string = "olm.catalogSource"
+
+	// CatalogSourceUpdateKey is the key for a label containing the name of a CatalogSource whose registry image is
+	// being polled for updated content.
+	CatalogSourceUpdateKey string = "olm.catalogSourceUpdate"
 )
 
 // RegistryEnsurer describes methods for ensuring a registry exists.
@@ -28,6 +34,18 @@ type RegistryChecker interface {
 	CheckRegistryServer(catalogSource *v1alpha1.CatalogSource) (healthy bool, err error)
 }
 
+// RegistryPoller describes methods for polling a registry for updated content.
+type RegistryPoller interface {
+	// PollRegistryServer polls the registry of the given CatalogSource for updated content, and rolls it out.
+	PollRegistryServer(catalogSource *v1alpha1.CatalogSource) error
+}
+
+// RegistryRemover describes methods for removing a registry that isn't cleaned up with its CatalogSource.
+type RegistryRemover interface {
+	// RemoveRegistryServer removes the registry server of the given deleted CatalogSource.
+	RemoveRegistryServer(catalogSource *v1alpha1.CatalogSource) error
+}
+
 // RegistryReconciler knows how to reconcile a registry.
 type RegistryReconciler interface {
 	RegistryChecker
@@ -42,9 +60,62 @@ type RegistryReconcilerFactory interface {
 // RegistryReconcilerFactory is a factory for RegistryReconcilers.
 type registryReconcilerFactory struct {
 	now                  nowFunc
+	healthCheck          healthCheckFunc
 	Lister               operatorlister.OperatorLister
 	OpClient             operatorclient.ClientInterface
 	ConfigMapServerImage string
+	ShardServerImage     string
+	RegistryReplicas     int32
+	inProcess            *inprocess.Server
+	loader               *inprocess.Loader
+}
+
+// RegistryReconcilerFactoryOption configures the RegistryReconcilers made by a RegistryReconcilerFactory.
+type RegistryReconcilerFactoryOption func(*registryReconcilerFactory)
+
+// WithRegistryReplicas runs the given number of replicas of each registry server in a Deployment, instead of a single
+// pod, unless its CatalogSource sets its own. Registry servers run as single pods when zero.
+func WithRegistryReplicas(replicas int32) RegistryReconcilerFactoryOption {
+	return func(factory *registryReconcilerFactory) {
+		factory.RegistryReplicas = replicas
+	}
+}
+
+// WithShardServerImage runs the registry servers of configmap CatalogSources backed by several ConfigMaps with the
+// given image, which must provide configmap-shard-server.
+func WithShardServerImage(image string) RegistryReconcilerFactoryOption {
+	return func(factory *registryReconcilerFactory) {
+		factory.ShardServerImage = image
+	}
+}
+
+// WithConnectionPool health checks registry servers over the connections in the given pool where it has them, instead
+// of dialing them for every check.
+func WithConnectionPool(pool *connection.Pool) RegistryReconcilerFactoryOption {
+	return func(factory *registryReconcilerFactory) {
+		factory.healthCheck = poolHealthCheck(pool)
+	}
+}
+
+// WithCatalogLoader loads the catalogs of CatalogSources with a URL with the given Loader, which limits the locations
+// they can be loaded from. No location is allowed by default.
+func WithCatalogLoader(loader *inprocess.Loader) RegistryReconcilerFactoryOption {
+	return func(factory *registryReconcilerFactory) {
+		factory.loader = loader
+	}
+}
+
+// WithRecordedHealth returns a RegistryReconcilerFactory making the same RegistryReconcilers as the given one, except
+// that they take the health of registry servers from the connection state recorded in the status of their
+// CatalogSources when they were last synced, instead of health checking them. Other factories are returned as is.
+func WithRecordedHealth(factory RegistryReconcilerFactory) RegistryReconcilerFactory {
+	f, ok := factory.(*registryReconcilerFactory)
+	if !ok {
+		return factory
+	}
+	recorded := *f
+	recorded.healthCheck = recordedHealthCheck
+	return &recorded
 }
 
 // ReconcilerForSource returns a RegistryReconciler based on the configuration of the given CatalogSource.
@@ -53,21 +124,33 @@ func (r *registryReconcilerFactory) ReconcilerForSource(source *v1alpha1.Catalog
 	switch source.Spec.SourceType {
 	case v1alpha1.SourceTypeInternal, v1alpha1.SourceTypeConfigmap:
 		return &ConfigMapRegistryReconciler{
-			now:      r.now,
-			Lister:   r.Lister,
-			OpClient: r.OpClient,
-			Image:    r.ConfigMapServerImage,
+			now:        r.now,
+			Lister:     r.Lister,
+			OpClient:   r.OpClient,
+			Image:      r.ConfigMapServerImage,
+			ShardImage: r.ShardServerImage,
+			Replicas:   r.RegistryReplicas,
+		}
+	case v1alpha1.SourceTypeURL:
+		return &InProcessRegistryReconciler{
+			now:         r.now,
+			healthCheck: r.healthCheck,
+			Server:      r.inProcess,
+			Loader:      r.loader,
 		}
 	case v1alpha1.SourceTypeGrpc:
 		if source.Spec.Image != "" {
 			return &GrpcRegistryReconciler{
-				now:      r.now,
-				Lister:   r.Lister,
-				OpClient: r.OpClient,
+				now:         r.now,
+				healthCheck: r.healthCheck,
+				Lister:      r.Lister,
+				OpClient:    r.OpClient,
+				Replicas:    r.RegistryReplicas,
 			}
 		} else if source.Spec.Address != "" {
 			return &GrpcAddressRegistryReconciler{
-				now: r.now,
+				now:         r.now,
+				healthCheck: r.healthCheck,
 			}
 		}
 	}
@@ -75,11 +158,18 @@ func (r *registryReconcilerFactory) ReconcilerForSource(source *v1alpha1.Catalog
 }
 
 // NewRegistryReconcilerFactory returns an initialized RegistryReconcilerFactory.
-func NewRegistryReconcilerFactory(lister operatorlister.OperatorLister, opClient operatorclient.ClientInterface, configMapServerImage string, now nowFunc) RegistryReconcilerFactory {
-	return &registryReconcilerFactory{
+func NewRegistryReconcilerFactory(lister operatorlister.OperatorLister, opClient operatorclient.ClientInterface, configMapServerImage string, now nowFunc, options ...RegistryReconcilerFactoryOption) RegistryReconcilerFactory {
+	factory := &registryReconcilerFactory{
 		now:                  now,
+		healthCheck:          grpcHealthCheck,
 		Lister:               lister,
 		OpClient:             opClient,
 		ConfigMapServerImage: configMapServerImage,
+		inProcess:            inprocess.NewServer(),
+		loader:               &inprocess.Loader{},
+	}
+	for _, option := range options {
+		option(factory)
 	}
+	return factory
 }
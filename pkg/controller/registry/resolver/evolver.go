@@ -1,55 +1,90 @@
 package resolver
 
 import (
+	"context"
+	"sort"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
 	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/pkg/errors"
-)
 
-// TODO: this should take a cancellable context for killing long resolution
-// TODO: return a set of errors or warnings of unusual states to know about (we expect evolve to always succeed, because it can be a no-op)
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+)
 
 // Evolvers modify a generation to a new state
 type Evolver interface {
-	Evolve(add map[OperatorSourceInfo]struct{}) error
+	// Evolve returns a ResolutionError listing every problem found if the requested operators can't be added.
+	// It stops and returns the context's error as soon as the context is done.
+	Evolve(ctx context.Context, add map[OperatorSourceInfo]struct{}) error
+	// Downgraded returns the operators that were removed from the generation because their required apis couldn't be satisfied
+	Downgraded() OperatorSet
+	// Problems returns the problems found while evolving, including those that didn't cause Evolve to fail
+	Problems() []Problem
 }
 
 type NamespaceGenerationEvolver struct {
-	querier SourceQuerier
-	gen     Generation
+	querier    SourceQuerier
+	gen        Generation
+	downgraded OperatorSet
+	problems   []Problem
 }
 
 func NewNamespaceGenerationEvolver(querier SourceQuerier, gen Generation) Evolver {
-	return &NamespaceGenerationEvolver{querier: querier, gen: gen}
+	return &NamespaceGenerationEvolver{querier: querier, gen: gen, downgraded: EmptyOperatorSet()}
 }
 
 // Evolve takes new requested operators, adds them to the generation, and attempts to resolve dependencies with querier
-func (e *NamespaceGenerationEvolver) Evolve(add map[OperatorSourceInfo]struct{}) error {
+func (e *NamespaceGenerationEvolver) Evolve(ctx context.Context, add map[OperatorSourceInfo]struct{}) error {
 	if err := e.querier.Queryable(); err != nil {
 		return err
 	}
 
 	// check for updates to existing operators
-	if err := e.checkForUpdates(); err != nil {
+	if err := e.checkForUpdates(ctx); err != nil {
 		return err
 	}
 
 	// fetch bundles for new operators (aren't yet tracked)
-	if err := e.addNewOperators(add); err != nil {
+	if err := e.addNewOperators(ctx, add); err != nil {
 		return err
 	}
 
-	// attempt to resolve any missing apis as a result expanding the generation of operators
-	if err := e.queryForRequiredAPIs(); err != nil {
-		return err
+	// attempt to resolve any missing packages and apis as a result expanding the generation of operators
+	// an operator added for one kind of dependency may bring in the other, so repeat until the generation stops growing
+	for {
+		size := len(e.gen.Operators())
+		if err := e.queryForRequiredPackages(ctx); err != nil {
+			return err
+		}
+		if err := e.queryForRequiredAPIs(ctx); err != nil {
+			return err
+		}
+		if len(e.gen.Operators()) == size {
+			break
+		}
 	}
 
-	// for any remaining missing APIs, attempt to downgrade the operator that required them
+	// for any remaining missing APIs and packages, attempt to downgrade the operators that required them
 	// this may contract the generation back to the original set!
 	e.downgradeAPIs()
+	e.downgradePackages()
 	return nil
 }
 
-func (e *NamespaceGenerationEvolver) checkForUpdates() error {
+func (e *NamespaceGenerationEvolver) Downgraded() OperatorSet {
+	return e.downgraded
+}
+
+func (e *NamespaceGenerationEvolver) Problems() []Problem {
+	return e.problems
+}
+
+// fail returns a ResolutionError for the problems found so far
+func (e *NamespaceGenerationEvolver) fail() error {
+	return ResolutionError{Problems: e.problems}
+}
+
+func (e *NamespaceGenerationEvolver) checkForUpdates(ctx context.Context) error {
 	// take a snapshot of the current generation so that we don't update the same operator twice in one resolution
 	for _, op := range e.gen.Operators().Snapshot() {
 		// only check for updates if we have sourceinfo
@@ -57,7 +92,10 @@ func (e *NamespaceGenerationEvolver) checkForUpdates() error {
 			continue
 		}
 
-		bundle, key, err := e.querier.FindReplacement(op.Version(), op.Identifier(), op.SourceInfo().Package, op.SourceInfo().Channel, op.SourceInfo().Catalog)
+		bundle, key, err := e.querier.FindReplacement(ctx, op.Version(), op.Identifier(), op.SourceInfo().Package, op.SourceInfo().Channel, op.SourceInfo().VersionRange, op.SourceInfo().Catalog)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil || bundle == nil {
 			continue
 		}
@@ -66,7 +104,12 @@ func (e *NamespaceGenerationEvolver) checkForUpdates() error {
 		if err != nil {
 			return errors.Wrap(err, "error parsing bundle")
 		}
+		o.sourceInfo.VersionRange = op.SourceInfo().VersionRange
 		if err := e.gen.AddOperator(o); err != nil {
+			if conflict, ok := err.(APIConflictError); ok {
+				e.problems = append(e.problems, newConflictProblem(conflict))
+				return e.fail()
+			}
 			return errors.Wrap(err, "error calculating generation changes due to new bundle")
 		}
 		e.gen.RemoveOperator(op)
@@ -74,35 +117,80 @@ func (e *NamespaceGenerationEvolver) checkForUpdates() error {
 	return nil
 }
 
-func (e *NamespaceGenerationEvolver) addNewOperators(add map[OperatorSourceInfo]struct{}) error {
+func (e *NamespaceGenerationEvolver) addNewOperators(ctx context.Context, add map[OperatorSourceInfo]struct{}) error {
+	failed := false
 	for s := range add {
+		r, err := parseVersionRange(s.VersionRange)
+		if err != nil {
+			e.problems = append(e.problems, newInvalidVersionRangeProblem(s, err))
+			failed = true
+			continue
+		}
+
 		var bundle *opregistry.Bundle
 		var key *CatalogKey
-		var err error
 		if s.StartingCSV != "" {
-			bundle, key, err = e.querier.FindBundle(s.Package, s.Channel, s.StartingCSV, s.Catalog)
+			bundle, key, err = e.querier.FindBundle(ctx, s.Package, s.Channel, s.StartingCSV, s.Catalog)
+			if err == nil {
+				err = checkVersionRange(bundle, s.VersionRange, r)
+			}
 		} else {
-			bundle, key, err = e.querier.FindLatestBundle(s.Package, s.Channel, s.Catalog)
+			bundle, key, err = e.querier.FindLatestBundle(ctx, s.Package, s.Channel, s.VersionRange, s.Catalog)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if rangeErr, ok := err.(VersionNotInRangeError); ok {
+			e.problems = append(e.problems, newNotInRangeProblem(s, rangeErr))
+			failed = true
+			continue
 		}
 		if err != nil {
-			// TODO: log or collect warnings
-			return errors.Wrapf(err, "%s not found", s)
+			// keep looking up the remaining operators so that every missing one is reported
+			e.problems = append(e.problems, newLookupProblem(classifyMissing(ctx, e.querier, s), s, err))
+			failed = true
+			continue
 		}
 
 		o, err := NewOperatorFromBundle(bundle, "", s.StartingCSV, *key)
 		if err != nil {
 			return errors.Wrap(err, "error parsing bundle")
 		}
+		o.sourceInfo.VersionRange = s.VersionRange
 		if err := e.gen.AddOperator(o); err != nil {
-			if err != nil {
-				return errors.Wrap(err, "error calculating generation changes due to new bundle")
+			if conflict, ok := err.(APIConflictError); ok {
+				e.problems = append(e.problems, newConflictProblem(conflict))
+				failed = true
+				continue
 			}
+			return errors.Wrap(err, "error calculating generation changes due to new bundle")
 		}
 	}
+	if failed {
+		return e.fail()
+	}
 	return nil
 }
 
-func (e *NamespaceGenerationEvolver) queryForRequiredAPIs() error {
+// classifyMissing determines why the bundle for a requested operator couldn't be found
+func classifyMissing(ctx context.Context, querier SourceQuerier, s OperatorSourceInfo) ProblemType {
+	pkg, _, err := querier.FindPackage(ctx, s.Package, s.Catalog)
+	if _, ok := err.(PackageNotFoundError); ok {
+		return PackageNotFound
+	}
+	if err != nil {
+		// the sources can't tell us anything more specific
+		return BundleNotFound
+	}
+	for _, c := range pkg.GetChannels() {
+		if c.GetName() == s.Channel {
+			return BundleNotFound
+		}
+	}
+	return ChannelNotFound
+}
+
+func (e *NamespaceGenerationEvolver) queryForRequiredAPIs(ctx context.Context) error {
 	e.gen.ResetUnchecked()
 
 	for {
@@ -112,14 +200,23 @@ func (e *NamespaceGenerationEvolver) queryForRequiredAPIs() error {
 		}
 		e.gen.MarkAPIChecked(*api)
 
-		// attempt to find a bundle that provides that api
-		if bundle, key, err := e.querier.FindProvider(*api); err == nil {
+		// attempt to find a bundle that provides that api, preferring the catalog of an operator that requires it
+		bundle, key, err := e.querier.FindProvider(ctx, *api, e.requirerCatalog(*api))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// a missing provider can't be told apart from one we didn't get to ask
+			return ctxErr
+		}
+		if err == nil {
 			// add a bundle that provides the api to the generation
 			o, err := NewOperatorFromBundle(bundle, "", "", *key)
 			if err != nil {
 				return errors.Wrap(err, "error parsing bundle")
 			}
 			if err := e.gen.AddOperator(o); err != nil {
+				if conflict, ok := err.(APIConflictError); ok {
+					e.problems = append(e.problems, newConflictProblem(conflict))
+					return e.fail()
+				}
 				return errors.Wrap(err, "error calculating generation changes due to new bundle")
 			}
 		}
@@ -127,12 +224,196 @@ func (e *NamespaceGenerationEvolver) queryForRequiredAPIs() error {
 	return nil
 }
 
+// queryForRequiredPackages adds the latest bundle in range from the default channel of every required package that isn't in the generation yet
+func (e *NamespaceGenerationEvolver) queryForRequiredPackages(ctx context.Context) error {
+	for _, missing := range e.missingPackages() {
+		dep := missing.dependency
+
+		// only one operator from a package can be installed in a namespace, so one that's already there but out of range
+		// can't be helped by another bundle from the same package
+		if e.packageProvider(dep.PackageName) != nil {
+			continue
+		}
+		if _, err := parseVersionRange(dep.VersionRange); err != nil {
+			continue
+		}
+
+		// prefer the catalog of an operator that requires the package, but fall back to every other catalog
+		pkg, key, err := e.querier.FindPackage(ctx, dep.PackageName, firstCatalog(missing.requirers))
+		if err != nil && ctx.Err() == nil {
+			pkg, key, err = e.querier.FindPackage(ctx, dep.PackageName, CatalogKey{})
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			continue
+		}
+
+		bundle, key, err := e.querier.FindLatestBundle(ctx, dep.PackageName, defaultChannel(pkg), dep.VersionRange, *key)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			continue
+		}
+
+		// add a bundle from the required package to the generation
+		o, err := NewOperatorFromBundle(bundle, "", "", *key)
+		if err != nil {
+			return errors.Wrap(err, "error parsing bundle")
+		}
+		o.sourceInfo.VersionRange = dep.VersionRange
+		if err := e.gen.AddOperator(o); err != nil {
+			if conflict, ok := err.(APIConflictError); ok {
+				e.problems = append(e.problems, newConflictProblem(conflict))
+				return e.fail()
+			}
+			return errors.Wrap(err, "error calculating generation changes due to new bundle")
+		}
+	}
+	return nil
+}
+
 func (e *NamespaceGenerationEvolver) downgradeAPIs() {
 	e.gen.ResetUnchecked()
 	for missingAPIs := e.gen.MissingAPIs(); len(missingAPIs) > 0; {
-		requirers := missingAPIs.PopAPIRequirers()
-		for _, op := range requirers {
+		api := missingAPIs.PopAPIKey()
+		requirers := e.requirers(*api)
+		if len(requirers) == 0 {
+			continue
+		}
+		e.problems = append(e.problems, newUnsatisfiableProblem(*api, requirers))
+		for name, op := range requirers {
 			e.gen.RemoveOperator(op)
+			e.downgraded[name] = op
+			e.problems = append(e.problems, newRemovedProblem(op, *api))
+		}
+	}
+}
+
+// requirerCatalog returns the catalog of the first operator, by name, that requires the given api and came from a catalog.
+// An empty key is returned if there is no such operator.
+func (e *NamespaceGenerationEvolver) requirerCatalog(api opregistry.APIKey) CatalogKey {
+	return firstCatalog(e.requirers(api))
+}
+
+// firstCatalog returns the catalog of the first operator in the set, by name, that came from a catalog.
+// An empty key is returned if there is no such operator.
+func firstCatalog(requirers OperatorSet) CatalogKey {
+	names := make([]string, 0, len(requirers))
+	for name := range requirers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if info := requirers[name].SourceInfo(); info != nil && info.Catalog.Name != "" {
+			return info.Catalog
+		}
+	}
+	return CatalogKey{}
+}
+
+// requirers returns the operators in the generation that require the given api
+func (e *NamespaceGenerationEvolver) requirers(api opregistry.APIKey) OperatorSet {
+	requirers := EmptyOperatorSet()
+	for name, op := range e.gen.Operators() {
+		if _, ok := op.RequiredAPIs()[api]; ok {
+			requirers[name] = op
+		}
+	}
+	return requirers
+}
+
+// downgradePackages removes the operators whose required packages aren't satisfied by the generation.
+// Removing an operator can leave the operators that required its package unsatisfied in turn, so repeat until none are left.
+func (e *NamespaceGenerationEvolver) downgradePackages() {
+	for missing := e.missingPackages(); len(missing) > 0; missing = e.missingPackages() {
+		for _, m := range missing {
+			removed := EmptyOperatorSet()
+			for name, op := range m.requirers {
+				// skip operators already removed for another missing package
+				if _, ok := e.gen.Operators()[name]; ok {
+					removed[name] = op
+				}
+			}
+			if len(removed) == 0 {
+				continue
+			}
+			e.problems = append(e.problems, newUnsatisfiablePackageProblem(m.dependency, removed))
+			for name, op := range removed {
+				e.gen.RemoveOperator(op)
+				e.downgraded[name] = op
+				e.problems = append(e.problems, newRemovedForPackageProblem(op, m.dependency))
+			}
+		}
+	}
+}
+
+// packageRequirement is a required package along with the operators in the generation that require it
+type packageRequirement struct {
+	dependency v1alpha1.PackageDependency
+	requirers  OperatorSet
+}
+
+// missingPackages returns the required packages that no operator in the generation satisfies, sorted by package and version range
+func (e *NamespaceGenerationEvolver) missingPackages() []packageRequirement {
+	requirers := map[v1alpha1.PackageDependency]OperatorSet{}
+	for name, op := range e.gen.Operators() {
+		for _, dep := range op.RequiredPackages() {
+			if e.packageSatisfied(dep) {
+				continue
+			}
+			if _, ok := requirers[dep]; !ok {
+				requirers[dep] = EmptyOperatorSet()
+			}
+			requirers[dep][name] = op
 		}
 	}
+
+	missing := make([]packageRequirement, 0, len(requirers))
+	for dep, ops := range requirers {
+		missing = append(missing, packageRequirement{dependency: dep, requirers: ops})
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].dependency.PackageName != missing[j].dependency.PackageName {
+			return missing[i].dependency.PackageName < missing[j].dependency.PackageName
+		}
+		return missing[i].dependency.VersionRange < missing[j].dependency.VersionRange
+	})
+	return missing
+}
+
+// packageSatisfied returns true if an operator from the required package, within its version range, is in the generation
+func (e *NamespaceGenerationEvolver) packageSatisfied(dep v1alpha1.PackageDependency) bool {
+	provider := e.packageProvider(dep.PackageName)
+	if provider == nil {
+		return false
+	}
+	r, err := parseVersionRange(dep.VersionRange)
+	if err != nil {
+		return false
+	}
+	return r == nil || r(*provider.Version())
+}
+
+// packageProvider returns the operator in the generation that was installed from the given package, if any.
+func (e *NamespaceGenerationEvolver) packageProvider(pkg string) OperatorSurface {
+	for _, op := range e.gen.Operators() {
+		if op.Package() == pkg {
+			return op
+		}
+	}
+	return nil
+}
+
+// defaultChannel returns the channel a package dependency is resolved from
+func defaultChannel(pkg *api.Package) string {
+	if name := pkg.GetDefaultChannelName(); name != "" {
+		return name
+	}
+	if channels := pkg.GetChannels(); len(channels) > 0 {
+		return channels[0].GetName()
+	}
+	return ""
 }
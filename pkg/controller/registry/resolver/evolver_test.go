@@ -1,11 +1,19 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
 	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver/fakes"
+	pkgfakes "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/client/fakes"
 )
 
 func TestNamespaceGenerationEvolver(t *testing.T) {
@@ -127,7 +135,7 @@ func TestNamespaceGenerationEvolver(t *testing.T) {
 			wantGen: NewGenerationFromOperators(
 				NewFakeOperatorSurface("op1", "pkgA", "c", "", "s", "", []opregistry.APIKey{{"g", "v", "k", "ks"}}, nil, nil, nil),
 			),
-			wantErr: fmt.Errorf("{nothing channel nothing.v1 {catsrc catsrc-namespace}} not found: no bundle found"),
+			wantErr: fmt.Errorf("nothing.v1 (nothing/channel in catsrc/catsrc-namespace) not found: no bundle found"),
 		},
 		{
 			// the incoming subscription requires apis that can't be found
@@ -414,7 +422,7 @@ func TestNamespaceGenerationEvolver(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := NewNamespaceGenerationEvolver(tt.fields.querier, tt.fields.gen)
-			err := e.Evolve(tt.args.add)
+			err := e.Evolve(context.TODO(), tt.args.add)
 			if tt.wantErr != nil {
 				require.EqualError(t, tt.wantErr, err.Error())
 			} else {
@@ -425,3 +433,371 @@ func TestNamespaceGenerationEvolver(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceGenerationEvolverProblems(t *testing.T) {
+	catalog := CatalogKey{"catsrc", "catsrc-namespace"}
+	key := opregistry.APIKey{"g", "v", "k", "ks"}
+
+	// a registry that only knows about package "a" with channel "alpha", and serves no bundles at all
+	registry := &pkgfakes.FakeRegistryClient{}
+	registry.GetPackageStub = func(ctx context.Context, req *api.GetPackageRequest, opts ...grpc.CallOption) (*api.Package, error) {
+		if req.GetName() != "a" {
+			return nil, fmt.Errorf("package %s not found", req.GetName())
+		}
+		return &api.Package{Name: "a", Channels: []*api.Channel{{Name: "alpha", CsvName: "a.v1"}}}, nil
+	}
+	registry.GetBundleReturns(nil, fmt.Errorf("no bundle found"))
+	registry.GetBundleForChannelReturns(nil, fmt.Errorf("no bundle found"))
+	missingQuerier := NewNamespaceSourceQuerier(map[CatalogKey]SourceClient{
+		catalog: &RegistryClient{Client: &client.Client{Registry: registry}},
+	})
+
+	// a source that can't be reached, so it can't tell whether it serves a package
+	unavailable := &fakes.FakeSourceClient{}
+	unavailable.GetPackageReturns(nil, fmt.Errorf("connection refused"))
+	unavailable.GetBundleInPackageChannelReturns(nil, fmt.Errorf("connection refused"))
+	unavailableQuerier := NewNamespaceSourceQuerier(map[CatalogKey]SourceClient{
+		catalog: unavailable,
+	})
+
+	tests := []struct {
+		name         string
+		querier      SourceQuerier
+		gen          Generation
+		add          map[OperatorSourceInfo]struct{}
+		wantFailed   bool
+		wantProblems map[ProblemType][]string
+	}{
+		{
+			name:    "MissingPackage",
+			querier: missingQuerier,
+			gen:     NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "b", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantFailed: true,
+			wantProblems: map[ProblemType][]string{
+				PackageNotFound: nil,
+			},
+		},
+		{
+			name:    "MissingChannel",
+			querier: missingQuerier,
+			gen:     NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "beta", Catalog: catalog}: {},
+			},
+			wantFailed: true,
+			wantProblems: map[ProblemType][]string{
+				ChannelNotFound: nil,
+			},
+		},
+		{
+			name:    "MissingBundle",
+			querier: missingQuerier,
+			gen:     NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", StartingCSV: "a.v0", Catalog: catalog}: {},
+			},
+			wantFailed: true,
+			wantProblems: map[ProblemType][]string{
+				BundleNotFound: nil,
+			},
+		},
+		{
+			name:    "MissingSourcesUnavailable",
+			querier: unavailableQuerier,
+			gen:     NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantFailed: true,
+			wantProblems: map[ProblemType][]string{
+				BundleNotFound: nil,
+			},
+		},
+		{
+			name: "AllMissingReported",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+				{Package: "b", Channel: "beta", Catalog: catalog}:  {},
+			},
+			wantFailed: true,
+			wantProblems: map[ProblemType][]string{
+				PackageNotFound: nil,
+			},
+		},
+		{
+			name: "CompetingProviders",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("b.v1", "b", "beta", "", APISet{key: {}}, nil, nil, nil),
+				},
+			}),
+			gen: NewGenerationFromOperators(
+				NewFakeOperatorSurface("a.v1", "a", "alpha", "", "catsrc", "", []opregistry.APIKey{key}, nil, nil, nil),
+			),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "b", Channel: "beta", Catalog: catalog}: {},
+			},
+			wantFailed: true,
+			wantProblems: map[ProblemType][]string{
+				CompetingProviders: {"a.v1", "b.v1"},
+			},
+		},
+		{
+			name: "UnsatisfiableRequiredAPI",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("b.v1", "b", "beta", "", nil, APISet{key: {}}, nil, nil),
+				},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "b", Channel: "beta", Catalog: catalog}: {},
+			},
+			wantProblems: map[ProblemType][]string{
+				RequiredAPINotSatisfiable:     {"b.v1"},
+				RemovedDueToMissingDependency: {"b.v1"},
+			},
+		},
+		{
+			name: "UnsatisfiableRequiredPackage",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(bundle("b.v1", "b", "beta", "", nil, nil, nil, nil), v1alpha1.PackageDependency{PackageName: "c"}),
+				},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "b", Channel: "beta", Catalog: catalog}: {},
+			},
+			wantProblems: map[ProblemType][]string{
+				RequiredPackageNotSatisfiable: {"b.v1"},
+				RemovedDueToMissingDependency: {"b.v1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewNamespaceGenerationEvolver(tt.querier, tt.gen)
+			err := e.Evolve(context.TODO(), tt.add)
+			if tt.wantFailed {
+				require.IsType(t, ResolutionError{}, err)
+				require.Equal(t, e.Problems(), err.(ResolutionError).Problems)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, e.Problems(), len(tt.add)*len(tt.wantProblems))
+			for _, p := range e.Problems() {
+				operators, ok := tt.wantProblems[p.Type]
+				require.True(t, ok, "unexpected problem %s", p)
+				require.Equal(t, operators, p.Operators)
+				require.NotEmpty(t, p.Message)
+			}
+		})
+	}
+}
+
+func TestNamespaceGenerationEvolverRequiredPackages(t *testing.T) {
+	catalog := CatalogKey{"catsrc", "catsrc-namespace"}
+	other := CatalogKey{"other", "catsrc-namespace"}
+	key := opregistry.APIKey{"g", "v", "k", "ks"}
+	requiresB := v1alpha1.PackageDependency{PackageName: "b", VersionRange: ">=1.0.0 <2.0.0"}
+
+	// an operator from package b, at version 0.0.0, that was installed without a subscription of its own
+	unsubscribedB := csv("b.v0", "", nil, nil, nil, nil, nil, nil)
+	unsubscribedB.SetAnnotations(map[string]string{v1alpha1.PackageAnnotationKey: "b"})
+	unsubscribedGen, err := NewGenerationFromCluster([]*v1alpha1.ClusterServiceVersion{unsubscribedB}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		bundles        map[CatalogKey][]*opregistry.Bundle
+		gen            Generation
+		add            map[OperatorSourceInfo]struct{}
+		wantOperators  map[string]OperatorSourceInfo
+		wantDowngraded []string
+		wantProblems   []ProblemType
+	}{
+		{
+			name: "ResolvesLatestInRangeFromDefaultChannel",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), requiresB),
+					withVersion(bundle("b.v2", "b", "stable", "b.v1", nil, nil, nil, nil), "2.0.0"),
+					withVersion(bundle("b.v1", "b", "stable", "", nil, nil, nil, nil), "1.0.0"),
+					withVersion(bundle("b.v3", "b", "beta", "", nil, nil, nil, nil), "1.5.0"),
+				},
+			},
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"b.v1": {Package: "b", Channel: "stable", Catalog: catalog, VersionRange: requiresB.VersionRange},
+			},
+		},
+		{
+			name: "ResolvesFromOtherCatalog",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), requiresB),
+				},
+				other: {
+					withVersion(bundle("b.v1", "b", "stable", "", nil, nil, nil, nil), "1.0.0"),
+				},
+			},
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"b.v1": {Package: "b", Channel: "stable", Catalog: other, VersionRange: requiresB.VersionRange},
+			},
+		},
+		{
+			name: "RequiredAPIOfRequiredPackage",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), requiresB),
+					withVersion(bundle("b.v1", "b", "stable", "", nil, APISet{key: {}}, nil, nil), "1.0.0"),
+					bundle("c.v1", "c", "stable", "", APISet{key: {}}, nil, nil, nil),
+				},
+			},
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"b.v1": {Package: "b", Channel: "stable", Catalog: catalog, VersionRange: requiresB.VersionRange},
+				"c.v1": {Package: "c", Channel: "stable", Catalog: catalog},
+			},
+		},
+		{
+			name: "AlreadyInstalled",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), v1alpha1.PackageDependency{PackageName: "b"}),
+				},
+			},
+			gen: NewGenerationFromOperators(
+				NewFakeOperatorSurface("b.v1", "b", "stable", "", "catsrc", "", nil, nil, nil, nil),
+			),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"b.v1": {Package: "b", Channel: "stable", Catalog: catalog},
+			},
+		},
+		{
+			name: "InstalledOutOfRange/Downgraded",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), requiresB),
+					withVersion(bundle("b.v1", "b", "stable", "", nil, nil, nil, nil), "1.0.0"),
+				},
+			},
+			gen: NewGenerationFromOperators(
+				// fake operators have version 0.0.0
+				NewFakeOperatorSurface("b.v0", "b", "stable", "", "catsrc", "", nil, nil, nil, nil),
+			),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"b.v0": {Package: "b", Channel: "stable", Catalog: catalog},
+			},
+			wantDowngraded: []string{"a.v1"},
+			wantProblems:   []ProblemType{RequiredPackageNotSatisfiable, RemovedDueToMissingDependency},
+		},
+		{
+			name: "InstalledWithoutSubscriptionOutOfRange/Downgraded",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), requiresB),
+					withVersion(bundle("b.v1", "b", "stable", "", nil, nil, nil, nil), "1.0.0"),
+				},
+			},
+			gen: unsubscribedGen,
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"b.v0": ExistingOperator,
+			},
+			wantDowngraded: []string{"a.v1"},
+			wantProblems:   []ProblemType{RequiredPackageNotSatisfiable, RemovedDueToMissingDependency},
+		},
+		{
+			name: "NoneInRange/Downgraded",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), requiresB),
+					withVersion(bundle("b.v2", "b", "stable", "", nil, nil, nil, nil), "2.0.0"),
+				},
+			},
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators:  map[string]OperatorSourceInfo{},
+			wantDowngraded: []string{"a.v1"},
+			wantProblems:   []ProblemType{RequiredPackageNotSatisfiable, RemovedDueToMissingDependency},
+		},
+		{
+			name: "Transitive/Downgraded",
+			bundles: map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), v1alpha1.PackageDependency{PackageName: "b"}),
+					withRequiredPackages(withVersion(bundle("b.v1", "b", "stable", "", nil, nil, nil, nil), "1.0.0"), v1alpha1.PackageDependency{PackageName: "c"}),
+				},
+			},
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators:  map[string]OperatorSourceInfo{},
+			wantDowngraded: []string{"a.v1", "b.v1"},
+			wantProblems: []ProblemType{
+				RequiredPackageNotSatisfiable, RemovedDueToMissingDependency,
+				RequiredPackageNotSatisfiable, RemovedDueToMissingDependency,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewNamespaceGenerationEvolver(NewFakeRegistrySourceQuerier(tt.bundles), tt.gen)
+			require.NoError(t, e.Evolve(context.TODO(), tt.add))
+
+			operators := map[string]OperatorSourceInfo{}
+			for name, op := range tt.gen.Operators() {
+				operators[name] = *op.SourceInfo()
+			}
+			require.Equal(t, tt.wantOperators, operators)
+
+			var downgraded []string
+			for name := range e.Downgraded() {
+				downgraded = append(downgraded, name)
+			}
+			require.ElementsMatch(t, tt.wantDowngraded, downgraded)
+
+			var problems []ProblemType
+			for _, p := range e.Problems() {
+				problems = append(problems, p.Type)
+				require.NotEmpty(t, p.Message)
+			}
+			require.ElementsMatch(t, tt.wantProblems, problems)
+		})
+	}
+}
@@ -27,6 +27,17 @@ type NamespaceGeneration struct {
 	operators     OperatorSet
 }
 
+// APIConflictError is returned when an operator is added to a generation that already has a different provider for one of its apis.
+type APIConflictError struct {
+	API         registry.APIKey
+	Provider    string
+	Conflicting string
+}
+
+func (e APIConflictError) Error() string {
+	return fmt.Sprintf("%v already provided by %s", e.API, e.Provider)
+}
+
 func NewEmptyGeneration() *NamespaceGeneration {
 	return &NamespaceGeneration{
 		providedAPIs:  EmptyAPIOwnerSet(),
@@ -55,9 +66,10 @@ func NewGenerationFromCluster(csvs []*v1alpha1.ClusterServiceVersion, subs []*v1
 		if sub, ok := subMap[op.Identifier()]; ok {
 			// No need to enable starting csv search since a csv already exists.
 			op.sourceInfo = &OperatorSourceInfo{
-				Package: sub.Spec.Package,
-				Channel: sub.Spec.Channel,
-				Catalog: CatalogKey{Name: sub.Spec.CatalogSource, Namespace: sub.Spec.CatalogSourceNamespace},
+				Package:      sub.Spec.Package,
+				Channel:      sub.Spec.Channel,
+				Catalog:      CatalogKey{Name: sub.Spec.CatalogSource, Namespace: sub.GetCatalogSourceNamespace()},
+				VersionRange: sub.Spec.VersionRange,
 			}
 		}
 		if err := g.AddOperator(op); err != nil {
@@ -71,7 +83,7 @@ func (g *NamespaceGeneration) AddOperator(o OperatorSurface) error {
 	// add provided apis, error if two owners (that isn't a replacement)
 	for api := range o.ProvidedAPIs() {
 		if provider, ok := g.providedAPIs[api]; ok && provider.Identifier() != o.Identifier() && o.Replaces() != provider.Identifier() {
-			return fmt.Errorf("%v already provided by %s", api, provider.Identifier())
+			return APIConflictError{API: api, Provider: provider.Identifier(), Conflicting: o.Identifier()}
 		}
 		g.providedAPIs[api] = o
 
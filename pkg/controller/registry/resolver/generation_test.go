@@ -1,7 +1,6 @@
 package resolver
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/blang/semver"
@@ -394,7 +393,7 @@ func TestNamespaceGeneration_AddOperator(t *testing.T) {
 			},
 			wantMissingAPIs:   EmptyAPIMultiOwnerSet(),
 			wantUncheckedAPIs: EmptyAPISet(),
-			wantErr:           fmt.Errorf("g/v/k (ks) already provided by existing"),
+			wantErr:           APIConflictError{API: opregistry.APIKey{Group: "g", Version: "v", Kind: "k", Plural: "ks"}, Provider: "existing", Conflicting: "new"},
 		},
 		{
 			name: "SatisfyWantedAPI",
@@ -203,33 +203,42 @@ type OperatorSourceInfo struct {
 	Channel     string
 	StartingCSV string
 	Catalog     CatalogKey
+
+	// VersionRange constrains the versions the operator may be installed or updated to. Empty means unconstrained.
+	VersionRange string
 }
 
 func (i *OperatorSourceInfo) String() string {
 	return fmt.Sprintf("%s/%s in %s/%s", i.Package, i.Channel, i.Catalog.Name, i.Catalog.Namespace)
 }
 
-var ExistingOperator = OperatorSourceInfo{"", "", "", CatalogKey{"", ""}}
+var ExistingOperator = OperatorSourceInfo{"", "", "", CatalogKey{"", ""}, ""}
 
 // OperatorSurface describes the API surfaces provided and required by an Operator.
 type OperatorSurface interface {
 	ProvidedAPIs() APISet
 	RequiredAPIs() APISet
+	RequiredPackages() []v1alpha1.PackageDependency
 	Identifier() string
 	Replaces() string
 	Version() *semver.Version
+	Package() string
 	SourceInfo() *OperatorSourceInfo
 	Bundle() *opregistry.Bundle
 }
 
 type Operator struct {
-	name         string
-	replaces     string
-	providedAPIs APISet
-	requiredAPIs APISet
-	version      *semver.Version
-	bundle       *opregistry.Bundle
-	sourceInfo   *OperatorSourceInfo
+	name             string
+	replaces         string
+	providedAPIs     APISet
+	requiredAPIs     APISet
+	requiredPackages []v1alpha1.PackageDependency
+	version          *semver.Version
+	bundle           *opregistry.Bundle
+	sourceInfo       *OperatorSourceInfo
+
+	// pkg is the package an installed operator recorded that it came from, if it didn't come from a subscription.
+	pkg string
 }
 
 var _ OperatorSurface = &Operator{}
@@ -252,12 +261,13 @@ func NewOperatorFromBundle(bundle *opregistry.Bundle, replaces string, startingC
 		r = csv.Spec.Replaces
 	}
 	return &Operator{
-		name:         csv.GetName(),
-		replaces:     r,
-		version:      &csv.Spec.Version.Version,
-		providedAPIs: providedAPIs,
-		requiredAPIs: requiredAPIs,
-		bundle:       bundle,
+		name:             csv.GetName(),
+		replaces:         r,
+		version:          &csv.Spec.Version.Version,
+		providedAPIs:     providedAPIs,
+		requiredAPIs:     requiredAPIs,
+		requiredPackages: csv.Spec.RequiredPackages,
+		bundle:           bundle,
 		sourceInfo: &OperatorSourceInfo{
 			Package:     bundle.Package,
 			Channel:     bundle.Channel,
@@ -293,12 +303,14 @@ func NewOperatorFromV1Alpha1CSV(csv *v1alpha1.ClusterServiceVersion) (*Operator,
 	}
 
 	return &Operator{
-		name:         csv.GetName(),
-		version:      &csv.Spec.Version.Version,
-		replaces:     csv.Spec.Replaces,
-		providedAPIs: providedAPIs,
-		requiredAPIs: requiredAPIs,
-		sourceInfo:   &ExistingOperator,
+		name:             csv.GetName(),
+		version:          &csv.Spec.Version.Version,
+		replaces:         csv.Spec.Replaces,
+		providedAPIs:     providedAPIs,
+		requiredAPIs:     requiredAPIs,
+		requiredPackages: csv.Spec.RequiredPackages,
+		sourceInfo:       &ExistingOperator,
+		pkg:              csv.GetAnnotations()[v1alpha1.PackageAnnotationKey],
 	}, nil
 }
 
@@ -310,6 +322,10 @@ func (o *Operator) RequiredAPIs() APISet {
 	return o.requiredAPIs
 }
 
+func (o *Operator) RequiredPackages() []v1alpha1.PackageDependency {
+	return o.requiredPackages
+}
+
 func (o *Operator) Identifier() string {
 	return o.name
 }
@@ -318,8 +334,15 @@ func (o *Operator) Replaces() string {
 	return o.replaces
 }
 
+// Package returns the package the operator belongs to, or an empty string if it can't be attributed to one.
 func (o *Operator) Package() string {
-	return o.bundle.Package
+	if o.sourceInfo != nil && o.sourceInfo.Package != "" {
+		return o.sourceInfo.Package
+	}
+	if o.bundle != nil {
+		return o.bundle.Package
+	}
+	return o.pkg
 }
 
 func (o *Operator) SourceInfo() *OperatorSourceInfo {
@@ -0,0 +1,219 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+)
+
+// ProblemType classifies a problem found while resolving a namespace.
+type ProblemType string
+
+const (
+	// PackageNotFound is reported when a requested package isn't served by any of the queried CatalogSources.
+	PackageNotFound ProblemType = "PackageNotFound"
+
+	// ChannelNotFound is reported when a requested package exists, but doesn't contain the requested channel.
+	ChannelNotFound ProblemType = "ChannelNotFound"
+
+	// BundleNotFound is reported when a requested bundle couldn't be found for any other reason.
+	BundleNotFound ProblemType = "BundleNotFound"
+
+	// RequiredAPINotSatisfiable is reported when no operator in the namespace or in any CatalogSource provides a required api.
+	RequiredAPINotSatisfiable ProblemType = "RequiredAPINotSatisfiable"
+
+	// RequiredPackageNotSatisfiable is reported when no operator in the namespace or in any CatalogSource satisfies a required package.
+	RequiredPackageNotSatisfiable ProblemType = "RequiredPackageNotSatisfiable"
+
+	// RemovedDueToMissingDependency is reported for every operator dropped from a resolution because one of its required apis
+	// or packages is unsatisfiable.
+	RemovedDueToMissingDependency ProblemType = "RemovedDueToMissingDependency"
+
+	// CompetingProviders is reported when two operators in the namespace would provide the same api.
+	CompetingProviders ProblemType = "CompetingProviders"
+
+	// VersionNotInRange is reported when the requested bundles exist, but none of them satisfy the requested version range.
+	VersionNotInRange ProblemType = "VersionNotInRange"
+
+	// InvalidVersionRange is reported when a requested version range can't be parsed.
+	InvalidVersionRange ProblemType = "InvalidVersionRange"
+
+	// ConstraintsNotSatisfiable is reported by the SatEvolver when no combination of bundles satisfies the subscriptions
+	// in the namespace along with their dependencies.
+	ConstraintsNotSatisfiable ProblemType = "ConstraintsNotSatisfiable"
+)
+
+// Problem is a single reason a resolution couldn't be satisfied.
+type Problem struct {
+	Type ProblemType
+
+	// SourceInfo is the package, channel and catalog the problem was found for, if any.
+	SourceInfo *OperatorSourceInfo
+
+	// Operators are the names of the operators involved in the problem, if any.
+	Operators []string
+
+	// API is the api the problem is about, if any.
+	API *opregistry.APIKey
+
+	// Package is the package dependency the problem is about, if any.
+	Package *v1alpha1.PackageDependency
+
+	// Message is a human-readable description of the problem.
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Type, p.Message)
+}
+
+// ResolutionError is returned when a namespace can't be resolved. It lists every problem found during the attempt.
+type ResolutionError struct {
+	Problems []Problem
+}
+
+var _ error = ResolutionError{}
+
+func (e ResolutionError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Details returns one line per problem, sorted so that repeated resolutions of the same namespace describe it identically.
+func (e ResolutionError) Details() []string {
+	details := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		details[i] = p.String()
+	}
+	sort.Strings(details)
+	return details
+}
+
+func newLookupProblem(problemType ProblemType, s OperatorSourceInfo, err error) Problem {
+	what := s.String()
+	if s.StartingCSV != "" {
+		what = fmt.Sprintf("%s (%s)", s.StartingCSV, what)
+	}
+	return Problem{
+		Type:       problemType,
+		SourceInfo: &s,
+		Message:    fmt.Sprintf("%s not found: %v", what, err),
+	}
+}
+
+func newNotInRangeProblem(s OperatorSourceInfo, err VersionNotInRangeError) Problem {
+	return Problem{
+		Type:       VersionNotInRange,
+		SourceInfo: &s,
+		Operators:  []string{err.Bundle},
+		Message:    fmt.Sprintf("no bundle for %s satisfies the version range: %v", s.String(), err),
+	}
+}
+
+func newInvalidVersionRangeProblem(s OperatorSourceInfo, err error) Problem {
+	return Problem{
+		Type:       InvalidVersionRange,
+		SourceInfo: &s,
+		Message:    fmt.Sprintf("can't resolve %s: %v", s.String(), err),
+	}
+}
+
+func newConflictProblem(err APIConflictError) Problem {
+	api := err.API
+	return Problem{
+		Type:      CompetingProviders,
+		Operators: []string{err.Provider, err.Conflicting},
+		API:       &api,
+		Message:   fmt.Sprintf("%s and %s both provide %s", err.Provider, err.Conflicting, api),
+	}
+}
+
+func newUnsatisfiableProblem(api opregistry.APIKey, requirers OperatorSet) Problem {
+	names := make([]string, 0, len(requirers))
+	for name := range requirers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return Problem{
+		Type:      RequiredAPINotSatisfiable,
+		Operators: names,
+		API:       &api,
+		Message:   fmt.Sprintf("%s required by %s is not provided by any operator or CatalogSource", api, strings.Join(names, ", ")),
+	}
+}
+
+func newRemovedProblem(op OperatorSurface, api opregistry.APIKey) Problem {
+	p := Problem{
+		Type:      RemovedDueToMissingDependency,
+		Operators: []string{op.Identifier()},
+		API:       &api,
+		Message:   fmt.Sprintf("%s removed from resolution because its required api %s is missing", op.Identifier(), api),
+	}
+	if info := op.SourceInfo(); info != nil && *info != ExistingOperator {
+		s := *info
+		p.SourceInfo = &s
+	}
+	return p
+}
+
+func newUnsatisfiablePackageProblem(dep v1alpha1.PackageDependency, requirers OperatorSet) Problem {
+	names := make([]string, 0, len(requirers))
+	for name := range requirers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return Problem{
+		Type:      RequiredPackageNotSatisfiable,
+		Operators: names,
+		Package:   &dep,
+		Message:   fmt.Sprintf("package %s required by %s is not provided by any operator or CatalogSource", packageDependencyString(dep), strings.Join(names, ", ")),
+	}
+}
+
+func newRemovedForPackageProblem(op OperatorSurface, dep v1alpha1.PackageDependency) Problem {
+	p := Problem{
+		Type:      RemovedDueToMissingDependency,
+		Operators: []string{op.Identifier()},
+		Package:   &dep,
+		Message:   fmt.Sprintf("%s removed from resolution because its required package %s is missing", op.Identifier(), packageDependencyString(dep)),
+	}
+	if info := op.SourceInfo(); info != nil && *info != ExistingOperator {
+		s := *info
+		p.SourceInfo = &s
+	}
+	return p
+}
+
+func packageDependencyString(dep v1alpha1.PackageDependency) string {
+	if dep.VersionRange == "" {
+		return dep.PackageName
+	}
+	return fmt.Sprintf("%s (%s)", dep.PackageName, dep.VersionRange)
+}
+
+func newUnsatisfiableSubscriptionProblem(s OperatorSourceInfo) Problem {
+	return Problem{
+		Type:       ConstraintsNotSatisfiable,
+		SourceInfo: &s,
+		Message:    fmt.Sprintf("no bundle for %s can be installed along with its dependencies and the operators in the namespace", s.String()),
+	}
+}
+
+func newConflictingSubscriptionsProblem(infos []OperatorSourceInfo) Problem {
+	names := make([]string, len(infos))
+	for i := range infos {
+		names[i] = infos[i].String()
+	}
+	sort.Strings(names)
+	return Problem{
+		Type:    ConstraintsNotSatisfiable,
+		Message: fmt.Sprintf("%s can't all be installed in the namespace together", strings.Join(names, ", ")),
+	}
+}
@@ -4,12 +4,20 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/operator-framework/operator-registry/pkg/api"
 	"github.com/operator-framework/operator-registry/pkg/client"
 	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 )
 
 const SkipPackageAnnotationKey = "olm.skipRange"
@@ -22,23 +30,188 @@ type SourceRef struct {
 }
 
 type SourceQuerier interface {
-	FindProvider(api opregistry.APIKey) (*opregistry.Bundle, *CatalogKey, error)
-	FindBundle(pkgName, channelName, bundleName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
-	FindLatestBundle(pkgName, channelName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
-	FindReplacement(currentVersion *semver.Version, bundleName, pkgName, channelName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
+	FindProvider(ctx context.Context, api opregistry.APIKey, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
+	FindBundle(ctx context.Context, pkgName, channelName, bundleName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
+	FindLatestBundle(ctx context.Context, pkgName, channelName, versionRange string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
+	FindReplacement(ctx context.Context, currentVersion *semver.Version, bundleName, pkgName, channelName, versionRange string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error)
+	FindPackage(ctx context.Context, pkgName string, initialSource CatalogKey) (*api.Package, *CatalogKey, error)
 	Queryable() error
 }
 
+// PackageNotFoundError is returned when none of the queried CatalogSources serve a package.
+type PackageNotFoundError struct {
+	Package string
+}
+
+func (e PackageNotFoundError) Error() string {
+	return fmt.Sprintf("package %s not found in any available CatalogSource", e.Package)
+}
+
+// VersionNotInRangeError is returned when a query found a bundle, but the bundle's version is outside of the requested
+// version range.
+type VersionNotInRangeError struct {
+	Bundle       string
+	Version      string
+	VersionRange string
+}
+
+func (e VersionNotInRangeError) Error() string {
+	return fmt.Sprintf("%s has version %s, which is outside of the version range %q", e.Bundle, e.Version, e.VersionRange)
+}
+
+// parseVersionRange parses a version range. An empty range is satisfied by every version and parses to nil.
+func parseVersionRange(versionRange string) (semver.Range, error) {
+	if versionRange == "" {
+		return nil, nil
+	}
+	r, err := semver.ParseRange(versionRange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version range %q: %v", versionRange, err)
+	}
+	return r, nil
+}
+
+// checkVersionRange returns a VersionNotInRangeError if the bundle's version doesn't satisfy the version range.
+func checkVersionRange(bundle *opregistry.Bundle, versionRange string, r semver.Range) error {
+	if r == nil {
+		return nil
+	}
+	csv, err := bundle.ClusterServiceVersion()
+	if err != nil {
+		return err
+	}
+	if v := csv.Spec.Version.Version; !r(v) {
+		return VersionNotInRangeError{Bundle: bundle.Name, Version: v.String(), VersionRange: versionRange}
+	}
+	return nil
+}
+
 type NamespaceSourceQuerier struct {
-	sources map[CatalogKey]client.Interface
+	sources         map[CatalogKey]SourceClient
+	priorities      map[CatalogKey]int32
+	globalNamespace string
+	queryTimeout    time.Duration
+	timedOut        map[CatalogKey]struct{}
+	timedOutLock    sync.Mutex
 }
 
 var _ SourceQuerier = &NamespaceSourceQuerier{}
 
-func NewNamespaceSourceQuerier(sources map[CatalogKey]client.Interface) *NamespaceSourceQuerier {
-	return &NamespaceSourceQuerier{
+// SourceQuerierOption configures a NamespaceSourceQuerier.
+type SourceQuerierOption func(*NamespaceSourceQuerier)
+
+// WithQueryTimeout makes the querier give up on any single query to a source after queryTimeout.
+// Sources that don't answer in time are treated as if they didn't have the requested content, and are reported by TimedOut.
+func WithQueryTimeout(queryTimeout time.Duration) SourceQuerierOption {
+	return func(q *NamespaceSourceQuerier) {
+		q.queryTimeout = queryTimeout
+	}
+}
+
+// WithSourcePriorities sets the priority of each source. Sources with a higher priority are consulted first.
+func WithSourcePriorities(priorities map[CatalogKey]int32) SourceQuerierOption {
+	return func(q *NamespaceSourceQuerier) {
+		q.priorities = priorities
+	}
+}
+
+// WithGlobalNamespace sets the namespace of the global catalogs, which are consulted after all other sources.
+func WithGlobalNamespace(namespace string) SourceQuerierOption {
+	return func(q *NamespaceSourceQuerier) {
+		q.globalNamespace = namespace
+	}
+}
+
+func NewNamespaceSourceQuerier(sources map[CatalogKey]SourceClient, options ...SourceQuerierOption) *NamespaceSourceQuerier {
+	q := &NamespaceSourceQuerier{
 		sources: sources,
 	}
+	for _, option := range options {
+		option(q)
+	}
+	return q
+}
+
+// orderedSources returns the keys of the sources in the order they should be consulted: the initial source first, then
+// sources outside of the global namespace, then global sources. Within each group, sources are ordered by descending
+// priority and then by namespace and name, so that repeated queries always pick the same source.
+func (q *NamespaceSourceQuerier) orderedSources(initialSource CatalogKey) []CatalogKey {
+	keys := make([]CatalogKey, 0, len(q.sources))
+	for key := range q.sources {
+		keys = append(keys, key)
+	}
+	rank := func(key CatalogKey) int {
+		switch {
+		case key == initialSource:
+			return 0
+		case key.Namespace != q.globalNamespace:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra < rb
+		}
+		if pa, pb := q.priorities[a], q.priorities[b]; pa != pb {
+			return pa > pb
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return keys
+}
+
+// TimedOut returns the sources that failed to answer at least one query before its deadline, sorted by namespace and name.
+func (q *NamespaceSourceQuerier) TimedOut() []CatalogKey {
+	q.timedOutLock.Lock()
+	defer q.timedOutLock.Unlock()
+
+	keys := make([]CatalogKey, 0, len(q.timedOut))
+	for key := range q.timedOut {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
+
+// query runs a single query against a source, bounding it by the query timeout and recording the source if it times out.
+func (q *NamespaceSourceQuerier) query(ctx context.Context, key CatalogKey, f func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		// the caller gave up, don't bother the source
+		return err
+	}
+
+	queryCtx := ctx
+	if q.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		queryCtx, cancel = context.WithTimeout(ctx, q.queryTimeout)
+		defer cancel()
+	}
+
+	err := f(queryCtx)
+	if err != nil && ctx.Err() == nil && isDeadlineExceeded(queryCtx, err) {
+		q.timedOutLock.Lock()
+		if q.timedOut == nil {
+			q.timedOut = map[CatalogKey]struct{}{}
+		}
+		q.timedOut[key] = struct{}{}
+		q.timedOutLock.Unlock()
+	}
+	return err
+}
+
+func isDeadlineExceeded(ctx context.Context, err error) bool {
+	return ctx.Err() == context.DeadlineExceeded || err == context.DeadlineExceeded || status.Code(err) == codes.DeadlineExceeded
 }
 
 func (q *NamespaceSourceQuerier) Queryable() error {
@@ -48,66 +221,158 @@ func (q *NamespaceSourceQuerier) Queryable() error {
 	return nil
 }
 
-func (q *NamespaceSourceQuerier) FindProvider(api opregistry.APIKey) (*opregistry.Bundle, *CatalogKey, error) {
-	for key, source := range q.sources {
-		if bundle, err := source.GetBundleThatProvides(context.TODO(), api.Group, api.Version, api.Kind); err == nil {
+func (q *NamespaceSourceQuerier) FindProvider(ctx context.Context, api opregistry.APIKey, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+	for _, key := range q.orderedSources(initialSource) {
+		source := q.sources[key]
+		var bundle *opregistry.Bundle
+		getBundle := func(groupOrName string) func(context.Context) error {
+			return func(ctx context.Context) (err error) {
+				bundle, err = source.GetBundleThatProvides(ctx, groupOrName, api.Version, api.Kind)
+				return
+			}
+		}
+		if err := q.query(ctx, key, getBundle(api.Group)); err == nil {
 			return bundle, &key, nil
 		}
-		if bundle, err := source.GetBundleThatProvides(context.TODO(), api.Plural+"."+api.Group, api.Version, api.Kind); err == nil {
+		if err := q.query(ctx, key, getBundle(api.Plural+"."+api.Group)); err == nil {
 			return bundle, &key, nil
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	return nil, nil, fmt.Errorf("%s not provided by a package in any CatalogSource", api)
 }
 
-func (q *NamespaceSourceQuerier) FindBundle(pkgName, channelName, bundleName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+func (q *NamespaceSourceQuerier) FindBundle(ctx context.Context, pkgName, channelName, bundleName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+	getBundle := func(source SourceClient, bundle **opregistry.Bundle) func(context.Context) error {
+		return func(ctx context.Context) (err error) {
+			*bundle, err = source.GetBundle(ctx, pkgName, channelName, bundleName)
+			return
+		}
+	}
+
 	if initialSource.Name != "" && initialSource.Namespace != "" {
 		source, ok := q.sources[initialSource]
 		if !ok {
 			return nil, nil, fmt.Errorf("CatalogSource %s not found", initialSource)
 		}
 
-		bundle, err := source.GetBundle(context.TODO(), pkgName, channelName, bundleName)
-		if err != nil {
+		var bundle *opregistry.Bundle
+		if err := q.query(ctx, initialSource, getBundle(source, &bundle)); err != nil {
 			return nil, nil, err
 		}
 		return bundle, &initialSource, nil
 	}
 
-	for key, source := range q.sources {
-		bundle, err := source.GetBundle(context.TODO(), pkgName, channelName, bundleName)
-		if err == nil {
+	for _, key := range q.orderedSources(initialSource) {
+		source := q.sources[key]
+		var bundle *opregistry.Bundle
+		if err := q.query(ctx, key, getBundle(source, &bundle)); err == nil {
 			return bundle, &key, nil
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	return nil, nil, fmt.Errorf("%s/%s/%s not found in any available CatalogSource", pkgName, channelName, bundleName)
 }
 
-func (q *NamespaceSourceQuerier) FindLatestBundle(pkgName, channelName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+func (q *NamespaceSourceQuerier) FindLatestBundle(ctx context.Context, pkgName, channelName, versionRange string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+	r, err := parseVersionRange(versionRange)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if initialSource.Name != "" && initialSource.Namespace != "" {
 		source, ok := q.sources[initialSource]
 		if !ok {
 			return nil, nil, fmt.Errorf("CatalogSource %s not found", initialSource)
 		}
 
-		bundle, err := source.GetBundleInPackageChannel(context.TODO(), pkgName, channelName)
+		bundle, err := q.latestInRange(ctx, initialSource, source, pkgName, channelName, versionRange, r)
 		if err != nil {
 			return nil, nil, err
 		}
 		return bundle, &initialSource, nil
 	}
 
-	for key, source := range q.sources {
-		bundle, err := source.GetBundleInPackageChannel(context.TODO(), pkgName, channelName)
+	var notInRange error
+	for _, key := range q.orderedSources(initialSource) {
+		bundle, err := q.latestInRange(ctx, key, q.sources[key], pkgName, channelName, versionRange, r)
 		if err == nil {
 			return bundle, &key, nil
 		}
+		if _, ok := err.(VersionNotInRangeError); ok && notInRange == nil {
+			notInRange = err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if notInRange != nil {
+		return nil, nil, notInRange
 	}
 	return nil, nil, fmt.Errorf("%s/%s not found in any available CatalogSource", pkgName, channelName)
 }
 
-func (q *NamespaceSourceQuerier) FindReplacement(currentVersion *semver.Version, bundleName, pkgName, channelName string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
-	errs := []error{}
+// latestInRange returns the newest bundle in a channel of a single source whose version satisfies the version range. It
+// walks back from the channel head through the bundles each one replaces, and returns a VersionNotInRangeError for the
+// channel head if none of them do.
+func (q *NamespaceSourceQuerier) latestInRange(ctx context.Context, key CatalogKey, source SourceClient, pkgName, channelName, versionRange string, r semver.Range) (*opregistry.Bundle, error) {
+	var head *opregistry.Bundle
+	if err := q.query(ctx, key, func(ctx context.Context) (err error) {
+		head, err = source.GetBundleInPackageChannel(ctx, pkgName, channelName)
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	headErr := checkVersionRange(head, versionRange, r)
+	if headErr == nil {
+		return head, nil
+	}
+	if _, ok := headErr.(VersionNotInRangeError); !ok {
+		return nil, headErr
+	}
+
+	visited := map[string]struct{}{}
+	for bundle := head; ; {
+		visited[bundle.Name] = struct{}{}
+		csv, err := bundle.ClusterServiceVersion()
+		if err != nil {
+			return nil, err
+		}
+		replaces := csv.Spec.Replaces
+		if _, ok := visited[replaces]; ok || replaces == "" {
+			return nil, headErr
+		}
+
+		if err := q.query(ctx, key, func(ctx context.Context) (err error) {
+			bundle, err = source.GetBundle(ctx, pkgName, channelName, replaces)
+			return
+		}); err != nil {
+			return nil, err
+		}
+
+		err = checkVersionRange(bundle, versionRange, r)
+		if _, ok := err.(VersionNotInRangeError); !ok {
+			if err != nil {
+				return nil, err
+			}
+			return bundle, nil
+		}
+	}
+}
+
+// FindReplacement looks for an update to the given bundle, preferring a channel head that skips the current version over
+// the bundle that directly replaces it. If the only updates found are outside of the version range, a
+// VersionNotInRangeError is returned.
+func (q *NamespaceSourceQuerier) FindReplacement(ctx context.Context, currentVersion *semver.Version, bundleName, pkgName, channelName, versionRange string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+	r, err := parseVersionRange(versionRange)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	if initialSource.Name != "" && initialSource.Namespace != "" {
 		source, ok := q.sources[initialSource]
@@ -115,51 +380,131 @@ func (q *NamespaceSourceQuerier) FindReplacement(currentVersion *semver.Version,
 			return nil, nil, fmt.Errorf("CatalogSource %s not found", initialSource.Name)
 		}
 
-		bundle, err := q.findChannelHead(currentVersion, pkgName, channelName, source)
+		bundle, err := q.replacementInSource(ctx, currentVersion, bundleName, pkgName, channelName, versionRange, r, initialSource, source)
 		if bundle != nil {
 			return bundle, &initialSource, nil
 		}
-		if err != nil {
-			errs = append(errs, err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
 		}
+		return nil, nil, err
+	}
 
-		bundle, err = source.GetReplacementBundleInPackageChannel(context.TODO(), bundleName, pkgName, channelName)
+	errs := []error{}
+	var notInRange error
+	for _, key := range q.orderedSources(initialSource) {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		bundle, err := q.replacementInSource(ctx, currentVersion, bundleName, pkgName, channelName, versionRange, r, key, q.sources[key])
 		if bundle != nil {
-			return bundle, &initialSource, nil
+			return bundle, &key, nil
+		}
+		if _, ok := err.(VersionNotInRangeError); ok {
+			if notInRange == nil {
+				notInRange = err
+			}
+			continue
 		}
 		if err != nil {
 			errs = append(errs, err)
 		}
-
-		return nil, nil, errors.NewAggregate(errs)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if notInRange != nil {
+		return nil, nil, notInRange
+	}
+	return nil, nil, errors.NewAggregate(errs)
+}
 
-	for key, source := range q.sources {
-		bundle, err := q.findChannelHead(currentVersion, pkgName, channelName, source)
+// replacementInSource looks for an update to the given bundle in a single source.
+func (q *NamespaceSourceQuerier) replacementInSource(ctx context.Context, currentVersion *semver.Version, bundleName, pkgName, channelName, versionRange string, r semver.Range, key CatalogKey, source SourceClient) (*opregistry.Bundle, error) {
+	errs := []error{}
+	var notInRange error
+	candidate := func(bundle *opregistry.Bundle, err error) *opregistry.Bundle {
 		if bundle != nil {
-			return bundle, &initialSource, nil
+			rangeErr := checkVersionRange(bundle, versionRange, r)
+			if rangeErr == nil {
+				return bundle
+			}
+			if _, ok := rangeErr.(VersionNotInRangeError); ok {
+				notInRange = rangeErr
+			} else {
+				errs = append(errs, rangeErr)
+			}
 		}
 		if err != nil {
 			errs = append(errs, err)
 		}
+		return nil
+	}
 
-		bundle, err = source.GetReplacementBundleInPackageChannel(context.TODO(), bundleName, pkgName, channelName)
-		if bundle != nil {
-			return bundle, &key, nil
+	if bundle := candidate(q.findChannelHead(ctx, currentVersion, pkgName, channelName, key, source)); bundle != nil {
+		return bundle, nil
+	}
+
+	var replacement *opregistry.Bundle
+	err := q.query(ctx, key, func(ctx context.Context) (err error) {
+		replacement, err = source.GetReplacementBundleInPackageChannel(ctx, bundleName, pkgName, channelName)
+		return
+	})
+	if bundle := candidate(replacement, err); bundle != nil {
+		return bundle, nil
+	}
+
+	if notInRange != nil {
+		return nil, notInRange
+	}
+	return nil, errors.NewAggregate(errs)
+}
+
+// FindPackage looks up a package's metadata. A PackageNotFoundError is only returned if every queried source reported
+// that it doesn't serve the package; otherwise the errors of the sources that couldn't be queried are returned.
+func (q *NamespaceSourceQuerier) FindPackage(ctx context.Context, pkgName string, initialSource CatalogKey) (*api.Package, *CatalogKey, error) {
+	keys := q.orderedSources(initialSource)
+	if initialSource.Name != "" && initialSource.Namespace != "" {
+		if _, ok := q.sources[initialSource]; !ok {
+			return nil, nil, fmt.Errorf("CatalogSource %s not found", initialSource)
 		}
-		if err != nil {
-			errs = append(errs, err)
+		keys = []CatalogKey{initialSource}
+	}
+
+	var errs []error
+	for _, key := range keys {
+		source := q.sources[key]
+		var pkg *api.Package
+		err := q.query(ctx, key, func(ctx context.Context) (err error) {
+			pkg, err = source.GetPackage(ctx, pkgName)
+			return
+		})
+		if err == nil {
+			return pkg, &key, nil
+		}
+		if _, ok := err.(PackageNotFoundError); !ok {
+			errs = append(errs, fmt.Errorf("error getting package %s from CatalogSource %s: %v", pkgName, key.String(), err))
 		}
 	}
-	return nil, nil, errors.NewAggregate(errs)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.NewAggregate(errs)
+	}
+	return nil, nil, PackageNotFoundError{Package: pkgName}
 }
 
-func (q *NamespaceSourceQuerier) findChannelHead(currentVersion *semver.Version, pkgName, channelName string, source client.Interface) (*opregistry.Bundle, error) {
+func (q *NamespaceSourceQuerier) findChannelHead(ctx context.Context, currentVersion *semver.Version, pkgName, channelName string, key CatalogKey, source SourceClient) (*opregistry.Bundle, error) {
 	if currentVersion == nil {
 		return nil, nil
 	}
 
-	latest, err := source.GetBundleInPackageChannel(context.TODO(), pkgName, channelName)
+	var latest *opregistry.Bundle
+	err := q.query(ctx, key, func(ctx context.Context) (err error) {
+		latest, err = source.GetBundleInPackageChannel(ctx, pkgName, channelName)
+		return
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -187,3 +532,44 @@ func (q *NamespaceSourceQuerier) findChannelHead(currentVersion *semver.Version,
 	}
 	return nil, nil
 }
+
+// rolledBackQuerier is a SourceQuerier that looks past upgrades that were rolled back, so that an operator is upgraded
+// to the next replacement in its channel rather than to an upgrade that already failed.
+type rolledBackQuerier struct {
+	SourceQuerier
+	rolledBack map[string]*v1alpha1.ClusterServiceVersion
+}
+
+// SkipRolledBack returns a SourceQuerier that finds the replacement of each of the given CSVs that was rolled back in
+// place of the CSV itself.
+func SkipRolledBack(querier SourceQuerier, csvs []*v1alpha1.ClusterServiceVersion) SourceQuerier {
+	rolledBack := map[string]*v1alpha1.ClusterServiceVersion{}
+	for _, csv := range csvs {
+		if csv.IsRolledBack() {
+			rolledBack[csv.GetName()] = csv
+		}
+	}
+	if len(rolledBack) == 0 {
+		return querier
+	}
+	return &rolledBackQuerier{SourceQuerier: querier, rolledBack: rolledBack}
+}
+
+// FindReplacement looks for an update to the given bundle, and for the update to each rolled back update it finds in
+// turn.
+func (q *rolledBackQuerier) FindReplacement(ctx context.Context, currentVersion *semver.Version, bundleName, pkgName, channelName, versionRange string, initialSource CatalogKey) (*opregistry.Bundle, *CatalogKey, error) {
+	bundle, key, err := q.SourceQuerier.FindReplacement(ctx, currentVersion, bundleName, pkgName, channelName, versionRange, initialSource)
+	skipped := map[string]struct{}{}
+	for bundle != nil {
+		csv, ok := q.rolledBack[bundle.Name]
+		if !ok {
+			break
+		}
+		if _, ok := skipped[bundle.Name]; ok {
+			return nil, nil, fmt.Errorf("replacements of rolled back csv %s form a cycle", bundle.Name)
+		}
+		skipped[bundle.Name] = struct{}{}
+		bundle, key, err = q.SourceQuerier.FindReplacement(ctx, &csv.Spec.Version.Version, csv.GetName(), pkgName, channelName, versionRange, *key)
+	}
+	return bundle, key, err
+}
@@ -4,27 +4,34 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/operator-framework/operator-registry/pkg/api"
 	"github.com/operator-framework/operator-registry/pkg/client"
 	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver/fakes"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/version"
+	pkgfakes "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/client/fakes"
 )
 
 func TestNewNamespaceSourceQuerier(t *testing.T) {
-	emptySources := map[CatalogKey]client.Interface{}
-	nonEmptySources := map[CatalogKey]client.Interface{
-		CatalogKey{"test", "ns"}: &fakes.FakeInterface{},
+	emptySources := map[CatalogKey]SourceClient{}
+	nonEmptySources := map[CatalogKey]SourceClient{
+		CatalogKey{"test", "ns"}: &fakes.FakeSourceClient{},
 	}
 	type args struct {
-		sources map[CatalogKey]client.Interface
+		sources map[CatalogKey]SourceClient
 	}
 	tests := []struct {
 		name string
@@ -62,7 +69,7 @@ func TestNewNamespaceSourceQuerier(t *testing.T) {
 
 func TestNamespaceSourceQuerier_Queryable(t *testing.T) {
 	type fields struct {
-		sources map[CatalogKey]client.Interface
+		sources map[CatalogKey]SourceClient
 	}
 	tests := []struct {
 		name   string
@@ -79,15 +86,15 @@ func TestNamespaceSourceQuerier_Queryable(t *testing.T) {
 		{
 			name: "empty",
 			fields: fields{
-				sources: map[CatalogKey]client.Interface{},
+				sources: map[CatalogKey]SourceClient{},
 			},
 			error: fmt.Errorf("no catalog sources available"),
 		},
 		{
 			name: "nonEmpty",
 			fields: fields{
-				sources: map[CatalogKey]client.Interface{
-					CatalogKey{"test", "ns"}: &fakes.FakeInterface{},
+				sources: map[CatalogKey]SourceClient{
+					CatalogKey{"test", "ns"}: &fakes.FakeSourceClient{},
 				},
 			},
 			error: nil,
@@ -105,8 +112,8 @@ func TestNamespaceSourceQuerier_Queryable(t *testing.T) {
 }
 
 func TestNamespaceSourceQuerier_FindProvider(t *testing.T) {
-	fakeSource := fakes.FakeInterface{}
-	sources := map[CatalogKey]client.Interface{
+	fakeSource := fakes.FakeSourceClient{}
+	sources := map[CatalogKey]SourceClient{
 		CatalogKey{"test", "ns"}: &fakeSource,
 	}
 
@@ -116,7 +123,7 @@ func TestNamespaceSourceQuerier_FindProvider(t *testing.T) {
 	}
 
 	type fields struct {
-		sources map[CatalogKey]client.Interface
+		sources map[CatalogKey]SourceClient
 	}
 	type args struct {
 		api opregistry.APIKey
@@ -164,7 +171,7 @@ func TestNamespaceSourceQuerier_FindProvider(t *testing.T) {
 			q := &NamespaceSourceQuerier{
 				sources: tt.fields.sources,
 			}
-			bundle, key, err := q.FindProvider(tt.args.api)
+			bundle, key, err := q.FindProvider(context.TODO(), tt.args.api, CatalogKey{})
 			require.Equal(t, err, tt.out.err)
 			require.Equal(t, bundle, tt.out.bundle)
 			require.Equal(t, key, tt.out.key)
@@ -172,9 +179,269 @@ func TestNamespaceSourceQuerier_FindProvider(t *testing.T) {
 	}
 }
 
+func TestNamespaceSourceQuerier_Timeout(t *testing.T) {
+	slowSource := fakes.FakeSourceClient{}
+	fastSource := fakes.FakeSourceClient{}
+	bundle := opregistry.NewBundle("test", "testPkg", "testChannel")
+	slowSource.GetBundleStub = func(ctx context.Context, pkgName, channelName, csvName string) (*opregistry.Bundle, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	fastSource.GetBundleStub = func(ctx context.Context, pkgName, channelName, csvName string) (*opregistry.Bundle, error) {
+		return bundle, nil
+	}
+	slowKey := CatalogKey{"slow", "ns"}
+	fastKey := CatalogKey{"fast", "ns"}
+	sources := map[CatalogKey]SourceClient{
+		slowKey: &slowSource,
+		fastKey: &fastSource,
+	}
+
+	tests := []struct {
+		name         string
+		ctx          func() (context.Context, context.CancelFunc)
+		queryTimeout time.Duration
+		source       CatalogKey
+		wantBundle   *opregistry.Bundle
+		wantErr      error
+		wantTimedOut []CatalogKey
+	}{
+		{
+			name:         "Fast/Found",
+			ctx:          func() (context.Context, context.CancelFunc) { return context.WithCancel(context.TODO()) },
+			queryTimeout: time.Millisecond,
+			source:       fastKey,
+			wantBundle:   bundle,
+			wantTimedOut: []CatalogKey{},
+		},
+		{
+			name:         "Slow/TimedOut",
+			ctx:          func() (context.Context, context.CancelFunc) { return context.WithCancel(context.TODO()) },
+			queryTimeout: time.Millisecond,
+			source:       slowKey,
+			wantErr:      context.DeadlineExceeded,
+			wantTimedOut: []CatalogKey{slowKey},
+		},
+		{
+			name: "Slow/ResolutionDeadline/NotRecorded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.TODO(), time.Millisecond)
+			},
+			source:       slowKey,
+			wantErr:      context.DeadlineExceeded,
+			wantTimedOut: []CatalogKey{},
+		},
+		{
+			name: "Cancelled/NotQueried",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.TODO())
+				cancel()
+				return ctx, cancel
+			},
+			source:       fastKey,
+			wantErr:      context.Canceled,
+			wantTimedOut: []CatalogKey{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			q := NewNamespaceSourceQuerier(sources, WithQueryTimeout(tt.queryTimeout))
+			got, _, err := q.FindBundle(ctx, "testPkg", "testChannel", "test", tt.source)
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantBundle, got)
+			require.Equal(t, tt.wantTimedOut, q.TimedOut())
+		})
+	}
+}
+
+func TestNamespaceSourceQuerier_FindReplacementDeadline(t *testing.T) {
+	slowSource := fakes.FakeSourceClient{}
+	slowSource.GetBundleInPackageChannelStub = func(ctx context.Context, pkgName, channelName string) (*opregistry.Bundle, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	slowSource.GetReplacementBundleInPackageChannelStub = func(ctx context.Context, bundleName, pkgName, channelName string) (*opregistry.Bundle, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	sources := map[CatalogKey]SourceClient{
+		{"a", "ns"}: &slowSource,
+		{"b", "ns"}: &slowSource,
+	}
+
+	// The resolution deadline is returned as is, rather than aggregated with the errors of each source
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+	q := NewNamespaceSourceQuerier(sources)
+	_, _, err := q.FindReplacement(ctx, nil, "test.v1", "testPkg", "testChannel", "", CatalogKey{})
+	require.Equal(t, context.DeadlineExceeded, err)
+	_, _, err = q.FindReplacement(ctx, nil, "test.v1", "testPkg", "testChannel", "", CatalogKey{"a", "ns"})
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	// No source is queried once it has passed
+	calls := slowSource.GetBundleInPackageChannelCallCount()
+	_, _, err = q.FindReplacement(ctx, nil, "test.v1", "testPkg", "testChannel", "", CatalogKey{})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, calls, slowSource.GetBundleInPackageChannelCallCount())
+}
+
+func TestNamespaceSourceQuerier_OrderedSources(t *testing.T) {
+	global := "global"
+	namespace := "ns"
+	sources := func(keys ...CatalogKey) map[CatalogKey]SourceClient {
+		m := map[CatalogKey]SourceClient{}
+		for _, key := range keys {
+			m[key] = &fakes.FakeSourceClient{}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name          string
+		sources       map[CatalogKey]SourceClient
+		priorities    map[CatalogKey]int32
+		initialSource CatalogKey
+		want          []CatalogKey
+	}{
+		{
+			name:    "NoPriorities/ByName",
+			sources: sources(CatalogKey{"b", namespace}, CatalogKey{"c", namespace}, CatalogKey{"a", namespace}),
+			want:    []CatalogKey{{"a", namespace}, {"b", namespace}, {"c", namespace}},
+		},
+		{
+			name:          "InitialSourceFirst",
+			sources:       sources(CatalogKey{"a", namespace}, CatalogKey{"b", namespace}, CatalogKey{"c", global}),
+			priorities:    map[CatalogKey]int32{{"a", namespace}: 10},
+			initialSource: CatalogKey{"c", global},
+			want:          []CatalogKey{{"c", global}, {"a", namespace}, {"b", namespace}},
+		},
+		{
+			name:    "NamespaceBeforeGlobal",
+			sources: sources(CatalogKey{"a", global}, CatalogKey{"b", namespace}),
+			priorities: map[CatalogKey]int32{
+				{"a", global}:    10,
+				{"b", namespace}: -10,
+			},
+			want: []CatalogKey{{"b", namespace}, {"a", global}},
+		},
+		{
+			name:    "ByPriorityWithinNamespace",
+			sources: sources(CatalogKey{"a", namespace}, CatalogKey{"b", namespace}, CatalogKey{"c", global}, CatalogKey{"d", global}),
+			priorities: map[CatalogKey]int32{
+				{"b", namespace}: 1,
+				{"d", global}:    1,
+			},
+			want: []CatalogKey{{"b", namespace}, {"a", namespace}, {"d", global}, {"c", global}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewNamespaceSourceQuerier(tt.sources, WithSourcePriorities(tt.priorities), WithGlobalNamespace(global))
+			require.Equal(t, tt.want, q.orderedSources(tt.initialSource))
+		})
+	}
+}
+
+func TestNamespaceSourceQuerier_VersionRange(t *testing.T) {
+	catalog := CatalogKey{"catsrc", "ns"}
+	v1 := withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0")
+	v2 := withVersion(bundle("a.v2", "a", "alpha", "a.v1", nil, nil, nil, nil), "1.1.0")
+	v3 := withVersion(bundle("a.v3", "a", "alpha", "a.v2", nil, nil, nil, nil), "2.0.0")
+	q := NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+		// the channel head must come first
+		catalog: {v3, v2, v1},
+	})
+
+	t.Run("FindLatestBundle", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			versionRange string
+			want         *opregistry.Bundle
+			wantErr      error
+		}{
+			{
+				name: "Unconstrained/Head",
+				want: v3,
+			},
+			{
+				name:         "HeadInRange",
+				versionRange: ">=1.0.0",
+				want:         v3,
+			},
+			{
+				name:         "HeadOutOfRange/WalksBack",
+				versionRange: ">=1.0.0 <2.0.0",
+				want:         v2,
+			},
+			{
+				name:         "HeadOutOfRange/WalksToTail",
+				versionRange: "<1.1.0",
+				want:         v1,
+			},
+			{
+				name:         "NoneInRange",
+				versionRange: ">=3.0.0",
+				wantErr:      VersionNotInRangeError{Bundle: "a.v3", Version: "2.0.0", VersionRange: ">=3.0.0"},
+			},
+			{
+				name:         "InvalidRange",
+				versionRange: "not-a-range",
+				wantErr:      fmt.Errorf(`invalid version range "not-a-range": Could not get version from string: "not-a-range"`),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, _, err := q.FindLatestBundle(context.TODO(), "a", "alpha", tt.versionRange, catalog)
+				require.Equal(t, tt.wantErr, err)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("FindReplacement", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			current      *opregistry.Bundle
+			versionRange string
+			want         *opregistry.Bundle
+			wantErr      error
+		}{
+			{
+				name:    "Unconstrained",
+				current: v2,
+				want:    v3,
+			},
+			{
+				name:         "ReplacementInRange",
+				current:      v1,
+				versionRange: ">=1.0.0 <2.0.0",
+				want:         v2,
+			},
+			{
+				name:         "ReplacementOutOfRange/Blocked",
+				current:      v2,
+				versionRange: ">=1.0.0 <2.0.0",
+				wantErr:      VersionNotInRangeError{Bundle: "a.v3", Version: "2.0.0", VersionRange: ">=1.0.0 <2.0.0"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				csv, err := tt.current.ClusterServiceVersion()
+				require.NoError(t, err)
+				got, _, err := q.FindReplacement(context.TODO(), &csv.Spec.Version.Version, tt.current.Name, "a", "alpha", tt.versionRange, catalog)
+				require.Equal(t, tt.wantErr, err)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+}
+
 func TestNamespaceSourceQuerier_FindPackage(t *testing.T) {
-	initialSource := fakes.FakeInterface{}
-	otherSource := fakes.FakeInterface{}
+	initialSource := fakes.FakeSourceClient{}
+	otherSource := fakes.FakeSourceClient{}
 	initalBundle := opregistry.NewBundle("test", "testPkg", "testChannel")
 	startingBundle := opregistry.NewBundle("starting-test", "testPkg", "testChannel")
 	otherBundle := opregistry.NewBundle("other", "otherPkg", "otherChannel")
@@ -198,13 +465,13 @@ func TestNamespaceSourceQuerier_FindPackage(t *testing.T) {
 	}
 	initialKey := CatalogKey{"initial", "ns"}
 	otherKey := CatalogKey{"other", "other"}
-	sources := map[CatalogKey]client.Interface{
+	sources := map[CatalogKey]SourceClient{
 		initialKey: &initialSource,
 		otherKey:   &otherSource,
 	}
 
 	type fields struct {
-		sources map[CatalogKey]client.Interface
+		sources map[CatalogKey]SourceClient
 	}
 	type args struct {
 		pkgName       string
@@ -269,9 +536,9 @@ func TestNamespaceSourceQuerier_FindPackage(t *testing.T) {
 			var key *CatalogKey
 			var err error
 			if tt.args.startingCSV != "" {
-				got, key, err = q.FindBundle(tt.args.pkgName, tt.args.channelName, tt.args.startingCSV, tt.args.initialSource)
+				got, key, err = q.FindBundle(context.TODO(), tt.args.pkgName, tt.args.channelName, tt.args.startingCSV, tt.args.initialSource)
 			} else {
-				got, key, err = q.FindLatestBundle(tt.args.pkgName, tt.args.channelName, tt.args.initialSource)
+				got, key, err = q.FindLatestBundle(context.TODO(), tt.args.pkgName, tt.args.channelName, "", tt.args.initialSource)
 			}
 			require.Equal(t, tt.out.err, err)
 			require.Equal(t, tt.out.bundle, got)
@@ -280,13 +547,85 @@ func TestNamespaceSourceQuerier_FindPackage(t *testing.T) {
 	}
 }
 
+func TestNamespaceSourceQuerier_FindPackageErrors(t *testing.T) {
+	// registry returns a source whose registry serves only the given package, and fails with err for any other
+	registry := func(served string, err error) SourceClient {
+		r := &pkgfakes.FakeRegistryClient{}
+		r.GetPackageStub = func(ctx context.Context, req *api.GetPackageRequest, opts ...grpc.CallOption) (*api.Package, error) {
+			if req.GetName() == served {
+				return &api.Package{Name: served}, nil
+			}
+			return nil, err
+		}
+		return &RegistryClient{Client: &client.Client{Registry: r}}
+	}
+	notFound := status.Error(codes.Unknown, "package a not found")
+	unavailable := status.Error(codes.Unavailable, "transport is closing")
+
+	first := CatalogKey{"first", "ns"}
+	second := CatalogKey{"second", "ns"}
+	tests := []struct {
+		name    string
+		sources map[CatalogKey]SourceClient
+		wantKey *CatalogKey
+		wantErr error
+	}{
+		{
+			name: "FoundPastUnavailableSource",
+			sources: map[CatalogKey]SourceClient{
+				first:  registry("", unavailable),
+				second: registry("a", notFound),
+			},
+			wantKey: &second,
+		},
+		{
+			name: "FoundInFake",
+			sources: map[CatalogKey]SourceClient{
+				first: &fakes.FakeSourceClient{GetPackageStub: func(ctx context.Context, name string) (*api.Package, error) {
+					return &api.Package{Name: name}, nil
+				}},
+			},
+			wantKey: &first,
+		},
+		{
+			name: "NotFound",
+			sources: map[CatalogKey]SourceClient{
+				first:  registry("", notFound),
+				second: registry("", notFound),
+			},
+			wantErr: PackageNotFoundError{Package: "a"},
+		},
+		{
+			name: "NotFoundAndUnavailable",
+			sources: map[CatalogKey]SourceClient{
+				first:  registry("", notFound),
+				second: registry("", unavailable),
+			},
+			wantErr: utilerrors.NewAggregate([]error{fmt.Errorf("error getting package a from CatalogSource second/ns: %v", unavailable)}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewNamespaceSourceQuerier(tt.sources)
+			pkg, key, err := q.FindPackage(context.TODO(), "a", CatalogKey{})
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "a", pkg.GetName())
+			require.Equal(t, tt.wantKey, key)
+		})
+	}
+}
+
 func TestNamespaceSourceQuerier_FindReplacement(t *testing.T) {
 	// TODO: clean up this test setup
-	initialSource := fakes.FakeInterface{}
-	otherSource := fakes.FakeInterface{}
-	replacementSource := fakes.FakeInterface{}
-	replacementAndLatestSource := fakes.FakeInterface{}
-	replacementAndNoAnnotationLatestSource := fakes.FakeInterface{}
+	initialSource := fakes.FakeSourceClient{}
+	otherSource := fakes.FakeSourceClient{}
+	replacementSource := fakes.FakeSourceClient{}
+	replacementAndLatestSource := fakes.FakeSourceClient{}
+	replacementAndNoAnnotationLatestSource := fakes.FakeSourceClient{}
 
 	latestVersion := semver.MustParse("1.0.0-1556661308")
 	csv := v1alpha1.ClusterServiceVersion{
@@ -361,7 +700,7 @@ func TestNamespaceSourceQuerier_FindReplacement(t *testing.T) {
 	replacementAndLatestKey := CatalogKey{"replat", "ns"}
 	replacementAndNoAnnotationLatestKey := CatalogKey{"replatbad", "ns"}
 
-	sources := map[CatalogKey]client.Interface{
+	sources := map[CatalogKey]SourceClient{
 		initialKey:                          &initialSource,
 		otherKey:                            &otherSource,
 		replacementKey:                      &replacementSource,
@@ -373,7 +712,7 @@ func TestNamespaceSourceQuerier_FindReplacement(t *testing.T) {
 	notInRange := semver.MustParse("1.0.0-1556661347")
 
 	type fields struct {
-		sources map[CatalogKey]client.Interface
+		sources map[CatalogKey]SourceClient
 	}
 	type args struct {
 		currentVersion *semver.Version
@@ -429,6 +768,12 @@ func TestNamespaceSourceQuerier_FindReplacement(t *testing.T) {
 			args:   args{&notInRange, "testPkg", "testChannel", "test.v1", replacementAndLatestKey},
 			out:    out{bundle: nextBundle, key: &replacementAndLatestKey, err: nil},
 		},
+		{
+			name:   "NoInitialSource/FindsLatestInFirstCatalog",
+			fields: fields{sources: sources},
+			args:   args{&startVersion, "testPkg", "testChannel", "test.v1", CatalogKey{}},
+			out:    out{bundle: latestBundle, key: &initialKey, err: nil},
+		},
 		{
 			name:   "IgnoresLatestAtLatest",
 			fields: fields{sources: sources},
@@ -444,7 +789,7 @@ func TestNamespaceSourceQuerier_FindReplacement(t *testing.T) {
 			var got *opregistry.Bundle
 			var key *CatalogKey
 			var err error
-			got, key, err = q.FindReplacement(tt.args.currentVersion, tt.args.bundleName, tt.args.pkgName, tt.args.channelName, tt.args.initialSource)
+			got, key, err = q.FindReplacement(context.TODO(), tt.args.currentVersion, tt.args.bundleName, tt.args.pkgName, tt.args.channelName, "", tt.args.initialSource)
 			if err != nil {
 				t.Log(err.Error())
 			}
@@ -61,14 +61,13 @@ func RBACForClusterServiceVersion(csv *v1alpha1.ClusterServiceVersion) (map[stri
 		return nil, err
 	}
 
-	// Assume the strategy is for a deployment
-	strategyDetailsDeployment, ok := strategy.(*install.StrategyDetailsDeployment)
+	strategyWithPermissions, ok := strategy.(install.StrategyWithPermissions)
 	if !ok {
-		return nil, fmt.Errorf("could not assert strategy implementation as deployment for CSV %s", csv.GetName())
+		return nil, fmt.Errorf("could not assert strategy implementation declares permissions for CSV %s", csv.GetName())
 	}
 
 	// Resolve Permissions
-	for _, permission := range strategyDetailsDeployment.Permissions {
+	for _, permission := range strategyWithPermissions.GetPermissions() {
 		// Create ServiceAccount if necessary
 		if _, ok := permissions[permission.ServiceAccountName]; !ok {
 			serviceAccount := &corev1.ServiceAccount{}
@@ -113,7 +112,7 @@ func RBACForClusterServiceVersion(csv *v1alpha1.ClusterServiceVersion) (map[stri
 	}
 
 	// Resolve ClusterPermissions as StepResources
-	for _, permission := range strategyDetailsDeployment.ClusterPermissions {
+	for _, permission := range strategyWithPermissions.GetClusterPermissions() {
 		// Create ServiceAccount if necessary
 		if _, ok := permissions[permission.ServiceAccountName]; !ok {
 			serviceAccount := &corev1.ServiceAccount{}
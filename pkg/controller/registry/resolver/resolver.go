@@ -3,9 +3,11 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -18,51 +20,142 @@ import (
 var timeNow = func() metav1.Time { return metav1.NewTime(time.Now().UTC()) }
 
 type Resolver interface {
-	ResolveSteps(namespace string, sourceQuerier SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error)
+	ResolveSteps(ctx context.Context, namespace string, sourceQuerier SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error)
+	PreviewSteps(ctx context.Context, namespace string, sourceQuerier SourceQuerier, sub *v1alpha1.Subscription) (*Preview, error)
+}
+
+// Preview describes the outcome of a resolution without any of it being applied to the cluster.
+type Preview struct {
+	// Steps are the steps an InstallPlan generated by the resolution would contain.
+	Steps []*v1alpha1.Step
+
+	// Bundles are the bundles chosen for each new or updated operator, keyed by where they were resolved from.
+	Bundles map[OperatorSourceInfo]*opregistry.Bundle
+
+	// Downgraded are the operators that were dropped from the resolution because their required apis couldn't be satisfied.
+	Downgraded OperatorSet
+
+	// Problems are the problems found while resolving, including those that caused operators to be downgraded.
+	Problems []Problem
 }
 
 type OperatorsV1alpha1Resolver struct {
-	subLister v1alpha1listers.SubscriptionLister
-	csvLister v1alpha1listers.ClusterServiceVersionLister
+	subLister  v1alpha1listers.SubscriptionLister
+	csvLister  v1alpha1listers.ClusterServiceVersionLister
+	newEvolver func(querier SourceQuerier, gen Generation) Evolver
 }
 
 var _ Resolver = &OperatorsV1alpha1Resolver{}
 
 func NewOperatorsV1alpha1Resolver(lister operatorlister.OperatorLister) *OperatorsV1alpha1Resolver {
 	return &OperatorsV1alpha1Resolver{
-		subLister: lister.OperatorsV1alpha1().SubscriptionLister(),
-		csvLister: lister.OperatorsV1alpha1().ClusterServiceVersionLister(),
+		subLister:  lister.OperatorsV1alpha1().SubscriptionLister(),
+		csvLister:  lister.OperatorsV1alpha1().ClusterServiceVersionLister(),
+		newEvolver: NewNamespaceGenerationEvolver,
 	}
 }
 
-func (r *OperatorsV1alpha1Resolver) ResolveSteps(namespace string, sourceQuerier SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
-	if err := sourceQuerier.Queryable(); err != nil {
+// NewSatResolver returns a resolver that evolves generations with a SatEvolver, choosing every operator a namespace
+// needs by solving its constraints together instead of greedily.
+func NewSatResolver(lister operatorlister.OperatorLister) *OperatorsV1alpha1Resolver {
+	r := NewOperatorsV1alpha1Resolver(lister)
+	r.newEvolver = NewSatEvolver
+	return r
+}
+
+func (r *OperatorsV1alpha1Resolver) ResolveSteps(ctx context.Context, namespace string, sourceQuerier SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
+	subs, err := r.listSubscriptions(namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := r.resolve(ctx, namespace, sourceQuerier, subs)
+	if err != nil {
 		return nil, nil, err
 	}
 
+	// operators that were asked for by a subscription but had to be dropped can't be installed, so fail the resolution
+	// instead of silently leaving their subscriptions behind
+	for _, op := range res.downgraded {
+		if _, ok := res.subscribed[*op.SourceInfo()]; ok {
+			return nil, nil, ResolutionError{Problems: res.problems}
+		}
+	}
+
+	return res.steps, res.updatedSubs, nil
+}
+
+// PreviewSteps resolves the namespace as if the given subscription were a regular subscription, without persisting anything.
+func (r *OperatorsV1alpha1Resolver) PreviewSteps(ctx context.Context, namespace string, sourceQuerier SourceQuerier, sub *v1alpha1.Subscription) (*Preview, error) {
+	subs, err := r.listSubscriptions(namespace)
+	if err != nil {
+		return nil, err
+	}
+	subs = append(subs, sub)
+
+	res, err := r.resolve(ctx, namespace, sourceQuerier, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Preview{
+		Steps:      res.steps,
+		Bundles:    res.bundles,
+		Downgraded: res.downgraded,
+		Problems:   res.problems,
+	}, nil
+}
+
+// listSubscriptions returns the subscriptions in the namespace that take part in resolution, omitting any dry-run subscriptions
+func (r *OperatorsV1alpha1Resolver) listSubscriptions(namespace string) ([]*v1alpha1.Subscription, error) {
+	allSubs, err := r.subLister.Subscriptions(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []*v1alpha1.Subscription
+	for _, s := range allSubs {
+		if !s.IsDryRun() {
+			subs = append(subs, s)
+		}
+	}
+	return subs, nil
+}
+
+type resolution struct {
+	steps       []*v1alpha1.Step
+	updatedSubs []*v1alpha1.Subscription
+	bundles     map[OperatorSourceInfo]*opregistry.Bundle
+	downgraded  OperatorSet
+	problems    []Problem
+	subscribed  map[OperatorSourceInfo]*v1alpha1.Subscription
+}
+
+func (r *OperatorsV1alpha1Resolver) resolve(ctx context.Context, namespace string, sourceQuerier SourceQuerier, subs []*v1alpha1.Subscription) (*resolution, error) {
+	if err := sourceQuerier.Queryable(); err != nil {
+		return nil, err
+	}
+
 	// create a generation - a representation of the current set of installed operators and their provided/required apis
 	allCSVs, err := r.csvLister.ClusterServiceVersions(namespace).List(labels.Everything())
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// TODO: build this index ahead of time
 	// omit copied csvs from generation - they indicate that apis are provided to the namespace, not by the namespace
+	// omit rolled back csvs too - the csvs they were rolled back to are installed instead, and upgrades look past them
 	var csvs []*v1alpha1.ClusterServiceVersion
 	for _, c := range allCSVs {
-		if !c.IsCopied() {
+		if !c.IsCopied() && !c.IsRolledBack() {
 			csvs = append(csvs, c)
 		}
 	}
-
-	subs, err := r.subLister.Subscriptions(namespace).List(labels.Everything())
-	if err != nil {
-		return nil, nil, err
-	}
+	sourceQuerier = SkipRolledBack(sourceQuerier, allCSVs)
 
 	gen, err := NewGenerationFromCluster(csvs, subs)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// create a map of operatorsourceinfo (subscription+catalogsource data) to the original subscriptions
@@ -72,14 +165,21 @@ func (r *OperatorsV1alpha1Resolver) ResolveSteps(namespace string, sourceQuerier
 
 	// evolve a generation by resolving the set of subscriptions (in `add`) by querying with `source`
 	// and taking the current generation (in `gen`) into account
-	if err := NewNamespaceGenerationEvolver(sourceQuerier, gen).Evolve(add); err != nil {
-		return nil, nil, err
+	evolver := r.newEvolver(sourceQuerier, gen)
+	if err := evolver.Evolve(ctx, add); err != nil {
+		return nil, err
 	}
 
 	// if there's no error, we were able to satsify all constraints in the subscription set, so we calculate what
 	// changes to persist to the cluster and write them out as `steps`
-	steps := []*v1alpha1.Step{}
-	updatedSubs := []*v1alpha1.Subscription{}
+	res := &resolution{
+		steps:       []*v1alpha1.Step{},
+		updatedSubs: []*v1alpha1.Subscription{},
+		bundles:     map[OperatorSourceInfo]*opregistry.Bundle{},
+		downgraded:  evolver.Downgraded(),
+		problems:    evolver.Problems(),
+		subscribed:  subMap,
+	}
 	for name, op := range gen.Operators() {
 		_, isAdded := add[*op.SourceInfo()]
 		existingSubscription, subExists := subMap[*op.SourceInfo()]
@@ -91,12 +191,14 @@ func (r *OperatorsV1alpha1Resolver) ResolveSteps(namespace string, sourceQuerier
 
 		// add steps for any new bundle
 		if op.Bundle() != nil {
+			res.bundles[*op.SourceInfo()] = op.Bundle()
+
 			bundleSteps, err := NewStepResourceFromBundle(op.Bundle(), namespace, op.Replaces(), op.SourceInfo().Catalog.Name, op.SourceInfo().Catalog.Namespace)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to turn bundle into steps")
+				return nil, fmt.Errorf("failed to turn bundle into steps")
 			}
 			for _, s := range bundleSteps {
-				steps = append(steps, &v1alpha1.Step{
+				res.steps = append(res.steps, &v1alpha1.Step{
 					Resolving: name,
 					Resource:  s,
 					Status:    v1alpha1.StepStatusUnknown,
@@ -109,9 +211,9 @@ func (r *OperatorsV1alpha1Resolver) ResolveSteps(namespace string, sourceQuerier
 				op.SourceInfo().StartingCSV = op.Identifier()
 				subStep, err := NewSubscriptionStepResource(namespace, *op.SourceInfo())
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
-				steps = append(steps, &v1alpha1.Step{
+				res.steps = append(res.steps, &v1alpha1.Step{
 					Resolving: name,
 					Resource:  subStep,
 					Status:    v1alpha1.StepStatusUnknown,
@@ -122,11 +224,11 @@ func (r *OperatorsV1alpha1Resolver) ResolveSteps(namespace string, sourceQuerier
 		// update existing subscriptions status
 		if subExists && existingSubscription.Status.CurrentCSV != op.Identifier() {
 			existingSubscription.Status.CurrentCSV = op.Identifier()
-			updatedSubs = append(updatedSubs, existingSubscription)
+			res.updatedSubs = append(res.updatedSubs, existingSubscription)
 		}
 	}
 
-	return steps, updatedSubs, nil
+	return res, nil
 }
 
 func (r *OperatorsV1alpha1Resolver) sourceInfoForNewSubscriptions(namespace string, subs map[OperatorSourceInfo]*v1alpha1.Subscription) (add map[OperatorSourceInfo]struct{}) {
@@ -146,7 +248,6 @@ func (r *OperatorsV1alpha1Resolver) sourceInfoForNewSubscriptions(namespace stri
 
 func (r *OperatorsV1alpha1Resolver) sourceInfoToSubscriptions(subs []*v1alpha1.Subscription) (add map[OperatorSourceInfo]*v1alpha1.Subscription) {
 	add = make(map[OperatorSourceInfo]*v1alpha1.Subscription)
-	var sourceNamespace string
 	for _, s := range subs {
 		startingCSV := s.Spec.StartingCSV
 		if s.Status.CurrentCSV != "" {
@@ -154,16 +255,12 @@ func (r *OperatorsV1alpha1Resolver) sourceInfoToSubscriptions(subs []*v1alpha1.S
 			// a starting csv search.
 			startingCSV = ""
 		}
-		if s.Spec.CatalogSourceNamespace == "" {
-			sourceNamespace = s.GetNamespace()
-		} else {
-			sourceNamespace = s.Spec.CatalogSourceNamespace
-		}
 		add[OperatorSourceInfo{
-			Package:     s.Spec.Package,
-			Channel:     s.Spec.Channel,
-			StartingCSV: startingCSV,
-			Catalog:     CatalogKey{Name: s.Spec.CatalogSource, Namespace: sourceNamespace},
+			Package:      s.Spec.Package,
+			Channel:      s.Spec.Channel,
+			StartingCSV:  startingCSV,
+			Catalog:      CatalogKey{Name: s.Spec.CatalogSource, Namespace: s.GetCatalogSourceNamespace()},
+			VersionRange: s.Spec.VersionRange,
 		}] = s.DeepCopy()
 	}
 	return
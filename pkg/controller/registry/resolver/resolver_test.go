@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -55,6 +56,8 @@ func TestNamespaceResolver(t *testing.T) {
 		clusterState []runtime.Object
 		querier      SourceQuerier
 		out          out
+		// satErr is the error expected from the SatResolver when it explains a failure differently
+		satErr error
 	}{
 		{
 			name: "SingleNewSubscription/NoDeps",
@@ -97,6 +100,31 @@ func TestNamespaceResolver(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SingleNewSubscription/ResolveOne/PrefersSubscriptionCatalog",
+			clusterState: []runtime.Object{
+				newSub(namespace, "a", "alpha", catalog),
+			},
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil),
+					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
+				},
+				CatalogKey{"a-catsrc", namespace}: {
+					bundle("c.v1", "c", "gamma", "", Provides1, nil, nil, nil),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil), namespace, "", catalog),
+					bundleSteps(bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil), namespace, "", catalog),
+					subSteps(namespace, "b.v1", "b", "beta", catalog),
+				},
+				subs: []*v1alpha1.Subscription{
+					updatedSub(namespace, "a.v1", "a", "alpha", catalog),
+				},
+			},
+		},
 		{
 			name: "SingleNewSubscription/ResolveOne/AdditionalBundleObjects",
 			clusterState: []runtime.Object{
@@ -151,7 +179,36 @@ func TestNamespaceResolver(t *testing.T) {
 					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
 				},
 			}),
-			out: nothing,
+			out: out{
+				err: ResolutionError{Problems: []Problem{
+					{
+						Type:      RequiredAPINotSatisfiable,
+						Operators: []string{"a.v1"},
+						API:       &opregistry.APIKey{Group: "g", Version: "v", Kind: "k", Plural: "ks"},
+						Message:   "g/v/k (ks) required by a.v1 is not provided by any operator or CatalogSource",
+					},
+					{
+						Type:       RemovedDueToMissingDependency,
+						SourceInfo: &OperatorSourceInfo{Package: "a", Channel: "alpha", Catalog: catalog},
+						Operators:  []string{"a.v1"},
+						API:        &opregistry.APIKey{Group: "g", Version: "v", Kind: "k", Plural: "ks"},
+						Message:    "a.v1 removed from resolution because its required api g/v/k (ks) is missing",
+					},
+				}},
+			},
+			satErr: ResolutionError{Problems: []Problem{
+				{
+					Type:      RequiredAPINotSatisfiable,
+					Operators: []string{"a.v1"},
+					API:       &opregistry.APIKey{Group: "g", Version: "v", Kind: "k", Plural: "ks"},
+					Message:   "g/v/k (ks) required by a.v1 is not provided by any operator or CatalogSource",
+				},
+				{
+					Type:       ConstraintsNotSatisfiable,
+					SourceInfo: &OperatorSourceInfo{Package: "a", Channel: "alpha", Catalog: catalog},
+					Message:    "no bundle for a/alpha in catsrc/catsrc-namespace can be installed along with its dependencies and the operators in the namespace",
+				},
+			}},
 		},
 		{
 			name: "InstalledSub/NoUpdates",
@@ -187,6 +244,44 @@ func TestNamespaceResolver(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "InstalledSub/UpgradeRolledBack/UpdateAvailable",
+			clusterState: []runtime.Object{
+				existingSub(namespace, "a.v1", "a", "alpha", catalog),
+				existingOperator(namespace, "a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+				rolledBack(existingOperator(namespace, "a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil)),
+			},
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v3", "a", "alpha", "a.v2", Provides1, nil, nil, nil),
+					bundle("a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil),
+					bundle("a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(bundle("a.v3", "a", "alpha", "a.v2", Provides1, nil, nil, nil), namespace, "a.v1", catalog),
+				},
+				subs: []*v1alpha1.Subscription{
+					updatedSub(namespace, "a.v3", "a", "alpha", catalog),
+				},
+			},
+		},
+		{
+			name: "InstalledSub/UpgradeRolledBack/NoUpdates",
+			clusterState: []runtime.Object{
+				existingSub(namespace, "a.v1", "a", "alpha", catalog),
+				existingOperator(namespace, "a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+				rolledBack(existingOperator(namespace, "a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil)),
+			},
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil),
+					bundle("a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+				},
+			}),
+			out: nothing,
+		},
 		{
 			name: "InstalledSub/NoRunningOperator",
 			clusterState: []runtime.Object{
@@ -364,6 +459,125 @@ func TestNamespaceResolver(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "SingleNewSubscription/VersionRange/ResolvesNewestInRange",
+			clusterState: []runtime.Object{
+				withVersionRange(newSub(namespace, "a", "alpha", catalog), "<2.0.0"),
+			},
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withVersion(bundle("a.v2", "a", "alpha", "a.v1", nil, nil, nil, nil), "2.0.0"),
+					withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"), namespace, "", catalog),
+				},
+				subs: []*v1alpha1.Subscription{
+					withVersionRange(updatedSub(namespace, "a.v1", "a", "alpha", catalog), "<2.0.0"),
+				},
+			},
+		},
+		{
+			name: "SingleNewSubscription/VersionRange/NoneInRange",
+			clusterState: []runtime.Object{
+				withVersionRange(newSub(namespace, "a", "alpha", catalog), ">=3.0.0"),
+			},
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withVersion(bundle("a.v2", "a", "alpha", "a.v1", nil, nil, nil, nil), "2.0.0"),
+					withVersion(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), "1.0.0"),
+				},
+			}),
+			out: out{
+				err: ResolutionError{Problems: []Problem{
+					{
+						Type:       VersionNotInRange,
+						SourceInfo: &OperatorSourceInfo{Package: "a", Channel: "alpha", Catalog: catalog, VersionRange: ">=3.0.0"},
+						Operators:  []string{"a.v2"},
+						Message:    `no bundle for a/alpha in catsrc/catsrc-namespace satisfies the version range: a.v2 has version 2.0.0, which is outside of the version range ">=3.0.0"`,
+					},
+				}},
+			},
+		},
+		{
+			name: "SingleNewSubscription/RequiredPackage/ResolveOne",
+			clusterState: []runtime.Object{
+				newSub(namespace, "a", "alpha", catalog),
+			},
+			querier: NewFakeRegistrySourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), v1alpha1.PackageDependency{PackageName: "b"}),
+					bundle("b.v1", "b", "beta", "", nil, nil, nil, nil),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(withRequiredPackages(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), v1alpha1.PackageDependency{PackageName: "b"}), namespace, "", catalog),
+					bundleSteps(bundle("b.v1", "b", "beta", "", nil, nil, nil, nil), namespace, "", catalog),
+					subSteps(namespace, "b.v1", "b", "beta", catalog),
+				},
+				subs: []*v1alpha1.Subscription{
+					updatedSub(namespace, "a.v1", "a", "alpha", catalog),
+				},
+			},
+		},
+		{
+			name: "SingleNewSubscription/RequiredPackageMissing",
+			clusterState: []runtime.Object{
+				newSub(namespace, "a", "alpha", catalog),
+			},
+			querier: NewFakeRegistrySourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withRequiredPackages(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), v1alpha1.PackageDependency{PackageName: "b", VersionRange: ">=1.0.0"}),
+				},
+			}),
+			out: out{
+				err: ResolutionError{Problems: []Problem{
+					{
+						Type:      RequiredPackageNotSatisfiable,
+						Operators: []string{"a.v1"},
+						Package:   &v1alpha1.PackageDependency{PackageName: "b", VersionRange: ">=1.0.0"},
+						Message:   "package b (>=1.0.0) required by a.v1 is not provided by any operator or CatalogSource",
+					},
+					{
+						Type:       RemovedDueToMissingDependency,
+						SourceInfo: &OperatorSourceInfo{Package: "a", Channel: "alpha", Catalog: catalog},
+						Operators:  []string{"a.v1"},
+						Package:    &v1alpha1.PackageDependency{PackageName: "b", VersionRange: ">=1.0.0"},
+						Message:    "a.v1 removed from resolution because its required package b (>=1.0.0) is missing",
+					},
+				}},
+			},
+			satErr: ResolutionError{Problems: []Problem{
+				{
+					Type:      RequiredPackageNotSatisfiable,
+					Operators: []string{"a.v1"},
+					Package:   &v1alpha1.PackageDependency{PackageName: "b", VersionRange: ">=1.0.0"},
+					Message:   "package b (>=1.0.0) required by a.v1 is not provided by any operator or CatalogSource",
+				},
+				{
+					Type:       ConstraintsNotSatisfiable,
+					SourceInfo: &OperatorSourceInfo{Package: "a", Channel: "alpha", Catalog: catalog},
+					Message:    "no bundle for a/alpha in catsrc/catsrc-namespace can be installed along with its dependencies and the operators in the namespace",
+				},
+			}},
+		},
+		{
+			name: "InstalledSub/VersionRange/UpdateBlocked",
+			clusterState: []runtime.Object{
+				withVersionRange(existingSub(namespace, "a.v1", "a", "alpha", catalog), "<2.0.0"),
+				existingOperator(namespace, "a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+			},
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					withVersion(bundle("a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil), "2.0.0"),
+					withVersion(bundle("a.v1", "a", "alpha", "", Provides1, nil, nil, nil), "1.0.0"),
+				},
+			}),
+			out: nothing,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -380,12 +594,24 @@ func TestNamespaceResolver(t *testing.T) {
 			lister.OperatorsV1alpha1().RegisterSubscriptionLister(namespace, informerFactory.Operators().V1alpha1().Subscriptions().Lister())
 			lister.OperatorsV1alpha1().RegisterClusterServiceVersionLister(namespace, informerFactory.Operators().V1alpha1().ClusterServiceVersions().Lister())
 
-			resolver := NewOperatorsV1alpha1Resolver(lister)
-			steps, subs, err := resolver.ResolveSteps(namespace, tt.querier)
-			require.Equal(t, tt.out.err, err)
-			t.Logf("%#v", steps)
-			RequireStepsEqual(t, expectedSteps, steps)
-			require.ElementsMatch(t, tt.out.subs, subs)
+			// both resolvers are expected to resolve the same inputs to the same outcome
+			resolvers := map[string]Resolver{
+				"Greedy": NewOperatorsV1alpha1Resolver(lister),
+				"Sat":    NewSatResolver(lister),
+			}
+			for name, resolver := range resolvers {
+				t.Run(name, func(t *testing.T) {
+					wantErr := tt.out.err
+					if name == "Sat" && tt.satErr != nil {
+						wantErr = tt.satErr
+					}
+					steps, subs, err := resolver.ResolveSteps(context.TODO(), namespace, tt.querier)
+					require.Equal(t, wantErr, err)
+					t.Logf("%#v", steps)
+					RequireStepsEqual(t, expectedSteps, steps)
+					require.ElementsMatch(t, tt.out.subs, subs)
+				})
+			}
 		})
 	}
 }
@@ -455,7 +681,7 @@ func TestNamespaceResolverRBAC(t *testing.T) {
 
 			resolver := NewOperatorsV1alpha1Resolver(lister)
 			querier := NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{catalog: tt.bundlesInCatalog})
-			steps, subs, err := resolver.ResolveSteps(namespace, querier)
+			steps, subs, err := resolver.ResolveSteps(context.TODO(), namespace, querier)
 			require.Equal(t, tt.out.err, err)
 			RequireStepsEqual(t, expectedSteps, steps)
 			require.ElementsMatch(t, tt.out.subs, subs)
@@ -463,6 +689,165 @@ func TestNamespaceResolverRBAC(t *testing.T) {
 	}
 }
 
+func TestNamespaceResolverPreview(t *testing.T) {
+	namespace := "catsrc-namespace"
+	catalog := CatalogKey{"catsrc", namespace}
+	type out struct {
+		steps      [][]*v1alpha1.Step
+		bundles    map[OperatorSourceInfo]string
+		downgraded []string
+		err        error
+	}
+	tests := []struct {
+		name         string
+		clusterState []runtime.Object
+		sub          *v1alpha1.Subscription
+		querier      SourceQuerier
+		out          out
+	}{
+		{
+			name: "DryRunSubscription/ResolveOne",
+			sub:  dryRunSub(newSub(namespace, "a", "alpha", catalog)),
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil),
+					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil), namespace, "", catalog),
+					bundleSteps(bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil), namespace, "", catalog),
+					subSteps(namespace, "b.v1", "b", "beta", catalog),
+				},
+				bundles: map[OperatorSourceInfo]string{
+					{Package: "a", Channel: "alpha", Catalog: catalog}: "a.v1",
+					{Package: "b", Channel: "beta", Catalog: catalog}:  "b.v1",
+				},
+				downgraded: []string{},
+			},
+		},
+		{
+			name: "DryRunSubscription/DependencyMissing",
+			sub:  dryRunSub(newSub(namespace, "a", "alpha", catalog)),
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
+				},
+			}),
+			out: out{
+				bundles:    map[OperatorSourceInfo]string{},
+				downgraded: []string{"a.v1"},
+			},
+		},
+		{
+			name: "DryRunSubscription/IncludesPendingUpdates",
+			clusterState: []runtime.Object{
+				existingSub(namespace, "a.v1", "a", "alpha", catalog),
+				existingOperator(namespace, "a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+			},
+			sub: dryRunSub(newSub(namespace, "b", "beta", catalog)),
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil),
+					bundle("b.v1", "b", "beta", "", nil, Requires1, nil, nil),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(bundle("a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil), namespace, "", catalog),
+					bundleSteps(bundle("b.v1", "b", "beta", "", nil, Requires1, nil, nil), namespace, "", catalog),
+				},
+				bundles: map[OperatorSourceInfo]string{
+					{Package: "a", Channel: "alpha", Catalog: catalog}: "a.v2",
+					{Package: "b", Channel: "beta", Catalog: catalog}:  "b.v1",
+				},
+				downgraded: []string{},
+			},
+		},
+		{
+			name: "OtherDryRunSubscriptionsIgnored",
+			clusterState: []runtime.Object{
+				dryRunSub(newSub(namespace, "b", "beta", catalog)),
+			},
+			sub: dryRunSub(newSub(namespace, "a", "alpha", catalog)),
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil),
+					bundle("b.v1", "b", "beta", "", nil, nil, nil, nil),
+				},
+			}),
+			out: out{
+				steps: [][]*v1alpha1.Step{
+					bundleSteps(bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil), namespace, "", catalog),
+				},
+				bundles: map[OperatorSourceInfo]string{
+					{Package: "a", Channel: "alpha", Catalog: catalog}: "a.v1",
+				},
+				downgraded: []string{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopc := make(chan struct{})
+			defer func() {
+				stopc <- struct{}{}
+			}()
+			expectedSteps := []*v1alpha1.Step{}
+			for _, steps := range tt.out.steps {
+				expectedSteps = append(expectedSteps, steps...)
+			}
+			informerFactory, _ := StartResolverInformers(namespace, stopc, tt.clusterState...)
+			lister := operatorlister.NewLister()
+			lister.OperatorsV1alpha1().RegisterSubscriptionLister(namespace, informerFactory.Operators().V1alpha1().Subscriptions().Lister())
+			lister.OperatorsV1alpha1().RegisterClusterServiceVersionLister(namespace, informerFactory.Operators().V1alpha1().ClusterServiceVersions().Lister())
+
+			resolver := NewOperatorsV1alpha1Resolver(lister)
+			preview, err := resolver.PreviewSteps(context.TODO(), namespace, tt.querier, tt.sub)
+			require.Equal(t, tt.out.err, err)
+			RequireStepsEqual(t, expectedSteps, preview.Steps)
+
+			bundles := map[OperatorSourceInfo]string{}
+			for info, b := range preview.Bundles {
+				bundles[info] = b.Name
+			}
+			require.Equal(t, tt.out.bundles, bundles)
+
+			downgraded := []string{}
+			for name := range preview.Downgraded {
+				downgraded = append(downgraded, name)
+			}
+			require.ElementsMatch(t, tt.out.downgraded, downgraded)
+		})
+	}
+}
+
+func TestNamespaceResolverIgnoresDryRunSubscriptions(t *testing.T) {
+	namespace := "catsrc-namespace"
+	catalog := CatalogKey{"catsrc", namespace}
+
+	stopc := make(chan struct{})
+	defer func() {
+		stopc <- struct{}{}
+	}()
+	informerFactory, _ := StartResolverInformers(namespace, stopc, dryRunSub(newSub(namespace, "a", "alpha", catalog)))
+	lister := operatorlister.NewLister()
+	lister.OperatorsV1alpha1().RegisterSubscriptionLister(namespace, informerFactory.Operators().V1alpha1().Subscriptions().Lister())
+	lister.OperatorsV1alpha1().RegisterClusterServiceVersionLister(namespace, informerFactory.Operators().V1alpha1().ClusterServiceVersions().Lister())
+
+	resolver := NewOperatorsV1alpha1Resolver(lister)
+	querier := NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+		catalog: {
+			bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil),
+		},
+	})
+	steps, subs, err := resolver.ResolveSteps(context.TODO(), namespace, querier)
+	require.NoError(t, err)
+	require.Empty(t, steps)
+	require.Empty(t, subs)
+}
+
 // Helpers for resolver tests
 
 func StartResolverInformers(namespace string, stopCh <-chan struct{}, objs ...runtime.Object) (externalversions.SharedInformerFactory, []cache.InformerSynced) {
@@ -502,6 +887,16 @@ func newSub(namespace, pkg, channel string, catalog CatalogKey) *v1alpha1.Subscr
 	}
 }
 
+func withVersionRange(sub *v1alpha1.Subscription, versionRange string) *v1alpha1.Subscription {
+	sub.Spec.VersionRange = versionRange
+	return sub
+}
+
+func dryRunSub(sub *v1alpha1.Subscription) *v1alpha1.Subscription {
+	sub.SetAnnotations(map[string]string{v1alpha1.SubscriptionDryRunAnnotationKey: "true"})
+	return sub
+}
+
 func updatedSub(namespace, operatorName, pkg, channel string, catalog CatalogKey) *v1alpha1.Subscription {
 	return &v1alpha1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
@@ -548,6 +943,12 @@ func existingOperator(namespace, operatorName, pkg, channel, replaces string, pr
 	return csv
 }
 
+func rolledBack(csv *v1alpha1.ClusterServiceVersion) *v1alpha1.ClusterServiceVersion {
+	csv.Status.Phase = v1alpha1.CSVPhaseFailed
+	csv.Status.Reason = v1alpha1.CSVReasonRolledBack
+	return csv
+}
+
 func bundleSteps(bundle *opregistry.Bundle, ns, replaces string, catalog CatalogKey) []*v1alpha1.Step {
 	if replaces == "" {
 		csv, _ := bundle.ClusterServiceVersion()
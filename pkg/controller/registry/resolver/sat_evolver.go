@@ -0,0 +1,572 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/blang/semver"
+	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/pkg/errors"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver/solver"
+)
+
+// SatEvolver evolves a generation by choosing every operator it needs at once, instead of growing the generation one
+// operator at a time like the NamespaceGenerationEvolver. Installed operators, subscriptions, channel membership,
+// provided and required apis, required packages and version ranges are modeled as boolean constraints and solved
+// together, so a choice that would leave another operator unsatisfiable is backtracked instead of kept.
+//
+// Each bundle the solver may pick is a variable. Among the choices that satisfy every constraint, it prefers:
+//   - updating installed operators to their replacement over keeping them
+//   - newer bundles from a subscription's channel over older ones
+//   - installed operators over new ones when satisfying a dependency
+//   - not installing anything that isn't needed
+type SatEvolver struct {
+	querier  SourceQuerier
+	gen      Generation
+	problems []Problem
+}
+
+var _ Evolver = &SatEvolver{}
+
+func NewSatEvolver(querier SourceQuerier, gen Generation) Evolver {
+	return &SatEvolver{querier: querier, gen: gen}
+}
+
+// Downgraded is always empty: operators are never dropped to satisfy the constraints, resolution fails instead.
+func (e *SatEvolver) Downgraded() OperatorSet {
+	return EmptyOperatorSet()
+}
+
+func (e *SatEvolver) Problems() []Problem {
+	return e.problems
+}
+
+func (e *SatEvolver) fail() error {
+	return ResolutionError{Problems: e.problems}
+}
+
+// satCandidate is a bundle the solver may add to the generation
+type satCandidate struct {
+	operator *Operator
+	literal  solver.Literal
+}
+
+// satSubscription is the clause that satisfies a subscription, along with where the subscription resolves from
+type satSubscription struct {
+	info   OperatorSourceInfo
+	clause []solver.Literal
+}
+
+// satUpgrade is an installed operator that may be replaced by a candidate
+type satUpgrade struct {
+	installed OperatorSurface
+	candidate *satCandidate
+}
+
+// satModel collects the variables and clauses of a resolution
+type satModel struct {
+	querier   SourceQuerier
+	variables int
+
+	installed     map[string]solver.Literal
+	candidates    []*satCandidate
+	candidateKeys map[string]*satCandidate
+	catalogs      map[CatalogKey]struct{}
+
+	// clauses are grouped so that the problem can be rebuilt without some of them, and ordered so that the solver's
+	// preferences follow the list above
+	units         [][]solver.Literal
+	subscriptions []satSubscription
+	dependencies  [][]solver.Literal
+	exclusions    [][]solver.Literal
+
+	// unprovided are the problems of candidates with a dependency that nothing provides at all
+	unprovided map[solver.Literal][]Problem
+
+	channels map[string][]*opregistry.Bundle
+}
+
+func (m *satModel) newLiteral() solver.Literal {
+	m.variables++
+	return solver.Literal(m.variables)
+}
+
+// candidate returns the candidate for a bundle, adding it to the model the first time the bundle is seen
+func (m *satModel) candidate(bundle *opregistry.Bundle, replaces, startingCSV string, key CatalogKey, versionRange string) (*satCandidate, error) {
+	id := key.String() + "/" + bundle.Name
+	if c, ok := m.candidateKeys[id]; ok {
+		return c, nil
+	}
+	o, err := NewOperatorFromBundle(bundle, replaces, startingCSV, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing bundle")
+	}
+	o.sourceInfo.VersionRange = versionRange
+	c := &satCandidate{operator: o, literal: m.newLiteral()}
+	m.candidates = append(m.candidates, c)
+	m.candidateKeys[id] = c
+	m.catalogs[key] = struct{}{}
+	return c, nil
+}
+
+// problem builds the solver problem for the model, leaving out the clauses of the subscriptions the filter rejects
+func (m *satModel) problem(include func(satSubscription) bool) *solver.Problem {
+	p := solver.NewProblem()
+	for i := 0; i < m.variables; i++ {
+		p.NewVariable()
+	}
+	for _, c := range m.units {
+		p.AddClause(c...)
+	}
+	for _, s := range m.subscriptions {
+		if include(s) {
+			p.AddClause(s.clause...)
+		}
+	}
+	for _, c := range m.dependencies {
+		p.AddClause(c...)
+	}
+	for _, c := range m.exclusions {
+		p.AtMostOne(c...)
+	}
+	return p
+}
+
+// Evolve adds the chosen operators to the generation, and replaces the installed operators that are updated
+func (e *SatEvolver) Evolve(ctx context.Context, add map[OperatorSourceInfo]struct{}) error {
+	if err := e.querier.Queryable(); err != nil {
+		return err
+	}
+
+	m := &satModel{
+		querier:       e.querier,
+		installed:     map[string]solver.Literal{},
+		candidateKeys: map[string]*satCandidate{},
+		unprovided:    map[solver.Literal][]Problem{},
+		catalogs:      map[CatalogKey]struct{}{},
+		channels:      map[string][]*opregistry.Bundle{},
+	}
+
+	upgrades, err := e.addInstalled(ctx, m)
+	if err != nil {
+		return err
+	}
+	if err := e.addSubscriptions(ctx, m, add); err != nil {
+		return err
+	}
+	if err := e.addDependencies(ctx, m); err != nil {
+		return err
+	}
+	e.addExclusions(m)
+
+	solution, err := m.problem(func(satSubscription) bool { return true }).Solve(ctx)
+	if err == solver.ErrUnsatisfiable {
+		if err := e.explain(ctx, m); err != nil {
+			return err
+		}
+		return e.fail()
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, u := range upgrades {
+		if solution.Value(u.candidate.literal) {
+			e.gen.RemoveOperator(u.installed)
+		}
+	}
+	for _, c := range m.candidates {
+		if !solution.Value(c.literal) {
+			continue
+		}
+		if err := e.gen.AddOperator(c.operator); err != nil {
+			return errors.Wrap(err, "error calculating generation changes due to new bundle")
+		}
+	}
+	return nil
+}
+
+// addInstalled adds a variable for every operator in the generation. Operators without a subscription must stay, the
+// others may be replaced by the bundle that replaces them in their channel.
+func (e *SatEvolver) addInstalled(ctx context.Context, m *satModel) ([]satUpgrade, error) {
+	installed := e.gen.Operators().Snapshot()
+	names := make([]string, 0, len(installed))
+	for name := range installed {
+		names = append(names, name)
+		m.installed[name] = m.newLiteral()
+	}
+	sort.Strings(names)
+
+	var upgrades []satUpgrade
+	for _, name := range names {
+		op := installed[name]
+		literal := m.installed[name]
+		if op.SourceInfo() == &ExistingOperator {
+			m.units = append(m.units, []solver.Literal{literal})
+			continue
+		}
+		m.catalogs[op.SourceInfo().Catalog] = struct{}{}
+
+		info := op.SourceInfo()
+		bundle, key, err := e.querier.FindReplacement(ctx, op.Version(), op.Identifier(), info.Package, info.Channel, info.VersionRange, info.Catalog)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if err != nil || bundle == nil {
+			m.units = append(m.units, []solver.Literal{literal})
+			continue
+		}
+
+		c, err := m.candidate(bundle, op.Identifier(), info.StartingCSV, *key, info.VersionRange)
+		if err != nil {
+			return nil, err
+		}
+		upgrades = append(upgrades, satUpgrade{installed: op, candidate: c})
+		m.subscriptions = append(m.subscriptions, satSubscription{info: *info, clause: []solver.Literal{c.literal, literal}})
+	}
+	return upgrades, nil
+}
+
+// addSubscriptions adds a clause for every new subscription, satisfied by any bundle in range from its channel
+func (e *SatEvolver) addSubscriptions(ctx context.Context, m *satModel, add map[OperatorSourceInfo]struct{}) error {
+	infos := make([]OperatorSourceInfo, 0, len(add))
+	for s := range add {
+		infos = append(infos, s)
+		m.catalogs[s.Catalog] = struct{}{}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].String() != infos[j].String() {
+			return infos[i].String() < infos[j].String()
+		}
+		return infos[i].StartingCSV < infos[j].StartingCSV
+	})
+
+	failed := false
+	for _, s := range infos {
+		r, err := parseVersionRange(s.VersionRange)
+		if err != nil {
+			e.problems = append(e.problems, newInvalidVersionRangeProblem(s, err))
+			failed = true
+			continue
+		}
+
+		var bundles []*opregistry.Bundle
+		var key *CatalogKey
+		if s.StartingCSV != "" {
+			var bundle *opregistry.Bundle
+			bundle, key, err = e.querier.FindBundle(ctx, s.Package, s.Channel, s.StartingCSV, s.Catalog)
+			bundles = []*opregistry.Bundle{bundle}
+		} else {
+			bundles, key, err = m.channel(ctx, s.Package, s.Channel, s.Catalog)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			e.problems = append(e.problems, newLookupProblem(classifyMissing(ctx, e.querier, s), s, err))
+			failed = true
+			continue
+		}
+
+		var clause []solver.Literal
+		var rangeErr error
+		for _, b := range bundles {
+			if err := checkVersionRange(b, s.VersionRange, r); err != nil {
+				if rangeErr == nil {
+					rangeErr = err
+				}
+				continue
+			}
+			c, err := m.candidate(b, "", s.StartingCSV, *key, s.VersionRange)
+			if err != nil {
+				return err
+			}
+			clause = append(clause, c.literal)
+		}
+		if len(clause) == 0 {
+			if notInRange, ok := rangeErr.(VersionNotInRangeError); ok {
+				e.problems = append(e.problems, newNotInRangeProblem(s, notInRange))
+			} else {
+				e.problems = append(e.problems, newLookupProblem(BundleNotFound, s, rangeErr))
+			}
+			failed = true
+			continue
+		}
+		m.subscriptions = append(m.subscriptions, satSubscription{info: s, clause: clause})
+	}
+	if failed {
+		return e.fail()
+	}
+	return nil
+}
+
+// addDependencies adds a clause for every api and package required by a candidate, satisfied by the installed operators
+// and candidates that provide it. Candidates found along the way have their own dependencies added in turn.
+func (e *SatEvolver) addDependencies(ctx context.Context, m *satModel) error {
+	installed := e.gen.Operators()
+	names := make([]string, 0, len(installed))
+	for name := range installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i := 0; i < len(m.candidates); i++ {
+		c := m.candidates[i]
+
+		apis := make([]opregistry.APIKey, 0, len(c.operator.RequiredAPIs()))
+		for api := range c.operator.RequiredAPIs() {
+			apis = append(apis, api)
+		}
+		sort.Slice(apis, func(i, j int) bool { return apis[i].String() < apis[j].String() })
+		for _, api := range apis {
+			clause := []solver.Literal{c.literal.Not()}
+			for _, name := range names {
+				if _, ok := installed[name].ProvidedAPIs()[api]; ok {
+					clause = append(clause, m.installed[name])
+				}
+			}
+			bundles, err := m.provider(ctx, api, c.operator.SourceInfo().Catalog)
+			if err != nil {
+				return err
+			}
+			for _, b := range bundles {
+				p, err := m.candidate(b.bundle, "", "", b.key, "")
+				if err != nil {
+					return err
+				}
+				clause = append(clause, p.literal)
+			}
+			if len(clause) == 1 {
+				m.unprovided[c.literal] = append(m.unprovided[c.literal], newUnsatisfiableProblem(api, OperatorSet{c.operator.Identifier(): c.operator}))
+			}
+			m.dependencies = append(m.dependencies, clause)
+		}
+
+		for _, dep := range c.operator.RequiredPackages() {
+			clause := []solver.Literal{c.literal.Not()}
+			r, err := parseVersionRange(dep.VersionRange)
+			if err != nil {
+				// an invalid range can't be satisfied
+				m.unprovided[c.literal] = append(m.unprovided[c.literal], newUnsatisfiablePackageProblem(dep, OperatorSet{c.operator.Identifier(): c.operator}))
+				m.dependencies = append(m.dependencies, clause)
+				continue
+			}
+			inRange := func(v *semver.Version) bool {
+				return r == nil || (v != nil && r(*v))
+			}
+			for _, name := range names {
+				if op := installed[name]; op.Package() == dep.PackageName && inRange(op.Version()) {
+					clause = append(clause, m.installed[name])
+				}
+			}
+			bundles, err := m.packageBundles(ctx, dep, c.operator.SourceInfo().Catalog)
+			if err != nil {
+				return err
+			}
+			for _, b := range bundles {
+				csv, err := b.bundle.ClusterServiceVersion()
+				if err != nil {
+					return errors.Wrap(err, "error parsing bundle")
+				}
+				if !inRange(&csv.Spec.Version.Version) {
+					continue
+				}
+				p, err := m.candidate(b.bundle, "", "", b.key, dep.VersionRange)
+				if err != nil {
+					return err
+				}
+				clause = append(clause, p.literal)
+			}
+			if len(clause) == 1 {
+				m.unprovided[c.literal] = append(m.unprovided[c.literal], newUnsatisfiablePackageProblem(dep, OperatorSet{c.operator.Identifier(): c.operator}))
+			}
+			m.dependencies = append(m.dependencies, clause)
+		}
+	}
+	return nil
+}
+
+// addExclusions allows at most one operator from each package, and at most one provider of each api
+func (e *SatEvolver) addExclusions(m *satModel) {
+	packages := map[string][]solver.Literal{}
+	providers := map[opregistry.APIKey][]solver.Literal{}
+
+	installed := e.gen.Operators()
+	names := make([]string, 0, len(installed))
+	for name := range installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		op := installed[name]
+		if pkg := op.Package(); pkg != "" {
+			packages[pkg] = append(packages[pkg], m.installed[name])
+		}
+		for api := range op.ProvidedAPIs() {
+			providers[api] = append(providers[api], m.installed[name])
+		}
+	}
+	for _, c := range m.candidates {
+		pkg := c.operator.SourceInfo().Package
+		packages[pkg] = append(packages[pkg], c.literal)
+		for api := range c.operator.ProvidedAPIs() {
+			providers[api] = append(providers[api], c.literal)
+		}
+	}
+
+	pkgs := make([]string, 0, len(packages))
+	for pkg := range packages {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		m.exclusions = append(m.exclusions, packages[pkg])
+	}
+
+	apis := make([]opregistry.APIKey, 0, len(providers))
+	for api := range providers {
+		apis = append(apis, api)
+	}
+	sort.Slice(apis, func(i, j int) bool { return apis[i].String() < apis[j].String() })
+	for _, api := range apis {
+		m.exclusions = append(m.exclusions, providers[api])
+	}
+}
+
+// explain records a problem for every subscription that can't be satisfied on its own, along with the dependencies of
+// its bundles that nothing provides, or a single problem for all of them if it's only their combination that can't be
+// satisfied
+func (e *SatEvolver) explain(ctx context.Context, m *satModel) error {
+	unsatisfied := false
+	for i := range m.subscriptions {
+		s := m.subscriptions[i]
+		_, err := m.problem(func(other satSubscription) bool { return other.info == s.info }).Solve(ctx)
+		if err == solver.ErrUnsatisfiable {
+			unsatisfied = true
+			for _, l := range s.clause {
+				e.problems = append(e.problems, m.unprovided[l]...)
+			}
+			e.problems = append(e.problems, newUnsatisfiableSubscriptionProblem(s.info))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if unsatisfied {
+		return nil
+	}
+
+	infos := make([]OperatorSourceInfo, 0, len(m.subscriptions))
+	for _, s := range m.subscriptions {
+		infos = append(infos, s.info)
+	}
+	e.problems = append(e.problems, newConflictingSubscriptionsProblem(infos))
+	return nil
+}
+
+// satBundle is a bundle along with the catalog it was found in
+type satBundle struct {
+	bundle *opregistry.Bundle
+	key    CatalogKey
+}
+
+// channel returns the bundles in a channel, from its head back through the bundles each one replaces
+func (m *satModel) channel(ctx context.Context, pkg, channel string, catalog CatalogKey) ([]*opregistry.Bundle, *CatalogKey, error) {
+	head, key, err := m.querier.FindLatestBundle(ctx, pkg, channel, "", catalog)
+	if err != nil {
+		return nil, nil, err
+	}
+	id := fmt.Sprintf("%s/%s/%s", key.String(), pkg, channel)
+	if bundles, ok := m.channels[id]; ok {
+		return bundles, key, nil
+	}
+
+	bundles := []*opregistry.Bundle{head}
+	visited := map[string]struct{}{head.Name: {}}
+	for current := head; ; {
+		csv, err := current.ClusterServiceVersion()
+		if err != nil || csv.Spec.Replaces == "" {
+			break
+		}
+		if _, ok := visited[csv.Spec.Replaces]; ok {
+			break
+		}
+		replaced, replacedKey, err := m.querier.FindBundle(ctx, pkg, channel, csv.Spec.Replaces, *key)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		if err != nil || *replacedKey != *key {
+			// the channel may be pruned in the catalog, so the bundles found so far will do
+			break
+		}
+		visited[replaced.Name] = struct{}{}
+		bundles = append(bundles, replaced)
+		current = replaced
+	}
+	m.channels[id] = bundles
+	return bundles, key, nil
+}
+
+// provider returns the bundles that provide an api in every catalog known to the resolution, starting with the catalog
+// of the operator that requires it
+func (m *satModel) provider(ctx context.Context, api opregistry.APIKey, requirerCatalog CatalogKey) ([]satBundle, error) {
+	keys := make([]CatalogKey, 0, len(m.catalogs)+1)
+	keys = append(keys, requirerCatalog)
+	for key := range m.catalogs {
+		if key != requirerCatalog {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys[1:], func(i, j int) bool { return keys[1+i].String() < keys[1+j].String() })
+
+	var out []satBundle
+	seen := map[string]struct{}{}
+	for _, initial := range keys {
+		bundle, key, err := m.querier.FindProvider(ctx, api, initial)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if err != nil {
+			continue
+		}
+		id := key.String() + "/" + bundle.Name
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, satBundle{bundle: bundle, key: *key})
+	}
+	return out, nil
+}
+
+// packageBundles returns the bundles in the default channel of a required package, preferring the catalog of the
+// operator that requires it
+func (m *satModel) packageBundles(ctx context.Context, dep v1alpha1.PackageDependency, requirerCatalog CatalogKey) ([]satBundle, error) {
+	pkg, key, err := m.querier.FindPackage(ctx, dep.PackageName, requirerCatalog)
+	if err != nil && ctx.Err() == nil {
+		pkg, key, err = m.querier.FindPackage(ctx, dep.PackageName, CatalogKey{})
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, nil
+	}
+
+	bundles, key, err := m.channel(ctx, dep.PackageName, defaultChannel(pkg), *key)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, nil
+	}
+	out := make([]satBundle, len(bundles))
+	for i, b := range bundles {
+		out[i] = satBundle{bundle: b, key: *key}
+	}
+	return out, nil
+}
@@ -0,0 +1,201 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSatEvolver(t *testing.T) {
+	catalog := CatalogKey{"catsrc", "catsrc-namespace"}
+	other := CatalogKey{"other", "other-namespace"}
+	key1 := opregistry.APIKey{Group: "g", Version: "v", Kind: "k", Plural: "ks"}
+	key2 := opregistry.APIKey{Group: "g2", Version: "v2", Kind: "k2", Plural: "k2s"}
+
+	tests := []struct {
+		name          string
+		querier       SourceQuerier
+		gen           Generation
+		add           map[OperatorSourceInfo]struct{}
+		wantOperators map[string]OperatorSourceInfo
+		wantProblems  []ProblemType
+	}{
+		{
+			name: "NewSubscription/PrefersHead",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v2", "a", "alpha", "a.v1", nil, nil, nil, nil),
+					bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil),
+				},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v2": {Package: "a", Channel: "alpha", Catalog: catalog},
+			},
+		},
+		{
+			name: "NewSubscription/HeadUnsatisfiable/OlderBundleChosen",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v2", "a", "alpha", "a.v1", nil, Requires1, nil, nil),
+					bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil),
+				},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+			},
+		},
+		{
+			name: "NewSubscription/DependencyResolved",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
+					bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil),
+				},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"b.v1": {Package: "b", Channel: "beta", Catalog: catalog},
+			},
+		},
+		{
+			name: "NewSubscription/DependencySatisfiedByInstalled",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
+					bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil),
+				},
+			}),
+			gen: NewGenerationFromOperators(
+				NewFakeOperatorSurface("c.v1", "c", "gamma", "", "catsrc", "", []opregistry.APIKey{key1}, nil, nil, nil),
+			),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"c.v1": {Package: "c", Channel: "gamma", Catalog: catalog},
+			},
+		},
+		{
+			name: "NewSubscription/ConflictingProviderAvoided",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v1", "a", "alpha", "", nil, Requires1, nil, nil),
+					// the provider in the subscription's catalog also provides an api an installed operator provides
+					bundle("b.v1", "b", "beta", "", Provides1.Union(Provides2), nil, nil, nil),
+				},
+				other: {
+					bundle("d.v1", "d", "delta", "", Provides1, nil, nil, nil),
+				},
+			}),
+			gen: NewGenerationFromOperators(
+				NewFakeOperatorSurface("c.v1", "c", "gamma", "", "other", "", []opregistry.APIKey{key2}, nil, nil, nil),
+			),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+				"c.v1": {Package: "c", Channel: "gamma", Catalog: other},
+				"d.v1": {Package: "d", Channel: "delta", Catalog: other},
+			},
+		},
+		{
+			name: "InstalledSub/UpdateUnsatisfiable/Kept",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v2", "a", "alpha", "a.v1", nil, Requires1, nil, nil),
+					bundle("a.v1", "a", "alpha", "", nil, nil, nil, nil),
+				},
+			}),
+			gen: NewGenerationFromOperators(
+				NewFakeOperatorSurface("a.v1", "a", "alpha", "", "catsrc", "", nil, nil, nil, nil),
+			),
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v1": {Package: "a", Channel: "alpha", Catalog: catalog},
+			},
+		},
+		{
+			name: "InstalledSub/Updated",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v2", "a", "alpha", "a.v1", Provides1, nil, nil, nil),
+					bundle("a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+				},
+			}),
+			gen: NewGenerationFromOperators(
+				NewFakeOperatorSurface("a.v1", "a", "alpha", "", "catsrc", "", []opregistry.APIKey{key1}, nil, nil, nil),
+			),
+			wantOperators: map[string]OperatorSourceInfo{
+				"a.v2": {Package: "a", Channel: "alpha", Catalog: catalog},
+			},
+		},
+		{
+			name: "CompetingSubscriptions/Unsatisfiable",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {
+					bundle("a.v1", "a", "alpha", "", Provides1, nil, nil, nil),
+					bundle("b.v1", "b", "beta", "", Provides1, nil, nil, nil),
+				},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+				{Package: "b", Channel: "beta", Catalog: catalog}:  {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{},
+			wantProblems:  []ProblemType{ConstraintsNotSatisfiable},
+		},
+		{
+			name: "MissingPackage",
+			querier: NewFakeSourceQuerier(map[CatalogKey][]*opregistry.Bundle{
+				catalog: {},
+			}),
+			gen: NewEmptyGeneration(),
+			add: map[OperatorSourceInfo]struct{}{
+				{Package: "a", Channel: "alpha", Catalog: catalog}: {},
+			},
+			wantOperators: map[string]OperatorSourceInfo{},
+			wantProblems:  []ProblemType{PackageNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewSatEvolver(tt.querier, tt.gen)
+			err := e.Evolve(context.TODO(), tt.add)
+			if len(tt.wantProblems) > 0 {
+				require.Equal(t, ResolutionError{Problems: e.Problems()}, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var problems []ProblemType
+			for _, p := range e.Problems() {
+				problems = append(problems, p.Type)
+				require.NotEmpty(t, p.Message)
+			}
+			require.ElementsMatch(t, tt.wantProblems, problems)
+
+			operators := map[string]OperatorSourceInfo{}
+			for name, op := range tt.gen.Operators() {
+				operators[name] = *op.SourceInfo()
+			}
+			require.Equal(t, tt.wantOperators, operators)
+			require.Empty(t, tt.gen.MissingAPIs())
+		})
+	}
+}
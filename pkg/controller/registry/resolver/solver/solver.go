@@ -0,0 +1,221 @@
+// Package solver is a small boolean satisfiability solver for the constraints built during resolution.
+package solver
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnsatisfiable is returned when no assignment satisfies every clause of a problem.
+var ErrUnsatisfiable = errors.New("constraints are unsatisfiable")
+
+// Literal is a variable or its negation. Variables are numbered from 1, negative literals are negations.
+type Literal int
+
+// Not returns the negation of the literal.
+func (l Literal) Not() Literal {
+	return -l
+}
+
+func (l Literal) variable() int {
+	if l < 0 {
+		return int(-l)
+	}
+	return int(l)
+}
+
+// Problem is a set of clauses over boolean variables, each of which must have at least one true literal.
+//
+// Solve is a DPLL search whose decisions follow the order clauses were added in: the first clause that isn't
+// satisfied yet has its first unassigned literal set to true, and variables that no clause needs are left false.
+// Clauses added earlier, and literals listed earlier within a clause, are therefore preferred.
+type Problem struct {
+	variables int
+	clauses   [][]Literal
+}
+
+// NewProblem returns an empty problem.
+func NewProblem() *Problem {
+	return &Problem{}
+}
+
+// NewVariable adds a variable to the problem and returns its positive literal.
+func (p *Problem) NewVariable() Literal {
+	p.variables++
+	return Literal(p.variables)
+}
+
+// AddClause requires at least one of the given literals to be true. A clause without literals can't be satisfied.
+func (p *Problem) AddClause(literals ...Literal) {
+	clause := make([]Literal, len(literals))
+	copy(clause, literals)
+	p.clauses = append(p.clauses, clause)
+}
+
+// AtMostOne requires that no two of the given literals are true.
+func (p *Problem) AtMostOne(literals ...Literal) {
+	for i := range literals {
+		for j := i + 1; j < len(literals); j++ {
+			p.AddClause(literals[i].Not(), literals[j].Not())
+		}
+	}
+}
+
+// Solution is a satisfying assignment of a problem's variables.
+type Solution []bool
+
+// Value returns whether the literal is true in the solution.
+func (s Solution) Value(l Literal) bool {
+	v := s[l.variable()]
+	if l < 0 {
+		return !v
+	}
+	return v
+}
+
+// decision is a literal chosen during search, along with where the trail stood before it was assigned
+type decision struct {
+	literal Literal
+	trail   int
+	flipped bool
+}
+
+type search struct {
+	problem   *Problem
+	values    []int8 // 1 for true, -1 for false, 0 for unassigned; indexed by variable
+	trail     []Literal
+	decisions []decision
+}
+
+// Solve searches for an assignment that satisfies every clause. It returns ErrUnsatisfiable if there is none, or the
+// context's error if the context is done first.
+func (p *Problem) Solve(ctx context.Context) (Solution, error) {
+	s := &search{
+		problem: p,
+		values:  make([]int8, p.variables+1),
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !s.propagate() {
+			if !s.backtrack() {
+				return nil, ErrUnsatisfiable
+			}
+			continue
+		}
+
+		next, ok := s.next()
+		if !ok {
+			break
+		}
+		s.decisions = append(s.decisions, decision{literal: next, trail: len(s.trail)})
+		s.assign(next)
+	}
+
+	solution := make(Solution, p.variables+1)
+	for v := 1; v <= p.variables; v++ {
+		solution[v] = s.values[v] > 0
+	}
+	return solution, nil
+}
+
+func (s *search) value(l Literal) int8 {
+	v := s.values[l.variable()]
+	if l < 0 {
+		return -v
+	}
+	return v
+}
+
+func (s *search) assign(l Literal) {
+	if l < 0 {
+		s.values[l.variable()] = -1
+	} else {
+		s.values[l.variable()] = 1
+	}
+	s.trail = append(s.trail, l)
+}
+
+// propagate assigns the last literal of every clause whose other literals are all false, until there are no such
+// clauses left. It returns false if a clause has all of its literals false.
+func (s *search) propagate() bool {
+	for changed := true; changed; {
+		changed = false
+		for _, clause := range s.problem.clauses {
+			var unassigned Literal
+			count := 0
+			satisfied := false
+			for _, l := range clause {
+				switch s.value(l) {
+				case 1:
+					satisfied = true
+				case 0:
+					unassigned = l
+					count++
+				}
+				if satisfied {
+					break
+				}
+			}
+			if satisfied {
+				continue
+			}
+			switch count {
+			case 0:
+				return false
+			case 1:
+				s.assign(unassigned)
+				changed = true
+			}
+		}
+	}
+	return true
+}
+
+// next returns the first unassigned literal of the first clause that isn't satisfied yet
+func (s *search) next() (Literal, bool) {
+	for _, clause := range s.problem.clauses {
+		var first Literal
+		satisfied := false
+		for _, l := range clause {
+			switch s.value(l) {
+			case 1:
+				satisfied = true
+			case 0:
+				if first == 0 {
+					first = l
+				}
+			}
+			if satisfied {
+				break
+			}
+		}
+		if !satisfied && first != 0 {
+			return first, true
+		}
+	}
+	return 0, false
+}
+
+// backtrack undoes assignments up to the most recent decision that hasn't been tried both ways, and tries its
+// negation instead. It returns false if every decision has been tried both ways.
+func (s *search) backtrack() bool {
+	for len(s.decisions) > 0 {
+		d := s.decisions[len(s.decisions)-1]
+		s.decisions = s.decisions[:len(s.decisions)-1]
+		for _, l := range s.trail[d.trail:] {
+			s.values[l.variable()] = 0
+		}
+		s.trail = s.trail[:d.trail]
+
+		if !d.flipped {
+			s.decisions = append(s.decisions, decision{literal: d.literal.Not(), trail: d.trail, flipped: true})
+			s.assign(d.literal.Not())
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package solver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		// build adds clauses over the given variables, numbered from 1
+		build     func(p *Problem, v []Literal)
+		variables int
+		want      []bool
+		wantErr   error
+	}{
+		{
+			name:      "NoClauses/AllFalse",
+			build:     func(p *Problem, v []Literal) {},
+			variables: 2,
+			want:      []bool{false, false},
+		},
+		{
+			name: "EmptyClause/Unsatisfiable",
+			build: func(p *Problem, v []Literal) {
+				p.AddClause()
+			},
+			wantErr: ErrUnsatisfiable,
+		},
+		{
+			name: "Unit",
+			build: func(p *Problem, v []Literal) {
+				p.AddClause(v[1].Not())
+				p.AddClause(v[0])
+			},
+			variables: 2,
+			want:      []bool{true, false},
+		},
+		{
+			name: "Contradiction/Unsatisfiable",
+			build: func(p *Problem, v []Literal) {
+				p.AddClause(v[0])
+				p.AddClause(v[0].Not())
+			},
+			variables: 1,
+			wantErr:   ErrUnsatisfiable,
+		},
+		{
+			name: "FirstLiteralPreferred",
+			build: func(p *Problem, v []Literal) {
+				p.AddClause(v[1], v[0], v[2])
+				p.AtMostOne(v...)
+			},
+			variables: 3,
+			want:      []bool{false, true, false},
+		},
+		{
+			name: "PreferenceBacktracked",
+			build: func(p *Problem, v []Literal) {
+				// v0 is preferred, but it requires v2, which conflicts with v3
+				p.AddClause(v[0], v[1])
+				p.AddClause(v[3])
+				p.AddClause(v[0].Not(), v[2])
+				p.AtMostOne(v[2], v[3])
+				p.AtMostOne(v[0], v[1])
+			},
+			variables: 4,
+			want:      []bool{false, true, false, true},
+		},
+		{
+			name: "ImplicationsFollowed",
+			build: func(p *Problem, v []Literal) {
+				p.AddClause(v[0])
+				p.AddClause(v[0].Not(), v[1])
+				p.AddClause(v[1].Not(), v[3], v[2])
+			},
+			variables: 4,
+			want:      []bool{true, true, false, true},
+		},
+		{
+			name: "Pigeonhole/Unsatisfiable",
+			build: func(p *Problem, v []Literal) {
+				// three pigeons, two holes: v[2*i+j] is pigeon i in hole j
+				for i := 0; i < 3; i++ {
+					p.AddClause(v[2*i], v[2*i+1])
+				}
+				for j := 0; j < 2; j++ {
+					p.AtMostOne(v[j], v[2+j], v[4+j])
+				}
+			},
+			variables: 6,
+			wantErr:   ErrUnsatisfiable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProblem()
+			v := make([]Literal, tt.variables)
+			for i := range v {
+				v[i] = p.NewVariable()
+			}
+			tt.build(p, v)
+
+			solution, err := p.Solve(context.TODO())
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+			got := make([]bool, len(v))
+			for i, l := range v {
+				got[i] = solution.Value(l)
+				require.Equal(t, !got[i], solution.Value(l.Not()))
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSolveContextDone(t *testing.T) {
+	p := NewProblem()
+	p.AddClause(p.NewVariable())
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err := p.Solve(ctx)
+	require.Equal(t, context.Canceled, err)
+}
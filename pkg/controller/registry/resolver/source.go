@@ -0,0 +1,52 @@
+//go:generate counterfeiter -o fakes/fake_source_client.go . SourceClient
+package resolver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SourceClient queries the registry server of a CatalogSource.
+type SourceClient interface {
+	client.Interface
+
+	// GetPackage returns the named package. It returns a PackageNotFoundError if the registry doesn't serve the package,
+	// and any other error as is.
+	GetPackage(ctx context.Context, name string) (*api.Package, error)
+}
+
+// RegistryClient is a SourceClient for an operator-registry server.
+type RegistryClient struct {
+	*client.Client
+}
+
+var _ SourceClient = &RegistryClient{}
+
+// NewRegistryClient connects to the operator-registry server at the given address.
+func NewRegistryClient(address string) (*RegistryClient, error) {
+	c, err := client.NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistryClient{Client: c}, nil
+}
+
+func (c *RegistryClient) GetPackage(ctx context.Context, name string) (*api.Package, error) {
+	pkg, err := c.Registry.GetPackage(ctx, &api.GetPackageRequest{Name: name})
+	if err != nil && isNotFound(err) {
+		return nil, PackageNotFoundError{Package: name}
+	}
+	return pkg, err
+}
+
+// isNotFound returns true if the registry server reported that what was asked for doesn't exist.
+// Registry servers report missing content as an unknown error whose message says it wasn't found.
+func isNotFound(err error) bool {
+	s := status.Convert(err)
+	return s.Code() == codes.NotFound || (s.Code() == codes.Unknown && strings.Contains(s.Message(), "not found"))
+}
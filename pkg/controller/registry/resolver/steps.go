@@ -84,6 +84,7 @@ func NewSubscriptionStepResource(namespace string, info OperatorSourceInfo) (v1a
 			Package:                info.Package,
 			Channel:                info.Channel,
 			StartingCSV:            info.StartingCSV,
+			VersionRange:           info.VersionRange,
 			InstallPlanApproval:    v1alpha1.ApprovalAutomatic,
 		},
 	}, info.Catalog.Name, info.Catalog.Namespace)
@@ -97,6 +98,14 @@ func NewStepResourceFromBundle(bundle *registry.Bundle, namespace, replaces, cat
 
 	csv.SetNamespace(namespace)
 	csv.Spec.Replaces = replaces
+	if bundle.Package != "" {
+		annotations := csv.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.PackageAnnotationKey] = bundle.Package
+		csv.SetAnnotations(annotations)
+	}
 
 	step, err := NewStepResourceFromObject(csv, catalogSourceName, catalogSourceNamespace)
 	if err != nil {
@@ -8,9 +8,11 @@ import (
 	"testing"
 
 	"github.com/blang/semver"
+	"github.com/operator-framework/operator-registry/pkg/api"
 	"github.com/operator-framework/operator-registry/pkg/client"
 	opregistry "github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -21,6 +23,7 @@ import (
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/install"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/resolver/fakes"
+	pkgfakes "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/client/fakes"
 )
 
 // RequireStepsEqual is similar to require.ElementsMatch, but produces better error messages
@@ -231,6 +234,40 @@ func withBundleObject(bundle *opregistry.Bundle, obj *unstructured.Unstructured)
 	return bundle
 }
 
+// withVersion returns a copy of the bundle whose csv has the given version
+func withVersion(b *opregistry.Bundle, version string) *opregistry.Bundle {
+	objs := make([]*unstructured.Unstructured, 0, len(b.Objects))
+	for _, o := range b.Objects {
+		o = o.DeepCopy()
+		if o.GetKind() == v1alpha1.ClusterServiceVersionKind {
+			if err := unstructured.SetNestedField(o.Object, version, "spec", "version"); err != nil {
+				panic(err)
+			}
+		}
+		objs = append(objs, o)
+	}
+	return opregistry.NewBundle(b.Name, b.Package, b.Channel, objs...)
+}
+
+// withRequiredPackages returns a copy of the bundle whose csv requires the given packages
+func withRequiredPackages(b *opregistry.Bundle, deps ...v1alpha1.PackageDependency) *opregistry.Bundle {
+	required := make([]interface{}, 0, len(deps))
+	for _, dep := range deps {
+		required = append(required, map[string]interface{}{"packageName": dep.PackageName, "versionRange": dep.VersionRange})
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(b.Objects))
+	for _, o := range b.Objects {
+		o = o.DeepCopy()
+		if o.GetKind() == v1alpha1.ClusterServiceVersionKind {
+			if err := unstructured.SetNestedSlice(o.Object, required, "spec", "requiredPackages"); err != nil {
+				panic(err)
+			}
+		}
+		objs = append(objs, o)
+	}
+	return opregistry.NewBundle(b.Name, b.Package, b.Channel, objs...)
+}
+
 func bundleWithPermissions(name, pkg, channel, replaces string, providedCRDs, requiredCRDs, providedAPIServices, requiredAPIServices APISet, permissions, clusterPermissions []install.StrategyDeploymentPermissions) *opregistry.Bundle {
 	bundleObjs := []*unstructured.Unstructured{u(csv(name, replaces, providedCRDs, requiredCRDs, providedAPIServices, requiredAPIServices, permissions, clusterPermissions))}
 	for p := range providedCRDs {
@@ -300,9 +337,10 @@ func TestBundleStub(t *testing.T) {
 
 // NewFakeSourceQuerier builds a querier that talks to fake registry stubs for testing
 func NewFakeSourceQuerier(bundlesByCatalog map[CatalogKey][]*opregistry.Bundle) *NamespaceSourceQuerier {
-	sources := map[CatalogKey]client.Interface{}
+	sources := map[CatalogKey]SourceClient{}
 	for catKey, bundles := range bundlesByCatalog {
-		source := &fakes.FakeInterface{}
+		bundles := bundles
+		source := &fakes.FakeSourceClient{}
 		source.GetBundleThatProvidesStub = func(ctx context.Context, groupOrName, version, kind string) (*opregistry.Bundle, error) {
 			for _, b := range bundles {
 				apis, err := b.ProvidedAPIs()
@@ -348,15 +386,117 @@ func NewFakeSourceQuerier(bundlesByCatalog map[CatalogKey][]*opregistry.Bundle)
 			}
 			return nil, fmt.Errorf("no bundle found")
 		}
+
+		source.GetPackageStub = func(ctx context.Context, name string) (*api.Package, error) {
+			pkg, err := fakePackage(bundles, name)
+			if err != nil {
+				return nil, PackageNotFoundError{Package: name}
+			}
+			return pkg, nil
+		}
 		sources[catKey] = source
 	}
 	return NewNamespaceSourceQuerier(sources)
 }
 
+// fakePackage describes the named package from the bundles that belong to it. The first bundle of the package sets its
+// default channel.
+func fakePackage(bundles []*opregistry.Bundle, name string) (*api.Package, error) {
+	var pkg *api.Package
+	for _, b := range bundles {
+		if b.Package != name {
+			continue
+		}
+		if pkg == nil {
+			pkg = &api.Package{Name: b.Package, DefaultChannelName: b.Channel}
+		}
+		found := false
+		for _, c := range pkg.Channels {
+			found = found || c.Name == b.Channel
+		}
+		if !found {
+			pkg.Channels = append(pkg.Channels, &api.Channel{Name: b.Channel, CsvName: b.Name})
+		}
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("package %s not found", name)
+	}
+	return pkg, nil
+}
+
+// NewFakeRegistrySourceQuerier builds a querier whose sources are registry clients backed by fake grpc stubs.
+func NewFakeRegistrySourceQuerier(bundlesByCatalog map[CatalogKey][]*opregistry.Bundle) *NamespaceSourceQuerier {
+	sources := map[CatalogKey]SourceClient{}
+	for catKey, bundles := range bundlesByCatalog {
+		bundles := bundles
+		registry := &pkgfakes.FakeRegistryClient{}
+		registry.GetPackageStub = func(ctx context.Context, req *api.GetPackageRequest, opts ...grpc.CallOption) (*api.Package, error) {
+			return fakePackage(bundles, req.GetName())
+		}
+		registry.GetBundleForChannelStub = func(ctx context.Context, req *api.GetBundleInChannelRequest, opts ...grpc.CallOption) (*api.Bundle, error) {
+			for _, b := range bundles {
+				if b.Package == req.GetPkgName() && b.Channel == req.GetChannelName() {
+					return apiBundle(b), nil
+				}
+			}
+			return nil, fmt.Errorf("no bundle found")
+		}
+		registry.GetBundleStub = func(ctx context.Context, req *api.GetBundleRequest, opts ...grpc.CallOption) (*api.Bundle, error) {
+			for _, b := range bundles {
+				if b.Package == req.GetPkgName() && b.Channel == req.GetChannelName() && b.Name == req.GetCsvName() {
+					return apiBundle(b), nil
+				}
+			}
+			return nil, fmt.Errorf("no bundle found")
+		}
+		registry.GetBundleThatReplacesStub = func(ctx context.Context, req *api.GetReplacementRequest, opts ...grpc.CallOption) (*api.Bundle, error) {
+			for _, b := range bundles {
+				csv, err := b.ClusterServiceVersion()
+				if err != nil {
+					return nil, err
+				}
+				if b.Package == req.GetPkgName() && b.Channel == req.GetChannelName() && csv.Spec.Replaces == req.GetCsvName() {
+					return apiBundle(b), nil
+				}
+			}
+			return nil, fmt.Errorf("no bundle found")
+		}
+		registry.GetDefaultBundleThatProvidesStub = func(ctx context.Context, req *api.GetDefaultProviderRequest, opts ...grpc.CallOption) (*api.Bundle, error) {
+			for _, b := range bundles {
+				apis, err := b.ProvidedAPIs()
+				if err != nil {
+					return nil, err
+				}
+				for a := range apis {
+					if a.Group == req.GetGroup() && a.Version == req.GetVersion() && a.Kind == req.GetKind() {
+						return apiBundle(b), nil
+					}
+				}
+			}
+			return nil, fmt.Errorf("no bundle found")
+		}
+		sources[catKey] = &RegistryClient{Client: &client.Client{Registry: registry}}
+	}
+	return NewNamespaceSourceQuerier(sources)
+}
+
+// apiBundle converts a bundle to the form a registry serves it in
+func apiBundle(b *opregistry.Bundle) *api.Bundle {
+	out := &api.Bundle{CsvName: b.Name, PackageName: b.Package, ChannelName: b.Channel}
+	for _, o := range b.Objects {
+		raw, err := json.Marshal(o)
+		if err != nil {
+			panic(err)
+		}
+		out.Object = append(out.Object, string(raw))
+	}
+	return out
+}
+
 // NewFakeSourceQuerier builds a querier that talks to fake registry stubs for testing
 func NewFakeSourceQuerierCustomReplacement(catKey CatalogKey, bundle *opregistry.Bundle) *NamespaceSourceQuerier {
-	sources := map[CatalogKey]client.Interface{}
-	source := &fakes.FakeInterface{}
+	sources := map[CatalogKey]SourceClient{}
+	source := &fakes.FakeSourceClient{}
 	source.GetBundleThatProvidesStub = func(ctx context.Context, groupOrName, version, kind string) (*opregistry.Bundle, error) {
 		return nil, fmt.Errorf("no bundle found")
 	}
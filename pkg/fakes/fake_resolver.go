@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	context "context"
 	sync "sync"
 
 	v1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
@@ -9,11 +10,28 @@ import (
 )
 
 type FakeResolver struct {
-	ResolveStepsStub        func(string, resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error)
+	PreviewStepsStub        func(context.Context, string, resolver.SourceQuerier, *v1alpha1.Subscription) (*resolver.Preview, error)
+	previewStepsMutex       sync.RWMutex
+	previewStepsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 resolver.SourceQuerier
+		arg4 *v1alpha1.Subscription
+	}
+	previewStepsReturns struct {
+		result1 *resolver.Preview
+		result2 error
+	}
+	previewStepsReturnsOnCall map[int]struct {
+		result1 *resolver.Preview
+		result2 error
+	}
+	ResolveStepsStub        func(context.Context, string, resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error)
 	resolveStepsMutex       sync.RWMutex
 	resolveStepsArgsForCall []struct {
-		arg1 string
-		arg2 resolver.SourceQuerier
+		arg1 context.Context
+		arg2 string
+		arg3 resolver.SourceQuerier
 	}
 	resolveStepsReturns struct {
 		result1 []*v1alpha1.Step
@@ -29,17 +47,84 @@ type FakeResolver struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeResolver) ResolveSteps(arg1 string, arg2 resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
+func (fake *FakeResolver) PreviewSteps(arg1 context.Context, arg2 string, arg3 resolver.SourceQuerier, arg4 *v1alpha1.Subscription) (*resolver.Preview, error) {
+	fake.previewStepsMutex.Lock()
+	ret, specificReturn := fake.previewStepsReturnsOnCall[len(fake.previewStepsArgsForCall)]
+	fake.previewStepsArgsForCall = append(fake.previewStepsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 resolver.SourceQuerier
+		arg4 *v1alpha1.Subscription
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("PreviewSteps", []interface{}{arg1, arg2, arg3, arg4})
+	fake.previewStepsMutex.Unlock()
+	if fake.PreviewStepsStub != nil {
+		return fake.PreviewStepsStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.previewStepsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeResolver) PreviewStepsCallCount() int {
+	fake.previewStepsMutex.RLock()
+	defer fake.previewStepsMutex.RUnlock()
+	return len(fake.previewStepsArgsForCall)
+}
+
+func (fake *FakeResolver) PreviewStepsCalls(stub func(context.Context, string, resolver.SourceQuerier, *v1alpha1.Subscription) (*resolver.Preview, error)) {
+	fake.previewStepsMutex.Lock()
+	defer fake.previewStepsMutex.Unlock()
+	fake.PreviewStepsStub = stub
+}
+
+func (fake *FakeResolver) PreviewStepsArgsForCall(i int) (context.Context, string, resolver.SourceQuerier, *v1alpha1.Subscription) {
+	fake.previewStepsMutex.RLock()
+	defer fake.previewStepsMutex.RUnlock()
+	argsForCall := fake.previewStepsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeResolver) PreviewStepsReturns(result1 *resolver.Preview, result2 error) {
+	fake.previewStepsMutex.Lock()
+	defer fake.previewStepsMutex.Unlock()
+	fake.PreviewStepsStub = nil
+	fake.previewStepsReturns = struct {
+		result1 *resolver.Preview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResolver) PreviewStepsReturnsOnCall(i int, result1 *resolver.Preview, result2 error) {
+	fake.previewStepsMutex.Lock()
+	defer fake.previewStepsMutex.Unlock()
+	fake.PreviewStepsStub = nil
+	if fake.previewStepsReturnsOnCall == nil {
+		fake.previewStepsReturnsOnCall = make(map[int]struct {
+			result1 *resolver.Preview
+			result2 error
+		})
+	}
+	fake.previewStepsReturnsOnCall[i] = struct {
+		result1 *resolver.Preview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResolver) ResolveSteps(arg1 context.Context, arg2 string, arg3 resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error) {
 	fake.resolveStepsMutex.Lock()
 	ret, specificReturn := fake.resolveStepsReturnsOnCall[len(fake.resolveStepsArgsForCall)]
 	fake.resolveStepsArgsForCall = append(fake.resolveStepsArgsForCall, struct {
-		arg1 string
-		arg2 resolver.SourceQuerier
-	}{arg1, arg2})
-	fake.recordInvocation("ResolveSteps", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 resolver.SourceQuerier
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ResolveSteps", []interface{}{arg1, arg2, arg3})
 	fake.resolveStepsMutex.Unlock()
 	if fake.ResolveStepsStub != nil {
-		return fake.ResolveStepsStub(arg1, arg2)
+		return fake.ResolveStepsStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -54,17 +139,17 @@ func (fake *FakeResolver) ResolveStepsCallCount() int {
 	return len(fake.resolveStepsArgsForCall)
 }
 
-func (fake *FakeResolver) ResolveStepsCalls(stub func(string, resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error)) {
+func (fake *FakeResolver) ResolveStepsCalls(stub func(context.Context, string, resolver.SourceQuerier) ([]*v1alpha1.Step, []*v1alpha1.Subscription, error)) {
 	fake.resolveStepsMutex.Lock()
 	defer fake.resolveStepsMutex.Unlock()
 	fake.ResolveStepsStub = stub
 }
 
-func (fake *FakeResolver) ResolveStepsArgsForCall(i int) (string, resolver.SourceQuerier) {
+func (fake *FakeResolver) ResolveStepsArgsForCall(i int) (context.Context, string, resolver.SourceQuerier) {
 	fake.resolveStepsMutex.RLock()
 	defer fake.resolveStepsMutex.RUnlock()
 	argsForCall := fake.resolveStepsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeResolver) ResolveStepsReturns(result1 []*v1alpha1.Step, result2 []*v1alpha1.Subscription, result3 error) {
@@ -99,6 +184,8 @@ func (fake *FakeResolver) ResolveStepsReturnsOnCall(i int, result1 []*v1alpha1.S
 func (fake *FakeResolver) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.previewStepsMutex.RLock()
+	defer fake.previewStepsMutex.RUnlock()
 	fake.resolveStepsMutex.RLock()
 	defer fake.resolveStepsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
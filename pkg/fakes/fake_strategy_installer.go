@@ -32,6 +32,27 @@ type FakeStrategyInstaller struct {
 	installReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UninstallStub        func(install.Strategy) error
+	uninstallMutex       sync.RWMutex
+	uninstallArgsForCall []struct {
+		arg1 install.Strategy
+	}
+	uninstallReturns struct {
+		result1 error
+	}
+	uninstallReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateTemplateAnnotationsStub        func() error
+	updateTemplateAnnotationsMutex       sync.RWMutex
+	updateTemplateAnnotationsArgsForCall []struct {
+	}
+	updateTemplateAnnotationsReturns struct {
+		result1 error
+	}
+	updateTemplateAnnotationsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -159,6 +180,118 @@ func (fake *FakeStrategyInstaller) InstallReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeStrategyInstaller) Uninstall(arg1 install.Strategy) error {
+	fake.uninstallMutex.Lock()
+	ret, specificReturn := fake.uninstallReturnsOnCall[len(fake.uninstallArgsForCall)]
+	fake.uninstallArgsForCall = append(fake.uninstallArgsForCall, struct {
+		arg1 install.Strategy
+	}{arg1})
+	fake.recordInvocation("Uninstall", []interface{}{arg1})
+	fake.uninstallMutex.Unlock()
+	if fake.UninstallStub != nil {
+		return fake.UninstallStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.uninstallReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeStrategyInstaller) UninstallCallCount() int {
+	fake.uninstallMutex.RLock()
+	defer fake.uninstallMutex.RUnlock()
+	return len(fake.uninstallArgsForCall)
+}
+
+func (fake *FakeStrategyInstaller) UninstallCalls(stub func(install.Strategy) error) {
+	fake.uninstallMutex.Lock()
+	defer fake.uninstallMutex.Unlock()
+	fake.UninstallStub = stub
+}
+
+func (fake *FakeStrategyInstaller) UninstallArgsForCall(i int) install.Strategy {
+	fake.uninstallMutex.RLock()
+	defer fake.uninstallMutex.RUnlock()
+	argsForCall := fake.uninstallArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStrategyInstaller) UninstallReturns(result1 error) {
+	fake.uninstallMutex.Lock()
+	defer fake.uninstallMutex.Unlock()
+	fake.UninstallStub = nil
+	fake.uninstallReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStrategyInstaller) UninstallReturnsOnCall(i int, result1 error) {
+	fake.uninstallMutex.Lock()
+	defer fake.uninstallMutex.Unlock()
+	fake.UninstallStub = nil
+	if fake.uninstallReturnsOnCall == nil {
+		fake.uninstallReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uninstallReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStrategyInstaller) UpdateTemplateAnnotations() error {
+	fake.updateTemplateAnnotationsMutex.Lock()
+	ret, specificReturn := fake.updateTemplateAnnotationsReturnsOnCall[len(fake.updateTemplateAnnotationsArgsForCall)]
+	fake.updateTemplateAnnotationsArgsForCall = append(fake.updateTemplateAnnotationsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("UpdateTemplateAnnotations", []interface{}{})
+	fake.updateTemplateAnnotationsMutex.Unlock()
+	if fake.UpdateTemplateAnnotationsStub != nil {
+		return fake.UpdateTemplateAnnotationsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updateTemplateAnnotationsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeStrategyInstaller) UpdateTemplateAnnotationsCallCount() int {
+	fake.updateTemplateAnnotationsMutex.RLock()
+	defer fake.updateTemplateAnnotationsMutex.RUnlock()
+	return len(fake.updateTemplateAnnotationsArgsForCall)
+}
+
+func (fake *FakeStrategyInstaller) UpdateTemplateAnnotationsCalls(stub func() error) {
+	fake.updateTemplateAnnotationsMutex.Lock()
+	defer fake.updateTemplateAnnotationsMutex.Unlock()
+	fake.UpdateTemplateAnnotationsStub = stub
+}
+
+func (fake *FakeStrategyInstaller) UpdateTemplateAnnotationsReturns(result1 error) {
+	fake.updateTemplateAnnotationsMutex.Lock()
+	defer fake.updateTemplateAnnotationsMutex.Unlock()
+	fake.UpdateTemplateAnnotationsStub = nil
+	fake.updateTemplateAnnotationsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStrategyInstaller) UpdateTemplateAnnotationsReturnsOnCall(i int, result1 error) {
+	fake.updateTemplateAnnotationsMutex.Lock()
+	defer fake.updateTemplateAnnotationsMutex.Unlock()
+	fake.UpdateTemplateAnnotationsStub = nil
+	if fake.updateTemplateAnnotationsReturnsOnCall == nil {
+		fake.updateTemplateAnnotationsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateTemplateAnnotationsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStrategyInstaller) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -166,6 +299,10 @@ func (fake *FakeStrategyInstaller) Invocations() map[string][][]interface{} {
 	defer fake.checkInstalledMutex.RUnlock()
 	fake.installMutex.RLock()
 	defer fake.installMutex.RUnlock()
+	fake.uninstallMutex.RLock()
+	defer fake.uninstallMutex.RUnlock()
+	fake.updateTemplateAnnotationsMutex.RLock()
+	defer fake.updateTemplateAnnotationsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -29,12 +29,13 @@ type replace struct {
 }
 
 // IsBeingReplaced returns the corresponding ClusterServiceVersion object that
-// is replacing the given CSV specified.
+// is replacing the given CSV specified. CSVs that were rolled back no longer
+// replace anything.
 //
 // If the corresponding ClusterServiceVersion is not found nil is returned.
 func (r *replace) IsBeingReplaced(in *v1alpha1.ClusterServiceVersion, csvsInNamespace map[string]*v1alpha1.ClusterServiceVersion) (replacedBy *v1alpha1.ClusterServiceVersion) {
 	for _, csv := range csvsInNamespace {
-		if csv.IsCopied() {
+		if csv.IsCopied() || csv.IsRolledBack() {
 			continue
 		}
 
@@ -0,0 +1,214 @@
+package operatorclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// UnstructuredClient contains methods for manipulating objects of any kind the cluster serves. Kinds are mapped to
+// their resources, and whether they are namespaced, with discovery, whose results are cached. The namespace is ignored
+// for cluster-scoped kinds.
+type UnstructuredClient interface {
+	Namespaced(gvk schema.GroupVersionKind) (bool, error)
+	GetUnstructured(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error)
+	ListUnstructured(gvk schema.GroupVersionKind, namespace string, options metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	CreateUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	UpdateUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	DeleteUnstructured(gvk schema.GroupVersionKind, namespace, name string, options *metav1.DeleteOptions) error
+}
+
+type unstructuredClient struct {
+	discovery discovery.DiscoveryInterface
+	rest      rest.Interface
+
+	// resources caches the resources served by each group version that has been discovered
+	resources     map[string]*metav1.APIResourceList
+	resourcesLock sync.RWMutex
+}
+
+var _ UnstructuredClient = &unstructuredClient{}
+
+// NewUnstructuredClient returns an UnstructuredClient that finds resources with the given discovery client, and makes
+// requests with the given REST client. Any REST client for the cluster will do, requests use absolute paths.
+func NewUnstructuredClient(discovery discovery.DiscoveryInterface, restClient rest.Interface) UnstructuredClient {
+	return &unstructuredClient{
+		discovery: discovery,
+		rest:      restClient,
+		resources: map[string]*metav1.APIResourceList{},
+	}
+}
+
+// resourceFor returns the resource that serves the given kind. A group version is only discovered again when the
+// cached resources don't serve the kind, which happens when it's new, e.g. when a CRD was just created.
+func (c *unstructuredClient) resourceFor(gvk schema.GroupVersionKind) (*metav1.APIResource, error) {
+	groupVersion := gvk.GroupVersion().String()
+	c.resourcesLock.RLock()
+	resources, ok := c.resources[groupVersion]
+	c.resourcesLock.RUnlock()
+	if ok {
+		if resource := findResource(resources, gvk.Kind); resource != nil {
+			return resource, nil
+		}
+	}
+
+	resources, err := c.discovery.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return nil, err
+	}
+	c.resourcesLock.Lock()
+	c.resources[groupVersion] = resources
+	c.resourcesLock.Unlock()
+
+	if resource := findResource(resources, gvk.Kind); resource != nil {
+		return resource, nil
+	}
+	return nil, fmt.Errorf("no resource serves %s", gvk)
+}
+
+// findResource returns the resource in the list that serves the given kind, if any.
+func findResource(resources *metav1.APIResourceList, kind string) *metav1.APIResource {
+	for _, resource := range resources.APIResources {
+		// Skip subresources, such as status and scale, which are listed alongside their resources
+		if resource.Kind == kind && !strings.Contains(resource.Name, "/") {
+			return &resource
+		}
+	}
+	return nil
+}
+
+// resourcePath returns the path of the named object of the given kind, or of the collection of objects of that kind
+// when the name is empty. The collection of a namespaced kind spans all namespaces when the namespace is empty too.
+func (c *unstructuredClient) resourcePath(gvk schema.GroupVersionKind, namespace, name string) (string, error) {
+	resource, err := c.resourceFor(gvk)
+	if err != nil {
+		return "", err
+	}
+
+	segments := []string{"/apis", gvk.Group, gvk.Version}
+	if gvk.Group == "" {
+		segments = []string{"/api", gvk.Version}
+	}
+	if resource.Namespaced {
+		if namespace == "" && name != "" {
+			return "", fmt.Errorf("%s %s is namespaced, but no namespace was given", gvk.Kind, name)
+		}
+		if namespace != "" {
+			segments = append(segments, "namespaces", namespace)
+		}
+	}
+	segments = append(segments, resource.Name, name)
+
+	return path.Join(segments...), nil
+}
+
+// Namespaced returns whether objects of the given kind live in namespaces.
+func (c *unstructuredClient) Namespaced(gvk schema.GroupVersionKind) (bool, error) {
+	resource, err := c.resourceFor(gvk)
+	if err != nil {
+		return false, err
+	}
+	return resource.Namespaced, nil
+}
+
+// GetUnstructured returns the existing object.
+func (c *unstructuredClient) GetUnstructured(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	glog.V(4).Infof("[GET UNSTRUCTURED]: %s %s:%s", gvk, namespace, name)
+	uri, err := c.resourcePath(gvk, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUnstructured(c.rest.Get().AbsPath(uri).DoRaw())
+}
+
+// ListUnstructured returns the objects of the given kind in the namespace, or in all namespaces if it is empty.
+// Large lists can be read a page at a time with the limit and continue options.
+func (c *unstructuredClient) ListUnstructured(gvk schema.GroupVersionKind, namespace string, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	glog.V(4).Infof("[LIST UNSTRUCTURED]: %s %s", gvk, namespace)
+	uri, err := c.resourcePath(gvk, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.rest.Get().AbsPath(uri).VersionedParams(&options, metav1.ParameterCodec).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{}
+	if err := list.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal list: %v", err)
+	}
+	return list, nil
+}
+
+// CreateUnstructured creates the object.
+func (c *unstructuredClient) CreateUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	glog.V(4).Infof("[CREATE UNSTRUCTURED]: %s %s:%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	uri, err := c.resourcePath(obj.GroupVersionKind(), obj.GetNamespace(), "")
+	if err != nil {
+		return nil, err
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUnstructured(c.rest.Post().AbsPath(uri).SetHeader("Content-Type", "application/json").Body(data).DoRaw())
+}
+
+// UpdateUnstructured replaces the existing object with the given one. The given object's resource version is used
+// as a precondition if it is set.
+func (c *unstructuredClient) UpdateUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	glog.V(4).Infof("[UPDATE UNSTRUCTURED]: %s %s:%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	uri, err := c.resourcePath(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return nil, err
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUnstructured(c.rest.Put().AbsPath(uri).SetHeader("Content-Type", "application/json").Body(data).DoRaw())
+}
+
+// DeleteUnstructured deletes the object.
+func (c *unstructuredClient) DeleteUnstructured(gvk schema.GroupVersionKind, namespace, name string, options *metav1.DeleteOptions) error {
+	glog.V(4).Infof("[DELETE UNSTRUCTURED]: %s %s:%s", gvk, namespace, name)
+	uri, err := c.resourcePath(gvk, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	request := c.rest.Delete().AbsPath(uri)
+	if options != nil {
+		data, err := json.Marshal(options)
+		if err != nil {
+			return err
+		}
+		request = request.SetHeader("Content-Type", "application/json").Body(data)
+	}
+	_, err = request.DoRaw()
+	return err
+}
+
+func decodeUnstructured(data []byte, err error) (*unstructured.Unstructured, error) {
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal object: %v", err)
+	}
+	return obj, nil
+}
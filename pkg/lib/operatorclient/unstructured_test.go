@@ -0,0 +1,131 @@
+package operatorclient
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestUnstructuredClient(t *testing.T) {
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	priorityClass := schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}
+
+	// the server answers gets of "missing" with NotFound, and echoes the bodies of all other requests
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/ns/configmaps/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "missing").Status())
+			return
+		}
+		if r.URL.Path == "/api/v1/configmaps" {
+			w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMapList","items":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns"}}]}`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) == 0 {
+			body = []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns"}}`)
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	restClient, err := rest.RESTClientFor(&rest.Config{
+		Host:    server.URL,
+		APIPath: "/api",
+		ContentConfig: rest.ContentConfig{
+			GroupVersion:         &corev1.SchemeGroupVersion,
+			NegotiatedSerializer: scheme.Codecs,
+		},
+	})
+	require.NoError(t, err)
+
+	discovery := &fake.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"},
+			},
+		},
+		{
+			GroupVersion: "scheduling.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "priorityclasses/status", Kind: "PriorityClass"},
+				{Name: "priorityclasses", Kind: "PriorityClass"},
+			},
+		},
+	}
+	client := NewUnstructuredClient(discovery, restClient)
+
+	namespaced, err := client.Namespaced(configMap)
+	require.NoError(t, err)
+	require.True(t, namespaced)
+	namespaced, err = client.Namespaced(priorityClass)
+	require.NoError(t, err)
+	require.False(t, namespaced)
+
+	cm := &unstructured.Unstructured{}
+	cm.SetGroupVersionKind(configMap)
+	cm.SetName("cm")
+	cm.SetNamespace("ns")
+	created, err := client.CreateUnstructured(cm)
+	require.NoError(t, err)
+	require.Equal(t, cm, created)
+
+	_, err = client.UpdateUnstructured(cm)
+	require.NoError(t, err)
+
+	got, err := client.GetUnstructured(configMap, "ns", "cm")
+	require.NoError(t, err)
+	require.Equal(t, "cm", got.GetName())
+
+	_, err = client.GetUnstructured(configMap, "ns", "missing")
+	require.True(t, k8serrors.IsNotFound(err))
+
+	list, err := client.ListUnstructured(configMap, "", metav1.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "cm", list.Items[0].GetName())
+
+	pc := &unstructured.Unstructured{}
+	pc.SetGroupVersionKind(priorityClass)
+	pc.SetName("pc")
+	// the namespace is ignored for cluster-scoped kinds
+	pc.SetNamespace("ns")
+	_, err = client.CreateUnstructured(pc)
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteUnstructured(priorityClass, "", "pc", &metav1.DeleteOptions{}))
+
+	_, err = client.CreateUnstructured(&unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Unknown"}})
+	require.EqualError(t, err, "no resource serves /v1, Kind=Unknown")
+
+	require.Equal(t, []string{
+		"POST /api/v1/namespaces/ns/configmaps",
+		"PUT /api/v1/namespaces/ns/configmaps/cm",
+		"GET /api/v1/namespaces/ns/configmaps/cm",
+		"GET /api/v1/namespaces/ns/configmaps/missing",
+		"GET /api/v1/configmaps?limit=10",
+		"POST /apis/scheduling.k8s.io/v1/priorityclasses",
+		"DELETE /apis/scheduling.k8s.io/v1/priorityclasses/pc",
+	}, requests)
+
+	// each group version was discovered once, and the core group again when it was asked for an unknown kind
+	require.Len(t, discovery.Actions(), 3)
+}
@@ -0,0 +1,52 @@
+package operatorlister
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	v1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
+	listers "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/listers/operators/v1"
+)
+
+type UnionCatalogPolicyLister struct {
+	catalogPolicyLister listers.CatalogPolicyLister
+	catalogPolicyLock   sync.RWMutex
+}
+
+// List lists all CatalogPolicies in the indexer.
+func (ucl *UnionCatalogPolicyLister) List(selector labels.Selector) (ret []*v1.CatalogPolicy, err error) {
+	ucl.catalogPolicyLock.RLock()
+	defer ucl.catalogPolicyLock.RUnlock()
+
+	if ucl.catalogPolicyLister == nil {
+		return nil, fmt.Errorf("no catalogPolicy lister registered")
+	}
+	return ucl.catalogPolicyLister.List(selector)
+}
+
+func (ucl *UnionCatalogPolicyLister) Get(name string) (*v1.CatalogPolicy, error) {
+	ucl.catalogPolicyLock.RLock()
+	defer ucl.catalogPolicyLock.RUnlock()
+
+	if ucl.catalogPolicyLister == nil {
+		return nil, fmt.Errorf("no catalogPolicy lister registered")
+	}
+	return ucl.catalogPolicyLister.Get(name)
+}
+
+func (ucl *UnionCatalogPolicyLister) RegisterCatalogPolicyLister(lister listers.CatalogPolicyLister) {
+	ucl.catalogPolicyLock.Lock()
+	defer ucl.catalogPolicyLock.Unlock()
+
+	ucl.catalogPolicyLister = lister
+}
+
+func (l *operatorsV1Lister) RegisterCatalogPolicyLister(lister listers.CatalogPolicyLister) {
+	l.catalogPolicyLister.RegisterCatalogPolicyLister(lister)
+}
+
+func (l *operatorsV1Lister) CatalogPolicyLister() listers.CatalogPolicyLister {
+	return l.catalogPolicyLister
+}
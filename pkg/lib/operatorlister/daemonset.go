@@ -0,0 +1,130 @@
+package operatorlister
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	appsv1 "k8s.io/client-go/listers/apps/v1"
+)
+
+type UnionDaemonSetLister struct {
+	daemonSetListers map[string]appsv1.DaemonSetLister
+	daemonSetLock    sync.RWMutex
+}
+
+// List lists all DaemonSets in the indexer.
+func (udsl *UnionDaemonSetLister) List(selector labels.Selector) (ret []*v1.DaemonSet, err error) {
+	udsl.daemonSetLock.RLock()
+	defer udsl.daemonSetLock.RUnlock()
+
+	set := make(map[types.UID]*v1.DaemonSet)
+	for _, dsl := range udsl.daemonSetListers {
+		daemonSets, err := dsl.List(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, daemonSet := range daemonSets {
+			set[daemonSet.GetUID()] = daemonSet
+		}
+	}
+
+	for _, daemonSet := range set {
+		ret = append(ret, daemonSet)
+	}
+
+	return
+}
+
+// DaemonSets returns an object that can list and get DaemonSets.
+func (udsl *UnionDaemonSetLister) DaemonSets(namespace string) appsv1.DaemonSetNamespaceLister {
+	udsl.daemonSetLock.RLock()
+	defer udsl.daemonSetLock.RUnlock()
+
+	// Check for specific namespace listers
+	if dsl, ok := udsl.daemonSetListers[namespace]; ok {
+		return dsl.DaemonSets(namespace)
+	}
+
+	// Check for any namespace-all listers
+	if dsl, ok := udsl.daemonSetListers[metav1.NamespaceAll]; ok {
+		return dsl.DaemonSets(namespace)
+	}
+
+	return &NullDaemonSetNamespaceLister{}
+}
+
+func (udsl *UnionDaemonSetLister) GetPodDaemonSets(pod *corev1.Pod) ([]*v1.DaemonSet, error) {
+	udsl.daemonSetLock.RLock()
+	defer udsl.daemonSetLock.RUnlock()
+
+	// Check for specific namespace listers
+	if dsl, ok := udsl.daemonSetListers[pod.GetNamespace()]; ok {
+		return dsl.GetPodDaemonSets(pod)
+	}
+
+	// Check for any namespace-all listers
+	if dsl, ok := udsl.daemonSetListers[metav1.NamespaceAll]; ok {
+		return dsl.GetPodDaemonSets(pod)
+	}
+
+	return nil, fmt.Errorf("no listers found for namespace %s", pod.GetNamespace())
+}
+
+func (udsl *UnionDaemonSetLister) GetHistoryDaemonSets(history *v1.ControllerRevision) ([]*v1.DaemonSet, error) {
+	udsl.daemonSetLock.RLock()
+	defer udsl.daemonSetLock.RUnlock()
+
+	// Check for specific namespace listers
+	if dsl, ok := udsl.daemonSetListers[history.GetNamespace()]; ok {
+		return dsl.GetHistoryDaemonSets(history)
+	}
+
+	// Check for any namespace-all listers
+	if dsl, ok := udsl.daemonSetListers[metav1.NamespaceAll]; ok {
+		return dsl.GetHistoryDaemonSets(history)
+	}
+
+	return nil, fmt.Errorf("no listers found for namespace %s", history.GetNamespace())
+}
+
+func (udsl *UnionDaemonSetLister) RegisterDaemonSetLister(namespace string, lister appsv1.DaemonSetLister) {
+	udsl.daemonSetLock.Lock()
+	defer udsl.daemonSetLock.Unlock()
+
+	if udsl.daemonSetListers == nil {
+		udsl.daemonSetListers = make(map[string]appsv1.DaemonSetLister)
+	}
+
+	udsl.daemonSetListers[namespace] = lister
+}
+
+func (l *appsV1Lister) RegisterDaemonSetLister(namespace string, lister appsv1.DaemonSetLister) {
+	l.daemonSetLister.RegisterDaemonSetLister(namespace, lister)
+}
+
+func (l *appsV1Lister) DaemonSetLister() appsv1.DaemonSetLister {
+	return l.daemonSetLister
+}
+
+// NullDaemonSetNamespaceLister is an implementation of a null DaemonSetNamespaceLister. It is
+// used to prevent nil pointers when no DaemonSetNamespaceLister has been registered for a given
+// namespace.
+type NullDaemonSetNamespaceLister struct {
+	appsv1.DaemonSetNamespaceLister
+}
+
+// List returns nil and an error explaining that this is a NullDaemonSetNamespaceLister.
+func (n *NullDaemonSetNamespaceLister) List(selector labels.Selector) (ret []*v1.DaemonSet, err error) {
+	return nil, fmt.Errorf("cannot list DaemonSets with a NullDaemonSetNamespaceLister")
+}
+
+// Get returns nil and an error explaining that this is a NullDaemonSetNamespaceLister.
+func (n *NullDaemonSetNamespaceLister) Get(name string) (*v1.DaemonSet, error) {
+	return nil, fmt.Errorf("cannot get DaemonSet with a NullDaemonSetNamespaceLister")
+}
@@ -27,8 +27,12 @@ type OperatorLister interface {
 //go:generate counterfeiter . AppsV1Lister
 type AppsV1Lister interface {
 	DeploymentLister() appsv1.DeploymentLister
+	StatefulSetLister() appsv1.StatefulSetLister
+	DaemonSetLister() appsv1.DaemonSetLister
 
 	RegisterDeploymentLister(namespace string, lister appsv1.DeploymentLister)
+	RegisterStatefulSetLister(namespace string, lister appsv1.StatefulSetLister)
+	RegisterDaemonSetLister(namespace string, lister appsv1.DaemonSetLister)
 }
 
 //go:generate counterfeiter . CoreV1Lister
@@ -91,17 +95,23 @@ type OperatorsV1alpha1Lister interface {
 //go:generate counterfeiter . OperatorsV1Lister
 type OperatorsV1Lister interface {
 	RegisterOperatorGroupLister(namespace string, lister v1.OperatorGroupLister)
+	RegisterCatalogPolicyLister(lister v1.CatalogPolicyLister)
 
 	OperatorGroupLister() v1.OperatorGroupLister
+	CatalogPolicyLister() v1.CatalogPolicyLister
 }
 
 type appsV1Lister struct {
-	deploymentLister *UnionDeploymentLister
+	deploymentLister  *UnionDeploymentLister
+	statefulSetLister *UnionStatefulSetLister
+	daemonSetLister   *UnionDaemonSetLister
 }
 
 func newAppsV1Lister() *appsV1Lister {
 	return &appsV1Lister{
-		deploymentLister: &UnionDeploymentLister{},
+		deploymentLister:  &UnionDeploymentLister{},
+		statefulSetLister: &UnionStatefulSetLister{},
+		daemonSetLister:   &UnionDaemonSetLister{},
 	}
 }
 
@@ -179,11 +189,13 @@ func newOperatorsV1alpha1Lister() *operatorsV1alpha1Lister {
 
 type operatorsV1Lister struct {
 	operatorGroupLister *UnionOperatorGroupLister
+	catalogPolicyLister *UnionCatalogPolicyLister
 }
 
 func newOperatorsV1Lister() *operatorsV1Lister {
 	return &operatorsV1Lister{
 		operatorGroupLister: &UnionOperatorGroupLister{},
+		catalogPolicyLister: &UnionCatalogPolicyLister{},
 	}
 }
 
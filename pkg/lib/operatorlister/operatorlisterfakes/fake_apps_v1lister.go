@@ -9,6 +9,16 @@ import (
 )
 
 type FakeAppsV1Lister struct {
+	DaemonSetListerStub        func() v1.DaemonSetLister
+	daemonSetListerMutex       sync.RWMutex
+	daemonSetListerArgsForCall []struct {
+	}
+	daemonSetListerReturns struct {
+		result1 v1.DaemonSetLister
+	}
+	daemonSetListerReturnsOnCall map[int]struct {
+		result1 v1.DaemonSetLister
+	}
 	DeploymentListerStub        func() v1.DeploymentLister
 	deploymentListerMutex       sync.RWMutex
 	deploymentListerArgsForCall []struct {
@@ -19,16 +29,90 @@ type FakeAppsV1Lister struct {
 	deploymentListerReturnsOnCall map[int]struct {
 		result1 v1.DeploymentLister
 	}
+	RegisterDaemonSetListerStub        func(string, v1.DaemonSetLister)
+	registerDaemonSetListerMutex       sync.RWMutex
+	registerDaemonSetListerArgsForCall []struct {
+		arg1 string
+		arg2 v1.DaemonSetLister
+	}
 	RegisterDeploymentListerStub        func(string, v1.DeploymentLister)
 	registerDeploymentListerMutex       sync.RWMutex
 	registerDeploymentListerArgsForCall []struct {
 		arg1 string
 		arg2 v1.DeploymentLister
 	}
+	RegisterStatefulSetListerStub        func(string, v1.StatefulSetLister)
+	registerStatefulSetListerMutex       sync.RWMutex
+	registerStatefulSetListerArgsForCall []struct {
+		arg1 string
+		arg2 v1.StatefulSetLister
+	}
+	StatefulSetListerStub        func() v1.StatefulSetLister
+	statefulSetListerMutex       sync.RWMutex
+	statefulSetListerArgsForCall []struct {
+	}
+	statefulSetListerReturns struct {
+		result1 v1.StatefulSetLister
+	}
+	statefulSetListerReturnsOnCall map[int]struct {
+		result1 v1.StatefulSetLister
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeAppsV1Lister) DaemonSetLister() v1.DaemonSetLister {
+	fake.daemonSetListerMutex.Lock()
+	ret, specificReturn := fake.daemonSetListerReturnsOnCall[len(fake.daemonSetListerArgsForCall)]
+	fake.daemonSetListerArgsForCall = append(fake.daemonSetListerArgsForCall, struct {
+	}{})
+	fake.recordInvocation("DaemonSetLister", []interface{}{})
+	fake.daemonSetListerMutex.Unlock()
+	if fake.DaemonSetListerStub != nil {
+		return fake.DaemonSetListerStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.daemonSetListerReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppsV1Lister) DaemonSetListerCallCount() int {
+	fake.daemonSetListerMutex.RLock()
+	defer fake.daemonSetListerMutex.RUnlock()
+	return len(fake.daemonSetListerArgsForCall)
+}
+
+func (fake *FakeAppsV1Lister) DaemonSetListerCalls(stub func() v1.DaemonSetLister) {
+	fake.daemonSetListerMutex.Lock()
+	defer fake.daemonSetListerMutex.Unlock()
+	fake.DaemonSetListerStub = stub
+}
+
+func (fake *FakeAppsV1Lister) DaemonSetListerReturns(result1 v1.DaemonSetLister) {
+	fake.daemonSetListerMutex.Lock()
+	defer fake.daemonSetListerMutex.Unlock()
+	fake.DaemonSetListerStub = nil
+	fake.daemonSetListerReturns = struct {
+		result1 v1.DaemonSetLister
+	}{result1}
+}
+
+func (fake *FakeAppsV1Lister) DaemonSetListerReturnsOnCall(i int, result1 v1.DaemonSetLister) {
+	fake.daemonSetListerMutex.Lock()
+	defer fake.daemonSetListerMutex.Unlock()
+	fake.DaemonSetListerStub = nil
+	if fake.daemonSetListerReturnsOnCall == nil {
+		fake.daemonSetListerReturnsOnCall = make(map[int]struct {
+			result1 v1.DaemonSetLister
+		})
+	}
+	fake.daemonSetListerReturnsOnCall[i] = struct {
+		result1 v1.DaemonSetLister
+	}{result1}
+}
+
 func (fake *FakeAppsV1Lister) DeploymentLister() v1.DeploymentLister {
 	fake.deploymentListerMutex.Lock()
 	ret, specificReturn := fake.deploymentListerReturnsOnCall[len(fake.deploymentListerArgsForCall)]
@@ -81,6 +165,38 @@ func (fake *FakeAppsV1Lister) DeploymentListerReturnsOnCall(i int, result1 v1.De
 	}{result1}
 }
 
+func (fake *FakeAppsV1Lister) RegisterDaemonSetLister(arg1 string, arg2 v1.DaemonSetLister) {
+	fake.registerDaemonSetListerMutex.Lock()
+	fake.registerDaemonSetListerArgsForCall = append(fake.registerDaemonSetListerArgsForCall, struct {
+		arg1 string
+		arg2 v1.DaemonSetLister
+	}{arg1, arg2})
+	fake.recordInvocation("RegisterDaemonSetLister", []interface{}{arg1, arg2})
+	fake.registerDaemonSetListerMutex.Unlock()
+	if fake.RegisterDaemonSetListerStub != nil {
+		fake.RegisterDaemonSetListerStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppsV1Lister) RegisterDaemonSetListerCallCount() int {
+	fake.registerDaemonSetListerMutex.RLock()
+	defer fake.registerDaemonSetListerMutex.RUnlock()
+	return len(fake.registerDaemonSetListerArgsForCall)
+}
+
+func (fake *FakeAppsV1Lister) RegisterDaemonSetListerCalls(stub func(string, v1.DaemonSetLister)) {
+	fake.registerDaemonSetListerMutex.Lock()
+	defer fake.registerDaemonSetListerMutex.Unlock()
+	fake.RegisterDaemonSetListerStub = stub
+}
+
+func (fake *FakeAppsV1Lister) RegisterDaemonSetListerArgsForCall(i int) (string, v1.DaemonSetLister) {
+	fake.registerDaemonSetListerMutex.RLock()
+	defer fake.registerDaemonSetListerMutex.RUnlock()
+	argsForCall := fake.registerDaemonSetListerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeAppsV1Lister) RegisterDeploymentLister(arg1 string, arg2 v1.DeploymentLister) {
 	fake.registerDeploymentListerMutex.Lock()
 	fake.registerDeploymentListerArgsForCall = append(fake.registerDeploymentListerArgsForCall, struct {
@@ -113,13 +229,105 @@ func (fake *FakeAppsV1Lister) RegisterDeploymentListerArgsForCall(i int) (string
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeAppsV1Lister) RegisterStatefulSetLister(arg1 string, arg2 v1.StatefulSetLister) {
+	fake.registerStatefulSetListerMutex.Lock()
+	fake.registerStatefulSetListerArgsForCall = append(fake.registerStatefulSetListerArgsForCall, struct {
+		arg1 string
+		arg2 v1.StatefulSetLister
+	}{arg1, arg2})
+	fake.recordInvocation("RegisterStatefulSetLister", []interface{}{arg1, arg2})
+	fake.registerStatefulSetListerMutex.Unlock()
+	if fake.RegisterStatefulSetListerStub != nil {
+		fake.RegisterStatefulSetListerStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppsV1Lister) RegisterStatefulSetListerCallCount() int {
+	fake.registerStatefulSetListerMutex.RLock()
+	defer fake.registerStatefulSetListerMutex.RUnlock()
+	return len(fake.registerStatefulSetListerArgsForCall)
+}
+
+func (fake *FakeAppsV1Lister) RegisterStatefulSetListerCalls(stub func(string, v1.StatefulSetLister)) {
+	fake.registerStatefulSetListerMutex.Lock()
+	defer fake.registerStatefulSetListerMutex.Unlock()
+	fake.RegisterStatefulSetListerStub = stub
+}
+
+func (fake *FakeAppsV1Lister) RegisterStatefulSetListerArgsForCall(i int) (string, v1.StatefulSetLister) {
+	fake.registerStatefulSetListerMutex.RLock()
+	defer fake.registerStatefulSetListerMutex.RUnlock()
+	argsForCall := fake.registerStatefulSetListerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppsV1Lister) StatefulSetLister() v1.StatefulSetLister {
+	fake.statefulSetListerMutex.Lock()
+	ret, specificReturn := fake.statefulSetListerReturnsOnCall[len(fake.statefulSetListerArgsForCall)]
+	fake.statefulSetListerArgsForCall = append(fake.statefulSetListerArgsForCall, struct {
+	}{})
+	fake.recordInvocation("StatefulSetLister", []interface{}{})
+	fake.statefulSetListerMutex.Unlock()
+	if fake.StatefulSetListerStub != nil {
+		return fake.StatefulSetListerStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.statefulSetListerReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppsV1Lister) StatefulSetListerCallCount() int {
+	fake.statefulSetListerMutex.RLock()
+	defer fake.statefulSetListerMutex.RUnlock()
+	return len(fake.statefulSetListerArgsForCall)
+}
+
+func (fake *FakeAppsV1Lister) StatefulSetListerCalls(stub func() v1.StatefulSetLister) {
+	fake.statefulSetListerMutex.Lock()
+	defer fake.statefulSetListerMutex.Unlock()
+	fake.StatefulSetListerStub = stub
+}
+
+func (fake *FakeAppsV1Lister) StatefulSetListerReturns(result1 v1.StatefulSetLister) {
+	fake.statefulSetListerMutex.Lock()
+	defer fake.statefulSetListerMutex.Unlock()
+	fake.StatefulSetListerStub = nil
+	fake.statefulSetListerReturns = struct {
+		result1 v1.StatefulSetLister
+	}{result1}
+}
+
+func (fake *FakeAppsV1Lister) StatefulSetListerReturnsOnCall(i int, result1 v1.StatefulSetLister) {
+	fake.statefulSetListerMutex.Lock()
+	defer fake.statefulSetListerMutex.Unlock()
+	fake.StatefulSetListerStub = nil
+	if fake.statefulSetListerReturnsOnCall == nil {
+		fake.statefulSetListerReturnsOnCall = make(map[int]struct {
+			result1 v1.StatefulSetLister
+		})
+	}
+	fake.statefulSetListerReturnsOnCall[i] = struct {
+		result1 v1.StatefulSetLister
+	}{result1}
+}
+
 func (fake *FakeAppsV1Lister) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.daemonSetListerMutex.RLock()
+	defer fake.daemonSetListerMutex.RUnlock()
 	fake.deploymentListerMutex.RLock()
 	defer fake.deploymentListerMutex.RUnlock()
+	fake.registerDaemonSetListerMutex.RLock()
+	defer fake.registerDaemonSetListerMutex.RUnlock()
 	fake.registerDeploymentListerMutex.RLock()
 	defer fake.registerDeploymentListerMutex.RUnlock()
+	fake.registerStatefulSetListerMutex.RLock()
+	defer fake.registerStatefulSetListerMutex.RUnlock()
+	fake.statefulSetListerMutex.RLock()
+	defer fake.statefulSetListerMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
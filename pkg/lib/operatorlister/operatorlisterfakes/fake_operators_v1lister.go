@@ -9,6 +9,16 @@ import (
 )
 
 type FakeOperatorsV1Lister struct {
+	CatalogPolicyListerStub        func() v1.CatalogPolicyLister
+	catalogPolicyListerMutex       sync.RWMutex
+	catalogPolicyListerArgsForCall []struct {
+	}
+	catalogPolicyListerReturns struct {
+		result1 v1.CatalogPolicyLister
+	}
+	catalogPolicyListerReturnsOnCall map[int]struct {
+		result1 v1.CatalogPolicyLister
+	}
 	OperatorGroupListerStub        func() v1.OperatorGroupLister
 	operatorGroupListerMutex       sync.RWMutex
 	operatorGroupListerArgsForCall []struct {
@@ -19,6 +29,11 @@ type FakeOperatorsV1Lister struct {
 	operatorGroupListerReturnsOnCall map[int]struct {
 		result1 v1.OperatorGroupLister
 	}
+	RegisterCatalogPolicyListerStub        func(v1.CatalogPolicyLister)
+	registerCatalogPolicyListerMutex       sync.RWMutex
+	registerCatalogPolicyListerArgsForCall []struct {
+		arg1 v1.CatalogPolicyLister
+	}
 	RegisterOperatorGroupListerStub        func(string, v1.OperatorGroupLister)
 	registerOperatorGroupListerMutex       sync.RWMutex
 	registerOperatorGroupListerArgsForCall []struct {
@@ -29,6 +44,58 @@ type FakeOperatorsV1Lister struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeOperatorsV1Lister) CatalogPolicyLister() v1.CatalogPolicyLister {
+	fake.catalogPolicyListerMutex.Lock()
+	ret, specificReturn := fake.catalogPolicyListerReturnsOnCall[len(fake.catalogPolicyListerArgsForCall)]
+	fake.catalogPolicyListerArgsForCall = append(fake.catalogPolicyListerArgsForCall, struct {
+	}{})
+	fake.recordInvocation("CatalogPolicyLister", []interface{}{})
+	fake.catalogPolicyListerMutex.Unlock()
+	if fake.CatalogPolicyListerStub != nil {
+		return fake.CatalogPolicyListerStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.catalogPolicyListerReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeOperatorsV1Lister) CatalogPolicyListerCallCount() int {
+	fake.catalogPolicyListerMutex.RLock()
+	defer fake.catalogPolicyListerMutex.RUnlock()
+	return len(fake.catalogPolicyListerArgsForCall)
+}
+
+func (fake *FakeOperatorsV1Lister) CatalogPolicyListerCalls(stub func() v1.CatalogPolicyLister) {
+	fake.catalogPolicyListerMutex.Lock()
+	defer fake.catalogPolicyListerMutex.Unlock()
+	fake.CatalogPolicyListerStub = stub
+}
+
+func (fake *FakeOperatorsV1Lister) CatalogPolicyListerReturns(result1 v1.CatalogPolicyLister) {
+	fake.catalogPolicyListerMutex.Lock()
+	defer fake.catalogPolicyListerMutex.Unlock()
+	fake.CatalogPolicyListerStub = nil
+	fake.catalogPolicyListerReturns = struct {
+		result1 v1.CatalogPolicyLister
+	}{result1}
+}
+
+func (fake *FakeOperatorsV1Lister) CatalogPolicyListerReturnsOnCall(i int, result1 v1.CatalogPolicyLister) {
+	fake.catalogPolicyListerMutex.Lock()
+	defer fake.catalogPolicyListerMutex.Unlock()
+	fake.CatalogPolicyListerStub = nil
+	if fake.catalogPolicyListerReturnsOnCall == nil {
+		fake.catalogPolicyListerReturnsOnCall = make(map[int]struct {
+			result1 v1.CatalogPolicyLister
+		})
+	}
+	fake.catalogPolicyListerReturnsOnCall[i] = struct {
+		result1 v1.CatalogPolicyLister
+	}{result1}
+}
+
 func (fake *FakeOperatorsV1Lister) OperatorGroupLister() v1.OperatorGroupLister {
 	fake.operatorGroupListerMutex.Lock()
 	ret, specificReturn := fake.operatorGroupListerReturnsOnCall[len(fake.operatorGroupListerArgsForCall)]
@@ -81,6 +148,37 @@ func (fake *FakeOperatorsV1Lister) OperatorGroupListerReturnsOnCall(i int, resul
 	}{result1}
 }
 
+func (fake *FakeOperatorsV1Lister) RegisterCatalogPolicyLister(arg1 v1.CatalogPolicyLister) {
+	fake.registerCatalogPolicyListerMutex.Lock()
+	fake.registerCatalogPolicyListerArgsForCall = append(fake.registerCatalogPolicyListerArgsForCall, struct {
+		arg1 v1.CatalogPolicyLister
+	}{arg1})
+	fake.recordInvocation("RegisterCatalogPolicyLister", []interface{}{arg1})
+	fake.registerCatalogPolicyListerMutex.Unlock()
+	if fake.RegisterCatalogPolicyListerStub != nil {
+		fake.RegisterCatalogPolicyListerStub(arg1)
+	}
+}
+
+func (fake *FakeOperatorsV1Lister) RegisterCatalogPolicyListerCallCount() int {
+	fake.registerCatalogPolicyListerMutex.RLock()
+	defer fake.registerCatalogPolicyListerMutex.RUnlock()
+	return len(fake.registerCatalogPolicyListerArgsForCall)
+}
+
+func (fake *FakeOperatorsV1Lister) RegisterCatalogPolicyListerCalls(stub func(v1.CatalogPolicyLister)) {
+	fake.registerCatalogPolicyListerMutex.Lock()
+	defer fake.registerCatalogPolicyListerMutex.Unlock()
+	fake.RegisterCatalogPolicyListerStub = stub
+}
+
+func (fake *FakeOperatorsV1Lister) RegisterCatalogPolicyListerArgsForCall(i int) v1.CatalogPolicyLister {
+	fake.registerCatalogPolicyListerMutex.RLock()
+	defer fake.registerCatalogPolicyListerMutex.RUnlock()
+	argsForCall := fake.registerCatalogPolicyListerArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeOperatorsV1Lister) RegisterOperatorGroupLister(arg1 string, arg2 v1.OperatorGroupLister) {
 	fake.registerOperatorGroupListerMutex.Lock()
 	fake.registerOperatorGroupListerArgsForCall = append(fake.registerOperatorGroupListerArgsForCall, struct {
@@ -116,8 +214,12 @@ func (fake *FakeOperatorsV1Lister) RegisterOperatorGroupListerArgsForCall(i int)
 func (fake *FakeOperatorsV1Lister) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.catalogPolicyListerMutex.RLock()
+	defer fake.catalogPolicyListerMutex.RUnlock()
 	fake.operatorGroupListerMutex.RLock()
 	defer fake.operatorGroupListerMutex.RUnlock()
+	fake.registerCatalogPolicyListerMutex.RLock()
+	defer fake.registerCatalogPolicyListerMutex.RUnlock()
 	fake.registerOperatorGroupListerMutex.RLock()
 	defer fake.registerOperatorGroupListerMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
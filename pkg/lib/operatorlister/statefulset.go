@@ -0,0 +1,113 @@
+package operatorlister
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	appsv1 "k8s.io/client-go/listers/apps/v1"
+)
+
+type UnionStatefulSetLister struct {
+	statefulSetListers map[string]appsv1.StatefulSetLister
+	statefulSetLock    sync.RWMutex
+}
+
+// List lists all StatefulSets in the indexer.
+func (usl *UnionStatefulSetLister) List(selector labels.Selector) (ret []*v1.StatefulSet, err error) {
+	usl.statefulSetLock.RLock()
+	defer usl.statefulSetLock.RUnlock()
+
+	set := make(map[types.UID]*v1.StatefulSet)
+	for _, sl := range usl.statefulSetListers {
+		statefulSets, err := sl.List(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, statefulSet := range statefulSets {
+			set[statefulSet.GetUID()] = statefulSet
+		}
+	}
+
+	for _, statefulSet := range set {
+		ret = append(ret, statefulSet)
+	}
+
+	return
+}
+
+// StatefulSets returns an object that can list and get StatefulSets.
+func (usl *UnionStatefulSetLister) StatefulSets(namespace string) appsv1.StatefulSetNamespaceLister {
+	usl.statefulSetLock.RLock()
+	defer usl.statefulSetLock.RUnlock()
+
+	// Check for specific namespace listers
+	if sl, ok := usl.statefulSetListers[namespace]; ok {
+		return sl.StatefulSets(namespace)
+	}
+
+	// Check for any namespace-all listers
+	if sl, ok := usl.statefulSetListers[metav1.NamespaceAll]; ok {
+		return sl.StatefulSets(namespace)
+	}
+
+	return &NullStatefulSetNamespaceLister{}
+}
+
+func (usl *UnionStatefulSetLister) GetPodStatefulSets(pod *corev1.Pod) ([]*v1.StatefulSet, error) {
+	usl.statefulSetLock.RLock()
+	defer usl.statefulSetLock.RUnlock()
+
+	// Check for specific namespace listers
+	if sl, ok := usl.statefulSetListers[pod.GetNamespace()]; ok {
+		return sl.GetPodStatefulSets(pod)
+	}
+
+	// Check for any namespace-all listers
+	if sl, ok := usl.statefulSetListers[metav1.NamespaceAll]; ok {
+		return sl.GetPodStatefulSets(pod)
+	}
+
+	return nil, fmt.Errorf("no listers found for namespace %s", pod.GetNamespace())
+}
+
+func (usl *UnionStatefulSetLister) RegisterStatefulSetLister(namespace string, lister appsv1.StatefulSetLister) {
+	usl.statefulSetLock.Lock()
+	defer usl.statefulSetLock.Unlock()
+
+	if usl.statefulSetListers == nil {
+		usl.statefulSetListers = make(map[string]appsv1.StatefulSetLister)
+	}
+
+	usl.statefulSetListers[namespace] = lister
+}
+
+func (l *appsV1Lister) RegisterStatefulSetLister(namespace string, lister appsv1.StatefulSetLister) {
+	l.statefulSetLister.RegisterStatefulSetLister(namespace, lister)
+}
+
+func (l *appsV1Lister) StatefulSetLister() appsv1.StatefulSetLister {
+	return l.statefulSetLister
+}
+
+// NullStatefulSetNamespaceLister is an implementation of a null StatefulSetNamespaceLister. It is
+// used to prevent nil pointers when no StatefulSetNamespaceLister has been registered for a given
+// namespace.
+type NullStatefulSetNamespaceLister struct {
+	appsv1.StatefulSetNamespaceLister
+}
+
+// List returns nil and an error explaining that this is a NullStatefulSetNamespaceLister.
+func (n *NullStatefulSetNamespaceLister) List(selector labels.Selector) (ret []*v1.StatefulSet, err error) {
+	return nil, fmt.Errorf("cannot list StatefulSets with a NullStatefulSetNamespaceLister")
+}
+
+// Get returns nil and an error explaining that this is a NullStatefulSetNamespaceLister.
+func (n *NullStatefulSetNamespaceLister) Get(name string) (*v1.StatefulSet, error) {
+	return nil, fmt.Errorf("cannot get StatefulSet with a NullStatefulSetNamespaceLister")
+}
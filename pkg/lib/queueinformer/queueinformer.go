@@ -150,6 +150,17 @@ func (l LegacySyncHandler) ToSyncer() kubestate.Syncer {
 
 // ToSyncerWithDelete returns the Syncer equivalent of the given sync handler and delete function.
 func (l LegacySyncHandler) ToSyncerWithDelete(onDelete func(obj interface{})) kubestate.Syncer {
+	return ContextSyncHandler(func(ctx context.Context, obj interface{}) error {
+		return l(obj)
+	}).ToSyncerWithDelete(onDelete)
+}
+
+// ContextSyncHandler is a LegacySyncHandler that's also given the context of the sync, which is done when the
+// QueueInformer stops.
+type ContextSyncHandler func(ctx context.Context, obj interface{}) error
+
+// ToSyncerWithDelete returns the Syncer equivalent of the given sync handler and delete function.
+func (h ContextSyncHandler) ToSyncerWithDelete(onDelete func(obj interface{})) kubestate.Syncer {
 	var syncer kubestate.SyncFunc = func(ctx context.Context, event kubestate.ResourceEvent) error {
 		logrus.New().WithField("event", fmt.Sprintf("%+v", event)).Trace("legacy syncer received event")
 		switch event.Type() {
@@ -161,7 +172,7 @@ func (l LegacySyncHandler) ToSyncerWithDelete(onDelete func(obj interface{})) ku
 			// Added and updated are treated the same
 			fallthrough
 		case kubestate.ResourceUpdated:
-			return l(event.Resource())
+			return h(ctx, event.Resource())
 		default:
 			return errors.Errorf("unexpected resource event type: %s", event.Type())
 		}
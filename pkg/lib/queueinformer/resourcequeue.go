@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
@@ -56,6 +57,28 @@ func (r *ResourceQueueSet) Requeue(namespace, name string) error {
 	return fmt.Errorf("couldn't find queue for resource")
 }
 
+// RequeueAfter requeues the resource in the set with the given name and namespace once the given duration has passed
+func (r *ResourceQueueSet) RequeueAfter(namespace, name string, duration time.Duration) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// We can build the key directly, will need to change if queue uses different key scheme
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	event := kubestate.NewResourceEvent(kubestate.ResourceUpdated, key)
+
+	if queue, ok := r.queueSet[metav1.NamespaceAll]; len(r.queueSet) == 1 && ok {
+		queue.AddAfter(event, duration)
+		return nil
+	}
+
+	if queue, ok := r.queueSet[namespace]; ok {
+		queue.AddAfter(event, duration)
+		return nil
+	}
+
+	return fmt.Errorf("couldn't find queue for resource")
+}
+
 // RequeueByKey adds the given key to the resource queue that should contain it
 func (r *ResourceQueueSet) RequeueByKey(key string) error {
 	r.mutex.RLock()
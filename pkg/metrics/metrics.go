@@ -132,6 +132,38 @@ var (
 			Help: "Monotonic count of CSV upgrades",
 		},
 	)
+
+	// exported since they're updated by the registry connection pools
+	CatalogSourceConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "catalog_source_connections",
+			Help: "Number of connections to catalog source registries, by component and state",
+		},
+		[]string{"component", "state"},
+	)
+
+	CatalogSourceReconnectCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "catalog_source_reconnect_count",
+			Help: "Monotonic count of rebuilt connections to catalog source registries, by component",
+		},
+		[]string{"component"},
+	)
+
+	// exported since they're updated by the package-server's package manifest cache
+	PackageManifestCacheHitCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "packagemanifest_cache_hit_count",
+			Help: "Monotonic count of catalogs whose package manifests were served from the package-server's cache",
+		},
+	)
+
+	PackageManifestCacheRefreshCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "packagemanifest_cache_refresh_count",
+			Help: "Monotonic count of catalogs whose package manifests were read into the package-server's cache",
+		},
+	)
 )
 
 func RegisterOLM() {
@@ -143,4 +175,13 @@ func RegisterCatalog() {
 	prometheus.MustRegister(installPlanCount)
 	prometheus.MustRegister(subscriptionCount)
 	prometheus.MustRegister(catalogSourceCount)
+	prometheus.MustRegister(CatalogSourceConnections)
+	prometheus.MustRegister(CatalogSourceReconnectCount)
+}
+
+func RegisterPackageServer() {
+	prometheus.MustRegister(CatalogSourceConnections)
+	prometheus.MustRegister(CatalogSourceReconnectCount)
+	prometheus.MustRegister(PackageManifestCacheHitCount)
+	prometheus.MustRegister(PackageManifestCacheRefreshCount)
 }
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/metrics"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators"
+)
+
+// cachedCatalog holds the package manifests read from the registry server of a catalog, by package name.
+type cachedCatalog struct {
+	mu sync.Mutex
+
+	// lastSync and lastConnect are those of the CatalogSource and the connection the manifests were read through, and
+	// resourceVersion that of the CatalogSource whose metadata the manifests hold.
+	lastSync        metav1.Time
+	lastConnect     metav1.Time
+	resourceVersion string
+	loaded          bool
+	manifests       map[string]*operators.PackageManifest
+}
+
+// upToDate returns true if the manifests were read since the registry server of the given connection was last synced,
+// from the current version of its CatalogSource.
+func (c *cachedCatalog) upToDate(source connection.Source) bool {
+	return c.loaded && c.lastConnect.Equal(&source.LastConnect) && c.lastSync.Equal(&source.CatalogSource.Status.LastSync) &&
+		c.resourceVersion == source.CatalogSource.GetResourceVersion()
+}
+
+// manifestCache caches the package manifests of each catalog, so that they're only read from its registry server again
+// once its CatalogSource is synced or changed, or its connection is rebuilt.
+type manifestCache struct {
+	mu       sync.Mutex
+	catalogs map[types.NamespacedName]*cachedCatalog
+}
+
+func newManifestCache() *manifestCache {
+	return &manifestCache{catalogs: make(map[types.NamespacedName]*cachedCatalog)}
+}
+
+func (m *manifestCache) catalog(key types.NamespacedName) *cachedCatalog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.catalogs[key]
+	if !ok {
+		c = &cachedCatalog{}
+		m.catalogs[key] = c
+	}
+	return c
+}
+
+// remove drops the manifests of the catalog with the given key.
+func (m *manifestCache) remove(key types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.catalogs, key)
+}
+
+// manifests returns the package manifests of the catalog with the given key and healthy connection, reading them from
+// its registry server if the cached ones are out of date. Cached manifests are kept if they can't be read again, and
+// must not be modified.
+func (m *manifestCache) manifests(logger *logrus.Entry, key types.NamespacedName, source connection.Source, client registryClient) map[string]*operators.PackageManifest {
+	c := m.catalog(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.upToDate(source) {
+		metrics.PackageManifestCacheHitCount.Inc()
+		return c.manifests
+	}
+
+	manifests, err := readManifests(client)
+	if err != nil {
+		logger.WithField("err", err.Error()).Warnf("error reading packages of CatalogSource %s", key.Name)
+		return c.manifests
+	}
+	metrics.PackageManifestCacheRefreshCount.Inc()
+
+	c.lastSync = source.CatalogSource.Status.LastSync
+	c.lastConnect = source.LastConnect
+	c.resourceVersion = source.CatalogSource.GetResourceVersion()
+	c.loaded = true
+	c.manifests = manifests
+	return c.manifests
+}
+
+// readManifests reads the package manifests of every package in the registry server of the given client.
+func readManifests(client registryClient) (map[string]*operators.PackageManifest, error) {
+	stream, err := client.ListPackages(context.Background(), &api.ListPackageRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := map[string]*operators.PackageManifest{}
+	for {
+		pkgName, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pkg, err := client.GetPackage(context.Background(), &api.GetPackageRequest{Name: pkgName.GetName()})
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := toPackageManifest(pkg, client)
+		if err != nil {
+			return nil, err
+		}
+		manifests[manifest.GetName()] = manifest
+	}
+	return manifests, nil
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+)
+
+// countingClient counts the times the packages of a registry are listed.
+type countingClient struct {
+	api.RegistryClient
+	listed int
+}
+
+func (c *countingClient) ListPackages(ctx context.Context, in *api.ListPackageRequest, opts ...grpc.CallOption) (api.Registry_ListPackagesClient, error) {
+	c.listed++
+	return c.RegistryClient.ListPackages(ctx, in, opts...)
+}
+
+func TestRegistryProviderCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	provider, err := NewFakeRegistryProvider(ctx, nil, nil, []string{"ns"}, "ns")
+	require.NoError(t, err)
+
+	catsrc := withRegistryServiceStatus(catalogSource("cool-operators", "ns"), "grpc", "cool-operators", "ns", port, metav1.NewTime(time.Now()))
+	addSource(t, provider, catsrc)
+	key := connection.Key(catsrc)
+	source, ok := provider.sources.Get(key)
+	require.True(t, ok)
+	counter := &countingClient{RegistryClient: source.Client.(*registryConn).RegistryClient}
+	source.Client.(*registryConn).RegistryClient = counter
+
+	// The packages of a catalog are read once
+	list, err := provider.List("ns")
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Items)
+	require.Equal(t, 1, counter.listed)
+
+	list, err = provider.List("ns")
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Items)
+	require.Equal(t, 1, counter.listed)
+
+	// Packages are got from the cache too, and listed in the requested namespace
+	pkg, err := provider.Get("other", "prometheus")
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+	require.Equal(t, "other", pkg.GetNamespace())
+	require.Equal(t, 1, counter.listed)
+
+	pkg, err = provider.Get("ns", "missing")
+	require.NoError(t, err)
+	require.Nil(t, pkg)
+
+	// Listing in another namespace doesn't change the cached packages
+	cached := provider.cache.catalog(key).manifests["prometheus"]
+	require.Equal(t, "ns", cached.GetNamespace())
+
+	// They're read again once the catalog is synced
+	synced := catsrc.DeepCopy()
+	synced.Status.LastSync = metav1.NewTime(time.Now().Add(time.Minute))
+	source.CatalogSource = synced
+	provider.sources.Add(key, source)
+	_, err = provider.List("ns")
+	require.NoError(t, err)
+	require.Equal(t, 2, counter.listed)
+
+	// And once the catalog's metadata changes, which the packages hold
+	relabeled := synced.DeepCopy()
+	relabeled.SetLabels(map[string]string{"tier": "certified"})
+	relabeled.SetResourceVersion("2")
+	source.CatalogSource = relabeled
+	provider.sources.Add(key, source)
+	pkg, err = provider.Get("ns", "prometheus")
+	require.NoError(t, err)
+	require.Equal(t, "certified", pkg.GetLabels()["tier"])
+	require.Equal(t, 3, counter.listed)
+
+	// And dropped once it's deleted
+	provider.catalogSourceDeleted(synced)
+	require.Empty(t, provider.cache.catalogs)
+}
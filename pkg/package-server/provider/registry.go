@@ -3,47 +3,86 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"sync"
 	"time"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/kubernetes/pkg/util/labels"
 
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/informers/externalversions"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorlister"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/queueinformer"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators"
 )
 
-const (
-	defaultConnectionTimeout = 5 * time.Second
-)
+var errUnrecoveredTransient = errors.New("connection didn't recover from TransientFailure")
+
+// registryDialer connects to registry servers. Connections are health checked with the gRPC health client, since the
+// operator-registry client registers the same protobuf types as the API server's dependencies.
+var registryDialer = connection.Dialer{
+	Dial:          dialRegistry,
+	Unrecoverable: func(err error) bool { return err == errUnrecoveredTransient },
+}
+
+type registryConn struct {
+	api.RegistryClient
+	health healthpb.HealthClient
+	conn   *grpc.ClientConn
+}
+
+func dialRegistry(address string) (connection.Client, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return &registryConn{
+		RegistryClient: api.NewRegistryClient(conn),
+		health:         healthpb.NewHealthClient(conn),
+		conn:           conn,
+	}, nil
+}
+
+func (c *registryConn) HealthCheck(ctx context.Context, reconnectTimeout time.Duration) (bool, error) {
+	res, err := c.health.Check(ctx, &healthpb.HealthCheckRequest{Service: "Registry"})
+	if err != nil {
+		if c.conn.GetState() == connectivity.TransientFailure {
+			ctx, cancel := context.WithTimeout(ctx, reconnectTimeout)
+			defer cancel()
+			if !c.conn.WaitForStateChange(ctx, connectivity.TransientFailure) {
+				return false, errUnrecoveredTransient
+			}
+		}
+		return false, err
+	}
+	return res.Status == healthpb.HealthCheckResponse_SERVING, nil
+}
 
-type sourceKey struct {
-	name      string
-	namespace string
+func (c *registryConn) Close() error {
+	return c.conn.Close()
 }
 
 type registryClient struct {
 	api.RegistryClient
 	source *operatorsv1alpha1.CatalogSource
-	conn   *grpc.ClientConn
 }
 
-func newRegistryClient(source *operatorsv1alpha1.CatalogSource, conn *grpc.ClientConn) registryClient {
+func newRegistryClient(source *operatorsv1alpha1.CatalogSource, client api.RegistryClient) registryClient {
 	return registryClient{
-		RegistryClient: api.NewRegistryClient(conn),
+		RegistryClient: client,
 		source:         source,
-		conn:           conn,
 	}
 }
 
@@ -51,9 +90,10 @@ func newRegistryClient(source *operatorsv1alpha1.CatalogSource, conn *grpc.Clien
 type RegistryProvider struct {
 	queueinformer.Operator
 
-	mu              sync.RWMutex
 	globalNamespace string
-	clients         map[sourceKey]registryClient
+	sources         *connection.Pool
+	policies        *operatorlister.UnionCatalogPolicyLister
+	cache           *manifestCache
 }
 
 var _ PackageManifestProvider = &RegistryProvider{}
@@ -63,7 +103,9 @@ func NewRegistryProvider(ctx context.Context, crClient versioned.Interface, oper
 		Operator: operator,
 
 		globalNamespace: globalNamespace,
-		clients:         make(map[sourceKey]registryClient),
+		sources:         connection.NewPool("package-server", metav1.Now, registryDialer),
+		policies:        &operatorlister.UnionCatalogPolicyLister{},
+		cache:           newManifestCache(),
 	}
 
 	for _, namespace := range watchedNamespaces {
@@ -75,7 +117,7 @@ func NewRegistryProvider(ctx context.Context, crClient versioned.Interface, oper
 		catsrcQueueInformer, err := queueinformer.NewQueueInformer(
 			ctx,
 			queueinformer.WithInformer(catsrcInformer.Informer()),
-			queueinformer.WithSyncer(queueinformer.LegacySyncHandler(p.syncCatalogSource).ToSyncerWithDelete(p.catalogSourceDeleted)),
+			queueinformer.WithSyncer(queueinformer.ContextSyncHandler(p.syncCatalogSource).ToSyncerWithDelete(p.catalogSourceDeleted)),
 		)
 		if err != nil {
 			return nil, err
@@ -83,38 +125,17 @@ func NewRegistryProvider(ctx context.Context, crClient versioned.Interface, oper
 		p.RegisterQueueInformer(catsrcQueueInformer)
 	}
 
-	return p, nil
-}
-
-func (p *RegistryProvider) getClient(key sourceKey) (registryClient, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	client, ok := p.clients[key]
-	return client, ok
-}
-
-func (p *RegistryProvider) setClient(client registryClient, key sourceKey) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	p.clients[key] = client
-}
-
-func (p *RegistryProvider) removeClient(key sourceKey) (registryClient, bool) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	client, ok := p.clients[key]
-	if !ok {
-		return registryClient{}, false
+	// CatalogPolicies are only read when listing, to omit the catalogs they deny
+	catalogPolicyInformer := externalversions.NewSharedInformerFactory(crClient, wakeupInterval).Operators().V1().CatalogPolicies()
+	p.policies.RegisterCatalogPolicyLister(catalogPolicyInformer.Lister())
+	if err := p.RegisterInformer(catalogPolicyInformer.Informer()); err != nil {
+		return nil, err
 	}
 
-	delete(p.clients, key)
-	return client, true
+	return p, nil
 }
 
-func (p *RegistryProvider) syncCatalogSource(obj interface{}) (syncError error) {
+func (p *RegistryProvider) syncCatalogSource(ctx context.Context, obj interface{}) (syncError error) {
 	source, ok := obj.(*operatorsv1alpha1.CatalogSource)
 	if !ok {
 		logrus.Errorf("catalogsource type assertion failed: wrong type: %#v", obj)
@@ -126,44 +147,31 @@ func (p *RegistryProvider) syncCatalogSource(obj interface{}) (syncError error)
 		"namespace": source.GetNamespace(),
 	})
 
-	if source.Status.RegistryServiceStatus == nil {
-		logger.Debug("registry service is not ready for grpc connection")
+	// Catalogs of CatalogSources with a URL are served on the catalog operator's loopback interface, so their packages
+	// aren't listed as PackageManifests. They're still resolved and installed by the catalog operator.
+	if source.Spec.SourceType == operatorsv1alpha1.SourceTypeURL {
+		logger.Debug("catalog is served from within the catalog operator, which isn't reachable")
 		return
 	}
 
-	key := sourceKey{source.GetName(), source.GetNamespace()}
-	client, ok := p.getClient(key)
-	if ok && source.Status.RegistryServiceStatus.ServiceName != "" {
-		logger.Info("update detected, attempting to reset grpc connection")
-		client.conn.ResetConnectBackoff()
-
-		ctx, cancel := context.WithTimeout(context.TODO(), defaultConnectionTimeout)
-		defer cancel()
-
-		changed := client.conn.WaitForStateChange(ctx, connectivity.TransientFailure)
-		if !changed {
-			logger.Debugf("grpc connection reset timeout")
-			syncError = fmt.Errorf("grpc connection reset timeout")
-			return
-		}
-
-		logger.Info("grpc connection reset")
+	if source.Status.RegistryServiceStatus == nil {
+		logger.Debug("registry service is not ready for grpc connection")
 		return
-	} else if ok {
-		// Address type grpc CatalogSource, drop the connection dial in to the new address
-		client.conn.Close()
 	}
 
-	logger.Info("attempting to add a new grpc connection")
-	conn, err := grpc.Dial(source.Address(), grpc.WithInsecure())
+	result, err := p.sources.Sync(ctx, logger, source)
 	if err != nil {
 		logger.WithField("err", err.Error()).Errorf("could not connect to registry service")
 		syncError = err
 		return
 	}
-
-	p.setClient(newRegistryClient(source, conn), key)
-	logger.Info("new grpc connection added")
+	if !result.Source.Healthy() {
+		syncError = fmt.Errorf("registry service for catalogsource %s/%s is not healthy yet", source.GetNamespace(), source.GetName())
+		return
+	}
+	if result.Connected {
+		logger.Info("new grpc connection added")
+	}
 
 	return
 }
@@ -193,21 +201,19 @@ func (p *RegistryProvider) catalogSourceDeleted(obj interface{}) {
 	})
 	logger.Debugf("attempting to remove grpc connection")
 
-	key := sourceKey{catsrc.GetName(), catsrc.GetNamespace()}
-	client, removed := p.removeClient(key)
-	if removed {
-		err := client.conn.Close()
-		if err != nil {
-			logger.WithField("err", err.Error()).Error("error closing connection")
-			utilruntime.HandleError(fmt.Errorf("error closing connection %s", err.Error()))
-			return
-		}
-		logger.Debug("grpc connection removed")
+	key := connection.Key(catsrc)
+	p.cache.remove(key)
+	if _, ok := p.sources.Get(key); !ok {
+		logger.Debugf("no gRPC connection to remove")
 		return
 	}
 
-	logger.Debugf("no gRPC connection to remove")
-
+	if err := p.sources.Remove(key); err != nil {
+		logger.WithField("err", err.Error()).Error("error closing connection")
+		utilruntime.HandleError(fmt.Errorf("error closing connection %s", err.Error()))
+		return
+	}
+	logger.Debug("grpc connection removed")
 }
 
 func (p *RegistryProvider) Get(namespace, name string) (*operators.PackageManifest, error) {
@@ -217,19 +223,22 @@ func (p *RegistryProvider) Get(namespace, name string) (*operators.PackageManife
 		"namespace": namespace,
 	})
 
-	pkgs, err := p.List(namespace)
+	var found *operators.PackageManifest
+	err := p.eachCatalog(logger, namespace, func(manifests map[string]*operators.PackageManifest) bool {
+		pkg, ok := manifests[name]
+		if ok {
+			found = p.inNamespace(pkg, namespace)
+		}
+		return !ok
+	})
 	if err != nil {
 		return nil, fmt.Errorf("could not list packages in namespace %s", namespace)
 	}
 
-	for _, pkg := range pkgs.Items {
-		if pkg.GetName() == name {
-			return &pkg, nil
-		}
+	if found == nil {
+		logger.Info("package not found")
 	}
-
-	logger.Info("package not found")
-	return nil, nil
+	return found, nil
 }
 
 func (p *RegistryProvider) List(namespace string) (*operators.PackageManifestList, error) {
@@ -238,52 +247,71 @@ func (p *RegistryProvider) List(namespace string) (*operators.PackageManifestLis
 		"namespace": namespace,
 	})
 
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
 	pkgs := []operators.PackageManifest{}
-	for _, client := range p.clients {
-		if client.source.GetNamespace() == namespace || client.source.GetNamespace() == p.globalNamespace || namespace == metav1.NamespaceAll {
-			logger.Debugf("found CatalogSource %s", client.source.GetName())
-
-			stream, err := client.ListPackages(context.Background(), &api.ListPackageRequest{})
-			if err != nil {
-				logger.WithField("err", err.Error()).Warnf("error getting stream")
-				continue
-			}
-			for {
-				pkgName, err := stream.Recv()
-				if err == io.EOF {
-					break
-				}
-
-				if err != nil {
-					logger.WithField("err", err.Error()).Warnf("error getting data")
-					break
-				}
-				pkg, err := client.GetPackage(context.Background(), &api.GetPackageRequest{Name: pkgName.GetName()})
-				if err != nil {
-					logger.WithField("err", err.Error()).Warnf("error getting package")
-					break
-				}
-				newPkg, err := toPackageManifest(pkg, client)
-				if err != nil {
-					logger.WithField("err", err.Error()).Warnf("error converting to packagemanifest")
-					break
-				}
-
-				// Set request namespace to stop kube clients from complaining about global namespace mismatch.
-				if namespace != metav1.NamespaceAll {
-					newPkg.SetNamespace(namespace)
-				}
-				pkgs = append(pkgs, *newPkg)
-			}
+	err := p.eachCatalog(logger, namespace, func(manifests map[string]*operators.PackageManifest) bool {
+		for _, pkg := range manifests {
+			pkgs = append(pkgs, *p.inNamespace(pkg, namespace))
 		}
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &operators.PackageManifestList{Items: pkgs}, nil
 }
 
+// inNamespace returns a copy of the given cached package manifest, listed in the given namespace.
+func (p *RegistryProvider) inNamespace(pkg *operators.PackageManifest, namespace string) *operators.PackageManifest {
+	out := pkg.DeepCopy()
+
+	// Set request namespace to stop kube clients from complaining about global namespace mismatch.
+	if namespace != metav1.NamespaceAll {
+		out.SetNamespace(namespace)
+	}
+	return out
+}
+
+// eachCatalog calls the given function with the package manifests of each healthy catalog that packages are listed from
+// in the given namespace, until it returns false.
+func (p *RegistryProvider) eachCatalog(logger *logrus.Entry, namespace string, f func(manifests map[string]*operators.PackageManifest) bool) error {
+	policies, err := p.policies.List(k8slabels.Everything())
+	if err != nil {
+		return fmt.Errorf("could not list catalog policies: %s", err)
+	}
+
+	for key, source := range p.sources.Sources() {
+		if key.Namespace != namespace && key.Namespace != p.globalNamespace && namespace != metav1.NamespaceAll {
+			continue
+		}
+		logger.Debugf("found CatalogSource %s", key.Name)
+
+		c, ok := source.Client.(*registryConn)
+		if !ok || !source.Healthy() {
+			logger.Debugf("omitting CatalogSource %s, hasn't yet become healthy", key.Name)
+			continue
+		}
+
+		// packages are listed in the requested namespace, or in their catalog's when listing all namespaces
+		policyNamespace := namespace
+		if namespace == metav1.NamespaceAll {
+			policyNamespace = key.Namespace
+		}
+		catalog := operatorsv1.CatalogSourceReference{Name: key.Name, Namespace: key.Namespace}
+		if deniedBy, err := operatorsv1.CatalogDeniedBy(policies, policyNamespace, catalog, source.CatalogSource.GetLabels()); err != nil || deniedBy != nil {
+			logger.WithError(err).Debugf("omitting CatalogSource %s, denied by catalog policy", key.Name)
+			continue
+		}
+
+		client := newRegistryClient(source.CatalogSource, c.RegistryClient)
+		if !f(p.cache.manifests(logger, key, source, client)) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 func toPackageManifest(pkg *api.Package, client registryClient) (*operators.PackageManifest, error) {
 	pkgChannels := pkg.GetChannels()
 	catsrc := client.source
@@ -19,9 +19,12 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
+	"github.com/operator-framework/operator-lifecycle-manager/pkg/controller/registry/connection"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/operatorclient"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/queueinformer"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators"
@@ -93,6 +96,19 @@ func NewFakeRegistryProvider(ctx context.Context, clientObjs []runtime.Object, k
 	return NewRegistryProvider(ctx, clientFake, op, resyncInterval, watchedNamespaces, globalNamespace)
 }
 
+// addSource adds a healthy connection to the test registry of the given CatalogSource to the given provider.
+func addSource(t *testing.T, provider *RegistryProvider, catsrc *operatorsv1alpha1.CatalogSource) {
+	client, err := dialRegistry(address + catsrc.Status.RegistryServiceStatus.Port)
+	require.NoError(t, err, "could not set up test grpc connection")
+	provider.sources.Add(connection.Key(catsrc), connection.Source{
+		Address:       catsrc.Address(),
+		Client:        client,
+		LastConnect:   metav1.Now(),
+		LastHealthy:   metav1.Now(),
+		CatalogSource: catsrc,
+	})
+}
+
 func catalogSource(name, namespace string) *operatorsv1alpha1.CatalogSource {
 	return &operatorsv1alpha1.CatalogSource{
 		ObjectMeta: metav1.ObjectMeta{
@@ -394,9 +410,7 @@ func TestRegistryProviderGet(t *testing.T) {
 
 			for _, cs := range test.catalogSources {
 				catsrc := (cs).(*operatorsv1alpha1.CatalogSource)
-				conn, err := grpc.Dial(address+catsrc.Status.RegistryServiceStatus.Port, grpc.WithInsecure())
-				require.NoError(t, err, "could not set up test grpc connection")
-				provider.clients[sourceKey{catsrc.GetName(), catsrc.GetNamespace()}] = newRegistryClient(catsrc, conn)
+				addSource(t, provider, catsrc)
 			}
 
 			packageManifest, err := provider.Get(test.request.packageNamespace, test.request.packageName)
@@ -595,9 +609,7 @@ func TestRegistryProviderList(t *testing.T) {
 
 			for _, cs := range test.catalogSources {
 				catsrc := (cs).(*operatorsv1alpha1.CatalogSource)
-				conn, err := grpc.Dial(address+catsrc.Status.RegistryServiceStatus.Port, grpc.WithInsecure())
-				require.NoError(t, err, "could not set up test grpc connection")
-				provider.clients[sourceKey{catsrc.GetName(), catsrc.GetNamespace()}] = newRegistryClient(catsrc, conn)
+				addSource(t, provider, catsrc)
 			}
 
 			packageManifestList, err := provider.List(test.requestNamespace)
@@ -613,3 +625,47 @@ func TestRegistryProviderList(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistryProviderListCatalogPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	policy := &operatorsv1.CatalogPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: operatorsv1.CatalogPolicySpec{
+			Namespaces: []string{"ns"},
+			Allowed:    []operatorsv1.CatalogSourceReference{{Name: "curated", Namespace: "global"}},
+		},
+	}
+	provider, err := NewFakeRegistryProvider(ctx, []runtime.Object{policy}, nil, []string{metav1.NamespaceAll}, "global")
+	require.NoError(t, err)
+	provider.RunInformers(ctx)
+	require.True(t, cache.WaitForCacheSync(ctx.Done(), provider.HasSynced))
+
+	addSource(t, provider, withRegistryServiceStatus(catalogSource("cool-operators", "ns"), "grpc", "cool-operators", "ns", port, metav1.NewTime(time.Now())))
+	addSource(t, provider, withRegistryServiceStatus(catalogSource("community", "global"), "grpc", "community", "global", port, metav1.NewTime(time.Now())))
+	addSource(t, provider, withRegistryServiceStatus(catalogSource("curated", "global"), "grpc", "curated", "global", port, metav1.NewTime(time.Now())))
+
+	catalogs := func(list *operators.PackageManifestList) map[string]struct{} {
+		names := map[string]struct{}{}
+		for _, pkg := range list.Items {
+			names[pkg.Status.CatalogSource] = struct{}{}
+		}
+		return names
+	}
+
+	// Only the allowed catalog is listed in the namespace the policy applies to
+	list, err := provider.List("ns")
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"curated": {}}, catalogs(list))
+
+	// Other namespaces see every global catalog
+	list, err = provider.List("other")
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"community": {}, "curated": {}}, catalogs(list))
+
+	// Packages listed across namespaces follow the policies of their catalog's namespace
+	list, err = provider.List(metav1.NamespaceAll)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"community": {}, "curated": {}}, catalogs(list))
+}
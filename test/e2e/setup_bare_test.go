@@ -121,7 +121,7 @@ func TestMain(m *testing.M) {
 		logrus.WithError(err).Fatalf("error configuring olm")
 	}
 	olmOperator.Run(ctx)
-	catalogOperator, err := catalog.NewOperator(ctx, *kubeConfigPath, utilclock.RealClock{}, catlogger, time.Minute, "quay.io/operatorframework/configmap-operator-registry:latest", *namespace, namespaces...)
+	catalogOperator, err := catalog.NewOperator(ctx, *kubeConfigPath, utilclock.RealClock{}, catlogger, time.Minute, "quay.io/operatorframework/configmap-operator-registry:latest", *namespace, namespaces)
 	if err != nil {
 		logrus.WithError(err).Fatalf("error configuring catalog")
 	}